@@ -0,0 +1,120 @@
+package dials
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// SourceValue pairs the WatchArgs passed to a watching Source's Watch method
+// with a new value for that Source, for use with ReportNewValues.
+type SourceValue struct {
+	Args  WatchArgs
+	Value reflect.Value
+}
+
+// batchValueUpdate carries new values for several sources that must be
+// installed together (or not at all).
+type batchValueUpdate struct {
+	updates   []valueUpdate
+	installed chan<- error
+}
+
+func (*batchValueUpdate) isStatusReport() {}
+
+// ReportNewValues reports new values for several watching sources of the same
+// Dials instance as a single unit, for sources whose values must change
+// together (e.g. sources for a TLS certificate and its key). The
+// configuration is re-stacked once with all the new values applied, and
+// verified (if enabled). If stacking or verification fails, none of the new
+// values are retained, and the error is returned.
+//
+// Like WatchArgs.BlockingReportNewValue, ReportNewValues blocks until the
+// new values have been installed or rejected, and should only be used in
+// cases similar to [github.com/vimeo/dials/sourcewrap.SetSources].
+//
+// Each Args must be the WatchArgs passed to Watch by Dials, (not a wrapper
+// around it) and all of them must belong to the same Dials instance.
+func ReportNewValues(ctx context.Context, vals ...SourceValue) error {
+	if len(vals) == 0 {
+		return nil
+	}
+	var c chan watchStatusUpdate
+	updates := make([]valueUpdate, len(vals))
+	for i, v := range vals {
+		wa, ok := v.Args.(*watchArgs)
+		if !ok {
+			return fmt.Errorf("unsupported WatchArgs of type %T at index %d", v.Args, i)
+		}
+		if c == nil {
+			c = wa.c
+		} else if wa.c != c {
+			return errors.New("WatchArgs belong to different Dials instances")
+		}
+		updates[i] = valueUpdate{source: wa.s, value: v.Value}
+	}
+
+	installed := make(chan error, 1)
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("context expired while attempting to submit new values: %w", ctx.Err())
+	case c <- &batchValueUpdate{updates: updates, installed: installed}:
+	}
+
+	// Submitted, now we wait for the new values to be handled.
+	select {
+	case err := <-installed:
+		if err != nil {
+			return fmt.Errorf("stacking failed: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("context expired while awaiting restack: %w", ctx.Err())
+	}
+}
+
+// updateSourceValues applies all the values in batch, and re-stacks the
+// configuration, restoring the previous values if that fails. Returns the
+// new value (if any).
+func (d *Dials[T]) updateSourceValues(
+	ctx context.Context,
+	t *T,
+	skipVerify bool,
+	sourceValues []sourceValue,
+	batch *batchValueUpdate,
+) *T {
+	prev := make([]reflect.Value, len(sourceValues))
+	for i, sv := range sourceValues {
+		prev[i] = sv.value
+	}
+	for _, u := range batch.updates {
+		for i, sv := range sourceValues {
+			if u.source == sv.source {
+				sourceValues[i].value = u.value
+				break
+			}
+		}
+	}
+
+	newVers, warnings, restackErr := d.restack(ctx, t, skipVerify, sourceValues)
+	if restackErr != nil {
+		d.params.log(ctx, LogLevelWarn, "rejected new configuration from batch of watching sources",
+			"sources", len(batch.updates), "error", restackErr)
+		// roll back all the values in the batch
+		for i := range sourceValues {
+			sourceValues[i].value = prev[i]
+		}
+		d.reportRestackError(ctx, restackErr, newVers)
+		batch.installed <- restackErr
+		return nil
+	}
+
+	d.install(newVers)
+	if len(warnings) > 0 {
+		d.submitEvent(ctx, &verifyWarningsEvent[T]{warnings: warnings, cfg: newVers})
+	}
+	d.submitOverrides(ctx, sourceValues)
+	batch.installed <- nil
+	return newVers
+}
@@ -0,0 +1,209 @@
+package dials
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// CanonicalHash returns a hex-encoded SHA-256 hash of the contents of cfg,
+// suitable for detecting whether anything actually changed between two
+// versions of a configuration (e.g. across restarts, or between the old and
+// new versions passed to a NewConfigHandler).
+//
+// The hash only depends on the values of cfg's fields, never on memory
+// addresses or on map iteration order: cfg is walked in the same order as
+// ToFlatMap (keyed by the same dotted paths), and map entries are hashed in
+// the order of their (canonically-encoded) keys. Values implementing
+// encoding.TextMarshaler (such as time.Time) are hashed by their text
+// encoding. Nil and empty slices and maps hash identically. Unexported
+// fields are ignored, as are channel, func and interface fields of structs.
+//
+// An error is returned if cfg contains a value that can't be hashed
+// deterministically (such as a channel within a slice), a cycle of pointers,
+// or a value whose MarshalText method fails.
+func CanonicalHash[T any](cfg *T) (string, error) {
+	v := reflect.ValueOf(cfg).Elem()
+	h := canonicalHasher{visiting: map[uintptr]struct{}{}}
+	if v.Kind() != reflect.Struct {
+		if err := h.encode(&h.buf, v); err != nil {
+			return "", err
+		}
+		return h.sum(), nil
+	}
+FIELDS:
+	for _, ff := range flatMapFields(v.Type(), nil, nil, nil) {
+		fv := v
+		for _, i := range ff.idx {
+			if fv.Kind() == reflect.Ptr {
+				if fv.IsNil() {
+					continue FIELDS
+				}
+				fv = fv.Elem()
+			}
+			fv = fv.Field(i)
+		}
+		if isEmptyLeaf(fv) {
+			continue
+		}
+		writeCanonicalString(&h.buf, ff.key)
+		if err := h.encode(&h.buf, fv); err != nil {
+			return "", fmt.Errorf("failed to hash %q: %w", ff.key, err)
+		}
+	}
+	return h.sum(), nil
+}
+
+// isEmptyLeaf indicates whether the leaf-field value v is unset, in which
+// case it's left out of the hash entirely.
+func isEmptyLeaf(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr:
+		return v.IsNil()
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	default:
+		return false
+	}
+}
+
+type canonicalHasher struct {
+	buf bytes.Buffer
+	// visiting tracks the pointers being traversed, to detect cycles
+	visiting map[uintptr]struct{}
+}
+
+func (h *canonicalHasher) sum() string {
+	s := sha256.Sum256(h.buf.Bytes())
+	return hex.EncodeToString(s[:])
+}
+
+// writeCanonicalString writes the length of s followed by s, so adjacent
+// strings can't run together ambiguously.
+func writeCanonicalString(buf *bytes.Buffer, s string) {
+	buf.WriteString(strconv.Itoa(len(s)))
+	buf.WriteByte(':')
+	buf.WriteString(s)
+}
+
+// encode writes an unambiguous encoding of v to buf. Each value is prefixed
+// with a byte identifying its kind.
+func (h *canonicalHasher) encode(buf *bytes.Buffer, v reflect.Value) error {
+	if v.CanAddr() && !v.Type().Implements(textMarshalerType) &&
+		v.Addr().Type().Implements(textMarshalerType) {
+		// MarshalText has a pointer receiver
+		v = v.Addr()
+	}
+	if v.CanInterface() && v.Type().Implements(textMarshalerType) &&
+		!(v.Kind() == reflect.Ptr && v.IsNil()) {
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s: %w", v.Type(), err)
+		}
+		buf.WriteByte('t')
+		writeCanonicalString(buf, string(text))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		buf.WriteByte('b')
+		buf.WriteString(strconv.FormatBool(v.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		buf.WriteByte('i')
+		writeCanonicalString(buf, strconv.FormatInt(v.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		buf.WriteByte('u')
+		writeCanonicalString(buf, strconv.FormatUint(v.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		buf.WriteByte('f')
+		writeCanonicalString(buf, strconv.FormatFloat(v.Float(), 'g', -1, 64))
+	case reflect.Complex64, reflect.Complex128:
+		buf.WriteByte('c')
+		writeCanonicalString(buf, strconv.FormatComplex(v.Complex(), 'g', -1, 128))
+	case reflect.String:
+		buf.WriteByte('s')
+		writeCanonicalString(buf, v.String())
+	case reflect.Ptr:
+		if v.IsNil() {
+			buf.WriteByte('n')
+			return nil
+		}
+		ptr := v.Pointer()
+		if _, cycle := h.visiting[ptr]; cycle {
+			return fmt.Errorf("cycle detected at pointer of type %s", v.Type())
+		}
+		h.visiting[ptr] = struct{}{}
+		defer delete(h.visiting, ptr)
+		buf.WriteByte('p')
+		return h.encode(buf, v.Elem())
+	case reflect.Interface:
+		if v.IsNil() {
+			buf.WriteByte('n')
+			return nil
+		}
+		buf.WriteByte('I')
+		writeCanonicalString(buf, v.Elem().Type().String())
+		return h.encode(buf, v.Elem())
+	case reflect.Slice, reflect.Array:
+		buf.WriteByte('l')
+		writeCanonicalString(buf, strconv.Itoa(v.Len()))
+		for i := 0; i < v.Len(); i++ {
+			if err := h.encode(buf, v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		return h.encodeMap(buf, v)
+	case reflect.Struct:
+		buf.WriteByte('S')
+		for i := 0; i < v.NumField(); i++ {
+			sf := v.Type().Field(i)
+			if !sf.IsExported() {
+				continue
+			}
+			writeCanonicalString(buf, sf.Name)
+			if err := h.encode(buf, v.Field(i)); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('.')
+	default:
+		return fmt.Errorf("can't hash value of type %s", v.Type())
+	}
+	return nil
+}
+
+// encodeMap writes the entries of the map v to buf, ordered by the
+// encodings of their keys.
+func (h *canonicalHasher) encodeMap(buf *bytes.Buffer, v reflect.Value) error {
+	type entry struct {
+		key []byte
+		val reflect.Value
+	}
+	entries := make([]entry, 0, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		kb := bytes.Buffer{}
+		if err := h.encode(&kb, iter.Key()); err != nil {
+			return err
+		}
+		entries = append(entries, entry{key: kb.Bytes(), val: iter.Value()})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return bytes.Compare(entries[i].key, entries[j].key) < 0
+	})
+	buf.WriteByte('m')
+	writeCanonicalString(buf, strconv.Itoa(len(entries)))
+	for _, e := range entries {
+		buf.Write(e.key)
+		if err := h.encode(buf, e.val); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package dials
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type hashTestDB struct {
+	Host string
+	Port int
+}
+
+type hashTestConfig struct {
+	Name     string
+	Labels   map[string]string
+	Weights  map[string][]float64
+	Backends []hashTestDB
+	DB       *hashTestDB
+	Started  time.Time
+	Callback func()
+	unexp    int
+}
+
+func TestCanonicalHash(t *testing.T) {
+	t.Parallel()
+	base := func() *hashTestConfig {
+		labels := map[string]string{}
+		for i := 0; i < 50; i++ {
+			labels["key"+strconv.Itoa(i)] = strconv.Itoa(i)
+		}
+		return &hashTestConfig{
+			Name:     "fim",
+			Labels:   labels,
+			Weights:  map[string][]float64{"a": {1, 2}, "b": nil},
+			Backends: []hashTestDB{{Host: "a", Port: 1}},
+			DB:       &hashTestDB{Host: "db"},
+			Started:  time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		}
+	}
+	baseHash, err := CanonicalHash(base())
+	require.NoError(t, err)
+	assert.Len(t, baseHash, 64)
+
+	for _, tbl := range []struct {
+		name   string
+		modify func(c *hashTestConfig)
+		same   bool
+	}{
+		{name: "unchanged", modify: func(c *hashTestConfig) {}, same: true},
+		{name: "map_rebuilt_in_reverse", modify: func(c *hashTestConfig) {
+			labels := map[string]string{}
+			for i := 49; i >= 0; i-- {
+				labels["key"+strconv.Itoa(i)] = strconv.Itoa(i)
+			}
+			c.Labels = labels
+		}, same: true},
+		{name: "nil_vs_empty", modify: func(c *hashTestConfig) {
+			c.Weights["b"] = []float64{}
+		}, same: true},
+		{name: "ignored_fields", modify: func(c *hashTestConfig) {
+			c.Callback = func() {}
+			c.unexp = 3
+		}, same: true},
+		{name: "map_value", modify: func(c *hashTestConfig) { c.Labels["key3"] = "three" }},
+		{name: "map_key", modify: func(c *hashTestConfig) {
+			delete(c.Labels, "key3")
+			c.Labels["key3a"] = "3"
+		}},
+		{name: "nested_slice", modify: func(c *hashTestConfig) { c.Weights["a"] = []float64{2, 1} }},
+		{name: "nil_pointer", modify: func(c *hashTestConfig) { c.DB = nil }},
+		{name: "pointer_field", modify: func(c *hashTestConfig) { c.DB.Port = 5432 }},
+		{name: "slice_elem", modify: func(c *hashTestConfig) { c.Backends[0].Host = "b" }},
+		{name: "time", modify: func(c *hashTestConfig) { c.Started = c.Started.Add(time.Second) }},
+	} {
+		c := base()
+		tbl.modify(c)
+		h, hashErr := CanonicalHash(c)
+		require.NoError(t, hashErr, tbl.name)
+		if tbl.same {
+			assert.Equal(t, baseHash, h, tbl.name)
+		} else {
+			assert.NotEqual(t, baseHash, h, tbl.name)
+		}
+	}
+}
+
+func TestCanonicalHashErrors(t *testing.T) {
+	t.Parallel()
+	type node struct {
+		Next *node
+	}
+	type cyclic struct {
+		Nodes []*node
+	}
+	n := &node{}
+	n.Next = n
+	_, err := CanonicalHash(&cyclic{Nodes: []*node{n}})
+	assert.ErrorContains(t, err, `failed to hash "Nodes": cycle detected`)
+
+	type chans struct {
+		Chans []chan int
+	}
+	_, err = CanonicalHash(&chans{Chans: []chan int{make(chan int)}})
+	assert.EqualError(t, err, `failed to hash "Chans": can't hash value of type chan int`)
+}
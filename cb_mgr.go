@@ -36,9 +36,52 @@ func (*watchErrorEvent[T]) isUserCallbackEvent() {}
 
 var _ userCallbackEvent = (*watchErrorEvent[struct{}])(nil)
 
+// verifyWarningsEvent sends the arguments to an OnVerifyWarnings callback.
+type verifyWarningsEvent[T any] struct {
+	warnings []error
+	cfg      *T
+}
+
+func (*verifyWarningsEvent[T]) isUserCallbackEvent() {}
+
+var _ userCallbackEvent = (*verifyWarningsEvent[struct{}])(nil)
+
+// verifyRejectedEvent sends the arguments to an OnVerifyRejected callback.
+type verifyRejectedEvent[T any] struct {
+	rejected *T
+	err      error
+}
+
+func (*verifyRejectedEvent[T]) isUserCallbackEvent() {}
+
+var _ userCallbackEvent = (*verifyRejectedEvent[struct{}])(nil)
+
+// overrideEvent sends the overrides found while composing a new version to
+// the OnOverride callback.
+type overrideEvent struct {
+	overrides []fieldOverride
+}
+
+func (*overrideEvent) isUserCallbackEvent() {}
+
+var _ userCallbackEvent = (*overrideEvent)(nil)
+
 type userCallbackHandle[T any] struct {
 	cb        NewConfigHandler[T]
 	minSerial uint64
+	// filter, if non-nil, is called with the (unredacted) old and new
+	// configurations, and cb is only called if it returns true.
+	filter func(oldConfig, newConfig *T) bool
+}
+
+// call calls h.cb with the configurations to pass to callbacks (see
+// callbackConfig), if h's filter (if any) accepts the change from oldConfig
+// to newConfig.
+func (h *userCallbackHandle[T]) call(ctx context.Context, oldConfig, newConfig, cbOld, cbNew *T) {
+	if h.filter != nil && !h.filter(oldConfig, newConfig) {
+		return
+	}
+	h.cb(ctx, cbOld, cbNew)
 }
 
 type userCallbackRegistration[T any] struct {
@@ -71,13 +114,30 @@ func (cbm *callbackMgr[T]) runCBs(ctx context.Context) {
 		switch e := ev.(type) {
 		case *watchErrorEvent[T]:
 			if cbm.p.OnWatchedError != nil {
-				cbm.p.OnWatchedError(ctx, e.err, e.oldConfig, e.newConfig)
+				cbm.p.OnWatchedError(ctx, e.err, cbm.p.callbackConfig(e.oldConfig), cbm.p.callbackConfig(e.newConfig))
+			}
+		case *verifyWarningsEvent[T]:
+			if cbm.p.OnVerifyWarnings != nil {
+				cbm.p.OnVerifyWarnings(ctx, e.warnings, cbm.p.callbackConfig(e.cfg))
+			}
+		case *verifyRejectedEvent[T]:
+			if cbm.p.OnVerifyRejected != nil {
+				cbm.p.OnVerifyRejected(ctx, cbm.p.callbackConfig(e.rejected), e.err)
+			}
+		case *overrideEvent:
+			if cbm.p.OnOverride != nil {
+				for _, o := range e.overrides {
+					cbm.p.OnOverride(ctx, o.path, o.winner, o.overridden)
+				}
 			}
 		case *newConfigEvent[T]:
 			lastSerial = e.serial
 			lastVersion = e.newConfig
+			cbm.p.log(ctx, LogLevelDebug, "dispatching new configuration to callbacks",
+				"serial", e.serial, "callbacks", len(newCfgCBs))
+			cbOld, cbNew := cbm.p.callbackConfig(e.oldConfig), cbm.p.callbackConfig(e.newConfig)
 			if cbm.p.OnNewConfig != nil && !e.globalCBsSuppressed {
-				cbm.p.OnNewConfig(ctx, e.oldConfig, e.newConfig)
+				cbm.p.OnNewConfig(ctx, cbOld, cbNew)
 			}
 			for _, cbh := range newCfgCBs {
 				if cbh.minSerial >= e.serial {
@@ -85,13 +145,14 @@ func (cbm *callbackMgr[T]) runCBs(ctx context.Context) {
 					// a version that we haven't caught up to yet.
 					continue
 				}
-				cbh.cb(ctx, e.oldConfig, e.newConfig)
+				cbh.call(ctx, e.oldConfig, e.newConfig, cbOld, cbNew)
 			}
 		case *userCallbackRegistration[T]:
 			// Serial values are assigned sequentially, so make sure we don't deliver an
 			// older config if we've fallen behind.
 			if e.serial.cfg != nil && e.serial.s < lastSerial {
-				e.handle.cb(ctx, e.serial.cfg, lastVersion)
+				e.handle.call(ctx, e.serial.cfg, lastVersion,
+					cbm.p.callbackConfig(e.serial.cfg), cbm.p.callbackConfig(lastVersion))
 			}
 			// add this callback to the set of callbacks
 			newCfgCBs = append(newCfgCBs, e.handle)
@@ -11,6 +11,11 @@ const (
 	// DialsFlagTagName is the name of the dialsflag tag.
 	DialsFlagTagName = "dialsflag"
 
+	// DialsFlagShortTag is the name of the dialsflagshort tag, which
+	// registers a single-character shorthand for a field's flag with the
+	// standard library's flag package.
+	DialsFlagShortTag = "dialsflagshort"
+
 	// DialsPFlagTagName is the name of the dialspflag tag.
 	DialsPFlagTag = "dialspflag"
 
@@ -19,4 +24,61 @@ const (
 
 	// HelpTextTag is the name of the struct tag for flag descriptions
 	DialsHelpTextTag = "dialsdesc"
+
+	// DialsSourceTag is the name of the dialssource tag, which restricts
+	// the sources allowed to set a field. (see dials.NamedSource)
+	DialsSourceTag = "dialssource"
+
+	// DialsMergeTag is the name of the dialsmerge tag, which controls how
+	// values from successive sources are combined. `dialsmerge:"append"`
+	// concatenates slices rather than replacing them, and
+	// `dialsmerge:"merge"` unions maps key-by-key (with later sources
+	// winning for any keys present in several).
+	DialsMergeTag = "dialsmerge"
+
+	// DialsSecretTag is the name of the dialssecret tag, which marks
+	// fields (with `dialssecret:"true"`) that must be redacted before
+	// logging. (see transform.RedactMangler)
+	DialsSecretTag = "dialssecret"
+
+	// DialsRequiredTag is the name of the dialsrequired tag, which marks
+	// fields (with `dialsrequired:"true"`) that must be set to a non-zero
+	// value by some source (or the defaults passed to Config).
+	DialsRequiredTag = "dialsrequired"
+
+	// DialsDefaultTag is the name of the dialsdefault tag, which declares
+	// a field's default value (in the same format as an environment
+	// variable). (see sources/defaults)
+	DialsDefaultTag = "dialsdefault"
+)
+
+// Names returned by the SourceName methods of the sources bundled with dials,
+// for use in `dialssource` tags.
+const (
+	// EnvSourceName identifies the env source.
+	EnvSourceName = "env"
+
+	// FileSourceName identifies the file sources.
+	FileSourceName = "file"
+
+	// FlagSourceName identifies both the flag and pflag sources.
+	FlagSourceName = "flag"
+
+	// StaticSourceName identifies the static source.
+	StaticSourceName = "static"
+
+	// AWSSSMSourceName identifies the AWS SSM Parameter Store source.
+	AWSSSMSourceName = "awsssm"
+
+	// VaultSourceName identifies the HashiCorp Vault source.
+	VaultSourceName = "vault"
+
+	// CmdSourceName identifies the command-output source.
+	CmdSourceName = "cmd"
+
+	// DefaultsSourceName identifies the struct-tag defaults source.
+	DefaultsSourceName = "defaults"
+
+	// EmbedFSSourceName identifies the fs.FS (e.g. embed.FS) source.
+	EmbedFSSourceName = "embedfs"
 )
@@ -0,0 +1,91 @@
+// Package auto provides a dials.Decoder that tries several other decoders in
+// turn, for use with sources (such as a generic `--config` flag) where the
+// format of the input isn't known ahead of time.
+package auto
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/vimeo/dials"
+	"github.com/vimeo/dials/decoders/json"
+	"github.com/vimeo/dials/decoders/toml"
+	"github.com/vimeo/dials/decoders/yaml"
+)
+
+// Decoder buffers its input and tries each of Decoders in order, returning
+// the result from the first one that succeeds.
+//
+// Ordering matters: YAML is (nearly) a superset of JSON, so a YAML decoder
+// will accept most JSON documents, and will also accept many inputs that
+// were intended as some other format, by interpreting them as a single
+// string scalar (although decoding such a scalar into a struct fails). The
+// stricter decoders should generally come first so that the most specific
+// format wins.
+//
+// If Decoders is empty, DefaultDecoders is used.
+type Decoder struct {
+	Decoders []dials.Decoder
+}
+
+var _ dials.Decoder = (*Decoder)(nil)
+
+// DefaultDecoders returns the decoders used by Decoder if none are
+// specified: JSON, then YAML, then TOML.
+//
+// JSON is the strictest of the three, so it's tried first, in which case
+// JSON-specific types (such as jsontypes.ParsingDuration) are used. YAML is
+// tried next, as it accepts most of what JSON does along with YAML-specific
+// syntax. TOML comes last.
+func DefaultDecoders() []dials.Decoder {
+	return []dials.Decoder{&json.Decoder{}, &yaml.Decoder{}, &toml.Decoder{}}
+}
+
+// Decode reads all of r, and then attempts to decode it with each decoder in
+// turn, returning the first successful result. If all the decoders fail, the
+// returned error is a *DecodeError containing each decoder's error.
+func (d *Decoder) Decode(r io.Reader, t *dials.Type) (reflect.Value, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("error reading input: %w", err)
+	}
+
+	decs := d.Decoders
+	if len(decs) == 0 {
+		decs = DefaultDecoders()
+	}
+
+	errs := make([]DecoderError, 0, len(decs))
+	for _, dec := range decs {
+		v, decErr := dec.Decode(bytes.NewReader(data), t)
+		if decErr == nil {
+			return v, nil
+		}
+		errs = append(errs, DecoderError{Decoder: dec, Err: decErr})
+	}
+	return reflect.Value{}, &DecodeError{Errs: errs}
+}
+
+// DecoderError is the error returned by a single decoder
+type DecoderError struct {
+	Decoder dials.Decoder
+	Err     error
+}
+
+// DecodeError is returned by Decoder.Decode if none of the decoders succeed.
+type DecodeError struct {
+	// Errs contains the error from each decoder, in the order they were
+	// tried
+	Errs []DecoderError
+}
+
+func (d *DecodeError) Error() string {
+	msgs := make([]string, len(d.Errs))
+	for i, e := range d.Errs {
+		msgs[i] = fmt.Sprintf("%T: %s", e.Decoder, e.Err)
+	}
+	return "no decoder succeeded: " + strings.Join(msgs, "; ")
+}
@@ -0,0 +1,75 @@
+package auto
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/vimeo/dials"
+	"github.com/vimeo/dials/decoders/json"
+	"github.com/vimeo/dials/sources/static"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testConfig struct {
+	Name    string        `dials:"name"`
+	Count   int           `dials:"count"`
+	Timeout time.Duration `dials:"timeout"`
+}
+
+func TestDecoder(t *testing.T) {
+	t.Parallel()
+	for _, itbl := range []struct {
+		name   string
+		data   string
+		expect testConfig
+	}{
+		{
+			name:   "json",
+			data:   `{"name": "foo", "count": 3, "timeout": "5s"}`,
+			expect: testConfig{Name: "foo", Count: 3, Timeout: 5 * time.Second},
+		},
+		{
+			name:   "yaml",
+			data:   "name: foo\ncount: 3\ntimeout: 5s\n",
+			expect: testConfig{Name: "foo", Count: 3, Timeout: 5 * time.Second},
+		},
+		{
+			name:   "toml",
+			data:   "name = \"foo\"\ncount = 3\n",
+			expect: testConfig{Name: "foo", Count: 3},
+		},
+	} {
+		tbl := itbl
+		t.Run(tbl.name, func(t *testing.T) {
+			t.Parallel()
+			d, err := dials.Config(
+				context.Background(),
+				&testConfig{},
+				&static.StringSource{Data: tbl.data, Decoder: &Decoder{}},
+			)
+			require.NoError(t, err)
+			assert.Equal(t, &tbl.expect, d.View())
+		})
+	}
+}
+
+func TestDecoderAllFail(t *testing.T) {
+	t.Parallel()
+	_, err := dials.Config(
+		context.Background(),
+		&testConfig{},
+		&static.StringSource{
+			Data:    "name: foo\n",
+			Decoder: &Decoder{Decoders: []dials.Decoder{&json.Decoder{}}},
+		},
+	)
+	require.Error(t, err)
+	decErr := &DecodeError{}
+	require.True(t, errors.As(err, &decErr))
+	require.Len(t, decErr.Errs, 1)
+	assert.IsType(t, &json.Decoder{}, decErr.Errs[0].Decoder)
+}
@@ -16,7 +16,10 @@ import (
 )
 
 // Decoder is a decoder that knows how to work with configs written in Cue
-type Decoder struct{}
+type Decoder struct {
+	// Flatten any anonymous struct fields into the parent
+	FlattenAnonymous bool
+}
 
 func must[T any](v T, err error) T {
 	if err != nil {
@@ -42,10 +45,12 @@ func (d *Decoder) Decode(r io.Reader, t *dials.Type) (reflect.Value, error) {
 
 	// If there aren't any json tags, copy over from any dials tags.
 	// Also, convert any time.Duration fields to jsontypes.ParsingDuration so we can decode those values as strings.
-	tfmr := transform.NewTransformer(t.Type(),
-		parsingDurMangler,
-		&tagformat.TagCopyingMangler{
-			SrcTag: common.DialsTagName, NewTag: jsonTagName})
+	manglers := []transform.Mangler{parsingDurMangler, &tagformat.TagCopyingMangler{
+		SrcTag: common.DialsTagName, NewTag: jsonTagName}}
+	if d.FlattenAnonymous {
+		manglers = append(manglers, transform.AnonymousFlattenMangler{})
+	}
+	tfmr := transform.NewTransformer(t.Type(), manglers...)
 	reflVal, tfmErr := tfmr.Translate()
 	if tfmErr != nil {
 		return reflect.Value{}, fmt.Errorf("failed to convert tags: %s", tfmErr)
@@ -206,3 +206,34 @@ _foobar: {
 	assert.Equal(t, "something", c.Val1)
 	assert.Equal(t, 42, c.Val2)
 }
+
+func TestCueFlattenAnonymous(t *testing.T) {
+	type Base struct {
+		Name    string        `dials:"name"`
+		Timeout time.Duration `dials:"timeout"`
+	}
+	type testConfig struct {
+		Base  `dials:"base"`
+		Count int `dials:"count"`
+	}
+
+	data := `{
+        "name": "something",
+        "timeout": "10s",
+        "count": 42
+    }`
+
+	myConfig := &testConfig{}
+	d, err := dials.Config(
+		context.Background(),
+		myConfig,
+		&static.StringSource{Data: data, Decoder: &Decoder{FlattenAnonymous: true}},
+	)
+	require.NoError(t, err)
+
+	c := d.View()
+
+	assert.Equal(t, "something", c.Name)
+	assert.Equal(t, 10*time.Second, c.Timeout)
+	assert.Equal(t, 42, c.Count)
+}
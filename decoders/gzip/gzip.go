@@ -0,0 +1,82 @@
+// Package gzip provides a dials.Decoder that transparently decompresses
+// gzip-compressed input before passing it to another decoder.
+package gzip
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/vimeo/dials"
+)
+
+// Extension is the file extension conventionally used for gzip-compressed
+// files.
+const Extension = ".gz"
+
+// magic is the header that begins every gzip stream (RFC 1952 ID1 and ID2).
+var magic = []byte{0x1f, 0x8b}
+
+// Decoder wraps another decoder, decompressing its input if it begins with
+// the gzip magic header. Input without the header is passed to Inner
+// unmodified, so a Decoder may be used for files that are only sometimes
+// compressed.
+//
+// Since file sources call Decode every time the file is re-read, a watching
+// file source decompresses every new version of the file.
+type Decoder struct {
+	Inner dials.Decoder
+}
+
+var _ dials.Decoder = (*Decoder)(nil)
+
+// New returns a Decoder wrapping inner.
+func New(inner dials.Decoder) *Decoder {
+	return &Decoder{Inner: inner}
+}
+
+// Decode decompresses r (if it's gzip-compressed) and passes the
+// decompressed stream to Inner.
+func (d *Decoder) Decode(r io.Reader, t *dials.Type) (reflect.Value, error) {
+	if d.Inner == nil {
+		return reflect.Value{}, fmt.Errorf("gzip decoder has a nil Inner decoder")
+	}
+	br := bufio.NewReader(r)
+	hdr, peekErr := br.Peek(len(magic))
+	if peekErr != nil && peekErr != io.EOF {
+		return reflect.Value{}, fmt.Errorf("error reading input: %w", peekErr)
+	}
+	if !bytes.Equal(hdr, magic) {
+		return d.Inner.Decode(br, t)
+	}
+
+	zr, zErr := gzip.NewReader(br)
+	if zErr != nil {
+		return reflect.Value{}, fmt.Errorf("failed to read gzip header: %w", zErr)
+	}
+	defer zr.Close()
+
+	// Buffer the decompressed contents so errors from a truncated or
+	// corrupt stream are reported as such, rather than as whatever
+	// error the inner decoder produces for the partial contents.
+	data, readErr := io.ReadAll(zr)
+	if readErr != nil {
+		return reflect.Value{}, fmt.Errorf("failed to decompress input: %w", readErr)
+	}
+	return d.Inner.Decode(bytes.NewReader(data), t)
+}
+
+// TrimExtension returns path with any trailing ".gz" extension removed, and
+// indicates whether it was present. This is useful for picking a decoder
+// based on the extension of the uncompressed file (e.g. "config.yaml.gz").
+func TrimExtension(path string) (string, bool) {
+	if !strings.EqualFold(filepath.Ext(path), Extension) {
+		return path, false
+	}
+	return path[:len(path)-len(Extension)], true
+}
@@ -0,0 +1,128 @@
+package gzip
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/vimeo/dials"
+	"github.com/vimeo/dials/decoders/json"
+	"github.com/vimeo/dials/sources/file"
+	"github.com/vimeo/dials/sources/static"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testConfig struct {
+	Name  string `dials:"name"`
+	Count int    `dials:"count"`
+}
+
+func compress(t testing.TB, data string) []byte {
+	t.Helper()
+	buf := bytes.Buffer{}
+	zw := gzip.NewWriter(&buf)
+	_, err := zw.Write([]byte(data))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func TestDecoder(t *testing.T) {
+	t.Parallel()
+	const data = `{"name": "fimbat", "count": 3}`
+	compressed := compress(t, data)
+	for _, itbl := range []struct {
+		name      string
+		data      []byte
+		expect    testConfig
+		expectErr string
+	}{
+		{
+			name:   "compressed",
+			data:   compressed,
+			expect: testConfig{Name: "fimbat", Count: 3},
+		},
+		{
+			name:   "uncompressed",
+			data:   []byte(data),
+			expect: testConfig{Name: "fimbat", Count: 3},
+		},
+		{
+			name:      "truncated",
+			data:      compressed[:len(compressed)-6],
+			expectErr: "failed to decompress input: unexpected EOF",
+		},
+		{
+			name:      "bad_header",
+			data:      []byte{0x1f, 0x8b, 0x00},
+			expectErr: "failed to read gzip header: unexpected EOF",
+		},
+	} {
+		tbl := itbl
+		t.Run(tbl.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := testConfig{}
+			d, err := dials.Config(context.Background(), &cfg,
+				&static.StringSource{Data: string(tbl.data), Decoder: New(&json.Decoder{})})
+			if tbl.expectErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tbl.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tbl.expect, *d.View())
+		})
+	}
+}
+
+func TestDecoderNilInner(t *testing.T) {
+	t.Parallel()
+	cfg := testConfig{}
+	_, err := dials.Config(context.Background(), &cfg,
+		&static.StringSource{Data: "{}", Decoder: &Decoder{}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "nil Inner decoder")
+}
+
+func TestDecoderFileSource(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "config.json.gz")
+	require.NoError(t, os.WriteFile(path, compress(t, `{"name": "fimbat", "count": 3}`), 0o600))
+
+	src, srcErr := file.NewSource(path, New(&json.Decoder{}))
+	require.NoError(t, srcErr)
+	cfg := testConfig{}
+	d, err := dials.Config(ctx, &cfg, src)
+	require.NoError(t, err)
+	assert.Equal(t, testConfig{Name: "fimbat", Count: 3}, *d.View())
+
+	// every read decompresses the current contents of the file
+	require.NoError(t, os.WriteFile(path, compress(t, `{"name": "foobar", "count": 4}`), 0o600))
+	newCfg, reloadErr := d.Reload(ctx)
+	require.NoError(t, reloadErr)
+	assert.Equal(t, testConfig{Name: "foobar", Count: 4}, *newCfg)
+}
+
+func TestTrimExtension(t *testing.T) {
+	t.Parallel()
+	for _, tbl := range []struct {
+		path    string
+		trimmed string
+		ok      bool
+	}{
+		{path: "/etc/config.yaml.gz", trimmed: "/etc/config.yaml", ok: true},
+		{path: "config.JSON.GZ", trimmed: "config.JSON", ok: true},
+		{path: "config.yaml", trimmed: "config.yaml", ok: false},
+		{path: "config.tgz", trimmed: "config.tgz", ok: false},
+	} {
+		trimmed, ok := TrimExtension(tbl.path)
+		assert.Equal(t, tbl.trimmed, trimmed, tbl.path)
+		assert.Equal(t, tbl.ok, ok, tbl.path)
+	}
+}
@@ -0,0 +1,299 @@
+// Package hcl provides a dials.Decoder for configuration files written in
+// HCL (HashiCorp Configuration Language) native syntax.
+package hcl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/vimeo/dials"
+	"github.com/vimeo/dials/common"
+	jsondec "github.com/vimeo/dials/decoders/json"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Extension is the file extension conventionally used for HCL files.
+const Extension = ".hcl"
+
+// Decoder decodes HCL native syntax (see
+// https://github.com/hashicorp/hcl/blob/main/hclsyntax/spec.md), parsed with
+// the hclsyntax package, consisting of attributes ("name = value") and
+// blocks ("name "label" { ... }").
+//
+// Attributes populate the field named by their name, and blocks populate
+// nested struct fields, with their bodies decoded recursively. Fields are
+// named by their `json` tag if present, then by their `dials` tag, and
+// otherwise by their field name, compared case-insensitively (as with the
+// JSON decoder). An unlabeled block maps to a struct (or pointer-to-struct)
+// field, or, if it's repeated, to a slice of structs. A labeled block (e.g.
+// `backend "primary" { ... }`) maps to a map[string]Struct field keyed by
+// the label, with additional labels producing further nested maps.
+// time.Duration fields may be set from strings such as "3s".
+//
+// Attribute values are evaluated without any variables or functions, so
+// literals, operators and templates are supported, but references to
+// variables (e.g. "var.name") and function calls are rejected with an
+// error. Syntax errors, evaluation errors, and attributes that are
+// duplicated or conflict with a block of the same name, are reported as an
+// *Error with the line and column of the problem.
+type Decoder struct{}
+
+var _ dials.Decoder = (*Decoder)(nil)
+
+// Error is returned for syntax errors, and for input that's valid HCL but
+// can't be decoded (e.g. uses variables, or has duplicate blocks).
+type Error struct {
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Msg)
+}
+
+func errAt(r hcl.Range, format string, args ...any) *Error {
+	return &Error{Line: r.Start.Line, Column: r.Start.Column, Msg: fmt.Sprintf(format, args...)}
+}
+
+// diagsErr converts the first error in diags to an *Error.
+func diagsErr(diags hcl.Diagnostics) error {
+	for _, diag := range diags {
+		if diag.Severity != hcl.DiagError {
+			continue
+		}
+		msg := diag.Summary
+		if diag.Detail != "" {
+			msg += "; " + diag.Detail
+		}
+		e := &Error{Msg: msg}
+		if diag.Subject != nil {
+			e.Line, e.Column = diag.Subject.Start.Line, diag.Subject.Start.Column
+		}
+		return e
+	}
+	return nil
+}
+
+// Decode reads from r, parsing it as HCL and depositing the values in the
+// fields of t.
+func (d *Decoder) Decode(r io.Reader, t *dials.Type) (reflect.Value, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("error reading HCL: %w", err)
+	}
+	f, diags := hclsyntax.ParseConfig(data, "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return reflect.Value{}, diagsErr(diags)
+	}
+	m, err := bodyToMap(f.Body.(*hclsyntax.Body), t.Type())
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	// Hand the equivalent JSON to the JSON decoder, which takes care of
+	// tags, durations and the like.
+	jsonBytes, err := json.Marshal(m)
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("failed to convert HCL to JSON: %w", err)
+	}
+	return (&jsondec.Decoder{}).Decode(bytes.NewReader(jsonBytes), t)
+}
+
+// bodyToMap converts the attributes and blocks in b to a map suitable for
+// encoding as JSON, using typ (the type being decoded into, if known) to
+// decide whether repeated and labeled blocks should be decoded as lists or
+// maps.
+func bodyToMap(b *hclsyntax.Body, typ reflect.Type) (map[string]any, error) {
+	out := make(map[string]any, len(b.Attributes)+len(b.Blocks))
+	for name, a := range b.Attributes {
+		val, diags := a.Expr.Value(nil)
+		if diags.HasErrors() {
+			return nil, diagsErr(diags)
+		}
+		v, err := ctyToJSON(val)
+		if err != nil {
+			return nil, errAt(a.Expr.Range(), "attribute %q: %s", name, err)
+		}
+		out[name] = v
+	}
+
+	// group the blocks by type, preserving order
+	blocksByType := map[string][]*hclsyntax.Block{}
+	types := []string{}
+	for _, blk := range b.Blocks {
+		if _, ok := out[blk.Type]; ok {
+			return nil, errAt(blk.TypeRange, "block %q conflicts with the attribute of the same name", blk.Type)
+		}
+		if _, ok := blocksByType[blk.Type]; !ok {
+			types = append(types, blk.Type)
+		}
+		blocksByType[blk.Type] = append(blocksByType[blk.Type], blk)
+	}
+
+	for _, name := range types {
+		v, err := blocksToValue(blocksByType[name], fieldType(typ, name))
+		if err != nil {
+			return nil, err
+		}
+		out[name] = v
+	}
+	return out, nil
+}
+
+// ctyToJSON converts v to a value suitable for encoding as JSON. Numbers
+// are converted to json.Numbers, so they're not truncated to float64.
+func ctyToJSON(v cty.Value) (any, error) {
+	if !v.IsKnown() {
+		return nil, fmt.Errorf("value is unknown")
+	}
+	if v.IsNull() {
+		return nil, nil
+	}
+	ty := v.Type()
+	switch {
+	case ty == cty.String:
+		return v.AsString(), nil
+	case ty == cty.Number:
+		return json.Number(v.AsBigFloat().Text('g', -1)), nil
+	case ty == cty.Bool:
+		return v.True(), nil
+	case ty.IsListType() || ty.IsTupleType() || ty.IsSetType():
+		out := make([]any, 0, v.LengthInt())
+		for it := v.ElementIterator(); it.Next(); {
+			_, ev := it.Element()
+			e, err := ctyToJSON(ev)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, e)
+		}
+		return out, nil
+	case ty.IsMapType() || ty.IsObjectType():
+		out := make(map[string]any, v.LengthInt())
+		for it := v.ElementIterator(); it.Next(); {
+			k, ev := it.Element()
+			e, err := ctyToJSON(ev)
+			if err != nil {
+				return nil, err
+			}
+			out[k.AsString()] = e
+		}
+		return out, nil
+	default:
+		return nil, fmt.Errorf("unsupported value of type %s", ty.FriendlyName())
+	}
+}
+
+// blocksToValue converts all the blocks of a single type to a value for a
+// field of type typ (which may be nil if there's no such field).
+func blocksToValue(blocks []*hclsyntax.Block, typ reflect.Type) (any, error) {
+	typ = derefType(typ)
+	if len(blocks[0].Labels) == 0 {
+		if typ != nil && (typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array) {
+			out := make([]any, 0, len(blocks))
+			for _, blk := range blocks {
+				if len(blk.Labels) != 0 {
+					return nil, errAt(blk.TypeRange, "block %q: expected no labels; found %d", blk.Type, len(blk.Labels))
+				}
+				m, err := bodyToMap(blk.Body, typ.Elem())
+				if err != nil {
+					return nil, err
+				}
+				out = append(out, m)
+			}
+			return out, nil
+		}
+		if len(blocks) > 1 {
+			return nil, errAt(blocks[1].TypeRange, "duplicate %q block", blocks[1].Type)
+		}
+		return bodyToMap(blocks[0].Body, typ)
+	}
+
+	nLabels := len(blocks[0].Labels)
+	out := map[string]any{}
+	for _, blk := range blocks {
+		if len(blk.Labels) != nLabels {
+			return nil, errAt(blk.TypeRange, "block %q: expected %d labels; found %d", blk.Type, nLabels, len(blk.Labels))
+		}
+		// descend through the (nested) maps, one level per label
+		m := out
+		elemType := typ
+		for i, label := range blk.Labels {
+			elemType = mapElemType(elemType)
+			if i == len(blk.Labels)-1 {
+				break
+			}
+			next, ok := m[label].(map[string]any)
+			if !ok {
+				next = map[string]any{}
+				m[label] = next
+			}
+			m = next
+		}
+		label := blk.Labels[len(blk.Labels)-1]
+		if _, dup := m[label]; dup {
+			return nil, errAt(blk.TypeRange, "duplicate %q block with labels %q", blk.Type, blk.Labels)
+		}
+		v, err := bodyToMap(blk.Body, elemType)
+		if err != nil {
+			return nil, err
+		}
+		m[label] = v
+	}
+	return out, nil
+}
+
+// derefType strips any pointers from t.
+func derefType(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// mapElemType returns the element type of t if it's a map, and nil
+// otherwise.
+func mapElemType(t reflect.Type) reflect.Type {
+	t = derefType(t)
+	if t == nil || t.Kind() != reflect.Map {
+		return nil
+	}
+	return t.Elem()
+}
+
+// fieldType returns the type of the field of struct type t that the JSON
+// decoder would populate from key, or nil if there's no such field.
+func fieldType(t reflect.Type, key string) reflect.Type {
+	t = derefType(t)
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name := sf.Name
+		tagged := false
+		for _, tag := range []string{jsondec.JSONTagName, common.DialsTagName} {
+			if n := strings.Split(sf.Tag.Get(tag), ",")[0]; n != "" {
+				name = n
+				tagged = true
+				break
+			}
+		}
+		if sf.Anonymous && !tagged {
+			if ft := fieldType(sf.Type, key); ft != nil {
+				return ft
+			}
+			continue
+		}
+		if strings.EqualFold(name, key) {
+			return sf.Type
+		}
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+package hcl
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/vimeo/dials"
+	"github.com/vimeo/dials/sources/static"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type backendConfig struct {
+	Address string
+	Weight  int
+}
+
+type listenerConfig struct {
+	Port int
+	TLS  bool `dials:"tls"`
+}
+
+type Common struct {
+	Verbose bool
+}
+
+type testConfig struct {
+	Common
+	Name      string
+	MaxConns  int `dials:"max_conns"`
+	Tags      []string
+	Labels    map[string]string
+	Timeout   time.Duration
+	Motd      string
+	Server    struct{ Host string }
+	Cache     *struct{ Size int }
+	Backends  map[string]backendConfig            `dials:"backend"`
+	Regions   map[string]map[string]backendConfig `dials:"region"`
+	Listeners []listenerConfig                    `dials:"listener"`
+	Path      string
+}
+
+func TestDecoder(t *testing.T) {
+	t.Parallel()
+	const data = `# a comment
+// another comment
+/* a
+   block comment */
+name = "fimbat"
+max_conns = 12
+verbose = true
+tags = [
+  "a",
+  "b", # trailing comma
+]
+labels = { env = "prod", "team": "video" }
+timeout = "3s"
+motd = <<-EOT
+    hello
+      world
+    EOT
+path = "C:\\temp\t$${HOME}"
+unknown = null
+
+server {
+  host = "localhost"
+}
+
+cache {
+  size = 512
+}
+
+backend "primary" {
+  address = "10.0.0.1"
+  weight  = 3
+}
+
+backend "secondary" {
+  address = "10.0.0.2"
+}
+
+region "us" "east" {
+  address = "east.local"
+}
+
+region "us" "west" {
+  address = "west.local"
+}
+
+listener {
+  port = 80
+}
+listener {
+  port = 443
+  tls  = true
+}
+`
+	cfg := testConfig{Path: "default"}
+	d, err := dials.Config(context.Background(), &cfg,
+		&static.StringSource{Data: data, Decoder: &Decoder{}})
+	require.NoError(t, err)
+	c := d.View()
+
+	assert.True(t, c.Verbose)
+	assert.Equal(t, "fimbat", c.Name)
+	assert.Equal(t, 12, c.MaxConns)
+	assert.Equal(t, []string{"a", "b"}, c.Tags)
+	assert.Equal(t, map[string]string{"env": "prod", "team": "video"}, c.Labels)
+	assert.Equal(t, 3*time.Second, c.Timeout)
+	assert.Equal(t, "hello\n  world\n", c.Motd)
+	assert.Equal(t, "C:\\temp\t${HOME}", c.Path)
+	assert.Equal(t, "localhost", c.Server.Host)
+	require.NotNil(t, c.Cache)
+	assert.Equal(t, 512, c.Cache.Size)
+	assert.Equal(t, map[string]backendConfig{
+		"primary":   {Address: "10.0.0.1", Weight: 3},
+		"secondary": {Address: "10.0.0.2"},
+	}, c.Backends)
+	assert.Equal(t, map[string]map[string]backendConfig{
+		"us": {
+			"east": {Address: "east.local"},
+			"west": {Address: "west.local"},
+		},
+	}, c.Regions)
+	assert.Equal(t, []listenerConfig{{Port: 80}, {Port: 443, TLS: true}}, c.Listeners)
+}
+
+func TestDecoderErrors(t *testing.T) {
+	t.Parallel()
+	for name, tbl := range map[string]struct {
+		data   string
+		expErr string
+	}{
+		"unterminated_string": {
+			data:   "name = \"fim\n",
+			expErr: "line 1, column 12: Invalid multi-line string",
+		},
+		"missing_value": {
+			data:   "name = \n",
+			expErr: "line 1, column 8: Invalid expression",
+		},
+		"unclosed_block": {
+			data:   "server {\n  host = \"x\"\n",
+			expErr: "line 1, column 8: Unclosed configuration block",
+		},
+		"two_attributes_on_a_line": {
+			data:   `name = "a" max_conns = 1`,
+			expErr: "line 1, column 12: Missing newline after argument",
+		},
+		"duplicate_attribute": {
+			data:   "name = \"a\"\nname = \"b\"\n",
+			expErr: "line 2, column 1: Attribute redefined",
+		},
+		"duplicate_block": {
+			data:   "server {}\nserver {}\n",
+			expErr: `line 2, column 1: duplicate "server" block`,
+		},
+		"duplicate_labeled_block": {
+			data:   "backend \"a\" {}\nbackend \"a\" {}\n",
+			expErr: `line 2, column 1: duplicate "backend" block with labels ["a"]`,
+		},
+		"mismatched_labels": {
+			data:   "backend \"a\" {}\nbackend {}\n",
+			expErr: `line 2, column 1: block "backend": expected 1 labels; found 0`,
+		},
+		"attribute_and_block": {
+			data:   "server = {}\nserver {}\n",
+			expErr: `line 2, column 1: block "server" conflicts with the attribute of the same name`,
+		},
+		"variable": {
+			data:   "name = var.name\n",
+			expErr: "line 1, column 8: Variables not allowed",
+		},
+		"interpolation": {
+			data:   "name = \"${var.name}\"\n",
+			expErr: "line 1, column 11: Variables not allowed",
+		},
+		"function_call": {
+			data:   "name = upper(\"fim\")\n",
+			expErr: "line 1, column 8: Function calls not allowed",
+		},
+		"unterminated_heredoc": {
+			data:   "motd = <<EOT\nhello\n",
+			expErr: "line 3, column 1: Unterminated template string",
+		},
+	} {
+		tbl := tbl
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			_, err := dials.Config(context.Background(), &testConfig{},
+				&static.StringSource{Data: tbl.data, Decoder: &Decoder{}})
+			require.Error(t, err)
+			assert.ErrorContains(t, err, tbl.expErr)
+			var hclErr *Error
+			assert.True(t, errors.As(err, &hclErr))
+		})
+	}
+}
+
+func TestDecoderLiteralExpressions(t *testing.T) {
+	t.Parallel()
+	const data = `
+name = "fim-${"bat"}"
+max_conns = 4 * 3
+verbose = 2 > 1
+`
+	d, err := dials.Config(context.Background(), &testConfig{},
+		&static.StringSource{Data: data, Decoder: &Decoder{}})
+	require.NoError(t, err)
+	c := d.View()
+	assert.Equal(t, "fim-bat", c.Name)
+	assert.Equal(t, 12, c.MaxConns)
+	assert.True(t, c.Verbose)
+}
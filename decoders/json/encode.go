@@ -0,0 +1,232 @@
+package json
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vimeo/dials"
+	"github.com/vimeo/dials/common"
+	"github.com/vimeo/dials/ptrify"
+)
+
+// Encoder serializes config structs as JSON, such that the output can be read
+// back with a Decoder.
+type Encoder struct{}
+
+var _ dials.Encoder = (*Encoder)(nil)
+
+var (
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	durationType      = reflect.TypeOf(time.Duration(0))
+)
+
+// Encode writes cfg to w as indented JSON.
+// Struct fields are keyed by their `dials` tag if present, and otherwise by
+// their name (as the Decoder expects), and nil fields are omitted.
+func (e *Encoder) Encode(w io.Writer, cfg any) error {
+	v, _, err := encodable(reflect.ValueOf(cfg))
+	if err != nil {
+		return err
+	}
+	out, marshalErr := json.MarshalIndent(v, "", "  ")
+	if marshalErr != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", marshalErr)
+	}
+	_, writeErr := w.Write(append(out, '\n'))
+	return writeErr
+}
+
+// jsonObject is an ordered set of key-value pairs, so struct fields are
+// serialized in declaration order.
+type jsonObject []jsonField
+
+type jsonField struct {
+	key string
+	val any
+}
+
+// MarshalJSON implements json.Marshaler
+func (o jsonObject) MarshalJSON() ([]byte, error) {
+	buf := bytes.Buffer{}
+	buf.WriteByte('{')
+	for i, f := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, kErr := json.Marshal(f.key)
+		if kErr != nil {
+			return nil, kErr
+		}
+		v, vErr := json.Marshal(f.val)
+		if vErr != nil {
+			return nil, fmt.Errorf("failed to marshal value for key %q: %w", f.key, vErr)
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// encodable converts v into a value that json.Marshal will serialize in the
+// form the Decoder expects. The boolean return is false for nil values.
+func encodable(v reflect.Value) (any, bool, error) {
+	if !v.IsValid() {
+		return nil, false, nil
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		if v.IsNil() {
+			return nil, false, nil
+		}
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return encodable(v.Elem())
+	case reflect.Struct:
+		if v.Type().Implements(textMarshalerType) {
+			// let the json library call MarshalText
+			return v.Interface(), true, nil
+		}
+		if reflect.PtrTo(v.Type()).Implements(textMarshalerType) {
+			// copy the value so we can pass a pointer
+			ptr := reflect.New(v.Type())
+			ptr.Elem().Set(v)
+			return ptr.Interface(), true, nil
+		}
+		out := jsonObject{}
+		if err := appendStructFields(&out, v); err != nil {
+			return nil, false, err
+		}
+		return out, true, nil
+	case reflect.Map:
+		out := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			k, kErr := mapKey(iter.Key())
+			if kErr != nil {
+				return nil, false, kErr
+			}
+			mv, _, mvErr := encodable(iter.Value())
+			if mvErr != nil {
+				return nil, false, mvErr
+			}
+			out[k] = mv
+		}
+		return out, true, nil
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 || v.Type().Implements(textMarshalerType) {
+			// let the json library handle byte-slices (and types
+			// like net.IP) natively
+			return v.Interface(), true, nil
+		}
+		out := make([]any, v.Len())
+		for i := range out {
+			elem, _, err := encodable(v.Index(i))
+			if err != nil {
+				return nil, false, err
+			}
+			out[i] = elem
+		}
+		return out, true, nil
+	case reflect.Chan, reflect.Func:
+		return nil, false, nil
+	default:
+		return v.Interface(), true, nil
+	}
+}
+
+// mapKey stringifies map keys the same way encoding/json does.
+func mapKey(k reflect.Value) (string, error) {
+	if k.Kind() == reflect.String {
+		return k.String(), nil
+	}
+	if tm, ok := k.Interface().(encoding.TextMarshaler); ok {
+		b, err := tm.MarshalText()
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal map key of type %s: %w", k.Type(), err)
+		}
+		return string(b), nil
+	}
+	switch k.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(k.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(k.Uint(), 10), nil
+	default:
+		return "", fmt.Errorf("unsupported map key type %s", k.Type())
+	}
+}
+
+func appendStructFields(out *jsonObject, v reflect.Value) error {
+	for i := 0; i < v.NumField(); i++ {
+		sf := v.Type().Field(i)
+		if ptrify.OmitField(sf) {
+			continue
+		}
+		key, keep := fieldKey(sf)
+		if !keep {
+			continue
+		}
+		fv := v.Field(i)
+		if sf.Anonymous && key == "" {
+			// untagged embedded structs are flattened into the
+			// parent by encoding/json
+			if fv.Kind() == reflect.Ptr {
+				if fv.IsNil() {
+					continue
+				}
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct {
+				if err := appendStructFields(out, fv); err != nil {
+					return err
+				}
+				continue
+			}
+			key = sf.Name
+		}
+		if fv.Type() == durationType {
+			// the Decoder parses durations from strings
+			*out = append(*out, jsonField{key: keyOrName(key, sf), val: fv.Interface().(time.Duration).String()})
+			continue
+		}
+		val, ok, err := encodable(fv)
+		if err != nil {
+			return fmt.Errorf("failed to encode field %q: %w", sf.Name, err)
+		}
+		if !ok {
+			continue
+		}
+		*out = append(*out, jsonField{key: keyOrName(key, sf), val: val})
+	}
+	return nil
+}
+
+func keyOrName(key string, sf reflect.StructField) string {
+	if key == "" {
+		return sf.Name
+	}
+	return key
+}
+
+// fieldKey returns the key the Decoder expects for sf: the name from its
+// `json` tag if present, then its `dials` tag. The string return is empty if
+// the field name should be used. The boolean return is false if
+// encoding/json ignores the field.
+func fieldKey(sf reflect.StructField) (string, bool) {
+	if jsonTag := strings.Split(sf.Tag.Get(JSONTagName), ",")[0]; jsonTag == "-" {
+		return "", false
+	} else if jsonTag != "" {
+		return jsonTag, true
+	}
+	return sf.Tag.Get(common.DialsTagName), true
+}
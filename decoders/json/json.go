@@ -18,7 +18,10 @@ import (
 const JSONTagName = "json"
 
 // Decoder is a decoder that knows how to work with text encoded in JSON
-type Decoder struct{}
+type Decoder struct {
+	// Flatten any anonymous struct fields into the parent
+	FlattenAnonymous bool
+}
 
 func must[T any](v T, err error) T {
 	if err != nil {
@@ -41,10 +44,12 @@ func (d *Decoder) Decode(r io.Reader, t *dials.Type) (reflect.Value, error) {
 	}
 
 	// If there aren't any json tags, copy over from any dials tags.
-	tfmr := transform.NewTransformer(t.Type(),
-		parsingDurMangler,
-		&tagformat.TagCopyingMangler{
-			SrcTag: common.DialsTagName, NewTag: JSONTagName})
+	manglers := []transform.Mangler{parsingDurMangler, &tagformat.TagCopyingMangler{
+		SrcTag: common.DialsTagName, NewTag: JSONTagName}}
+	if d.FlattenAnonymous {
+		manglers = append(manglers, transform.AnonymousFlattenMangler{})
+	}
+	tfmr := transform.NewTransformer(t.Type(), manglers...)
 	val, tfmErr := tfmr.Translate()
 	if tfmErr != nil {
 		return reflect.Value{}, fmt.Errorf("failed to convert tags: %s", tfmErr)
@@ -0,0 +1,196 @@
+// Package properties provides a dials.Decoder for Java-style .properties
+// files.
+package properties
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/vimeo/dials"
+	"github.com/vimeo/dials/common"
+	"github.com/vimeo/dials/parse"
+	"github.com/vimeo/dials/tagformat/caseconversion"
+	"github.com/vimeo/dials/transform"
+)
+
+// Extension is the file extension conventionally used for properties files.
+const Extension = ".properties"
+
+// Decoder decodes Java-style .properties files (see
+// https://docs.oracle.com/javase/8/docs/api/java/util/Properties.html#load-java.io.Reader-)
+// consisting of "key=value" (or "key: value") lines.
+//
+// Keys are split on "." to address nested struct fields, with each level
+// named by its `dials` tag if present, or by its field name converted to
+// lowerCamelCase otherwise. e.g. "db.host" populates the Host field nested
+// within a struct-typed field named DB (or tagged `dials:"db"`).
+// Fields of embedded (anonymous) structs are addressed as if they were
+// fields of the enclosing struct.
+//
+// Values are parsed in the same way as environment variables and flags (see
+// the parse package), so numeric, boolean, duration, slice and map fields may
+// all be populated from their string representations.
+//
+// Lines beginning with "#" or "!" are comments, and lines ending with an odd
+// number of backslashes continue on the next line (with leading whitespace
+// removed). Keys that don't correspond to a field are ignored, and if a key
+// is repeated, the last value wins.
+type Decoder struct {
+	// Delimiters configures how slices and maps are parsed. Unset
+	// delimiters retain their defaults (see parse.DefaultDelimiters).
+	Delimiters parse.Delimiters
+}
+
+var _ dials.Decoder = (*Decoder)(nil)
+
+// Decode reads from r, parsing it as a properties file and depositing the
+// values in the fields of t.
+func (d *Decoder) Decode(r io.Reader, t *dials.Type) (reflect.Value, error) {
+	props, err := parseProperties(r)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	// flatten the nested fields, joining the names of each level with "."
+	flattenMangler := transform.NewFlattenManglerWithSeparator(
+		common.DialsTagName, caseconversion.EncodeUpperCamelCase, caseconversion.EncodeLowerCamelCase, ".")
+	// convert all the flattened fields to strings, so they can be set
+	// directly from the property values.
+	stringCastingMangler := transform.NewStringCastingMangler(d.Delimiters)
+	tfmr := transform.NewTransformer(t.Type(), flattenMangler, stringCastingMangler)
+
+	val, err := tfmr.Translate()
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	valType := val.Type()
+	for i := 0; i < val.NumField(); i++ {
+		sf := valType.Field(i)
+		key := sf.Tag.Get(common.DialsTagName)
+		if v, ok := props[key]; ok {
+			val.Field(i).Set(reflect.ValueOf(&v))
+		}
+	}
+
+	return tfmr.ReverseTranslate(val)
+}
+
+// parseProperties reads the key-value pairs from a properties file
+func parseProperties(r io.Reader) (map[string]string, error) {
+	props := map[string]string{}
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		startLine := lineNum
+		line := strings.TrimLeft(scanner.Text(), " \t\f")
+		if line == "" || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+		// join any continuation lines
+		for continues(line) && scanner.Scan() {
+			lineNum++
+			line = line[:len(line)-1] + strings.TrimLeft(scanner.Text(), " \t\f")
+		}
+		if continues(line) {
+			// a continuation at the end of the file is dropped
+			line = line[:len(line)-1]
+		}
+
+		rawKey, rawVal := splitKeyValue(line)
+		key, keyErr := unescape(rawKey)
+		if keyErr != nil {
+			return nil, fmt.Errorf("line %d: invalid key: %w", startLine, keyErr)
+		}
+		v, valErr := unescape(rawVal)
+		if valErr != nil {
+			return nil, fmt.Errorf("line %d: invalid value for key %q: %w", startLine, key, valErr)
+		}
+		props[key] = v
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading properties: %w", err)
+	}
+	return props, nil
+}
+
+// continues returns true if line ends with an odd number of backslashes
+// (i.e. an unescaped backslash), and so continues on the next line.
+func continues(line string) bool {
+	n := 0
+	for i := len(line) - 1; i >= 0 && line[i] == '\\'; i-- {
+		n++
+	}
+	return n%2 == 1
+}
+
+// splitKeyValue splits a (logical) line into its (still escaped) key and
+// value. The key ends at the first unescaped "=", ":" or whitespace, which
+// may be surrounded by whitespace.
+func splitKeyValue(line string) (string, string) {
+	keyEnd := len(line)
+	for i := 0; i < len(line); i++ {
+		if line[i] == '\\' {
+			// skip the escaped character
+			i++
+			continue
+		}
+		if strings.IndexByte("=: \t\f", line[i]) >= 0 {
+			keyEnd = i
+			break
+		}
+	}
+	key := line[:keyEnd]
+	rest := strings.TrimLeft(line[keyEnd:], " \t\f")
+	if rest != "" && (rest[0] == '=' || rest[0] == ':') {
+		rest = strings.TrimLeft(rest[1:], " \t\f")
+	}
+	return key, rest
+}
+
+// unescape resolves the escape sequences in s. As with Java's
+// implementation, a backslash before any character other than t, n, r, f
+// or u (for a \uXXXX escape) is dropped.
+func unescape(s string) (string, error) {
+	if strings.IndexByte(s, '\\') < 0 {
+		return s, nil
+	}
+	b := strings.Builder{}
+	b.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '\\' || i == len(s)-1 {
+			b.WriteByte(c)
+			continue
+		}
+		i++
+		switch s[i] {
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 'f':
+			b.WriteByte('\f')
+		case 'u':
+			if i+4 >= len(s) {
+				return "", fmt.Errorf("truncated unicode escape %q", s[i-1:])
+			}
+			code, err := strconv.ParseUint(s[i+1:i+5], 16, 16)
+			if err != nil {
+				return "", fmt.Errorf("malformed unicode escape %q", s[i-1:i+5])
+			}
+			b.WriteRune(rune(code))
+			i += 4
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String(), nil
+}
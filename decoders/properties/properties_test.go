@@ -0,0 +1,146 @@
+package properties
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vimeo/dials"
+	"github.com/vimeo/dials/sources/static"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type dbConfig struct {
+	Host    string
+	Port    int
+	Timeout time.Duration
+}
+
+type Common struct {
+	Verbose bool
+}
+
+type testConfig struct {
+	Common
+	Name     string
+	MaxConns int
+	Tags     []string
+	Labels   map[string]string
+	DB       dbConfig
+	Replica  *dbConfig `dials:"replica_db"`
+	Greeting string    `dials:"greeting"`
+	Path     string
+}
+
+func TestDecoder(t *testing.T) {
+	t.Parallel()
+	const data = `# a comment
+! another comment
+
+name = fimbat
+maxConns: 12
+verbose=true
+tags=a,b,\
+     c
+labels=env:prod,team:video
+db.host=localhost
+db.port 5432
+db.timeout=3s
+replica_db.host : replica.local
+greeting=caf\u00e9\tbar
+path=C:\\temp
+unknown.key=ignored
+name=fimbat2
+`
+	cfg := testConfig{Path: "default"}
+	d, err := dials.Config(context.Background(), &cfg,
+		&static.StringSource{Data: data, Decoder: &Decoder{}})
+	require.NoError(t, err)
+	assert.Equal(t, testConfig{
+		Common:   Common{Verbose: true},
+		Name:     "fimbat2",
+		MaxConns: 12,
+		Tags:     []string{"a", "b", "c"},
+		Labels:   map[string]string{"env": "prod", "team": "video"},
+		DB:       dbConfig{Host: "localhost", Port: 5432, Timeout: 3 * time.Second},
+		Replica:  &dbConfig{Host: "replica.local"},
+		Greeting: "café\tbar",
+		Path:     `C:\temp`,
+	}, *d.View())
+}
+
+func TestDecoderErrors(t *testing.T) {
+	t.Parallel()
+	for _, itbl := range []struct {
+		name      string
+		data      string
+		expectErr string
+	}{
+		{
+			name:      "bad_int",
+			data:      "maxConns=twelve",
+			expectErr: "twelve",
+		},
+		{
+			name:      "truncated_escape",
+			data:      "# comment\nname=\\u00e",
+			expectErr: `line 2: invalid value for key "name": truncated unicode escape "\\u00e"`,
+		},
+		{
+			name:      "malformed_escape",
+			data:      "na\\u00zzme=foo",
+			expectErr: `line 1: invalid key: malformed unicode escape "\\u00zz"`,
+		},
+	} {
+		tbl := itbl
+		t.Run(tbl.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := dials.Config(context.Background(), &testConfig{},
+				&static.StringSource{Data: tbl.data, Decoder: &Decoder{}})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tbl.expectErr)
+		})
+	}
+}
+
+func TestParseProperties(t *testing.T) {
+	t.Parallel()
+	for _, tbl := range []struct {
+		name   string
+		data   string
+		expect map[string]string
+	}{
+		{
+			name:   "separators",
+			data:   "a=1\nb:2\nc 3\nd = 4\ne\t:\t5\nf==6\n",
+			expect: map[string]string{"a": "1", "b": "2", "c": "3", "d": "4", "e": "5", "f": "=6"},
+		},
+		{
+			name:   "escaped_separators_in_key",
+			data:   `key\=with\:seps\ and\ space = value`,
+			expect: map[string]string{"key=with:seps and space": "value"},
+		},
+		{
+			name:   "even_backslashes_dont_continue",
+			data:   "a=foo\\\\\nb=bar",
+			expect: map[string]string{"a": `foo\`, "b": "bar"},
+		},
+		{
+			name:   "trailing_continuation",
+			data:   "a=foo\\",
+			expect: map[string]string{"a": "foo"},
+		},
+		{
+			name:   "indented_comment",
+			data:   "   # not=a property\n\t!nor=this",
+			expect: map[string]string{},
+		},
+	} {
+		props, err := parseProperties(strings.NewReader(tbl.data))
+		require.NoError(t, err, tbl.name)
+		assert.Equal(t, tbl.expect, props, tbl.name)
+	}
+}
@@ -19,6 +19,8 @@ const TOMLTagName = "toml"
 
 // Decoder is a decoder than understands TOML.
 type Decoder struct {
+	// Flatten any anonymous struct fields into the parent
+	FlattenAnonymous bool
 }
 
 // Decode will read from `r` and parse it as TOML depositing the relevant values
@@ -31,9 +33,12 @@ func (d *Decoder) Decode(r io.Reader, t *dials.Type) (reflect.Value, error) {
 
 	// Use the TagCopyingMangler to copy over TOML tags from dials tags if TOML
 	// tags aren't specified.
-	tfmr := transform.NewTransformer(t.Type(),
-		&tagformat.TagCopyingMangler{
-			SrcTag: common.DialsTagName, NewTag: TOMLTagName})
+	manglers := []transform.Mangler{&tagformat.TagCopyingMangler{
+		SrcTag: common.DialsTagName, NewTag: TOMLTagName}}
+	if d.FlattenAnonymous {
+		manglers = append(manglers, transform.AnonymousFlattenMangler{})
+	}
+	tfmr := transform.NewTransformer(t.Type(), manglers...)
 	val, tfmErr := tfmr.Translate()
 	if tfmErr != nil {
 		return reflect.Value{}, fmt.Errorf("failed to convert tags: %s", tfmErr)
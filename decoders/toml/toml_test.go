@@ -3,6 +3,7 @@ package toml
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/vimeo/dials"
 	"github.com/vimeo/dials/sources/static"
@@ -167,3 +168,34 @@ func TestDecoderBadMarkup(t *testing.T) {
 	)
 	require.Error(t, err)
 }
+
+func TestTOMLFlattenAnonymous(t *testing.T) {
+	type Base struct {
+		Name    string        `dials:"name"`
+		Timeout time.Duration `dials:"timeout"`
+	}
+	type testConfig struct {
+		Base  `dials:"base"`
+		Count int `dials:"count"`
+	}
+
+	data := `
+        name = "something"
+        timeout = "10s"
+        count = 42
+`
+
+	myConfig := &testConfig{}
+	d, err := dials.Config(
+		context.Background(),
+		myConfig,
+		&static.StringSource{Data: data, Decoder: &Decoder{FlattenAnonymous: true}},
+	)
+	require.NoError(t, err)
+
+	c := d.View()
+
+	assert.Equal(t, "something", c.Name)
+	assert.Equal(t, 10*time.Second, c.Timeout)
+	assert.Equal(t, 42, c.Count)
+}
@@ -0,0 +1,165 @@
+package yaml
+
+import (
+	"encoding"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/vimeo/dials"
+	"github.com/vimeo/dials/common"
+	"github.com/vimeo/dials/ptrify"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Encoder serializes config structs as YAML, such that the output can be read
+// back with a Decoder with the same FlattenAnonymous setting.
+type Encoder struct {
+	// Flatten any anonymous struct fields into the parent
+	FlattenAnonymous bool
+}
+
+var _ dials.Encoder = (*Encoder)(nil)
+
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+// Encode writes cfg to w as YAML.
+// Struct fields are keyed by their `dials` tag if present, and otherwise by
+// their lowercased name (as the Decoder expects), and nil fields are omitted.
+func (e *Encoder) Encode(w io.Writer, cfg any) error {
+	v, _, err := e.encodable(reflect.ValueOf(cfg))
+	if err != nil {
+		return err
+	}
+	out, marshalErr := yaml.Marshal(v)
+	if marshalErr != nil {
+		return fmt.Errorf("failed to marshal YAML: %w", marshalErr)
+	}
+	_, writeErr := w.Write(out)
+	return writeErr
+}
+
+// encodable converts v into a value that yaml.Marshal will serialize in the
+// form the Decoder expects. The boolean return is false for nil values.
+func (e *Encoder) encodable(v reflect.Value) (any, bool, error) {
+	if !v.IsValid() {
+		return nil, false, nil
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		if v.IsNil() {
+			return nil, false, nil
+		}
+	}
+	if v.Type().Implements(textMarshalerType) {
+		b, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to marshal %s: %w", v.Type(), err)
+		}
+		return string(b), true, nil
+	}
+	if v.Kind() != reflect.Ptr && reflect.PtrTo(v.Type()).Implements(textMarshalerType) {
+		// copy the value so we can take its address
+		ptr := reflect.New(v.Type())
+		ptr.Elem().Set(v)
+		return e.encodable(ptr)
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return e.encodable(v.Elem())
+	case reflect.Struct:
+		out := yaml.MapSlice{}
+		if err := e.appendStructFields(&out, v); err != nil {
+			return nil, false, err
+		}
+		return out, true, nil
+	case reflect.Map:
+		out := make(map[any]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			k, _, kErr := e.encodable(iter.Key())
+			if kErr != nil {
+				return nil, false, kErr
+			}
+			mv, _, mvErr := e.encodable(iter.Value())
+			if mvErr != nil {
+				return nil, false, mvErr
+			}
+			out[k] = mv
+		}
+		return out, true, nil
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			// let the yaml library handle byte-slices natively
+			return v.Interface(), true, nil
+		}
+		out := make([]any, v.Len())
+		for i := range out {
+			elem, _, err := e.encodable(v.Index(i))
+			if err != nil {
+				return nil, false, err
+			}
+			out[i] = elem
+		}
+		return out, true, nil
+	case reflect.Chan, reflect.Func:
+		return nil, false, nil
+	default:
+		return v.Interface(), true, nil
+	}
+}
+
+func (e *Encoder) appendStructFields(out *yaml.MapSlice, v reflect.Value) error {
+	for i := 0; i < v.NumField(); i++ {
+		sf := v.Type().Field(i)
+		if ptrify.OmitField(sf) {
+			continue
+		}
+		fv := v.Field(i)
+		if e.FlattenAnonymous && sf.Anonymous {
+			if fv.Kind() == reflect.Ptr {
+				if fv.IsNil() {
+					continue
+				}
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct && !fv.Type().Implements(textMarshalerType) {
+				if err := e.appendStructFields(out, fv); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		key, keep := fieldKey(sf)
+		if !keep {
+			continue
+		}
+		val, ok, err := e.encodable(fv)
+		if err != nil {
+			return fmt.Errorf("failed to encode field %q: %w", sf.Name, err)
+		}
+		if !ok {
+			continue
+		}
+		*out = append(*out, yaml.MapItem{Key: key, Value: val})
+	}
+	return nil
+}
+
+// fieldKey returns the key the Decoder expects for sf: the name from its
+// `yaml` tag if present, then its `dials` tag, and finally its lowercased
+// name. The boolean return is false if the yaml library ignores the field.
+func fieldKey(sf reflect.StructField) (string, bool) {
+	if yamlTag := strings.Split(sf.Tag.Get(YAMLTagName), ",")[0]; yamlTag == "-" {
+		return "", false
+	} else if yamlTag != "" {
+		return yamlTag, true
+	}
+	if dialsTag := sf.Tag.Get(common.DialsTagName); dialsTag != "" {
+		return dialsTag, true
+	}
+	return strings.ToLower(sf.Name), true
+}
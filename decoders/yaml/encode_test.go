@@ -0,0 +1,79 @@
+package yaml
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vimeo/dials"
+	"github.com/vimeo/dials/sources/static"
+)
+
+func TestEncodeRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	type Embedded struct {
+		Region string
+	}
+	type database struct {
+		Host    string        `dials:"host"`
+		Timeout time.Duration `dials:"timeout"`
+	}
+	type testConfig struct {
+		Embedded
+		Name    string `dials:"name"`
+		Port    int
+		Enabled bool
+		Ratio   float64
+		IP      net.IP
+		Start   time.Time
+		Tags    []string
+		Limits  map[string]int
+		Ports   map[int]string
+		DB      database `dials:"db"`
+		Replica *database
+		Unset   *database
+		Ignored string `yaml:"-"`
+	}
+
+	initial := `
+region: us-east1
+name: something
+port: 8080
+enabled: true
+ratio: 0.25
+ip: 10.0.0.1
+start: 2020-01-02T03:04:05Z
+tags: [a, b]
+limits: {cpu: 4}
+ports: {80: http}
+db:
+  host: db.example.com
+  timeout: 1.5s
+replica:
+  host: replica.example.com
+`
+	d, err := dials.Config(context.Background(), &testConfig{Ignored: "default"},
+		&static.StringSource{Data: initial, Decoder: &Decoder{FlattenAnonymous: true}})
+	require.NoError(t, err)
+	c := d.View()
+	assert.Equal(t, "us-east1", c.Region)
+	assert.Equal(t, 1500*time.Millisecond, c.DB.Timeout)
+	assert.Equal(t, map[int]string{80: "http"}, c.Ports)
+	assert.Equal(t, "replica.example.com", c.Replica.Host)
+
+	buf := bytes.Buffer{}
+	require.NoError(t, d.Dump(&buf, &Encoder{FlattenAnonymous: true}))
+	assert.NotContains(t, buf.String(), "unset")
+	assert.NotContains(t, buf.String(), "ignored")
+
+	reloaded, reloadErr := dials.Config(context.Background(), &testConfig{Ignored: "default"},
+		&static.StringSource{Data: buf.String(), Decoder: &Decoder{FlattenAnonymous: true}})
+	require.NoError(t, reloadErr, "failed to reload dumped config:\n%s", buf.String())
+	assert.Equal(t, c, reloaded.View())
+}
@@ -1,6 +1,8 @@
 package yaml
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -8,6 +10,7 @@ import (
 
 	"github.com/vimeo/dials"
 	"github.com/vimeo/dials/common"
+	"github.com/vimeo/dials/ptrify"
 	"github.com/vimeo/dials/tagformat"
 	"github.com/vimeo/dials/transform"
 
@@ -21,6 +24,17 @@ const YAMLTagName = "yaml"
 type Decoder struct {
 	// Flatten any anonymous struct fields into the parent
 	FlattenAnonymous bool
+
+	// MultiDocument decodes every `---`-separated document in the input
+	// (rather than just the first), overlaying each on top of the
+	// previous ones in order. Keys present in later documents override
+	// those in earlier documents, with nested mappings (both structs and
+	// maps) merged key-by-key, and sequences replaced wholesale. Keys
+	// that are absent (or null) in later documents retain their values
+	// from earlier documents.
+	// As in single-document mode, unknown keys are ignored in every
+	// document.
+	MultiDocument bool
 }
 
 // Decode reads from `r` and decodes what is read as YAML depositing the
@@ -45,7 +59,11 @@ func (d *Decoder) Decode(r io.Reader, t *dials.Type) (reflect.Value, error) {
 	}
 
 	instance := val.Addr().Interface()
-	err = yaml.Unmarshal(yamlBytes, instance)
+	if d.MultiDocument {
+		err = decodeAllDocuments(yamlBytes, instance)
+	} else {
+		err = yaml.Unmarshal(yamlBytes, instance)
+	}
 	if err != nil {
 		return reflect.Value{}, err
 	}
@@ -57,3 +75,103 @@ func (d *Decoder) Decode(r io.Reader, t *dials.Type) (reflect.Value, error) {
 
 	return unmangledVal, nil
 }
+
+// decodeAllDocuments decodes each document in yamlBytes into a fresh value,
+// overlaying each on the value pointed to by instance in turn.
+func decodeAllDocuments(yamlBytes []byte, instance any) error {
+	out := reflect.ValueOf(instance).Elem()
+	dec := yaml.NewDecoder(bytes.NewReader(yamlBytes))
+	for i := 0; ; i++ {
+		doc := reflect.New(out.Type())
+		if err := dec.Decode(doc.Interface()); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("failed to decode YAML document %d: %w", i, err)
+		}
+		overlayDocument(out, doc.Elem())
+	}
+}
+
+// overlayDocument overlays the set fields of the (pointerified) value doc onto
+// base.
+func overlayDocument(base, doc reflect.Value) {
+	switch doc.Kind() {
+	case reflect.Struct:
+		for i := 0; i < doc.NumField(); i++ {
+			if !doc.Type().Field(i).IsExported() {
+				continue
+			}
+			overlayDocument(base.Field(i), doc.Field(i))
+		}
+	case reflect.Ptr:
+		if doc.IsNil() {
+			return
+		}
+		if base.IsNil() || doc.Type().Elem().Kind() != reflect.Struct ||
+			ptrify.IsUnmarshalerStruct(doc.Type().Elem()) {
+			base.Set(doc)
+			return
+		}
+		overlayDocument(base.Elem(), doc.Elem())
+	case reflect.Map:
+		if doc.IsNil() {
+			return
+		}
+		if base.IsNil() {
+			base.Set(doc)
+			return
+		}
+		iter := doc.MapRange()
+		for iter.Next() {
+			k, v := iter.Key(), iter.Value()
+			base.SetMapIndex(k, overlayMapValue(base.MapIndex(k), v))
+		}
+	case reflect.Slice, reflect.Interface:
+		if doc.IsNil() {
+			return
+		}
+		base.Set(doc)
+	default:
+		if !doc.IsZero() {
+			base.Set(doc)
+		}
+	}
+}
+
+// overlayMapValue returns the value to store in a map for a key present in
+// a later document: nested mappings are merged key-by-key into a copy of
+// the existing value (map values aren't addressable), and anything else
+// replaces it.
+func overlayMapValue(existing, doc reflect.Value) reflect.Value {
+	if !existing.IsValid() {
+		return doc
+	}
+	// Untyped mappings (e.g. in a map[string]interface{}) decode as
+	// map[interface{}]interface{}; merge them if both documents have one.
+	if doc.Kind() == reflect.Interface {
+		if existing.IsNil() || doc.IsNil() ||
+			existing.Elem().Kind() != reflect.Map || existing.Elem().Type() != doc.Elem().Type() {
+			return doc
+		}
+		overlayDocument(existing.Elem(), doc.Elem())
+		return existing
+	}
+	switch doc.Kind() {
+	case reflect.Map:
+	case reflect.Struct:
+		if ptrify.IsUnmarshalerStruct(doc.Type()) {
+			return doc
+		}
+	case reflect.Ptr:
+		if doc.Type().Elem().Kind() != reflect.Struct || ptrify.IsUnmarshalerStruct(doc.Type().Elem()) {
+			return doc
+		}
+	default:
+		return doc
+	}
+	merged := reflect.New(doc.Type()).Elem()
+	merged.Set(existing)
+	overlayDocument(merged, doc)
+	return merged
+}
@@ -11,6 +11,8 @@ import (
 
 	"github.com/vimeo/dials"
 	"github.com/vimeo/dials/sources/static"
+	"github.com/vimeo/dials/sourcewrap"
+	"github.com/vimeo/dials/transform"
 )
 
 func TestYAML(t *testing.T) {
@@ -213,3 +215,189 @@ func TestDecoderBadMarkup(t *testing.T) {
 	)
 	require.Error(t, err)
 }
+
+func TestMultiDocumentYAML(t *testing.T) {
+	t.Parallel()
+
+	type database struct {
+		Host string
+		Port int
+	}
+	type testConfig struct {
+		Name   string
+		Labels map[string]string
+		Hosts  []string
+		DB     database
+		Extra  *string
+	}
+	yamlData := `---
+name: first
+labels:
+  team: core
+  env: dev
+hosts: [a, b]
+db:
+  host: db.example.com
+  port: 5432
+extra: something
+---
+# an empty document
+---
+labels:
+  env: prod
+hosts: [c]
+db:
+  port: 5433
+extra: null
+---
+extra: last
+`
+
+	for _, tbl := range []struct {
+		name     string
+		multiDoc bool
+		expected testConfig
+	}{
+		{
+			name:     "single_document",
+			multiDoc: false,
+			expected: testConfig{
+				Name:   "first",
+				Labels: map[string]string{"team": "core", "env": "dev"},
+				Hosts:  []string{"a", "b"},
+				DB:     database{Host: "db.example.com", Port: 5432},
+				Extra:  func() *string { s := "something"; return &s }(),
+			},
+		},
+		{
+			name:     "multi_document",
+			multiDoc: true,
+			expected: testConfig{
+				Name:   "first",
+				Labels: map[string]string{"team": "core", "env": "prod"},
+				Hosts:  []string{"c"},
+				DB:     database{Host: "db.example.com", Port: 5433},
+				Extra:  func() *string { s := "last"; return &s }(),
+			},
+		},
+	} {
+		tbl := tbl
+		t.Run(tbl.name, func(t *testing.T) {
+			t.Parallel()
+			d, err := dials.Config(
+				context.Background(),
+				&testConfig{},
+				&static.StringSource{Data: yamlData, Decoder: &Decoder{MultiDocument: tbl.multiDoc}},
+			)
+			require.NoError(t, err)
+			assert.Equal(t, &tbl.expected, d.View())
+		})
+	}
+}
+
+func TestMultiDocumentYAMLNestedMaps(t *testing.T) {
+	t.Parallel()
+
+	type backend struct {
+		Host string
+		Port int
+	}
+	type testConfig struct {
+		Limits   map[string]map[string]int
+		Backends map[string]backend
+		Extra    map[string]interface{}
+	}
+	yamlData := `---
+limits:
+  api:
+    read: 10
+    write: 5
+  batch:
+    read: 1
+backends:
+  primary:
+    host: a.example.com
+    port: 80
+extra:
+  nested:
+    a: 1
+    b: 2
+  scalar: one
+---
+limits:
+  api:
+    write: 7
+backends:
+  primary:
+    port: 8080
+  secondary:
+    host: b.example.com
+extra:
+  nested:
+    b: 3
+  scalar: two
+`
+	d, err := dials.Config(
+		context.Background(),
+		&testConfig{},
+		&static.StringSource{Data: yamlData, Decoder: &Decoder{MultiDocument: true}},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, &testConfig{
+		Limits: map[string]map[string]int{
+			"api":   {"read": 10, "write": 7},
+			"batch": {"read": 1},
+		},
+		Backends: map[string]backend{
+			"primary":   {Host: "a.example.com", Port: 8080},
+			"secondary": {Host: "b.example.com"},
+		},
+		Extra: map[string]interface{}{
+			"nested": map[interface{}]interface{}{"a": 1, "b": 3},
+			"scalar": "two",
+		},
+	}, d.View())
+}
+
+func TestMultiDocumentYAMLBadMarkup(t *testing.T) {
+	t.Parallel()
+
+	type testConfig struct {
+		Name string
+	}
+	_, err := dials.Config(
+		context.Background(),
+		&testConfig{},
+		&static.StringSource{Data: "name: ok\n---\nname: [unterminated\n", Decoder: &Decoder{MultiDocument: true}},
+	)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to decode YAML document 1")
+}
+
+func TestYAMLScalarToSlice(t *testing.T) {
+	t.Parallel()
+
+	type testConfig struct {
+		Tags     []string        `dials:"tags"`
+		Ports    []int           `dials:"ports"`
+		Timeouts []time.Duration `dials:"timeouts"`
+	}
+	yamlData := `
+tags: prod
+ports: [80, "443"]
+timeouts: 3s
+`
+	d, err := dials.Config(
+		context.Background(),
+		&testConfig{},
+		sourcewrap.NewTransformingSource(
+			&static.StringSource{Data: yamlData, Decoder: &Decoder{}},
+			&transform.ScalarToSliceMangler{}),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, &testConfig{
+		Tags:     []string{"prod"},
+		Ports:    []int{80, 443},
+		Timeouts: []time.Duration{3 * time.Second},
+	}, d.View())
+}
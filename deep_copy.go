@@ -6,6 +6,27 @@ import (
 	"reflect"
 )
 
+// DeepCopier can be implemented by types used within configuration structs
+// to override the reflection-based deep-copy dials makes of the
+// configuration whenever it's composed from its sources (and by Snapshot).
+// This is useful for large values that are never modified after
+// construction (e.g. parsed certificates or big lookup tables), which can
+// return themselves (or a shallow copy) to share the underlying data rather
+// than cloning it.
+//
+// DialsDeepCopy must return a value of the implementing type (or a pointer
+// to one, if implemented with a pointer receiver on a non-pointer field).
+// It is not called for nil pointers.
+//
+// Since the copy may share memory with the original, such types should be
+// treated as immutable: sources that populate fields nested within them
+// (rather than replacing the value as a whole) may modify the shared data.
+type DeepCopier interface {
+	DialsDeepCopy() any
+}
+
+var deepCopierType = reflect.TypeOf((*DeepCopier)(nil)).Elem()
+
 // takes a concrete value for in and returns a concrete deep copy Value
 func realDeepCopy(in interface{}) reflect.Value {
 	v := reflect.ValueOf(in)
@@ -66,6 +87,10 @@ func (d *deepCopier) deepCopy(in, out reflect.Value) {
 
 	d.registerPair(in, out)
 
+	if d.customCopy(in, out) {
+		return
+	}
+
 	switch in.Kind() {
 	case reflect.Struct:
 		d.deepCopyStruct(in, out)
@@ -83,6 +108,43 @@ func (d *deepCopier) deepCopy(in, out reflect.Value) {
 	}
 }
 
+// customCopy uses the DeepCopier implementation of in's type (if any) to
+// populate out, returning false if in doesn't implement DeepCopier.
+func (d *deepCopier) customCopy(in, out reflect.Value) bool {
+	if !in.CanInterface() || !out.CanSet() {
+		return false
+	}
+	var dc DeepCopier
+	switch {
+	case in.Kind() == reflect.Interface:
+		// interfaces are handled by the concrete value's type
+		return false
+	case in.Type().Implements(deepCopierType):
+		dc = in.Interface().(DeepCopier)
+	case in.CanAddr() && reflect.PtrTo(in.Type()).Implements(deepCopierType):
+		dc = in.Addr().Interface().(DeepCopier)
+	default:
+		return false
+	}
+	if in.Kind() == reflect.Ptr && in.IsNil() {
+		return true
+	}
+
+	cpIface := dc.DialsDeepCopy()
+	cp := reflect.ValueOf(cpIface)
+	switch {
+	case cp.IsValid() && cp.Type().AssignableTo(in.Type()):
+		out.Set(cp)
+	case cp.IsValid() && cp.Kind() == reflect.Ptr && !cp.IsNil() &&
+		cp.Type().Elem().AssignableTo(in.Type()):
+		out.Set(cp.Elem())
+	default:
+		panic(fmt.Errorf("DialsDeepCopy method on type %s returned incompatible value of type %T",
+			in.Type(), cpIface))
+	}
+	return true
+}
+
 func (d *deepCopier) deepCopyIface(in, out reflect.Value) {
 	if in.IsNil() {
 		return
@@ -420,3 +420,67 @@ func verifyDifferentPointers(t testing.TB, seenPtrs map[uintptr]struct{}, fname
 		}
 	}
 }
+
+type sharedRuleset struct {
+	Rules map[string]string
+}
+
+// DialsDeepCopy shares the ruleset rather than cloning it.
+func (r *sharedRuleset) DialsDeepCopy() any {
+	return r
+}
+
+type sharedTable struct {
+	Entries []int
+}
+
+// DialsDeepCopy returns a shallow copy, sharing the backing array.
+func (s sharedTable) DialsDeepCopy() any {
+	return s
+}
+
+type copyCounter struct {
+	N int
+}
+
+// DialsDeepCopy uses a pointer receiver on a non-pointer field, and returns
+// a pointer.
+func (c *copyCounter) DialsDeepCopy() any {
+	return &copyCounter{N: c.N + 1}
+}
+
+func TestDeepCopyDeepCopier(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		Rules    *sharedRuleset
+		NilRules *sharedRuleset
+		Table    sharedTable
+		Counter  copyCounter
+		Other    []int
+	}
+	in := &config{
+		Rules:   &sharedRuleset{Rules: map[string]string{"a": "b"}},
+		Table:   sharedTable{Entries: []int{1, 2, 3}},
+		Counter: copyCounter{N: 1},
+		Other:   []int{4, 5},
+	}
+	out := realDeepCopy(in).Interface().(*config)
+	if out == in {
+		t.Fatalf("top-level pointer not copied")
+	}
+	if out.Rules != in.Rules {
+		t.Errorf("Rules pointer not shared: got %p; want %p", out.Rules, in.Rules)
+	}
+	if out.NilRules != nil {
+		t.Errorf("NilRules: got %p; want nil", out.NilRules)
+	}
+	if &out.Table.Entries[0] != &in.Table.Entries[0] {
+		t.Errorf("Table entries not shared")
+	}
+	if out.Counter.N != 2 {
+		t.Errorf("Counter.N: got %d; want 2", out.Counter.N)
+	}
+	if &out.Other[0] == &in.Other[0] {
+		t.Errorf("Other slice shared; expected a deep copy")
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"io"
 	"reflect"
+	"sync/atomic"
+	"time"
 
 	"github.com/vimeo/dials/ptrify"
 )
@@ -22,6 +24,17 @@ type WatchedErrorHandler[T any] func(ctx context.Context, err error, oldConfig,
 // interval between new configs.
 type NewConfigHandler[T any] func(ctx context.Context, oldConfig, newConfig *T)
 
+// VerifyWarningsHandler is a callback that's called with the non-fatal
+// warnings returned by a [VerifiedConfigWithWarnings] implementation's
+// VerifyWithWarnings method when the config it was called on is installed.
+type VerifyWarningsHandler[T any] func(ctx context.Context, warnings []error, cfg *T)
+
+// VerifyRejectedHandler is a callback that's called with a configuration
+// composed after a watching source provided a new value, which wasn't
+// installed because its Verify() method (see [VerifiedConfig]) returned
+// err.
+type VerifyRejectedHandler[T any] func(ctx context.Context, rejected *T, err error)
+
 // Params provides options for setting Dials's behavior in some cases.
 type Params[T any] struct {
 	// OnWatchedError is called when either of several conditions are met:
@@ -49,6 +62,8 @@ type Params[T any] struct {
 	// OnWatchedError callback, with callbacks being executed in-order.
 	// In the event that a call to OnNewConfig blocks too long, some calls
 	// may be dropped.
+	// Without watching sources there's no callback goroutine, so it's
+	// called directly by Reload and SetSourcePriority instead.
 	OnNewConfig NewConfigHandler[T]
 
 	// DelayInitialVerification skips calls to Verify() until the EnableVerification()
@@ -70,6 +85,112 @@ type Params[T any] struct {
 	//  - DelayInitialVerification was set to true when Config was called
 	//  - EnableVerification has not been called (without it returning an error)
 	CallGlobalCallbacksAfterVerificationEnabled bool
+
+	// OnVerifyWarnings is called with any warnings returned by the
+	// VerifyWithWarnings method of configurations implementing
+	// [VerifiedConfigWithWarnings], if the configuration is otherwise
+	// valid (and installed).
+	//
+	// For new versions from watching sources, OnVerifyWarnings runs on the
+	// same "callback" goroutine as OnNewConfig and OnWatchedError. For the
+	// initial configuration, it is called synchronously before Config
+	// returns.
+	OnVerifyWarnings VerifyWarningsHandler[T]
+
+	// OnVerifyRejected is called when a configuration composed after a
+	// watching source provides a new value fails verification, with the
+	// rejected configuration and the error from Verify(), so rejections
+	// can be reported separately from errors from sources and failures to
+	// re-stack. The previous configuration remains installed.
+	// OnWatchedError is still called for such failures as well.
+	//
+	// OnVerifyRejected runs on the same "callback" goroutine as
+	// OnNewConfig and OnWatchedError. The rejected configuration must not
+	// be modified.
+	OnVerifyRejected VerifyRejectedHandler[T]
+
+	// OnOverride, if non-nil, is called for each leaf field that several
+	// sources provided conflicting (non-nil, differing) values for, with
+	// the source whose value was used and those it overrode, to help
+	// explain why a field doesn't have the value one source provided.
+	// Comparing the sources' values has a cost, so it's only done when
+	// OnOverride is set.
+	//
+	// It's called synchronously for the initial configuration before
+	// Config returns (and by Validate), and on the same "callback"
+	// goroutine as OnNewConfig and OnWatchedError when a new value from a
+	// watching source is installed, or a new version is installed by
+	// Reload or SetSourcePriority. (which call it synchronously if there's
+	// no callback goroutine; see Reload)
+	OnOverride OverrideHandler
+
+	// Logger, if non-nil, receives structured log entries at key points
+	// in the configuration lifecycle: reading each source, composing
+	// and verifying the configuration, installing new versions, errors
+	// reported by watching sources and dispatching callbacks.
+	// A nil Logger disables logging.
+	Logger Logger
+
+	// Metrics, if non-nil, is notified of re-stacking durations,
+	// verification failures, errors from sources and newly installed
+	// configuration versions after Config returns. A nil Metrics
+	// disables these notifications.
+	Metrics Metrics
+
+	// CallbackChannelCapacity sets the capacity of the channel used to
+	// deliver events to callbacks (OnNewConfig, OnWatchedError,
+	// OnVerifyWarnings and those registered with RegisterCallback) when
+	// there's at least one watching source. Events are dropped rather than
+	// blocking updates from sources when the channel is full (e.g. because
+	// a callback is slow and sources are updating rapidly), so a larger
+	// capacity reduces the likelihood of drops at the cost of memory (and
+	// potentially more stale configurations being delivered while
+	// callbacks catch up). Zero uses the default of 64, and negative values
+	// are rejected by Config.
+	CallbackChannelCapacity int
+
+	// OnDroppedEvent, if non-nil, is called whenever an event destined for
+	// the callbacks is dropped because the callback channel is full (see
+	// CallbackChannelCapacity), with the total number of events dropped so
+	// far (see Dials.DroppedCallbackEvents). It's called synchronously
+	// from the goroutine handling updates from watching sources, so it
+	// must not block.
+	OnDroppedEvent func(ctx context.Context, totalDropped uint64)
+
+	// EventsChannelCapacity sets the capacity of the channel returned by
+	// Events. New versions are dropped rather than blocking when the
+	// channel is full, so a consumer that falls behind misses
+	// intermediate versions; a larger capacity lets it fall further
+	// behind before that happens. Callbacks (see RegisterCallback) remain
+	// the recommended way to be notified of every version. Zero uses the
+	// default of 1, and negative values are rejected by Config.
+	EventsChannelCapacity int
+
+	// ZeroSecretsInCallbacks zeroes any fields tagged `dialssecret:"true"`
+	// in the configurations passed to callbacks (those in Params, and
+	// those registered with RegisterCallback), and delivered on the
+	// Events channel. The configuration returned by View() is unaffected.
+	// If a configuration's secrets can't be zeroed, the error is logged
+	// (see Logger), and the configuration is passed along unmodified.
+	ZeroSecretsInCallbacks bool
+
+	// OnSourceWarning is called with any non-fatal warnings returned by
+	// the ValueWithWarnings method of Sources implementing
+	// [SourceWithWarnings], allowing a configuration to be installed even
+	// if one (optional) field couldn't be read.
+	//
+	// It's called synchronously, after reading the source, from Config and
+	// Reload.
+	OnSourceWarning SourceWarningsHandler
+
+	// InitialVerifyTimeout, if positive, bounds the initial verification
+	// done by Config. The configuration's Verify (or VerifyContext or
+	// VerifyWithWarnings) method is run on another goroutine, and Config
+	// returns an error if it hasn't returned within the timeout. The
+	// context passed to VerifyContext is canceled when the timeout
+	// expires, but a verifier that ignores it is left running in the
+	// background.
+	InitialVerifyTimeout time.Duration
 }
 
 // Config populates the passed in config struct by reading the values from the
@@ -90,14 +211,33 @@ type Params[T any] struct {
 //
 // More complicated verification/initialization should be done by
 // consuming from the channel returned by `Events()`.
+//
+// T must be a struct type; Sources populate (and Config stacks) the values
+// of its fields. To configure a map or slice (e.g. a
+// map[string]ServiceConfig), wrap it in a struct with a single field, and
+// nest the contents of the file under that field's name:
+//
+//	type Config struct {
+//		Services map[string]ServiceConfig `dials:"services"`
+//	}
 func (p Params[T]) Config(ctx context.Context, t *T, sources ...Source) (*Dials[T], error) {
+	if p.CallbackChannelCapacity < 0 {
+		return nil, fmt.Errorf("negative CallbackChannelCapacity %d", p.CallbackChannelCapacity)
+	}
+	if p.EventsChannelCapacity < 0 {
+		return nil, fmt.Errorf("negative EventsChannelCapacity %d", p.EventsChannelCapacity)
+	}
+	evCap := p.EventsChannelCapacity
+	if evCap == 0 {
+		evCap = 1
+	}
 
 	watcherChan := make(chan watchStatusUpdate)
 	computed := make([]sourceValue, len(sources))
 
-	typeOfT := reflect.TypeOf(t)
-	if typeOfT.Kind() != reflect.Ptr {
-		return nil, fmt.Errorf("config type %T is not a pointer", t)
+	typeOfT, err := checkConfigType(t)
+	if err != nil {
+		return nil, err
 	}
 
 	tVal := realDeepCopy(t)
@@ -110,53 +250,90 @@ func (p Params[T]) Config(ctx context.Context, t *T, sources ...Source) (*Dials[
 	for i, source := range sources {
 		s := source
 
-		v, err := source.Value(valueCtx, typeInstance)
+		readStart := time.Now()
+		v, err := p.readSource(valueCtx, source, typeInstance)
 		if err != nil {
+			p.log(ctx, LogLevelError, "failed to read source value",
+				"source_type", sourceType(s), "duration", time.Since(readStart), "error", err)
 			return nil, err
 		}
+		p.log(ctx, LogLevelDebug, "read source value",
+			"source_type", sourceType(s), "duration", time.Since(readStart))
 		computed[i] = sourceValue{
 			source:   s,
 			value:    v,
 			watching: false,
 		}
 
-		if w, ok := source.(Watcher); ok {
+		if w, ok := watcher(source); ok {
 			someoneWatching = true
 			computed[i].watching = true
 			wa := watchArgs{c: watcherChan, s: source}
 			err = w.Watch(ctx, typeInstance, &wa)
 			if err != nil {
+				p.log(ctx, LogLevelError, "failed to start watching source",
+					"source_type", sourceType(s), "error", err)
 				return nil, err
 			}
 		}
 	}
 
+	composeStart := time.Now()
 	newValue, err := compose(tVal.Interface(), computed)
 	if err != nil {
+		p.log(ctx, LogLevelError, "failed to compose configuration",
+			"duration", time.Since(composeStart), "error", err)
 		return nil, err
 	}
+	p.log(ctx, LogLevelDebug, "composed configuration",
+		"duration", time.Since(composeStart))
 
 	nv, _ := newValue.(*T)
 
 	d := &Dials[T]{
-		updatesChan: make(chan *T, 1),
+		updatesChan: make(chan *T, evCap),
 		params:      p,
+		reload: &reloadState[T]{
+			sources:      append([]Source(nil), sources...),
+			typeInstance: typeInstance,
+			base:         tVal.Interface().(*T),
+			computed:     computed,
+			skipVerify:   p.DelayInitialVerification,
+		},
 	}
-	d.value.Store(&versionedConfig[T]{serial: 0, cfg: nv})
+	d.value.Store(&versionedConfig[T]{serial: 0, cfg: nv, replaced: make(chan struct{})})
 
 	// Verify that the configuration is valid if a Verify() method is present.
-	if vf, ok := newValue.(VerifiedConfig); ok && !p.SkipInitialVerification && !p.DelayInitialVerification {
-		if vfErr := vf.Verify(); vfErr != nil {
+	if !p.SkipInitialVerification && !p.DelayInitialVerification {
+		if reqErr := checkRequired(reflect.ValueOf(newValue)); reqErr != nil {
+			p.log(ctx, LogLevelError, "initial configuration is missing required fields",
+				"error", reqErr)
+			return nil, reqErr
+		}
+		warnings, vfErr := p.initialVerify(ctx, newValue)
+		if vfErr != nil {
+			p.log(ctx, LogLevelError, "initial configuration verification failed",
+				"error", vfErr)
 			return nil, fmt.Errorf("initial configuration verification failed: %w", vfErr)
 		}
+		p.log(ctx, LogLevelDebug, "verified initial configuration",
+			"warnings", len(warnings))
+		if len(warnings) > 0 && p.OnVerifyWarnings != nil {
+			p.OnVerifyWarnings(ctx, warnings, p.callbackConfig(nv))
+		}
 	}
+	p.reportOverrides(ctx, computed)
 
 	// After this point, computed is owned by the monitor goroutine
 	if someoneWatching {
 		// Give the callback channel enough capacity that we
 		// don't have to worry about dropping anything most of
 		// the time.
-		cbch := make(chan userCallbackEvent, 64)
+		cbCap := p.CallbackChannelCapacity
+		if cbCap == 0 {
+			cbCap = defaultCallbackChannelCapacity
+		}
+		cbch := make(chan userCallbackEvent, cbCap)
 		d.cbch = cbch
 		cbmgr := callbackMgr[T]{
 			p:  &p,
@@ -166,11 +343,59 @@ func (p Params[T]) Config(ctx context.Context, t *T, sources ...Source) (*Dials[
 
 		monCtl := make(chan verifyEnable[T], 3)
 		d.monCtl = monCtl
-		go d.monitor(ctx, tVal.Interface().(*T), computed, watcherChan, monCtl)
+		reloadCh := make(chan reloadRequest[T])
+		monDone := make(chan struct{})
+		d.reload.reqs = reloadCh
+		d.reload.monDone = monDone
+		go d.monitor(ctx, tVal.Interface().(*T), computed, watcherChan, monCtl, reloadCh, monDone)
 	}
 	return d, nil
 }
 
+// checkConfigType returns the type of t, which must be a pointer to a
+// struct.
+func checkConfigType(t any) (reflect.Type, error) {
+	typeOfT := reflect.TypeOf(t)
+	if typeOfT.Kind() != reflect.Ptr {
+		return nil, fmt.Errorf("config type %T is not a pointer", t)
+	}
+	if k := typeOfT.Elem().Kind(); k != reflect.Struct {
+		return nil, fmt.Errorf("config type %s is a %s, not a struct; wrap it in a field of a struct type",
+			typeOfT.Elem(), k)
+	}
+	return typeOfT, nil
+}
+
+// initialVerify calls verify, enforcing InitialVerifyTimeout (if set).
+func (p *Params[T]) initialVerify(ctx context.Context, cfg any) ([]error, error) {
+	if p.InitialVerifyTimeout <= 0 {
+		return verify(ctx, cfg)
+	}
+	vfCtx, cancel := context.WithTimeout(ctx, p.InitialVerifyTimeout)
+	defer cancel()
+
+	type verifyResult struct {
+		warnings []error
+		err      error
+	}
+	// buffered so an abandoned verifier doesn't block forever
+	res := make(chan verifyResult, 1)
+	go func() {
+		warnings, err := verify(vfCtx, cfg)
+		res <- verifyResult{warnings: warnings, err: err}
+	}()
+	select {
+	case r := <-res:
+		return r.warnings, r.err
+	case <-vfCtx.Done():
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("context expired during verification: %w", ctx.Err())
+		}
+		return nil, fmt.Errorf("verification did not complete within %s: %w",
+			p.InitialVerifyTimeout, vfCtx.Err())
+	}
+}
+
 // Config populates the passed in config struct by reading the values from the
 // different Sources. The order of the sources denotes the precedence of the formats
 // so the last source passed to the function has the ability to override fields that
@@ -181,6 +406,100 @@ func Config[T any](ctx context.Context, t *T, sources ...Source) (*Dials[T], err
 	return Params[T]{}.Config(ctx, t, sources...)
 }
 
+// Load is a one-shot alternative to Config for programs (such as simple CLIs)
+// that only need the configuration's value at startup, and have no use for
+// the Dials handle.
+//
+// It calls Config, and returns the resulting configuration after tearing
+// down any background goroutines Config started (by canceling the context
+// passed to Config and waiting for the goroutine handling updates from
+// watching sources to exit). As a result, watching sources are effectively
+// read once, and callbacks registered in p (e.g. OnNewConfig) are never
+// called.
+func (p Params[T]) Load(ctx context.Context, t *T, sources ...Source) (*T, error) {
+	loadCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	d, err := p.Config(loadCtx, t, sources...)
+	if err != nil {
+		return nil, err
+	}
+	cfg := d.View()
+
+	cancel()
+	if d.reload.monDone != nil {
+		<-d.reload.monDone
+	}
+	return cfg, nil
+}
+
+// Load is a one-shot alternative to Config, returning the configuration
+// without a Dials handle, and without leaving any background goroutines
+// running. See Params.Load for details.
+func Load[T any](ctx context.Context, t *T, sources ...Source) (*T, error) {
+	return Params[T]{}.Load(ctx, t, sources...)
+}
+
+// Validate is a dry-run alternative to Config, for checking a configuration
+// (e.g. for a --check-config flag) without using it. It reads each source
+// once (without starting any watches, so no background goroutines are left
+// running), stacks the values as Config would, and returns the error from
+// the resulting configuration's Verify method (see [VerifiedConfig]), if
+// any.
+//
+// Verification always runs, regardless of SkipInitialVerification and
+// DelayInitialVerification, although InitialVerifyTimeout still applies. Any
+// warnings from a [VerifiedConfigWithWarnings] implementation are passed to
+// OnVerifyWarnings (if set), and any overridden values to OnOverride (if
+// set), before Validate returns; no other callbacks are called.
+func (p Params[T]) Validate(ctx context.Context, t *T, sources ...Source) error {
+	typeOfT, err := checkConfigType(t)
+	if err != nil {
+		return err
+	}
+
+	tVal := realDeepCopy(t)
+
+	valueCtx, cancelValues := context.WithCancel(ctx)
+	defer cancelValues()
+
+	typeInstance := &Type{ptrify.Pointerify(typeOfT.Elem(), tVal.Elem())}
+	computed := make([]sourceValue, len(sources))
+	for i, source := range sources {
+		v, err := p.readSource(valueCtx, source, typeInstance)
+		if err != nil {
+			return err
+		}
+		computed[i] = sourceValue{source: source, value: v}
+	}
+
+	newValue, err := compose(tVal.Interface(), computed)
+	if err != nil {
+		return err
+	}
+
+	if reqErr := checkRequired(reflect.ValueOf(newValue)); reqErr != nil {
+		return reqErr
+	}
+	warnings, vfErr := p.initialVerify(ctx, newValue)
+	if vfErr != nil {
+		return fmt.Errorf("configuration verification failed: %w", vfErr)
+	}
+	if len(warnings) > 0 && p.OnVerifyWarnings != nil {
+		nv, _ := newValue.(*T)
+		p.OnVerifyWarnings(ctx, warnings, p.callbackConfig(nv))
+	}
+	p.reportOverrides(ctx, computed)
+	return nil
+}
+
+// Validate is a dry-run alternative to Config, reading and verifying the
+// configuration without starting any watches or background goroutines. See
+// Params.Validate for details.
+func Validate[T any](ctx context.Context, t *T, sources ...Source) error {
+	return Params[T]{}.Validate(ctx, t, sources...)
+}
+
 // Source interface is implemented by each configuration source that is used to
 // populate the config struct such as environment variables, command line flags,
 // config files, and more
@@ -190,6 +509,8 @@ type Source interface {
 	// long-lived goroutines.
 	// Config() will cancel the context passed to this method upon Config's
 	// return.
+	// The context is derived from the one passed to Config (or Reload), so
+	// it carries any values attached with WithSourceOptions.
 	// Implementations that need to handle state changes with long-lived
 	// background goroutines should implement the Watcher interface, which
 	// explicitly provides a way to supply state updates.
@@ -203,6 +524,15 @@ type Decoder interface {
 	Decode(io.Reader, *Type) (reflect.Value, error)
 }
 
+// Encoder interface is implemented by data formats that can serialize a
+// config struct such that the output can be read back by the corresponding
+// Decoder (using the same tags and naming conventions). Dials currently
+// includes implementations for YAML and JSON.
+type Encoder interface {
+	// Encode writes cfg (a pointer to a config struct) to w.
+	Encode(w io.Writer, cfg any) error
+}
+
 type valueUpdate struct {
 	source    Source
 	value     reflect.Value
@@ -330,6 +660,32 @@ type Watcher interface {
 	Watch(context.Context, *Type, WatchArgs) error
 }
 
+// StaticSource may be implemented by Sources that implement Watcher (usually
+// wrappers, or sources for which watching is optional), but won't report
+// changes in a particular configuration. If Static returns true, the Source
+// is treated as if it didn't implement Watcher: Config doesn't call its
+// Watch method, nor count it when deciding whether to start the goroutine
+// that handles updates from watching sources, and Reload re-reads it.
+type StaticSource interface {
+	// Static returns true if the Source will never report a new value.
+	// It may be called more than once, and must return the same value
+	// every time.
+	Static() bool
+}
+
+// watcher returns s as a Watcher if it implements Watcher and isn't a
+// StaticSource reporting itself as static.
+func watcher(s Source) (Watcher, bool) {
+	w, ok := s.(Watcher)
+	if !ok {
+		return nil, false
+	}
+	if ss, isStatic := s.(StaticSource); isStatic && ss.Static() {
+		return nil, false
+	}
+	return w, true
+}
+
 // VerifiedConfig implements the Verify method, allowing Dials to execute the
 // Verify method before returning/installing a new version of the
 // configuration.
@@ -341,10 +697,59 @@ type VerifiedConfig interface {
 	Verify() error
 }
 
+// VerifiedConfigCtx is an alternative to [VerifiedConfig] for configuration
+// types whose verification needs a context (e.g. to respect cancellation
+// while checking that a referenced file exists). If a configuration type
+// implements both, VerifyContext is called instead of Verify.
+type VerifiedConfigCtx interface {
+	// VerifyContext should return a non-nil error if the configuration is
+	// invalid.
+	// The context is the one passed to Config (or EnableVerification, when
+	// verification is being enabled).
+	VerifyContext(ctx context.Context) error
+}
+
+// VerifiedConfigWithWarnings is an alternative to [VerifiedConfig] for
+// configuration types that want to flag questionable (but acceptable)
+// configurations without rejecting them. If a configuration type implements
+// it along with [VerifiedConfigCtx] or [VerifiedConfig], only
+// VerifyWithWarnings is called.
+type VerifiedConfigWithWarnings interface {
+	// VerifyWithWarnings should return a non-nil error if the
+	// configuration is invalid. If err is nil, the configuration will be
+	// installed and any warnings passed to the
+	// [Params].OnVerifyWarnings callback. (warnings are discarded if err
+	// is non-nil)
+	// The same restrictions on complex or blocking work as
+	// [VerifiedConfig].Verify apply.
+	VerifyWithWarnings() (warnings []error, err error)
+}
+
+// verify calls the VerifyWithWarnings, VerifyContext or Verify method on cfg
+// (in order of preference), if any are implemented.
+func verify(ctx context.Context, cfg any) ([]error, error) {
+	switch vf := cfg.(type) {
+	case VerifiedConfigWithWarnings:
+		warnings, err := vf.VerifyWithWarnings()
+		if err != nil {
+			return nil, err
+		}
+		return warnings, nil
+	case VerifiedConfigCtx:
+		return nil, vf.VerifyContext(ctx)
+	case VerifiedConfig:
+		return nil, vf.Verify()
+	default:
+		return nil, nil
+	}
+}
+
 // versionedConfig is the value-type of the value struct
 type versionedConfig[T any] struct {
 	serial uint64
 	cfg    *T
+	// replaced is closed once a newer version is installed
+	replaced chan struct{}
 }
 
 // CfgSerial is an opaque object unique to a config-version
@@ -354,11 +759,29 @@ type CfgSerial[T any] struct {
 }
 
 // Events returns a channel that will get a message every time the configuration
-// is updated.
+// is updated. Versions are dropped if the channel is full (see
+// Params.EventsChannelCapacity).
 func (d *Dials[T]) Events() <-chan *T {
 	return d.updatesChan
 }
 
+// Sources returns the sources passed to Config, in precedence order (later
+// sources override earlier ones), reflecting any calls to
+// SetSourcePriority. The returned slice is a copy, and may be modified
+// freely.
+func (d *Dials[T]) Sources() []Source {
+	return d.reload.sourceList()
+}
+
+// Snapshot returns a deep copy of the current configuration. Unlike View,
+// the returned value shares no memory (including map, slice and pointer
+// fields) with dials' internal state or other callers, so it can be held
+// (and modified) freely for the duration of e.g. a long-running request
+// without observing any subsequent updates.
+func (d *Dials[T]) Snapshot() *T {
+	return realDeepCopy(d.View()).Interface().(*T)
+}
+
 // Fill populates the passed struct with the current value of the configuration.
 // It is a thin wrapper around assignment
 // deprecated: assign return value from View() instead
@@ -366,6 +789,24 @@ func (d *Dials[T]) Fill(blankConfig *T) {
 	*blankConfig = *d.View()
 }
 
+// FillVersion populates the passed struct with the current value of the
+// configuration, and returns the serial token for that same version (as
+// ViewVersion would). Like Fill, this is a shallow copy, so map, slice and
+// pointer fields are shared with dials' internal state and must not be
+// modified.
+func (d *Dials[T]) FillVersion(dst *T) CfgSerial[T] {
+	cfg, serial := d.ViewVersion()
+	*dst = *cfg
+	return serial
+}
+
+// Dump serializes the current configuration (as returned by View()) to w
+// using enc, producing a document that can be fed back in as a config file
+// with the corresponding Decoder.
+func (d *Dials[T]) Dump(w io.Writer, enc Encoder) error {
+	return enc.Encode(w, d.View())
+}
+
 type userCallbackUnregisterToken[T any] struct {
 	d *Dials[T]
 	h *userCallbackHandle[T]
@@ -402,14 +843,23 @@ type UnregisterCBFunc func(ctx context.Context) bool
 // serial must be obtained from [Dials.ViewVersion()]. Catch-up callbacks are
 // suppressed if passed passed an invalid CfgSerial (including the zero-value)
 //
-// May return a nil [UnregisterCBFunc] if the context expires
+// May return a nil [UnregisterCBFunc] if the context expires, or if the
+// context passed to Config has expired (so no more versions will be
+// installed).
 //
 // The returned UnregisterCBFunc will block until the relevant callback has
 // been removed from the set of callbacks.
 func (d *Dials[T]) RegisterCallback(ctx context.Context, serial CfgSerial[T], cb NewConfigHandler[T]) UnregisterCBFunc {
+	return d.registerCallback(ctx, serial, cb, nil)
+}
+
+// registerCallback implements RegisterCallback, with cb only called for new
+// versions accepted by filter, if it's non-nil. (see userCallbackHandle)
+func (d *Dials[T]) registerCallback(ctx context.Context, serial CfgSerial[T], cb NewConfigHandler[T], filter func(oldConfig, newConfig *T) bool) UnregisterCBFunc {
 	handle := userCallbackHandle[T]{
 		cb:        cb,
 		minSerial: serial.s,
+		filter:    filter,
 	}
 	submitted := d.submitEventBlocking(ctx, &userCallbackRegistration[T]{
 		handle: &handle,
@@ -426,6 +876,52 @@ func (d *Dials[T]) RegisterCallback(ctx context.Context, serial CfgSerial[T], cb
 	return tok.unregister
 }
 
+// RegisterCallbackForPaths is like RegisterCallback, but cb is only called
+// for new versions in which the value of at least one of the fields
+// identified by paths changed. Paths use the same dotted keys as ToFlatMap
+// (each component is a field's `dials` tag, or its name if it has none), and
+// name either a leaf field (e.g. "Logging.Level"), or a struct field, in
+// which case a change to any field nested within it counts (e.g. "Logging").
+//
+// Fields are compared with reflect.DeepEqual. Paths that don't name any
+// field never match, and changes to channel, func and interface fields
+// (which ToFlatMap omits) aren't detected.
+func (d *Dials[T]) RegisterCallbackForPaths(ctx context.Context, serial CfgSerial[T], paths []string, cb NewConfigHandler[T]) UnregisterCBFunc {
+	fields := matchFlatFields(reflect.TypeOf((*T)(nil)).Elem(), paths)
+	// Compare the unredacted configurations, so changes to secrets are
+	// detected even if ZeroSecretsInCallbacks is set.
+	return d.registerCallback(ctx, serial, cb, func(oldConfig, newConfig *T) bool {
+		return flatFieldsChanged(fields, reflect.ValueOf(oldConfig), reflect.ValueOf(newConfig))
+	})
+}
+
+// WaitForVersionAfter blocks until a configuration version newer than the one
+// represented by serial has been installed, or the context expires.
+// If the current version is already newer than serial, it returns
+// immediately.  The returned config and [CfgSerial] may be for a version
+// later than the one immediately following serial.
+//
+// serial should be obtained from [Dials.ViewVersion()]. (the zero-value is
+// treated as the initial version)
+//
+// New versions from watching sources, Reload and SetSourcePriority are all
+// waited for, so it waits even if there are no watching sources (or the
+// context passed to Config has expired). Returns an error if the context
+// expires first.
+func (d *Dials[T]) WaitForVersionAfter(ctx context.Context, serial CfgSerial[T]) (*T, CfgSerial[T], error) {
+	for {
+		cur := d.loadVersioned()
+		if cur.serial > serial.s {
+			return cur.cfg, CfgSerial[T]{s: cur.serial, cfg: cur.cfg}, nil
+		}
+		select {
+		case <-cur.replaced:
+		case <-ctx.Done():
+			return nil, CfgSerial[T]{}, fmt.Errorf("context expired while awaiting new version: %w", ctx.Err())
+		}
+	}
+}
+
 // returns the new value (if any)
 func (d *Dials[T]) updateSourceValue(
 	ctx context.Context,
@@ -440,55 +936,109 @@ func (d *Dials[T]) updateSourceValue(
 			break
 		}
 	}
-	newInterface, stackErr := compose(t, sourceValues)
-	if stackErr != nil {
-		oldVal := d.View()
-		newVal, _ := newInterface.(*T)
-		d.submitEvent(ctx, &watchErrorEvent[T]{
-			err: stackErr, oldConfig: oldVal, newConfig: newVal,
-		})
+	newVers, warnings, restackErr := d.restack(ctx, t, skipVerify, sourceValues)
+	if restackErr != nil {
+		d.params.log(ctx, LogLevelWarn, "rejected new configuration from watching source",
+			"source_type", sourceType(watchTab.source), "error", restackErr)
+		d.reportRestackError(ctx, restackErr, newVers)
 		if watchTab.installed != nil {
-			watchTab.installed <- stackErr
+			watchTab.installed <- restackErr
 		}
 		return nil
 	}
 
-	// Verify that the configuration is valid if a Verify() method is present.
-	if vf, ok := newInterface.(VerifiedConfig); ok && !skipVerify {
-		if vfErr := vf.Verify(); vfErr != nil {
-			oldVal := d.View()
+	d.install(newVers)
+	if len(warnings) > 0 {
+		d.submitEvent(ctx, &verifyWarningsEvent[T]{warnings: warnings, cfg: newVers})
+	}
+	d.submitOverrides(ctx, sourceValues)
 
-			newVal := newInterface.(*T)
+	// If there's an installed channel, poke it.
+	if watchTab.installed != nil {
+		watchTab.installed <- nil
+	}
 
-			d.submitEvent(ctx, &watchErrorEvent[T]{
-				err: vfErr, oldConfig: oldVal, newConfig: newVal,
-			})
+	return newVers
+}
 
-			if watchTab.installed != nil {
-				watchTab.installed <- vfErr
-			}
-			return nil
-		}
+// restack composes the source values on top of t, and verifies the result
+// (unless skipVerify is set). It returns the new configuration and any
+// verification warnings, but does not install it.
+// If verification fails, the (unverified) composed configuration is
+// returned alongside the error.
+func (d *Dials[T]) restack(
+	ctx context.Context,
+	t *T,
+	skipVerify bool,
+	sourceValues []sourceValue,
+) (*T, []error, error) {
+	composeStart := time.Now()
+	newInterface, stackErr := compose(t, sourceValues)
+	if stackErr != nil {
+		return nil, nil, stackErr
 	}
-
+	d.params.log(ctx, LogLevelDebug, "composed configuration",
+		"duration", time.Since(composeStart))
 	newVers := newInterface.(*T)
 
-	_, oldSerial := d.ViewVersion()
+	// Verify that the configuration is valid if a Verify() method is present.
+	if skipVerify {
+		d.params.metrics().RestackDuration(time.Since(composeStart))
+		return newVers, nil, nil
+	}
+	warnings, vfErr := verifyRequired(ctx, newInterface)
+	d.params.metrics().RestackDuration(time.Since(composeStart))
+	if vfErr != nil {
+		d.params.metrics().VerifyFailure()
+		return newVers, nil, vfErr
+	}
+	d.params.log(ctx, LogLevelDebug, "verified configuration",
+		"warnings", len(warnings))
+	return newVers, warnings, nil
+}
 
-	// We can do a blind-store here because this goroutine (monitor()) has
-	// exclusive ownership of writes to this atomic-value
-	d.value.Store(&versionedConfig[T]{serial: oldSerial.s + 1, cfg: newVers})
-	select {
-	case d.updatesChan <- newVers:
-	default:
+// reportRestackError notifies callbacks that re-stacking after an update
+// from a watching source failed. newVers is the configuration returned by
+// restack, which is only non-nil if verification failed.
+func (d *Dials[T]) reportRestackError(ctx context.Context, restackErr error, newVers *T) {
+	d.submitEvent(ctx, &watchErrorEvent[T]{
+		err: restackErr, oldConfig: d.View(), newConfig: newVers,
+	})
+	if newVers != nil && d.params.OnVerifyRejected != nil {
+		d.submitEvent(ctx, &verifyRejectedEvent[T]{rejected: newVers, err: restackErr})
 	}
+}
 
-	// If there's an installed channel, poke it.
-	if watchTab.installed != nil {
-		watchTab.installed <- nil
+// submitOverrides sends any overrides in the composition of sourceValues to
+// the OnOverride callback (if set).
+func (d *Dials[T]) submitOverrides(ctx context.Context, sourceValues []sourceValue) {
+	if d.params.OnOverride == nil {
+		return
 	}
+	if overrides := findOverrides(sourceValues); len(overrides) > 0 {
+		d.submitEvent(ctx, &overrideEvent{overrides: overrides})
+	}
+}
 
-	return newVers
+// install stores newVers as the current version and notifies the Events
+// channel. Only one goroutine may install new versions at a time. (the
+// monitor goroutine, if it's running)
+func (d *Dials[T]) install(newVers *T) {
+	old := d.loadVersioned()
+	oldSerial := CfgSerial[T]{s: old.serial, cfg: old.cfg}
+
+	// We can do a blind-store here because the caller has exclusive
+	// ownership of writes to this atomic-value
+	d.value.Store(&versionedConfig[T]{serial: oldSerial.s + 1, cfg: newVers, replaced: make(chan struct{})})
+	close(old.replaced)
+	d.params.log(context.Background(), LogLevelInfo, "installed new configuration version",
+		"serial", oldSerial.s+1)
+	d.params.metrics().ConfigInstalled(oldSerial.s + 1)
+	evVers := d.params.callbackConfig(newVers)
+	select {
+	case d.updatesChan <- evVers:
+	default:
+	}
 }
 
 func (d *Dials[T]) markSourceDone(
@@ -515,11 +1065,21 @@ func (d *Dials[T]) markSourceDone(
 	return false
 }
 
+// defaultCallbackChannelCapacity is the capacity of the callback channel if
+// Params.CallbackChannelCapacity is unset.
+const defaultCallbackChannelCapacity = 64
+
 func (d *Dials[T]) submitEventBlocking(ctx context.Context, ev userCallbackEvent) bool {
 	// don't panic
 	if d.cbch == nil {
 		return false
 	}
+	d.cbchMu.RLock()
+	defer d.cbchMu.RUnlock()
+	if d.monitorExited() {
+		// cbch is closed (or about to be)
+		return false
+	}
 	select {
 	case <-ctx.Done():
 		return false
@@ -528,19 +1088,51 @@ func (d *Dials[T]) submitEventBlocking(ctx context.Context, ev userCallbackEvent
 	}
 }
 
+// monitorExited indicates whether the monitor goroutine has exited (it
+// returns false if it was never started).
+func (d *Dials[T]) monitorExited() bool {
+	select {
+	case <-d.reload.monDone:
+		return true
+	default:
+		return false
+	}
+}
+
 func (d *Dials[T]) submitEvent(ctx context.Context, ev userCallbackEvent) {
 	// don't panic
 	if d.cbch == nil {
 		return
 	}
+	d.cbchMu.RLock()
+	defer d.cbchMu.RUnlock()
+	if d.monitorExited() {
+		return
+	}
 	select {
 	case <-ctx.Done():
 	case d.cbch <- ev:
 		// never block we'd rather drop callbacks than deadlock the watchers
 	default:
+		dropped := atomic.AddUint64(&d.droppedEvents, 1)
+		d.params.log(ctx, LogLevelWarn, "dropped callback event; callback channel full",
+			"event_type", fmt.Sprintf("%T", ev), "dropped", dropped)
+		if d.params.OnDroppedEvent != nil {
+			d.params.OnDroppedEvent(ctx, dropped)
+		}
 	}
 }
 
+// DroppedCallbackEvents returns the number of events (new configurations,
+// errors and warnings) that were dropped rather than delivered to callbacks
+// because the callback channel was full, generally because a callback was
+// slow to return while sources were updating rapidly. A non-zero value means
+// that some callbacks missed intermediate configurations (although the most
+// recent configuration is always available via View).
+func (d *Dials[T]) DroppedCallbackEvents() uint64 {
+	return atomic.LoadUint64(&d.droppedEvents)
+}
+
 type verifyEnableResp[T any] struct {
 	// only one of error or cfgTok will be returned
 	err error
@@ -551,6 +1143,9 @@ type verifyEnableResp[T any] struct {
 // verifyEnable is the payload type for the monCtl channel used to signal that
 // verification should be enabled.
 type verifyEnable[T any] struct {
+	// ctx is the context passed to EnableVerification (for use by
+	// VerifyContext)
+	ctx context.Context
 	// resp must have capacity 1
 	resp chan<- verifyEnableResp[T]
 }
@@ -580,15 +1175,22 @@ func (d *Dials[T]) EnableVerification(ctx context.Context) (*T, CfgSerial[T], er
 		return cfg, tok, nil
 	} else if d.monCtl == nil {
 		cfg, tok := d.ViewVersion()
-		if vc, ok := any(cfg).(VerifiedConfig); ok {
-			return nil, CfgSerial[T]{}, vc.Verify()
+		warnings, vfErr := verifyRequired(ctx, cfg)
+		if vfErr != nil {
+			return nil, CfgSerial[T]{}, vfErr
+		}
+		d.reload.mu.Lock()
+		d.reload.skipVerify = false
+		d.reload.mu.Unlock()
+		if len(warnings) > 0 && d.params.OnVerifyWarnings != nil {
+			d.params.OnVerifyWarnings(ctx, warnings, d.params.callbackConfig(cfg))
 		}
 		return cfg, tok, nil
 	}
 	// must have capacity 1
 	resp := make(chan verifyEnableResp[T], 1)
 	select {
-	case d.monCtl <- verifyEnable[T]{resp: resp}:
+	case d.monCtl <- verifyEnable[T]{ctx: ctx, resp: resp}:
 	case <-ctx.Done():
 		return nil, CfgSerial[T]{}, fmt.Errorf("context expired while signaling: %w", ctx.Err())
 	}
@@ -602,18 +1204,20 @@ func (d *Dials[T]) EnableVerification(ctx context.Context) (*T, CfgSerial[T], er
 
 }
 
-func (d *Dials[T]) monitorEnableVerify(ve verifyEnable[T]) bool {
+func (d *Dials[T]) monitorEnableVerify(ctx context.Context, ve verifyEnable[T]) bool {
 	vt, serial := d.ViewVersion()
-	if vf, ok := any(vt).(VerifiedConfig); ok {
-		if vfErr := vf.Verify(); vfErr != nil {
-			ve.resp <- verifyEnableResp[T]{
-				err: vfErr,
-				v:   nil,
-				tok: CfgSerial[T]{},
-			}
-
-			return false
+	warnings, vfErr := verifyRequired(ve.ctx, vt)
+	if vfErr != nil {
+		ve.resp <- verifyEnableResp[T]{
+			err: vfErr,
+			v:   nil,
+			tok: CfgSerial[T]{},
 		}
+
+		return false
+	}
+	if len(warnings) > 0 {
+		d.submitEvent(ctx, &verifyWarningsEvent[T]{warnings: warnings, cfg: vt})
 	}
 	ve.resp <- verifyEnableResp[T]{
 		err: nil,
@@ -629,9 +1233,23 @@ func (d *Dials[T]) monitor(
 	sourceValues []sourceValue,
 	watcherChan chan watchStatusUpdate,
 	monCtl <-chan verifyEnable[T],
+	reloadCh <-chan reloadRequest[T],
+	monDone chan<- struct{},
 ) {
-	defer close(d.cbch)
+	defer func() {
+		d.cbchMu.Lock()
+		defer d.cbchMu.Unlock()
+		close(d.cbch)
+	}()
 	skipVerify := d.params.DelayInitialVerification
+	defer func() {
+		// hand the verification state back for any subsequent Reload
+		// calls before signaling that we've exited.
+		d.reload.mu.Lock()
+		defer d.reload.mu.Unlock()
+		d.reload.skipVerify = skipVerify
+		close(monDone)
+	}()
 	for {
 		select {
 		case <-ctx.Done():
@@ -648,10 +1266,14 @@ func (d *Dials[T]) monitor(
 				}
 				continue
 			}
-			skipVerify = !d.monitorEnableVerify(v)
+			skipVerify = !d.monitorEnableVerify(ctx, v)
+		case r := <-reloadCh:
+			d.monitorReload(ctx, t, skipVerify, sourceValues, r)
 		case watchTab := <-watcherChan:
 			switch v := watchTab.(type) {
 			case *valueUpdate:
+				d.params.log(ctx, LogLevelDebug, "received new value from watching source",
+					"source_type", sourceType(v.source))
 				oldConfig, oldSerial := d.ViewVersion()
 				newConfig := d.updateSourceValue(ctx, t, skipVerify, sourceValues, v)
 				if newConfig != nil {
@@ -663,7 +1285,24 @@ func (d *Dials[T]) monitor(
 							d.params.CallGlobalCallbacksAfterVerificationEnabled,
 					})
 				}
+			case *batchValueUpdate:
+				d.params.log(ctx, LogLevelDebug, "received batch of new values from watching sources",
+					"sources", len(v.updates))
+				oldConfig, oldSerial := d.ViewVersion()
+				newConfig := d.updateSourceValues(ctx, t, skipVerify, sourceValues, v)
+				if newConfig != nil {
+					d.submitEvent(ctx, &newConfigEvent[T]{
+						oldConfig: oldConfig,
+						newConfig: newConfig,
+						serial:    oldSerial.s + 1,
+						globalCBsSuppressed: skipVerify &&
+							d.params.CallGlobalCallbacksAfterVerificationEnabled,
+					})
+				}
 			case *watchErrorReport:
+				d.params.log(ctx, LogLevelWarn, "watching source reported an error",
+					"source_type", sourceType(v.source), "error", v.err)
+				d.params.metrics().SourceError(sourceType(v.source))
 				if !skipVerify && !d.params.CallGlobalCallbacksAfterVerificationEnabled {
 					d.submitEvent(ctx, &watchErrorEvent[T]{
 						err: fmt.Errorf("error reported by source of type %T: %w",
@@ -673,6 +1312,8 @@ func (d *Dials[T]) monitor(
 					})
 				}
 			case *watcherDone:
+				d.params.log(ctx, LogLevelDebug, "watching source finished",
+					"source_type", sourceType(v.source))
 				if !d.markSourceDone(ctx, sourceValues, v) {
 					// if there are no watching sources, just exit.
 					return
@@ -695,6 +1336,9 @@ func compose(t interface{}, sources []sourceValue) (interface{}, error) {
 		}
 		o := newOverlayer()
 		sv := o.dc.deepCopyValue(s)
+		// drop any values for fields this source isn't permitted to set
+		// (see NamedSource)
+		clearRestrictedFields(sv, sourceName(source.source))
 		if overlayErr := o.overlayStruct(value, sv); overlayErr != nil {
 			return nil, overlayErr
 		}
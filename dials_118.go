@@ -3,19 +3,33 @@
 package dials
 
 import (
+	"sync"
 	"sync/atomic"
 )
 
 // Dials is the main access point for your configuration.
 type Dials[T any] struct {
+	// droppedEvents counts callback events dropped because the callback
+	// channel was full. It's accessed atomically, and kept first for
+	// 64-bit alignment on 32-bit platforms.
+	droppedEvents uint64
+
 	value       atomic.Value
 	updatesChan chan *T
 	params      Params[T]
 	cbch        chan<- userCallbackEvent
-	monCtl      chan<- verifyEnable[T]
+	// cbchMu is held for reading while sending on cbch, and for writing
+	// while closing it, so events submitted after the monitor goroutine
+	// exits are dropped rather than sent on a closed channel.
+	cbchMu sync.RWMutex
+	monCtl chan<- verifyEnable[T]
+	reload *reloadState[T]
 }
 
 // View returns the configuration struct populated.
+// The returned pointer is shared with dials' internal state (and with other
+// callers of View), so it must not be modified. Map, slice and pointer fields
+// are shared as well; use Snapshot for a fully independent copy.
 func (d *Dials[T]) View() *T {
 	v, _ := d.value.Load().(*versionedConfig[T])
 	// v cannot be nil because we initialize this value immediately after
@@ -30,3 +44,9 @@ func (d *Dials[T]) ViewVersion() (*T, CfgSerial[T]) {
 	// creating the the Dials object
 	return v.cfg, CfgSerial[T]{s: v.serial, cfg: v.cfg}
 }
+
+// loadVersioned returns the current version.
+func (d *Dials[T]) loadVersioned() *versionedConfig[T] {
+	v, _ := d.value.Load().(*versionedConfig[T])
+	return v
+}
@@ -8,6 +8,7 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -39,6 +40,158 @@ func TestFill(t *testing.T) {
 	}
 }
 
+func TestSources(t *testing.T) {
+	t.Parallel()
+	type testConfig struct {
+		Foo string
+	}
+	type ptrifiedConfig struct {
+		Foo *string
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s1 := &fakeSource{outVal: ptrifiedConfig{}}
+	s2 := &fakeWatchingSource{fakeSource: fakeSource{outVal: ptrifiedConfig{}}}
+	d, err := Config(ctx, &testConfig{}, s1, s2)
+	require.NoError(t, err)
+
+	srcs := d.Sources()
+	assert.Equal(t, []Source{s1, s2}, srcs)
+	// make sure we got a copy
+	srcs[0] = nil
+	assert.Equal(t, []Source{s1, s2}, d.Sources())
+
+	noSrcs, err := Config(ctx, &testConfig{})
+	require.NoError(t, err)
+	assert.Empty(t, noSrcs.Sources())
+}
+
+func TestSnapshot(t *testing.T) {
+	t.Parallel()
+	type testConfig struct {
+		Names []string
+		Attrs map[string]int
+		Ptr   *int
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	one := 1
+	d, err := Config(ctx, &testConfig{
+		Names: []string{"a", "b"},
+		Attrs: map[string]int{"x": 1},
+		Ptr:   &one,
+	})
+	require.NoError(t, err)
+
+	snap := d.Snapshot()
+	assert.Equal(t, d.View(), snap)
+	assert.NotSame(t, d.View(), snap)
+
+	snap.Names[0] = "z"
+	snap.Attrs["x"] = 2
+	*snap.Ptr = 3
+	assert.Equal(t, &testConfig{
+		Names: []string{"a", "b"},
+		Attrs: map[string]int{"x": 1},
+		Ptr:   &one,
+	}, d.View())
+	assert.Equal(t, 1, one)
+}
+
+func TestFillVersion(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	foo := "foo"
+	w := fakeWatchingSource{fakeSource: fakeSource{outVal: reloadTestPtrConfig{Foo: &foo}}}
+	d, err := Config(ctx, &reloadTestConfig{Bar: "bar"}, &w)
+	require.NoError(t, err)
+
+	dst := reloadTestConfig{}
+	serial := d.FillVersion(&dst)
+	assert.Equal(t, reloadTestConfig{Foo: "foo", Bar: "bar"}, dst)
+	_, viewSerial := d.ViewVersion()
+	assert.Equal(t, viewSerial, serial)
+
+	baz := "baz"
+	w.send(ctx, reflect.ValueOf(reloadTestPtrConfig{Foo: &baz}))
+	<-d.Events()
+
+	newSerial := d.FillVersion(&dst)
+	assert.Equal(t, reloadTestConfig{Foo: "baz", Bar: "bar"}, dst)
+	assert.Equal(t, serial.s+1, newSerial.s)
+	assert.NotSame(t, d.View(), &dst)
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	foo, bar := "foo", "bar"
+	src := fakeSource{outVal: reloadTestPtrConfig{Foo: &foo}}
+	w := fakeWatchingSource{fakeSource: fakeSource{outVal: reloadTestPtrConfig{Bar: &bar}}}
+	cfg, err := Params[reloadTestConfig]{
+		OnNewConfig: func(ctx context.Context, oldConfig, newConfig *reloadTestConfig) {
+			t.Errorf("unexpected call to OnNewConfig with %+v", newConfig)
+		},
+	}.Load(ctx, &reloadTestConfig{}, &src, &w)
+	require.NoError(t, err)
+	assert.Equal(t, &reloadTestConfig{Foo: "foo", Bar: "bar"}, cfg)
+
+	// the monitor goroutine has exited, so nothing will ever receive a
+	// new value from the watching source.
+	baz := "baz"
+	sendCtx, sendCancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer sendCancel()
+	assert.ErrorIs(t, w.args.ReportNewValue(sendCtx, reflect.ValueOf(reloadTestPtrConfig{Bar: &baz}).Convert(w.t.t)),
+		context.DeadlineExceeded)
+
+	badFoo := "bad"
+	_, loadErr := Load(ctx, &reloadTestConfig{}, &fakeSource{outVal: reloadTestPtrConfig{Foo: &badFoo}})
+	assert.EqualError(t, loadErr, "initial configuration verification failed: bad foo")
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	foo, bar, badFoo := "foo", "bar", "bad"
+	w := fakeWatchingSource{fakeSource: fakeSource{outVal: reloadTestPtrConfig{Bar: &bar}}}
+	require.NoError(t, Validate(ctx, &reloadTestConfig{},
+		&fakeSource{outVal: reloadTestPtrConfig{Foo: &foo}}, &w))
+	// the watching source was read, but not watched
+	assert.Nil(t, w.args)
+
+	// verification isn't skipped, even when Config would skip it
+	err := Params[reloadTestConfig]{
+		SkipInitialVerification:  true,
+		DelayInitialVerification: true,
+	}.Validate(ctx, &reloadTestConfig{Bar: "bar"},
+		&fakeSource{outVal: reloadTestPtrConfig{Foo: &badFoo}})
+	assert.EqualError(t, err, "configuration verification failed: bad foo")
+
+	// later sources still take precedence
+	require.NoError(t, Validate(ctx, &reloadTestConfig{},
+		&fakeSource{outVal: reloadTestPtrConfig{Foo: &badFoo}},
+		&fakeSource{outVal: reloadTestPtrConfig{Foo: &foo}}))
+
+	var warned *warningVerifier
+	require.NoError(t, Params[warningVerifier]{
+		OnVerifyWarnings: func(ctx context.Context, warnings []error, cfg *warningVerifier) {
+			warned = cfg
+		},
+	}.Validate(ctx, &warningVerifier{Valid: true, Foo: "odd"}))
+	assert.NotNil(t, warned)
+
+	assert.EqualError(t, Validate(ctx, &bar), "config type string is a string, not a struct; wrap it in a field of a struct type")
+}
+
 type fakeSource struct {
 	outVal interface{}
 }
@@ -964,3 +1117,548 @@ func ExampleDials_RegisterCallback() {
 	// Output:
 	// Foo: foozle
 }
+
+func TestWaitForVersionAfter(t *testing.T) {
+	t.Parallel()
+	type testConfig struct {
+		Foo string
+	}
+
+	type ptrifiedConfig struct {
+		Foo *string
+	}
+
+	base := testConfig{
+		Foo: "foo",
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	w := fakeWatchingSource{fakeSource: fakeSource{outVal: ptrifiedConfig{}}}
+	d, err := Config(ctx, &base, &w)
+	require.NoError(t, err)
+
+	_, initialSerial := d.ViewVersion()
+
+	// nothing new yet, so this should time out.
+	shortCtx, shortCancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer shortCancel()
+	_, _, waitErr := d.WaitForVersionAfter(shortCtx, initialSerial)
+	require.ErrorIs(t, waitErr, context.DeadlineExceeded)
+
+	type waitResult struct {
+		cfg    *testConfig
+		serial CfgSerial[testConfig]
+		err    error
+	}
+	resCh := make(chan waitResult, 1)
+	go func() {
+		cfg, serial, err := d.WaitForVersionAfter(ctx, initialSerial)
+		resCh <- waitResult{cfg: cfg, serial: serial, err: err}
+	}()
+
+	fimStr := "fim"
+	w.send(ctx, reflect.ValueOf(ptrifiedConfig{Foo: &fimStr}))
+
+	res := <-resCh
+	require.NoError(t, res.err)
+	assert.Equal(t, "fim", res.cfg.Foo)
+	assert.Greater(t, res.serial.s, initialSerial.s)
+
+	// The version we're asking about is already stale, so this should
+	// return immediately.
+	cfg, serial, err := d.WaitForVersionAfter(ctx, initialSerial)
+	require.NoError(t, err)
+	assert.Equal(t, "fim", cfg.Foo)
+	assert.Equal(t, res.serial, serial)
+}
+
+func TestWaitForVersionAfterNoWatchers(t *testing.T) {
+	t.Parallel()
+	type testConfig struct {
+		Foo string
+	}
+
+	type ptrifiedConfig struct {
+		Foo *string
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	src := fakeSource{outVal: ptrifiedConfig{}}
+	d, err := Config(ctx, &testConfig{Foo: "foo"}, &src)
+	require.NoError(t, err)
+
+	_, serial := d.ViewVersion()
+	shortCtx, shortCancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer shortCancel()
+	_, _, waitErr := d.WaitForVersionAfter(shortCtx, serial)
+	require.ErrorIs(t, waitErr, context.DeadlineExceeded)
+
+	// a new version installed by Reload ends the wait
+	type waitResult struct {
+		cfg *testConfig
+		err error
+	}
+	resCh := make(chan waitResult, 1)
+	go func() {
+		cfg, _, err := d.WaitForVersionAfter(ctx, serial)
+		resCh <- waitResult{cfg: cfg, err: err}
+	}()
+
+	fim := "fim"
+	src.outVal = ptrifiedConfig{Foo: &fim}
+	_, reloadErr := d.Reload(ctx)
+	require.NoError(t, reloadErr)
+
+	res := <-resCh
+	require.NoError(t, res.err)
+	assert.Equal(t, "fim", res.cfg.Foo)
+}
+
+func TestWaitForVersionAfterMonitorExited(t *testing.T) {
+	t.Parallel()
+	type testConfig struct {
+		Foo string
+	}
+
+	type ptrifiedConfig struct {
+		Foo *string
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w := fakeWatchingSource{fakeSource: fakeSource{outVal: ptrifiedConfig{}}}
+	d, err := Config(ctx, &testConfig{Foo: "foo"}, &w)
+	require.NoError(t, err)
+
+	cancel()
+	<-d.reload.monDone
+
+	// Reload may still install new versions, so this waits for the
+	// context to expire.
+	_, serial := d.ViewVersion()
+	waitCtx, waitCancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer waitCancel()
+	_, _, waitErr := d.WaitForVersionAfter(waitCtx, serial)
+	require.ErrorIs(t, waitErr, context.DeadlineExceeded)
+
+	// registering callbacks once the monitor has exited is a no-op
+	assert.Nil(t, d.RegisterCallback(context.Background(), serial, func(context.Context, *testConfig, *testConfig) {}))
+}
+
+type warningVerifier struct {
+	Valid bool
+	Foo   string
+}
+
+func (w warningVerifier) VerifyWithWarnings() ([]error, error) {
+	if !w.Valid {
+		return []error{errors.New("ignored")}, errors.New("invalid")
+	}
+	if w.Foo == "" {
+		return nil, nil
+	}
+	return []error{fmt.Errorf("questionable Foo %q", w.Foo)}, nil
+}
+
+var _ VerifiedConfigWithWarnings = (*warningVerifier)(nil)
+
+func TestConfigWithWarningVerifier(t *testing.T) {
+	t.Parallel()
+
+	type ptrifiedConfig struct {
+		Valid *bool
+		Foo   *string
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	type warnEvent struct {
+		warnings []error
+		cfg      *warningVerifier
+	}
+	warnCh := make(chan warnEvent, 3)
+	errCh := make(chan error, 1)
+	p := Params[warningVerifier]{
+		OnVerifyWarnings: func(ctx context.Context, warnings []error, cfg *warningVerifier) {
+			warnCh <- warnEvent{warnings: warnings, cfg: cfg}
+		},
+		OnWatchedError: func(ctx context.Context, err error, oldConfig, newConfig *warningVerifier) {
+			errCh <- err
+		},
+	}
+
+	fooStr := "foo"
+	w := fakeWatchingSource{fakeSource: fakeSource{outVal: ptrifiedConfig{Foo: &fooStr}}}
+	d, err := p.Config(ctx, &warningVerifier{Valid: true}, &w)
+	require.NoError(t, err)
+	assert.Equal(t, "foo", d.View().Foo)
+
+	// initial warnings are delivered synchronously
+	require.Len(t, warnCh, 1)
+	initWarn := <-warnCh
+	assert.Equal(t, []error{errors.New(`questionable Foo "foo"`)}, initWarn.warnings)
+	assert.Same(t, d.View(), initWarn.cfg)
+
+	// warnings don't block installation of new versions
+	barStr := "bar"
+	w.send(ctx, reflect.ValueOf(ptrifiedConfig{Foo: &barStr}))
+	newCfg := <-d.Events()
+	assert.Equal(t, "bar", newCfg.Foo)
+	barWarn := <-warnCh
+	assert.Equal(t, []error{errors.New(`questionable Foo "bar"`)}, barWarn.warnings)
+	assert.Same(t, newCfg, barWarn.cfg)
+
+	// errors still do
+	invalid := false
+	w.send(ctx, reflect.ValueOf(ptrifiedConfig{Valid: &invalid, Foo: &barStr}))
+	assert.EqualError(t, <-errCh, "invalid")
+	assert.Same(t, newCfg, d.View())
+	assert.Empty(t, warnCh)
+}
+
+type ctxVerifierKey struct{}
+
+// ctxVerifier fails verification unless the context carries a
+// ctxVerifierKey value matching Want.
+type ctxVerifier struct {
+	Want string
+}
+
+func (c ctxVerifier) Verify() error {
+	return errors.New("Verify called instead of VerifyContext")
+}
+
+func (c ctxVerifier) VerifyContext(ctx context.Context) error {
+	if got, _ := ctx.Value(ctxVerifierKey{}).(string); got != c.Want {
+		return fmt.Errorf("context value %q doesn't match %q", got, c.Want)
+	}
+	return ctx.Err()
+}
+
+var _ VerifiedConfigCtx = (*ctxVerifier)(nil)
+
+func TestConfigWithCtxVerifier(t *testing.T) {
+	t.Parallel()
+
+	type ptrifiedConfig struct {
+		Want *string
+	}
+
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), ctxVerifierKey{}, "foo"))
+	defer cancel()
+
+	_, badErr := Config(ctx, &ctxVerifier{Want: "bar"})
+	assert.EqualError(t, badErr, `initial configuration verification failed: context value "foo" doesn't match "bar"`)
+
+	errCh := make(chan error, 1)
+	w := fakeWatchingSource{fakeSource: fakeSource{outVal: ptrifiedConfig{}}}
+	d, err := Params[ctxVerifier]{
+		OnWatchedError: func(ctx context.Context, err error, oldConfig, newConfig *ctxVerifier) {
+			errCh <- err
+		},
+	}.Config(ctx, &ctxVerifier{Want: "foo"}, &w)
+	require.NoError(t, err)
+
+	// updates are verified with the context passed to Config
+	barStr := "bar"
+	w.send(ctx, reflect.ValueOf(ptrifiedConfig{Want: &barStr}))
+	assert.EqualError(t, <-errCh, `context value "foo" doesn't match "bar"`)
+	assert.Equal(t, "foo", d.View().Want)
+
+	// delayed verification uses the context passed to EnableVerification
+	delayed, delayErr := Params[ctxVerifier]{DelayInitialVerification: true}.Config(
+		ctx, &ctxVerifier{Want: "baz"}, &fakeWatchingSource{fakeSource: fakeSource{outVal: ptrifiedConfig{}}})
+	require.NoError(t, delayErr)
+	_, _, enableErr := delayed.EnableVerification(ctx)
+	assert.EqualError(t, enableErr, `context value "foo" doesn't match "baz"`)
+	cfg, _, enableErr := delayed.EnableVerification(context.WithValue(ctx, ctxVerifierKey{}, "baz"))
+	require.NoError(t, enableErr)
+	assert.Equal(t, "baz", cfg.Want)
+}
+
+func TestCallbackChannelCapacity(t *testing.T) {
+	t.Parallel()
+	const burst = 200
+
+	// countDelivered sends a burst of new values while OnNewConfig is
+	// blocked, and returns the number of calls to OnNewConfig, the number
+	// of dropped events and the values passed to OnDroppedEvent.
+	countDelivered := func(t testing.TB, capacity int) (int, uint64, []uint64) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		w := fakeWatchingSource{fakeSource: fakeSource{outVal: reloadTestPtrConfig{}}}
+		release := make(chan struct{})
+		var mu sync.Mutex
+		delivered := 0
+		droppedCBs := []uint64{}
+		d, err := Params[reloadTestConfig]{
+			CallbackChannelCapacity: capacity,
+			OnDroppedEvent: func(ctx context.Context, totalDropped uint64) {
+				// called from the monitor goroutine
+				mu.Lock()
+				defer mu.Unlock()
+				droppedCBs = append(droppedCBs, totalDropped)
+			},
+			OnNewConfig: func(ctx context.Context, oldConfig, newConfig *reloadTestConfig) {
+				<-release
+				mu.Lock()
+				defer mu.Unlock()
+				delivered++
+			},
+		}.Config(ctx, &reloadTestConfig{}, &w)
+		require.NoError(t, err)
+		_, initSerial := d.ViewVersion()
+
+		for i := 0; i < burst; i++ {
+			bar := strconv.Itoa(i)
+			w.send(ctx, reflect.ValueOf(reloadTestPtrConfig{Bar: &bar}))
+		}
+		// Reload is handled by the monitor goroutine after all the
+		// values sent above.
+		_, reloadErr := d.Reload(ctx)
+		require.NoError(t, reloadErr)
+		close(release)
+
+		// A callback registered with a stale serial is called as soon as
+		// the callback goroutine has processed everything queued ahead
+		// of it.
+		caughtUp := make(chan struct{})
+		var once sync.Once
+		d.RegisterCallback(ctx, initSerial, func(ctx context.Context, oldCfg, newCfg *reloadTestConfig) {
+			once.Do(func() { close(caughtUp) })
+		})
+		<-caughtUp
+
+		mu.Lock()
+		defer mu.Unlock()
+		return delivered, d.DroppedCallbackEvents(), droppedCBs
+	}
+
+	delivered, dropped, droppedCBs := countDelivered(t, 0)
+	assert.Less(t, delivered, burst, "expected drops with the default capacity")
+	assert.Equal(t, uint64(burst-delivered), dropped)
+	require.NotEmpty(t, droppedCBs)
+	assert.Equal(t, dropped, droppedCBs[len(droppedCBs)-1])
+	assert.Len(t, droppedCBs, int(dropped))
+
+	delivered, dropped, droppedCBs = countDelivered(t, 2*burst)
+	assert.Equal(t, burst, delivered)
+	assert.Zero(t, dropped)
+	assert.Empty(t, droppedCBs)
+
+	_, err := Params[reloadTestConfig]{CallbackChannelCapacity: -1}.Config(
+		context.Background(), &reloadTestConfig{})
+	assert.EqualError(t, err, "negative CallbackChannelCapacity -1")
+}
+
+type pathsLogging struct {
+	Level  string
+	Format string `dials:"fmt"`
+}
+
+type pathsConfig struct {
+	Logging pathsLogging
+	Port    int
+}
+
+type ptrPathsConfig struct {
+	Logging *struct {
+		Level  *string
+		Format *string `dials:"fmt"`
+	}
+	Port *int
+}
+
+func TestRegisterCallbackForPaths(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	w := fakeWatchingSource{fakeSource: fakeSource{outVal: ptrPathsConfig{}}}
+	d, err := Config(ctx, &pathsConfig{Logging: pathsLogging{Level: "info"}}, &w)
+	require.NoError(t, err)
+	_, serial := d.ViewVersion()
+
+	called := make(chan string, 10)
+	register := func(name string, paths ...string) {
+		require.NotNil(t, d.RegisterCallbackForPaths(ctx, serial, paths,
+			func(ctx context.Context, oldCfg, newCfg *pathsConfig) { called <- name }))
+	}
+	register("logging", "Logging")
+	register("format", "Logging.fmt")
+	register("port", "Port")
+	register("format_or_port", "Logging.fmt", "Port")
+	register("unknown", "Logging.Format", "Nope")
+	// callbacks are called in order of registration, so this one signals
+	// that all the others have had their chance
+	require.NotNil(t, d.RegisterCallback(ctx, serial,
+		func(ctx context.Context, oldCfg, newCfg *pathsConfig) { called <- "all" }))
+
+	calledFor := func(val ptrPathsConfig) []string {
+		w.send(ctx, reflect.ValueOf(val))
+		names := []string{}
+		for n := range called {
+			if n == "all" {
+				return names
+			}
+			names = append(names, n)
+		}
+		return names
+	}
+
+	port, debug, json := 8080, "debug", "json"
+	logging := func(level, format *string) *struct {
+		Level  *string
+		Format *string `dials:"fmt"`
+	} {
+		return &struct {
+			Level  *string
+			Format *string `dials:"fmt"`
+		}{Level: level, Format: format}
+	}
+
+	assert.Equal(t, []string{"port", "format_or_port"}, calledFor(ptrPathsConfig{Port: &port}))
+	assert.Equal(t, []string{"logging", "port", "format_or_port"},
+		calledFor(ptrPathsConfig{Logging: logging(&debug, nil)}))
+	assert.Equal(t, []string{"logging", "format", "format_or_port"},
+		calledFor(ptrPathsConfig{Logging: logging(&debug, &json)}))
+	// nothing changed, so only the unfiltered callback is called
+	assert.Empty(t, calledFor(ptrPathsConfig{Logging: logging(&debug, &json)}))
+}
+
+func TestEventsChannelCapacity(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const versions = 5
+	for _, tbl := range []struct {
+		capacity int
+		expected []string
+	}{
+		// only the first new version fits with the default capacity
+		{capacity: 0, expected: []string{"0"}},
+		{capacity: versions, expected: []string{"0", "1", "2", "3", "4"}},
+	} {
+		w := fakeWatchingSource{fakeSource: fakeSource{outVal: reloadTestPtrConfig{}}}
+		d, err := Params[reloadTestConfig]{EventsChannelCapacity: tbl.capacity}.Config(
+			ctx, &reloadTestConfig{}, &w)
+		require.NoError(t, err)
+
+		for i := 0; i < versions; i++ {
+			bar := strconv.Itoa(i)
+			w.send(ctx, reflect.ValueOf(reloadTestPtrConfig{Bar: &bar}))
+		}
+		// Reload is handled by the monitor goroutine after all the
+		// values sent above.
+		_, reloadErr := d.Reload(ctx)
+		require.NoError(t, reloadErr)
+
+		received := []string{}
+		for len(d.Events()) > 0 {
+			received = append(received, (<-d.Events()).Bar)
+		}
+		assert.Equal(t, tbl.expected, received, "capacity %d", tbl.capacity)
+	}
+
+	_, err := Params[reloadTestConfig]{EventsChannelCapacity: -1}.Config(ctx, &reloadTestConfig{})
+	assert.EqualError(t, err, "negative EventsChannelCapacity -1")
+}
+
+// slowVerifier takes Delay to verify, ignoring any cancelation of its
+// context unless Ctx is set.
+type slowVerifier struct {
+	Delay time.Duration
+	Ctx   bool
+}
+
+func (s *slowVerifier) VerifyContext(ctx context.Context) error {
+	if !s.Ctx {
+		time.Sleep(s.Delay)
+		return nil
+	}
+	select {
+	case <-time.After(s.Delay):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func TestInitialVerifyTimeout(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	p := Params[slowVerifier]{InitialVerifyTimeout: 10 * time.Millisecond}
+	d, err := p.Config(ctx, &slowVerifier{})
+	require.NoError(t, err)
+	assert.Equal(t, &slowVerifier{}, d.View())
+
+	for _, cfg := range []slowVerifier{{Delay: time.Hour, Ctx: true}, {Delay: time.Second}} {
+		start := time.Now()
+		_, err = p.Config(ctx, &cfg)
+		assert.EqualError(t, err,
+			"initial configuration verification failed: verification did not complete within 10ms: context deadline exceeded")
+		assert.Less(t, time.Since(start), cfg.Delay)
+	}
+
+	// no timeout by default
+	d, err = Params[slowVerifier]{}.Config(ctx, &slowVerifier{Delay: 20 * time.Millisecond})
+	require.NoError(t, err)
+	assert.Equal(t, 20*time.Millisecond, d.View().Delay)
+}
+
+func TestConfigNonStruct(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	services := map[string]reloadTestConfig{}
+	_, mapErr := Config(ctx, &services, &fakeSource{outVal: map[string]reloadTestConfig{}})
+	assert.EqualError(t, mapErr, "config type map[string]dials.reloadTestConfig is a map, not a struct; wrap it in a field of a struct type")
+
+	hosts := []string{}
+	_, sliceErr := Config(ctx, &hosts)
+	assert.EqualError(t, sliceErr, "config type []string is a slice, not a struct; wrap it in a field of a struct type")
+}
+
+func TestOnVerifyRejected(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	type rejection struct {
+		rejected *reloadTestConfig
+		err      error
+	}
+	rejections := make(chan rejection, 1)
+	watchErrs := make(chan error, 2)
+	w := fakeWatchingSource{fakeSource: fakeSource{outVal: reloadTestPtrConfig{}}}
+	d, err := Params[reloadTestConfig]{
+		OnVerifyRejected: func(ctx context.Context, rejected *reloadTestConfig, err error) {
+			rejections <- rejection{rejected: rejected, err: err}
+		},
+		OnWatchedError: func(ctx context.Context, err error, oldConfig, newConfig *reloadTestConfig) {
+			watchErrs <- err
+		},
+	}.Config(ctx, &reloadTestConfig{Foo: "good"}, &w)
+	require.NoError(t, err)
+
+	bad, bar := "bad", "bar"
+	w.send(ctx, reflect.ValueOf(reloadTestPtrConfig{Foo: &bad, Bar: &bar}))
+	r := <-rejections
+	assert.Equal(t, &reloadTestConfig{Foo: "bad", Bar: "bar"}, r.rejected)
+	assert.EqualError(t, r.err, "bad foo")
+	// OnWatchedError is still called
+	assert.EqualError(t, <-watchErrs, "bad foo")
+	assert.Equal(t, &reloadTestConfig{Foo: "good"}, d.View())
+
+	// errors that aren't verification failures aren't rejections
+	w.args.ReportError(ctx, errors.New("fimbat"))
+	assert.EqualError(t, <-watchErrs, "error reported by source of type *dials.fakeWatchingSource: fimbat")
+	assert.Empty(t, rejections)
+}
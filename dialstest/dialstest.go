@@ -0,0 +1,198 @@
+// Package dialstest provides Sources for use in tests of code using dials
+// (e.g. Verify methods and OnNewConfig callbacks), allowing tests to
+// control exactly which values Dials sees, and when.
+package dialstest
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+
+	"github.com/vimeo/dials"
+	"github.com/vimeo/dials/sources/static"
+)
+
+// Option configures a StaticSource or ControllableWatcher.
+type Option func(*options)
+
+type options struct {
+	explicitZeros bool
+}
+
+// ExplicitZeros configures a StaticSource or ControllableWatcher to set
+// every field of the values passed to it, including those with zero values,
+// so e.g. a bool may be set back to false. (nil pointers, slices, maps and
+// interfaces are still treated as unset; see static.NewExplicitValueSource)
+func ExplicitZeros() Option {
+	return func(o *options) {
+		o.explicitZeros = true
+	}
+}
+
+func (o *options) newValueSource(v any) (*static.ValueSource, error) {
+	if o.explicitZeros {
+		return static.NewExplicitValueSource(v)
+	}
+	return static.NewValueSource(v)
+}
+
+// StaticSource is a non-watching dials.Source providing a value of the
+// config type T. The value may be replaced with Set, to be picked up by
+// the next call to Dials.Reload.
+//
+// As with static.ValueSource, fields with their zero values are treated as
+// unset, and don't override values from earlier Sources (or the defaults
+// passed to dials.Config), unless the ExplicitZeros option is used.
+type StaticSource[T any] struct {
+	opts options
+
+	mu  sync.Mutex
+	val *static.ValueSource
+}
+
+var _ dials.Source = (*StaticSource[struct{}])(nil)
+
+// NewStaticSource constructs a StaticSource providing v, which must point
+// to a struct. v is deep-copied, so it may be freely modified after
+// NewStaticSource returns.
+func NewStaticSource[T any](v *T, opts ...Option) (*StaticSource[T], error) {
+	s := &StaticSource[T]{}
+	for _, opt := range opts {
+		opt(&s.opts)
+	}
+	vs, err := s.opts.newValueSource(v)
+	if err != nil {
+		return nil, err
+	}
+	s.val = vs
+	return s, nil
+}
+
+// Set replaces the value returned by future calls to Value. v is
+// deep-copied.
+func (s *StaticSource[T]) Set(v *T) error {
+	vs, err := s.opts.newValueSource(v)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.val = vs
+	return nil
+}
+
+// Value implements dials.Source
+func (s *StaticSource[T]) Value(ctx context.Context, t *dials.Type) (reflect.Value, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.val.Value(ctx, t)
+}
+
+// ControllableWatcher is a watching dials.Source whose new values are
+// supplied by calling Push (rather than from some external system), so
+// tests can deterministically exercise Verify methods and callbacks as the
+// configuration changes.
+//
+// As with StaticSource, fields with their zero values are treated as unset,
+// unless the ExplicitZeros option is used.
+type ControllableWatcher[T any] struct {
+	// src holds the most recently installed value
+	src StaticSource[T]
+
+	watchMu sync.Mutex
+	t       *dials.Type
+	args    dials.WatchArgs
+}
+
+var _ dials.Source = (*ControllableWatcher[struct{}])(nil)
+var _ dials.Watcher = (*ControllableWatcher[struct{}])(nil)
+
+// NewControllableWatcher constructs a ControllableWatcher with the initial
+// value v, which must point to a struct.
+func NewControllableWatcher[T any](v *T, opts ...Option) (*ControllableWatcher[T], error) {
+	c := &ControllableWatcher[T]{}
+	for _, opt := range opts {
+		opt(&c.src.opts)
+	}
+	vs, err := c.src.opts.newValueSource(v)
+	if err != nil {
+		return nil, err
+	}
+	c.src.val = vs
+	return c, nil
+}
+
+// Value implements dials.Source, returning the most recent value passed to
+// Push (or NewControllableWatcher) that was installed.
+func (c *ControllableWatcher[T]) Value(ctx context.Context, t *dials.Type) (reflect.Value, error) {
+	return c.src.Value(ctx, t)
+}
+
+// Watch implements dials.Watcher, retaining args for Push, ReportError and
+// Done.
+func (c *ControllableWatcher[T]) Watch(_ context.Context, t *dials.Type, args dials.WatchArgs) error {
+	c.watchMu.Lock()
+	defer c.watchMu.Unlock()
+	c.t = t
+	c.args = args
+	return nil
+}
+
+func (c *ControllableWatcher[T]) watchArgs() (*dials.Type, dials.WatchArgs, error) {
+	c.watchMu.Lock()
+	defer c.watchMu.Unlock()
+	if c.args == nil {
+		return nil, nil, errors.New("Watch has not been called (was this source passed to dials.Config?)")
+	}
+	return c.t, c.args, nil
+}
+
+// Push reports v as the source's new value, and blocks until Dials has
+// either installed the resulting configuration (calling any callbacks
+// asynchronously), or rejected it, in which case the error (e.g. from a
+// Verify method) is returned. v is deep-copied.
+//
+// If the new value is installed, it also replaces the value returned by
+// Value.
+func (c *ControllableWatcher[T]) Push(ctx context.Context, v *T) error {
+	t, args, err := c.watchArgs()
+	if err != nil {
+		return err
+	}
+	vs, err := c.src.opts.newValueSource(v)
+	if err != nil {
+		return err
+	}
+	val, err := vs.Value(ctx, t)
+	if err != nil {
+		return err
+	}
+	if err := args.BlockingReportNewValue(ctx, val); err != nil {
+		return err
+	}
+	c.src.mu.Lock()
+	defer c.src.mu.Unlock()
+	c.src.val = vs
+	return nil
+}
+
+// ReportError reports err as an error from this source, which is passed
+// to the OnWatchedError callback (see dials.Params).
+func (c *ControllableWatcher[T]) ReportError(ctx context.Context, err error) error {
+	_, args, argsErr := c.watchArgs()
+	if argsErr != nil {
+		return argsErr
+	}
+	return args.ReportError(ctx, err)
+}
+
+// Done indicates that this source won't report any more values.
+func (c *ControllableWatcher[T]) Done(ctx context.Context) error {
+	_, args, err := c.watchArgs()
+	if err != nil {
+		return err
+	}
+	args.Done(ctx)
+	return nil
+}
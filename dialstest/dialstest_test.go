@@ -0,0 +1,119 @@
+package dialstest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vimeo/dials"
+)
+
+type testConfig struct {
+	Name string
+	Port int
+}
+
+func (c *testConfig) Verify() error {
+	if c.Port < 0 {
+		return errors.New("negative port")
+	}
+	return nil
+}
+
+func TestStaticSource(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	src, err := NewStaticSource(&testConfig{Name: "svc"})
+	require.NoError(t, err)
+	d, err := dials.Config(ctx, &testConfig{Port: 80}, src)
+	require.NoError(t, err)
+	assert.Equal(t, &testConfig{Name: "svc", Port: 80}, d.View())
+
+	require.NoError(t, src.Set(&testConfig{Port: 8080}))
+	cfg, err := d.Reload(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &testConfig{Port: 8080}, cfg)
+
+	require.NoError(t, src.Set(&testConfig{Port: -1}))
+	_, err = d.Reload(ctx)
+	assert.EqualError(t, err, "failed to reload configuration: negative port")
+
+	var nilCfg *testConfig
+	_, err = NewStaticSource(nilCfg)
+	assert.Error(t, err)
+}
+
+func TestControllableWatcher(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	w, err := NewControllableWatcher(&testConfig{Name: "svc"})
+	require.NoError(t, err)
+	assert.EqualError(t, w.Push(ctx, &testConfig{}),
+		"Watch has not been called (was this source passed to dials.Config?)")
+
+	newConfigs := make(chan *testConfig, 3)
+	watchErrs := make(chan error, 1)
+	d, err := dials.Params[testConfig]{
+		OnNewConfig: func(ctx context.Context, oldConfig, newConfig *testConfig) {
+			newConfigs <- newConfig
+		},
+		OnWatchedError: func(ctx context.Context, err error, oldConfig, newConfig *testConfig) {
+			watchErrs <- err
+		},
+	}.Config(ctx, &testConfig{Port: 80}, w)
+	require.NoError(t, err)
+	assert.Equal(t, &testConfig{Name: "svc", Port: 80}, d.View())
+
+	require.NoError(t, w.Push(ctx, &testConfig{Name: "svc2"}))
+	// the push is installed by the time Push returns
+	assert.Equal(t, &testConfig{Name: "svc2", Port: 80}, d.View())
+	assert.Equal(t, &testConfig{Name: "svc2", Port: 80}, <-newConfigs)
+
+	assert.ErrorContains(t, w.Push(ctx, &testConfig{Port: -1}), "negative port")
+	assert.Equal(t, &testConfig{Name: "svc2", Port: 80}, d.View())
+	assert.EqualError(t, <-watchErrs, "negative port")
+
+	require.NoError(t, w.ReportError(ctx, errors.New("connection lost")))
+	assert.ErrorContains(t, <-watchErrs, "connection lost")
+	require.NoError(t, w.Done(ctx))
+}
+
+func TestExplicitZeros(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	type toggleConfig struct {
+		Enabled bool
+		Port    int
+	}
+
+	w, err := NewControllableWatcher(&toggleConfig{Enabled: true, Port: 80}, ExplicitZeros())
+	require.NoError(t, err)
+	d, err := dials.Config(ctx, &toggleConfig{Port: 443}, w)
+	require.NoError(t, err)
+	assert.Equal(t, &toggleConfig{Enabled: true, Port: 80}, d.View())
+
+	// false and 0 override the previous values
+	require.NoError(t, w.Push(ctx, &toggleConfig{}))
+	assert.Equal(t, &toggleConfig{}, d.View())
+
+	require.NoError(t, w.Push(ctx, &toggleConfig{Enabled: true}))
+	assert.Equal(t, &toggleConfig{Enabled: true}, d.View())
+
+	src, err := NewStaticSource(&toggleConfig{Enabled: true}, ExplicitZeros())
+	require.NoError(t, err)
+	sd, err := dials.Config(ctx, &toggleConfig{Port: 443}, src)
+	require.NoError(t, err)
+	assert.Equal(t, &toggleConfig{Enabled: true}, sd.View())
+
+	require.NoError(t, src.Set(&toggleConfig{Port: 8080}))
+	cfg, err := sd.Reload(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &toggleConfig{Port: 8080}, cfg)
+}
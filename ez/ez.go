@@ -9,7 +9,10 @@ import (
 	"github.com/vimeo/dials"
 	"github.com/vimeo/dials/common"
 	"github.com/vimeo/dials/decoders/cue"
+	"github.com/vimeo/dials/decoders/gzip"
+	"github.com/vimeo/dials/decoders/hcl"
 	"github.com/vimeo/dials/decoders/json"
+	"github.com/vimeo/dials/decoders/properties"
 	"github.com/vimeo/dials/decoders/toml"
 	"github.com/vimeo/dials/decoders/yaml"
 	"github.com/vimeo/dials/sources/env"
@@ -86,7 +89,8 @@ type Params[T any] struct {
 	// FlattenAnonymousFields inserts the AnonymousFlattenMangler into the
 	// chain so decoders that do not handle anonymous fields never see such
 	// things.
-	// (Currently only affects the yaml decoder)
+	// (Affects the JSON, YAML, TOML and Cue decoders constructed by this
+	// package)
 	FlattenAnonymousFields bool
 }
 
@@ -280,19 +284,19 @@ func YAMLConfigEnvFlag[T any, TP ConfigWithConfigPath[T]](ctx context.Context, c
 // JSONConfigEnvFlag takes advantage of the ConfigWithConfigPath cfg, thinly
 // wraping ConfigFileEnvFlag with the decoder statically set to JSON.
 func JSONConfigEnvFlag[T any, TP ConfigWithConfigPath[T]](ctx context.Context, cfg TP, params Params[T]) (*dials.Dials[T], error) {
-	return ConfigFileEnvFlag(ctx, cfg, func(string) dials.Decoder { return &json.Decoder{} }, params)
+	return ConfigFileEnvFlag(ctx, cfg, func(string) dials.Decoder { return &json.Decoder{FlattenAnonymous: params.FlattenAnonymousFields} }, params)
 }
 
 // CueConfigEnvFlag takes advantage of the ConfigWithConfigPath cfg, thinly
 // wraping ConfigFileEnvFlag with the decoder statically set to Cue.
 func CueConfigEnvFlag[T any, TP ConfigWithConfigPath[T]](ctx context.Context, cfg TP, params Params[T]) (*dials.Dials[T], error) {
-	return ConfigFileEnvFlag(ctx, cfg, func(string) dials.Decoder { return &cue.Decoder{} }, params)
+	return ConfigFileEnvFlag(ctx, cfg, func(string) dials.Decoder { return &cue.Decoder{FlattenAnonymous: params.FlattenAnonymousFields} }, params)
 }
 
 // TOMLConfigEnvFlag takes advantage of the ConfigWithConfigPath cfg, thinly
 // wraping ConfigFileEnvFlag with the decoder statically set to TOML.
 func TOMLConfigEnvFlag[T any, TP ConfigWithConfigPath[T]](ctx context.Context, cfg TP, params Params[T]) (*dials.Dials[T], error) {
-	return ConfigFileEnvFlag(ctx, cfg, func(string) dials.Decoder { return &toml.Decoder{} }, params)
+	return ConfigFileEnvFlag(ctx, cfg, func(string) dials.Decoder { return &toml.Decoder{FlattenAnonymous: params.FlattenAnonymousFields} }, params)
 }
 
 // DecoderFromExtension is a DecoderFactory that returns an appropriate decoder
@@ -305,17 +309,31 @@ func DecoderFromExtension(path string) dials.Decoder {
 // DecoderFromExtension is a DecoderFactory that returns an appropriate decoder
 // based on the extension of the filename or nil if there is not an appropriate
 // mapping.
+// Gzip-compressed files with a trailing ".gz" extension (e.g.
+// "config.yaml.gz") are decompressed before being decoded based on the
+// preceding extension.
 func DecoderFromExtensionWithParams[T any](path string, p Params[T]) dials.Decoder {
+	if trimmed, ok := gzip.TrimExtension(path); ok {
+		inner := DecoderFromExtensionWithParams(trimmed, p)
+		if inner == nil {
+			return nil
+		}
+		return gzip.New(inner)
+	}
 	ext := filepath.Ext(path)
 	switch strings.ToLower(ext) {
 	case ".yaml", ".yml":
 		return &yaml.Decoder{FlattenAnonymous: p.FlattenAnonymousFields}
 	case ".json":
-		return &json.Decoder{}
+		return &json.Decoder{FlattenAnonymous: p.FlattenAnonymousFields}
 	case ".toml":
-		return &toml.Decoder{}
+		return &toml.Decoder{FlattenAnonymous: p.FlattenAnonymousFields}
 	case ".cue":
-		return &cue.Decoder{}
+		return &cue.Decoder{FlattenAnonymous: p.FlattenAnonymousFields}
+	case properties.Extension:
+		return &properties.Decoder{}
+	case hcl.Extension:
+		return &hcl.Decoder{}
 	default:
 		return nil
 	}
@@ -325,7 +343,7 @@ func DecoderFromExtensionWithParams[T any](path string, p Params[T]) dials.Decod
 // ConfigWithConfigPath cfg and thinly wraps ConfigFileEnvFlag and and thinly
 // wraps ConfigFileEnvFlag choosing the dials.Decoder used when handling the
 // file contents based on the file extension (from the limited set of JSON,
-// Cue, YAML and TOML).
+// Cue, YAML, TOML, HCL and Java-style properties).
 func FileExtensionDecoderConfigEnvFlag[T any, TP ConfigWithConfigPath[T]](ctx context.Context, cfg TP, params Params[T]) (*dials.Dials[T], error) {
 	return ConfigFileEnvFlagDecoderFactoryParams(ctx, cfg, DecoderFromExtensionWithParams[T], params)
 }
@@ -12,6 +12,14 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/vimeo/dials"
+	"github.com/vimeo/dials/decoders/cue"
+	"github.com/vimeo/dials/decoders/gzip"
+	"github.com/vimeo/dials/decoders/hcl"
+	dialsjson "github.com/vimeo/dials/decoders/json"
+	"github.com/vimeo/dials/decoders/properties"
+	"github.com/vimeo/dials/decoders/toml"
+	"github.com/vimeo/dials/decoders/yaml"
 	"github.com/vimeo/dials/tagformat/caseconversion"
 )
 
@@ -281,3 +289,37 @@ func TestJSONConfigEnvFlagWithNewConfigCallback(t *testing.T) {
 	assert.EqualValues(t, expectedFinalConfig, *finalViewEventCfg)
 	assert.EqualValues(t, expectedFinalConfig, *view.View())
 }
+
+func TestDecoderFromExtensionGzip(t *testing.T) {
+	t.Parallel()
+	for _, tbl := range []struct {
+		path   string
+		expect dials.Decoder
+	}{
+		{path: "fim.yaml.gz", expect: gzip.New(&yaml.Decoder{})},
+		{path: "fim.JSON.gz", expect: gzip.New(&dialsjson.Decoder{})},
+		{path: "fim.toml", expect: &toml.Decoder{}},
+		{path: "fim.properties.gz", expect: gzip.New(&properties.Decoder{})},
+		{path: "fim.hcl.gz", expect: gzip.New(&hcl.Decoder{})},
+		{path: "fim.txt.gz", expect: nil},
+		{path: "fim.gz", expect: nil},
+	} {
+		assert.Equal(t, tbl.expect, DecoderFromExtension(tbl.path), tbl.path)
+	}
+}
+
+func TestDecoderFromExtensionFlattenAnonymous(t *testing.T) {
+	t.Parallel()
+	p := Params[config]{FlattenAnonymousFields: true}
+	for _, tbl := range []struct {
+		path   string
+		expect dials.Decoder
+	}{
+		{path: "fim.yaml", expect: &yaml.Decoder{FlattenAnonymous: true}},
+		{path: "fim.json", expect: &dialsjson.Decoder{FlattenAnonymous: true}},
+		{path: "fim.toml", expect: &toml.Decoder{FlattenAnonymous: true}},
+		{path: "fim.cue", expect: &cue.Decoder{FlattenAnonymous: true}},
+	} {
+		assert.Equal(t, tbl.expect, DecoderFromExtensionWithParams(tbl.path, p), tbl.path)
+	}
+}
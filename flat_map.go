@@ -0,0 +1,341 @@
+package dials
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vimeo/dials/common"
+	"github.com/vimeo/dials/parse"
+	"github.com/vimeo/dials/ptrify"
+)
+
+// FlatMapKeySeparator separates the components of the dotted-path keys used
+// by ToFlatMap and FromFlatMap.
+const FlatMapKeySeparator = "."
+
+var (
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	durationType        = reflect.TypeOf(time.Duration(0))
+	emptyStructType     = reflect.TypeOf(struct{}{})
+)
+
+// flatField ties a dotted-path key to the index-path of the field it
+// addresses.
+type flatField struct {
+	key string
+	idx []int
+}
+
+// flatMapFields walks the struct-type t, returning the leaf fields with their
+// keys. Each key component is the field's `dials` tag if set, or its name
+// otherwise.  Embedded structs without a `dials` tag don't contribute a
+// component.
+func flatMapFields(t reflect.Type, prefix []string, idx []int, out []flatField) []flatField {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if ptrify.OmitField(sf) {
+			continue
+		}
+		switch sf.Type.Kind() {
+		case reflect.Chan, reflect.Func, reflect.Interface:
+			// not something we can represent as a string
+			continue
+		default:
+		}
+		name, tagged := sf.Tag.Lookup(common.DialsTagName)
+		if !tagged || name == "" {
+			name = sf.Name
+		}
+		fieldPrefix := append(prefix[:len(prefix):len(prefix)], name)
+		fieldIdx := append(idx[:len(idx):len(idx)], i)
+
+		st := sf.Type
+		if st.Kind() == reflect.Ptr {
+			st = st.Elem()
+		}
+		if st.Kind() == reflect.Struct && !ptrify.IsTextUnmarshalerStruct(st) {
+			if sf.Anonymous && !tagged {
+				fieldPrefix = prefix
+			}
+			out = flatMapFields(st, fieldPrefix, fieldIdx, out)
+			continue
+		}
+		out = append(out, flatField{key: strings.Join(fieldPrefix, FlatMapKeySeparator), idx: fieldIdx})
+	}
+	return out
+}
+
+// matchFlatFields returns the leaf fields of t that are named by one of
+// paths, or nested within a field named by one of paths. If t isn't a
+// struct, there are no fields to match.
+func matchFlatFields(t reflect.Type, paths []string) []flatField {
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	out := []flatField{}
+	for _, ff := range flatMapFields(t, nil, nil, nil) {
+		for _, p := range paths {
+			if ff.key == p || strings.HasPrefix(ff.key, p+FlatMapKeySeparator) {
+				out = append(out, ff)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// flatFieldValue returns the value of the field ff within the struct
+// pointed to by v, or an invalid value if v, or one of the structs the field
+// is nested within, is a nil pointer.
+func flatFieldValue(v reflect.Value, ff flatField) reflect.Value {
+	fv := v
+	for _, i := range ff.idx {
+		if fv.Kind() == reflect.Ptr {
+			if fv.IsNil() {
+				return reflect.Value{}
+			}
+			fv = fv.Elem()
+		}
+		fv = fv.Field(i)
+	}
+	return fv
+}
+
+// flatFieldsChanged indicates whether any of fields differ between the
+// structs pointed to by oldCfg and newCfg.
+func flatFieldsChanged(fields []flatField, oldCfg, newCfg reflect.Value) bool {
+	for _, ff := range fields {
+		oldV, newV := flatFieldValue(oldCfg, ff), flatFieldValue(newCfg, ff)
+		if oldV.IsValid() != newV.IsValid() {
+			return true
+		}
+		if oldV.IsValid() && !reflect.DeepEqual(oldV.Interface(), newV.Interface()) {
+			return true
+		}
+	}
+	return false
+}
+
+// ToFlatMap serializes cfg into a map from dotted-path keys to string values
+// in the format understood by the parse package (and FromFlatMap).
+// Unset (nil) pointers, slices and maps are omitted, as are values of types
+// that can't be represented as strings (e.g. channels or interfaces).
+func ToFlatMap[T any](cfg *T) map[string]string {
+	v := reflect.ValueOf(cfg).Elem()
+	out := map[string]string{}
+	if v.Kind() != reflect.Struct {
+		return out
+	}
+FIELDS:
+	for _, ff := range flatMapFields(v.Type(), nil, nil, nil) {
+		fv := v
+		for _, i := range ff.idx {
+			if fv.Kind() == reflect.Ptr {
+				if fv.IsNil() {
+					continue FIELDS
+				}
+				fv = fv.Elem()
+			}
+			fv = fv.Field(i)
+		}
+		if s, ok := encodeFlatValue(fv); ok {
+			out[ff.key] = s
+		}
+	}
+	return out
+}
+
+// FromFlatMap constructs a new T from a map of dotted-path keys to string
+// values, as generated by ToFlatMap. Values are parsed with the parse package,
+// or encoding.TextUnmarshaler if the field implements it.
+// Unknown keys result in an error.
+func FromFlatMap[T any](m map[string]string) (*T, error) {
+	out := new(T)
+	v := reflect.ValueOf(out).Elem()
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("config type %s is not a struct", v.Type())
+	}
+	fields := map[string][]int{}
+	for _, ff := range flatMapFields(v.Type(), nil, nil, nil) {
+		if _, dup := fields[ff.key]; dup {
+			return nil, fmt.Errorf("multiple fields in %s map to key %q", v.Type(), ff.key)
+		}
+		fields[ff.key] = ff.idx
+	}
+
+	for k, s := range m {
+		idx, ok := fields[k]
+		if !ok {
+			return nil, fmt.Errorf("unknown key %q for type %s", k, v.Type())
+		}
+		fv := v
+		for _, i := range idx {
+			if fv.Kind() == reflect.Ptr {
+				if fv.IsNil() {
+					fv.Set(reflect.New(fv.Type().Elem()))
+				}
+				fv = fv.Elem()
+			}
+			fv = fv.Field(i)
+		}
+		if err := decodeFlatValue(s, fv); err != nil {
+			return nil, fmt.Errorf("failed to decode value for key %q: %w", k, err)
+		}
+	}
+	return out, nil
+}
+
+func decodeFlatValue(s string, fv reflect.Value) error {
+	target := fv
+	if target.Kind() == reflect.Ptr {
+		target = reflect.New(fv.Type().Elem()).Elem()
+	}
+	if reflect.PtrTo(target.Type()).Implements(textUnmarshalerType) {
+		if err := target.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s)); err != nil {
+			return err
+		}
+	} else {
+		parsed, parseErr := parse.String(s, target.Type())
+		if parseErr != nil {
+			return parseErr
+		}
+		// scalars come back as pointers, slices and maps don't.
+		if parsed.Kind() == reflect.Ptr {
+			parsed = parsed.Elem()
+		}
+		target.Set(parsed.Convert(target.Type()))
+	}
+	if fv.Kind() == reflect.Ptr {
+		fv.Set(target.Addr())
+	}
+	return nil
+}
+
+func encodeFlatValue(v reflect.Value) (string, bool) {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "", false
+		}
+		v = v.Elem()
+	}
+	if v.Type().Implements(textMarshalerType) || (v.CanAddr() && v.Addr().Type().Implements(textMarshalerType)) {
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return "", false
+		}
+		m, ok := v.Interface().(encoding.TextMarshaler)
+		if !ok {
+			m = v.Addr().Interface().(encoding.TextMarshaler)
+		}
+		b, err := m.MarshalText()
+		if err != nil {
+			return "", false
+		}
+		return string(b), true
+	}
+	switch v.Kind() {
+	case reflect.Slice:
+		if v.IsNil() {
+			return "", false
+		}
+		parts := make([]string, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			p, ok := encodeFlatElem(v.Index(i))
+			if !ok {
+				return "", false
+			}
+			parts[i] = p
+		}
+		return strings.Join(parts, ","), true
+	case reflect.Map:
+		if v.IsNil() {
+			return "", false
+		}
+		return encodeFlatMapValue(v)
+	default:
+		return encodeFlatScalar(v)
+	}
+}
+
+// encodeFlatElem encodes an element of a slice or map, quoting strings so
+// they survive splitting.
+func encodeFlatElem(v reflect.Value) (string, bool) {
+	if v.Kind() == reflect.String {
+		return strconv.Quote(v.String()), true
+	}
+	return encodeFlatScalar(v)
+}
+
+func encodeFlatMapValue(v reflect.Value) (string, bool) {
+	type kv struct {
+		k string
+		v reflect.Value
+	}
+	kvs := make([]kv, 0, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		k, ok := encodeFlatElem(iter.Key())
+		if !ok {
+			return "", false
+		}
+		kvs = append(kvs, kv{k: k, v: iter.Value()})
+	}
+	// sort so we get a stable output
+	sort.Slice(kvs, func(i, j int) bool { return kvs[i].k < kvs[j].k })
+
+	parts := make([]string, 0, len(kvs))
+	for _, e := range kvs {
+		switch {
+		case v.Type().Elem() == emptyStructType:
+			// sets are just the list of keys
+			parts = append(parts, e.k)
+		case e.v.Kind() == reflect.Slice:
+			// slice-valued maps repeat the key for every element
+			for i := 0; i < e.v.Len(); i++ {
+				ev, ok := encodeFlatElem(e.v.Index(i))
+				if !ok {
+					return "", false
+				}
+				parts = append(parts, e.k+":"+ev)
+			}
+		default:
+			ev, ok := encodeFlatElem(e.v)
+			if !ok {
+				return "", false
+			}
+			parts = append(parts, e.k+":"+ev)
+		}
+	}
+	return strings.Join(parts, ","), true
+}
+
+func encodeFlatScalar(v reflect.Value) (string, bool) {
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), true
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.Type() == durationType {
+			return time.Duration(v.Int()).String(), true
+		}
+		return strconv.FormatInt(v.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), true
+	case reflect.Float32:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 32), true
+	case reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 64), true
+	case reflect.Complex64:
+		return strconv.FormatComplex(v.Complex(), 'g', -1, 64), true
+	case reflect.Complex128:
+		return strconv.FormatComplex(v.Complex(), 'g', -1, 128), true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,101 @@
+package dials
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlatMapRoundTrip(t *testing.T) {
+	type database struct {
+		Host    string `dials:"host"`
+		Port    int
+		Timeout time.Duration
+		Addrs   []net.IP
+	}
+	type Embedded struct {
+		Region string
+	}
+	type config struct {
+		Embedded
+		Name     string
+		Enabled  *bool
+		Ratio    float64
+		Tags     []string
+		Ports    []uint16
+		Labels   map[string]string
+		Limits   map[string]int
+		Groups   map[string][]string
+		Features map[string]struct{}
+		IP       net.IP
+		DB       database `dials:"db"`
+		Replica  *database
+		Unset    *database
+		Skipped  string `dials:"-"`
+	}
+
+	enabled := true
+	cfg := config{
+		Embedded: Embedded{Region: "us-east1"},
+		Name:     "some, name",
+		Enabled:  &enabled,
+		Ratio:    0.25,
+		Tags:     []string{"a", "b,c", `d"e`},
+		Ports:    []uint16{80, 443},
+		Labels:   map[string]string{"team": "core", "env": "prod:1"},
+		Limits:   map[string]int{"cpu": 4, "mem": -1},
+		Groups:   map[string][]string{"admins": {"alice", "bob"}, "users": {"carol"}},
+		Features: map[string]struct{}{"fizz": {}, "buzz": {}},
+		IP:       net.ParseIP("10.0.0.1"),
+		DB: database{
+			Host:    "db.example.com",
+			Port:    5432,
+			Timeout: 1500 * time.Millisecond,
+		},
+		Replica: &database{Host: "replica.example.com"},
+		Skipped: "ignored",
+	}
+
+	m := ToFlatMap(&cfg)
+	assert.Equal(t, map[string]string{
+		"Region":          "us-east1",
+		"Name":            "some, name",
+		"Enabled":         "true",
+		"Ratio":           "0.25",
+		"Tags":            `"a","b,c","d\"e"`,
+		"Ports":           "80,443",
+		"Labels":          `"env":"prod:1","team":"core"`,
+		"Limits":          `"cpu":4,"mem":-1`,
+		"Groups":          `"admins":"alice","admins":"bob","users":"carol"`,
+		"Features":        `"buzz","fizz"`,
+		"IP":              "10.0.0.1",
+		"db.host":         "db.example.com",
+		"db.Port":         "5432",
+		"db.Timeout":      "1.5s",
+		"Replica.host":    "replica.example.com",
+		"Replica.Port":    "0",
+		"Replica.Timeout": "0s",
+	}, m)
+
+	out, err := FromFlatMap[config](m)
+	require.NoError(t, err)
+
+	expected := cfg
+	expected.Skipped = ""
+	assert.Equal(t, &expected, out)
+}
+
+func TestFromFlatMapErrors(t *testing.T) {
+	type config struct {
+		Port int
+	}
+
+	_, unknownErr := FromFlatMap[config](map[string]string{"Host": "foo"})
+	assert.EqualError(t, unknownErr, `unknown key "Host" for type dials.config`)
+
+	_, parseErr := FromFlatMap[config](map[string]string{"Port": "eighty"})
+	assert.ErrorContains(t, parseErr, `failed to decode value for key "Port"`)
+}
@@ -0,0 +1,75 @@
+package dials
+
+import (
+	"context"
+	"fmt"
+)
+
+// LogLevel is the severity of a log entry passed to a [Logger]. The values
+// match those of the standard library's log/slog levels, so they can be
+// converted directly (e.g. slog.Level(lvl)).
+type LogLevel int
+
+const (
+	// LogLevelDebug is used for routine lifecycle events (sources being
+	// read, configurations being composed, callbacks being dispatched)
+	LogLevelDebug LogLevel = -4
+	// LogLevelInfo is used for new configuration versions being installed
+	LogLevelInfo LogLevel = 0
+	// LogLevelWarn is used for rejected configurations, errors reported
+	// by watching sources and dropped callback events
+	LogLevelWarn LogLevel = 4
+	// LogLevelError is used for errors that cause Config to fail
+	LogLevelError LogLevel = 8
+)
+
+// String implements fmt.Stringer
+func (l LogLevel) String() string {
+	switch l {
+	case LogLevelDebug:
+		return "DEBUG"
+	case LogLevelInfo:
+		return "INFO"
+	case LogLevelWarn:
+		return "WARN"
+	case LogLevelError:
+		return "ERROR"
+	default:
+		return fmt.Sprintf("LogLevel(%d)", int(l))
+	}
+}
+
+// Logger receives structured log entries at key transitions in the
+// lifecycle of a Dials instance. (see [Params].Logger)
+//
+// args are alternating keys (strings) and values, in the style of log/slog,
+// so a *slog.Logger can be adapted with
+//
+//	type slogAdapter struct{ l *slog.Logger }
+//
+//	func (s slogAdapter) Log(ctx context.Context, lvl dials.LogLevel, msg string, args ...any) {
+//		s.l.Log(ctx, slog.Level(lvl), msg, args...)
+//	}
+//
+// Keys used by dials include "source_type", "serial", "duration" and "error".
+//
+// Log may be called concurrently from multiple goroutines (including the
+// goroutine that calls Config and the background goroutines that handle
+// updates from watching sources and run callbacks), and should not block.
+type Logger interface {
+	Log(ctx context.Context, level LogLevel, msg string, args ...any)
+}
+
+// log calls the configured Logger (if any)
+func (p *Params[T]) log(ctx context.Context, level LogLevel, msg string, args ...any) {
+	if p.Logger == nil {
+		return
+	}
+	p.Logger.Log(ctx, level, msg, args...)
+}
+
+// sourceType returns the value used with the "source_type" key in log
+// entries.
+func sourceType(s Source) string {
+	return fmt.Sprintf("%T", s)
+}
@@ -0,0 +1,127 @@
+package dials
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type logEntry struct {
+	level LogLevel
+	msg   string
+	args  map[string]any
+}
+
+type recordingLogger struct {
+	mu      sync.Mutex
+	entries []logEntry
+}
+
+func (r *recordingLogger) Log(ctx context.Context, level LogLevel, msg string, args ...any) {
+	m := make(map[string]any, len(args)/2)
+	for i := 0; i+1 < len(args); i += 2 {
+		m[args[i].(string)] = args[i+1]
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, logEntry{level: level, msg: msg, args: m})
+}
+
+func (r *recordingLogger) find(msg string) []logEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := []logEntry{}
+	for _, e := range r.entries {
+		if e.msg == msg {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+func TestLogger(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	foo, bar := "foo", "bar"
+	src := fakeSource{outVal: reloadTestPtrConfig{Foo: &foo}}
+	w := fakeWatchingSource{fakeSource: fakeSource{outVal: reloadTestPtrConfig{Bar: &bar}}}
+	logger := recordingLogger{}
+	newConfs := make(chan *reloadTestConfig, 1)
+	d, err := Params[reloadTestConfig]{
+		Logger: &logger,
+		OnNewConfig: func(ctx context.Context, oldConfig, newConfig *reloadTestConfig) {
+			newConfs <- newConfig
+		},
+	}.Config(ctx, &reloadTestConfig{}, &src, &w)
+	require.NoError(t, err)
+
+	reads := logger.find("read source value")
+	require.Len(t, reads, 2)
+	for i, s := range []Source{&src, &w} {
+		assert.Equal(t, LogLevelDebug, reads[i].level)
+		assert.Equal(t, sourceType(s), reads[i].args["source_type"])
+		assert.Contains(t, reads[i].args, "duration")
+	}
+	assert.Len(t, logger.find("composed configuration"), 1)
+	assert.Len(t, logger.find("verified initial configuration"), 1)
+
+	baz := "baz"
+	w.send(ctx, reflect.ValueOf(reloadTestPtrConfig{Bar: &baz}))
+	<-newConfs
+	<-d.Events()
+
+	installs := logger.find("installed new configuration version")
+	require.Len(t, installs, 1)
+	assert.Equal(t, LogLevelInfo, installs[0].level)
+	assert.Equal(t, uint64(1), installs[0].args["serial"])
+	assert.Len(t, logger.find("received new value from watching source"), 1)
+	dispatches := logger.find("dispatching new configuration to callbacks")
+	require.Len(t, dispatches, 1)
+	assert.Equal(t, uint64(1), dispatches[0].args["serial"])
+
+	// a value that fails verification should be logged as a warning
+	badFoo := "bad"
+	w.send(ctx, reflect.ValueOf(reloadTestPtrConfig{Foo: &badFoo, Bar: &baz}))
+	// Reload goes through the monitor goroutine, so once it returns the
+	// rejected value has been handled. (the watching source's latest value
+	// is retained, so the reload is rejected as well)
+	_, reloadErr := d.Reload(ctx)
+	require.EqualError(t, reloadErr, "failed to reload configuration: bad foo")
+	assert.Len(t, logger.find("rejected reloaded configuration"), 1)
+	rejected := logger.find("rejected new configuration from watching source")
+	require.Len(t, rejected, 1)
+	assert.Equal(t, LogLevelWarn, rejected[0].level)
+	assert.Equal(t, sourceType(&w), rejected[0].args["source_type"])
+	assert.EqualError(t, rejected[0].args["error"].(error), "bad foo")
+}
+
+func TestLoggerConfigFailure(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	logger := recordingLogger{}
+	_, err := Params[reloadTestConfig]{Logger: &logger}.Config(
+		ctx, &reloadTestConfig{}, &errSource{err: errors.New("boom")})
+	require.Error(t, err)
+
+	failures := logger.find("failed to read source value")
+	require.Len(t, failures, 1)
+	assert.Equal(t, LogLevelError, failures[0].level)
+	assert.Equal(t, sourceType(&errSource{}), failures[0].args["source_type"])
+	assert.EqualError(t, failures[0].args["error"].(error), "boom")
+}
+
+type errSource struct {
+	err error
+}
+
+func (e *errSource) Value(context.Context, *Type) (reflect.Value, error) {
+	return reflect.Value{}, e.err
+}
@@ -0,0 +1,42 @@
+package dials
+
+import "time"
+
+// Metrics receives notifications suitable for exporting as counters and
+// histograms (e.g. to Prometheus or statsd) from the goroutine that
+// handles updates from watching sources and calls to [Dials.Reload].
+// (see [Params].Metrics)
+//
+// Implementations may be called concurrently and should not block.
+type Metrics interface {
+	// RestackDuration is called with the time taken to compose (and
+	// verify, if enabled) the configuration after a source's value
+	// changes.
+	RestackDuration(time.Duration)
+	// VerifyFailure is called when a re-stacked configuration fails
+	// verification and is rejected.
+	VerifyFailure()
+	// SourceError is called when a source reports an error, with the
+	// source's type (as formatted by the %T verb).
+	SourceError(sourceType string)
+	// ConfigInstalled is called when a new configuration version is
+	// installed, with the new version's serial.
+	ConfigInstalled(serial uint64)
+}
+
+// nopMetrics implements Metrics by doing nothing
+type nopMetrics struct{}
+
+func (nopMetrics) RestackDuration(time.Duration) {}
+func (nopMetrics) VerifyFailure()                {}
+func (nopMetrics) SourceError(string)            {}
+func (nopMetrics) ConfigInstalled(uint64)        {}
+
+// metrics returns the configured Metrics, or a no-op implementation if
+// Metrics is nil.
+func (p *Params[T]) metrics() Metrics {
+	if p.Metrics == nil {
+		return nopMetrics{}
+	}
+	return p.Metrics
+}
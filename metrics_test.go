@@ -0,0 +1,81 @@
+package dials
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingMetrics struct {
+	mu              sync.Mutex
+	restacks        []time.Duration
+	verifyFailures  int
+	sourceErrors    []string
+	installedSerial []uint64
+}
+
+func (r *recordingMetrics) RestackDuration(d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.restacks = append(r.restacks, d)
+}
+
+func (r *recordingMetrics) VerifyFailure() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.verifyFailures++
+}
+
+func (r *recordingMetrics) SourceError(sourceType string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sourceErrors = append(r.sourceErrors, sourceType)
+}
+
+func (r *recordingMetrics) ConfigInstalled(serial uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.installedSerial = append(r.installedSerial, serial)
+}
+
+func TestMetrics(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bar := "bar"
+	w := fakeWatchingSource{fakeSource: fakeSource{outVal: reloadTestPtrConfig{Bar: &bar}}}
+	m := recordingMetrics{}
+	errs := make(chan error, 1)
+	d, err := Params[reloadTestConfig]{
+		Metrics: &m,
+		OnWatchedError: func(ctx context.Context, err error, oldConfig, newConfig *reloadTestConfig) {
+			errs <- err
+		},
+	}.Config(ctx, &reloadTestConfig{}, &w)
+	require.NoError(t, err)
+
+	baz := "baz"
+	w.send(ctx, reflect.ValueOf(reloadTestPtrConfig{Bar: &baz}))
+	<-d.Events()
+
+	badFoo := "bad"
+	w.send(ctx, reflect.ValueOf(reloadTestPtrConfig{Foo: &badFoo, Bar: &baz}))
+	assert.EqualError(t, <-errs, "bad foo")
+
+	require.NoError(t, w.args.ReportError(ctx, errors.New("boom")))
+	<-errs
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	assert.Len(t, m.restacks, 2)
+	assert.Equal(t, 1, m.verifyFailures)
+	assert.Equal(t, []string{sourceType(&w)}, m.sourceErrors)
+	assert.Equal(t, []uint64{1}, m.installedSerial)
+}
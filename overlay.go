@@ -5,11 +5,20 @@ import (
 	"fmt"
 	"reflect"
 
+	"github.com/vimeo/dials/common"
 	"github.com/vimeo/dials/ptrify"
 )
 
 var errCanSetField = errors.New("cannot set field")
 
+// values of the dialsmerge tag
+const (
+	// mergeAppend concatenates slices from successive sources
+	mergeAppend = "append"
+	// mergeMaps unions maps from successive sources key-by-key
+	mergeMaps = "merge"
+)
+
 type overlayer struct {
 	dc *deepCopier
 }
@@ -48,7 +57,7 @@ func (o *overlayer) overlayField(base, overlay reflect.Value) error {
 				return fmt.Errorf("unexpected kind for mangled pointer target: %s",
 					base.Type().Elem().Kind())
 			}
-			if ptrify.IsTextUnmarshalerStruct(base.Type().Elem()) {
+			if ptrify.IsUnmarshalerStruct(base.Type().Elem()) {
 				return fmt.Errorf("unexpected shallow-copy-struct as pointer target types: base: %s; overlay %s",
 					base.Type(), overlay.Type())
 			}
@@ -57,7 +66,7 @@ func (o *overlayer) overlayField(base, overlay reflect.Value) error {
 			base.Set(reflect.New(base.Type().Elem()))
 			return o.overlayStruct(base.Elem(), overlay.Elem())
 		}
-		if ptrify.IsTextUnmarshalerStruct(base.Type().Elem()) {
+		if ptrify.IsUnmarshalerStruct(base.Type().Elem()) {
 			// base is not nil and we're not deep-copying, so we can overwrite the pointer.
 			if overlay.Type().AssignableTo(base.Type()) {
 				base.Set(overlay)
@@ -72,7 +81,7 @@ func (o *overlayer) overlayField(base, overlay reflect.Value) error {
 	case reflect.Interface:
 		return o.overlayInterface(base, overlay)
 	case reflect.Struct:
-		if ptrify.IsTextUnmarshalerStruct(base.Type()) {
+		if ptrify.IsUnmarshalerStruct(base.Type()) {
 			// base is not nil and we're not deep-copying, so we can shallow-copy
 			switch overlay.Kind() {
 			case reflect.Ptr:
@@ -97,6 +106,22 @@ func (o *overlayer) overlayField(base, overlay reflect.Value) error {
 			return o.overlayStruct(base, overlay.Elem())
 		}
 		return o.overlayStruct(base, overlay)
+	case reflect.Array:
+		if overlay.Kind() == reflect.Ptr {
+			overlay = overlay.Elem()
+		}
+		if overlay.Type() == base.Type() {
+			base.Set(overlay)
+			return nil
+		}
+		// The elements of arrays of structs are pointerified, so
+		// overlay each of the elements the source set.
+		for i := 0; i < base.Len(); i++ {
+			if err := o.overlayField(base.Index(i), overlay.Index(i)); err != nil {
+				return fmt.Errorf("failed to overlay element %d: %w", i, err)
+			}
+		}
+		return nil
 	default:
 		// this probably will be a pointer to the value we want because
 		// we explicitly pointerify fields, but there's a chance that
@@ -137,7 +162,8 @@ func (o *overlayer) overlayStruct(base, overlay reflect.Value) error {
 			continue
 		default:
 		}
-		if overlayErr := o.overlayField(
+		if overlayErr := o.overlayMergeField(
+			base.Type().Field(i),
 			currentField,
 			overlay.Field(j)); overlayErr != nil {
 			return fmt.Errorf("failed to set field %q (number %d): %s",
@@ -150,6 +176,102 @@ func (o *overlayer) overlayStruct(base, overlay reflect.Value) error {
 	return nil
 }
 
+// overlayMergeField dispatches to overlayField, or one of the merging
+// variants, depending on the value of the field's `dialsmerge` tag.
+func (o *overlayer) overlayMergeField(sf reflect.StructField, base, overlay reflect.Value) error {
+	switch mode := sf.Tag.Get(common.DialsMergeTag); mode {
+	case "":
+		return o.overlayField(base, overlay)
+	case mergeAppend:
+		return o.overlayAppendSlice(base, overlay)
+	case mergeMaps:
+		return o.overlayMergeMap(base, overlay)
+	default:
+		return fmt.Errorf("unknown %s mode %q", common.DialsMergeTag, mode)
+	}
+}
+
+// overlayAppendSlice concatenates the contents of overlay onto base (both of
+// which must be slices) rather than replacing it.
+// A nil overlay leaves base unchanged, while an empty overlay only replaces a
+// nil base.
+func (o *overlayer) overlayAppendSlice(base, overlay reflect.Value) error {
+	if base.Kind() != reflect.Slice {
+		return fmt.Errorf("%s:%q is only supported on slices, not %s",
+			common.DialsMergeTag, mergeAppend, base.Type())
+	}
+	if overlay.Kind() == reflect.Ptr {
+		if overlay.IsNil() {
+			return nil
+		}
+		overlay = overlay.Elem()
+	}
+	if overlay.Kind() != reflect.Slice || !overlay.Type().AssignableTo(base.Type()) {
+		return fmt.Errorf("type %s is not assignable to %s",
+			overlay.Type(), base.Type())
+	}
+	if overlay.IsNil() {
+		return nil
+	}
+	if !base.CanSet() {
+		return errCanSetField
+	}
+	if base.IsNil() {
+		base.Set(overlay)
+		return nil
+	}
+	// allocate a new backing array so we never scribble over one that
+	// may be shared with a lower layer.
+	merged := reflect.MakeSlice(base.Type(), 0, base.Len()+overlay.Len())
+	merged = reflect.AppendSlice(merged, base)
+	merged = reflect.AppendSlice(merged, overlay)
+	base.Set(merged)
+	return nil
+}
+
+// overlayMergeMap unions the entries of overlay into base (both of which must
+// be maps), with values from overlay replacing those in base for any keys
+// present in both.
+// A nil overlay leaves base unchanged, while an empty overlay only replaces a
+// nil base.
+func (o *overlayer) overlayMergeMap(base, overlay reflect.Value) error {
+	if base.Kind() != reflect.Map {
+		return fmt.Errorf("%s:%q is only supported on maps, not %s",
+			common.DialsMergeTag, mergeMaps, base.Type())
+	}
+	if overlay.Kind() == reflect.Ptr {
+		if overlay.IsNil() {
+			return nil
+		}
+		overlay = overlay.Elem()
+	}
+	if overlay.Kind() != reflect.Map || !overlay.Type().AssignableTo(base.Type()) {
+		return fmt.Errorf("type %s is not assignable to %s",
+			overlay.Type(), base.Type())
+	}
+	if overlay.IsNil() {
+		return nil
+	}
+	if !base.CanSet() {
+		return errCanSetField
+	}
+	if base.IsNil() {
+		base.Set(overlay)
+		return nil
+	}
+	// allocate a new map so we never mutate one that may be shared with a
+	// lower layer.
+	merged := reflect.MakeMapWithSize(base.Type(), base.Len()+overlay.Len())
+	for _, m := range [...]reflect.Value{base, overlay} {
+		iter := m.MapRange()
+		for iter.Next() {
+			merged.SetMapIndex(iter.Key(), iter.Value())
+		}
+	}
+	base.Set(merged)
+	return nil
+}
+
 func (o *overlayer) overlayInterface(base, overlay reflect.Value) error {
 	if base.Kind() != reflect.Interface {
 		panic(fmt.Errorf("invalid base of kind %s as argument to overlayInterface; only Interface allowed",
@@ -170,7 +292,7 @@ func (o *overlayer) overlayInterface(base, overlay reflect.Value) error {
 			base.Set(overlay.Elem())
 			return nil
 		}
-		if overlay.Elem().Type() == base.Elem().Type() {
+		if overlay.Elem().Type() == base.Elem().Type() && kindOverlayable(overlay.Elem().Kind()) {
 			// they're the same underlying type, just
 			// overlay-away (leave the base as an interface
 			// so we fall into the next case)
@@ -180,8 +302,11 @@ func (o *overlayer) overlayInterface(base, overlay reflect.Value) error {
 			}
 			return nil
 		}
-		// interface values don't have the same type, and neither is
-		// nil, treat it the same way as if base is nil, and just overlay.
+		// interface values don't have the same type, or have a
+		// type there's nothing to descend into (e.g. a string, or
+		// raw decoder-specific data), and neither is nil: replace
+		// the value wholesale (later non-nil values win).
+		// compose has already deep-copied the overlay.
 		base.Set(overlay.Elem())
 		return nil
 	case reflect.Ptr:
@@ -218,7 +343,7 @@ func (o *overlayer) overlayInterface(base, overlay reflect.Value) error {
 		return nil
 	case reflect.Struct:
 		if !base.IsNil() {
-			if base.Elem().Type() == overlay.Type() || base.Elem().Type() == reflect.PtrTo(overlay.Type()) {
+			if base.Elem().Type() == overlay.Type() {
 				out := reflect.New(base.Elem().Type())
 				o.dc.deepCopy(base.Elem(), out.Elem())
 				if err := o.overlayField(out.Elem(), overlay); err != nil {
@@ -227,6 +352,20 @@ func (o *overlayer) overlayInterface(base, overlay reflect.Value) error {
 				base.Set(out.Elem())
 				return nil
 			}
+			if base.Elem().Type() == reflect.PtrTo(overlay.Type()) {
+				// overlay onto a copy of the pointee, so we don't
+				// mutate a value that may be shared with a lower
+				// layer.
+				out := reflect.New(overlay.Type())
+				if !base.Elem().IsNil() {
+					o.dc.deepCopy(base.Elem().Elem(), out.Elem())
+				}
+				if err := o.overlayField(out.Elem(), overlay); err != nil {
+					return fmt.Errorf("failed to overlay interface onto struct field: %s", err)
+				}
+				base.Set(out)
+				return nil
+			}
 		}
 		if overlay.Type().Implements(base.Type()) {
 			base.Set(overlay)
@@ -297,6 +436,18 @@ func (o *overlayer) overlayInterface(base, overlay reflect.Value) error {
 	return fmt.Errorf("fallthrough overlay interface type %s onto %s", overlay.Type(), base.Type())
 }
 
+// kindOverlayable returns whether overlayField can descend into a value of
+// kind k held by an interface (merging it with a base value of the same
+// type), rather than just replacing it.
+func kindOverlayable(k reflect.Kind) bool {
+	switch k {
+	case reflect.Ptr, reflect.Struct:
+		return true
+	default:
+		return false
+	}
+}
+
 func kindNilable(k reflect.Kind) bool {
 	switch k {
 	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice, reflect.Chan, reflect.Func:
@@ -9,6 +9,10 @@ import (
 func TestOverlay(t *testing.T) {
 	type sInt struct{ J int }
 	type sBool struct{ J bool }
+	type sPtrs struct {
+		A *int
+		B *bool
+	}
 	three := int(3)
 	True := true
 	sampleChan := make(chan struct{})
@@ -298,6 +302,44 @@ func TestOverlay(t *testing.T) {
 				J: 3,
 			}, T: &now},
 		},
+		"iface_scalar_replaced": {
+			base:     &struct{ K interface{} }{K: "foo"},
+			overlay:  struct{ K interface{} }{K: "bar"},
+			expected: struct{ K interface{} }{K: "bar"},
+		},
+		"iface_scalar_base_nil": {
+			base:     &struct{ K interface{} }{K: nil},
+			overlay:  struct{ K interface{} }{K: 42},
+			expected: struct{ K interface{} }{K: 42},
+		},
+		"iface_different_types_replaced": {
+			base:     &struct{ K interface{} }{K: map[string]interface{}{"a": 1}},
+			overlay:  struct{ K interface{} }{K: []interface{}{"b"}},
+			expected: struct{ K interface{} }{K: []interface{}{"b"}},
+		},
+		// structs (and pointers to them) with the same dynamic type
+		// are overlaid recursively
+		"iface_struct_same_type_merged": {
+			base:     &struct{ K interface{} }{K: sPtrs{A: &three}},
+			overlay:  struct{ K interface{} }{K: sPtrs{B: &True}},
+			expected: struct{ K interface{} }{K: sPtrs{A: &three, B: &True}},
+		},
+		"iface_struct_ptr_same_type_merged": {
+			base:     &struct{ K interface{} }{K: &sPtrs{A: &three}},
+			overlay:  struct{ K interface{} }{K: &sPtrs{B: &True}},
+			expected: struct{ K interface{} }{K: &sPtrs{A: &three, B: &True}},
+		},
+		// while other values are replaced, rather than merged
+		"iface_map_replaced_not_merged": {
+			base:     &struct{ K interface{} }{K: map[string]interface{}{"a": 1}},
+			overlay:  struct{ K interface{} }{K: map[string]interface{}{"b": 2}},
+			expected: struct{ K interface{} }{K: map[string]interface{}{"b": 2}},
+		},
+		"iface_nil_overlay": {
+			base:     &struct{ K interface{} }{K: "foo"},
+			overlay:  struct{ K interface{} }{K: nil},
+			expected: struct{ K interface{} }{K: "foo"},
+		},
 		"map_simple_base_nil": {
 			base:     &struct{ K map[string]string }{K: nil},
 			overlay:  struct{ K map[string]string }{K: map[string]string{"foo": "bar"}},
@@ -397,3 +439,170 @@ func TestOverlay(t *testing.T) {
 
 	}
 }
+
+func TestComposeAppendSlices(t *testing.T) {
+	type conf struct {
+		Allow   []string `dialsmerge:"append"`
+		Replace []string
+	}
+
+	for name, inst := range map[string]struct {
+		defaults conf
+		sources  []conf
+		expected conf
+	}{
+		"two_sources": {
+			sources: []conf{
+				{Allow: []string{"a", "b"}, Replace: []string{"a", "b"}},
+				{Allow: []string{"c"}, Replace: []string{"c"}},
+			},
+			expected: conf{Allow: []string{"a", "b", "c"}, Replace: []string{"c"}},
+		},
+		"three_sources_with_defaults": {
+			defaults: conf{Allow: []string{"default"}},
+			sources: []conf{
+				{Allow: []string{"a"}},
+				{Allow: []string{"b", "c"}},
+				{Allow: []string{"d"}},
+			},
+			expected: conf{Allow: []string{"default", "a", "b", "c", "d"}},
+		},
+		"three_sources_nil_middle": {
+			sources: []conf{
+				{Allow: []string{"a"}},
+				{Allow: nil},
+				{Allow: []string{"b"}},
+			},
+			expected: conf{Allow: []string{"a", "b"}},
+		},
+		"empty_does_not_clear": {
+			sources: []conf{
+				{Allow: []string{"a"}, Replace: []string{"a"}},
+				{Allow: []string{}, Replace: []string{}},
+			},
+			expected: conf{Allow: []string{"a"}, Replace: []string{}},
+		},
+		"empty_over_nil": {
+			sources: []conf{
+				{Allow: nil},
+				{Allow: []string{}},
+			},
+			expected: conf{Allow: []string{}},
+		},
+		"all_nil": {
+			sources:  []conf{{}, {}, {}},
+			expected: conf{},
+		},
+	} {
+		entry := inst
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			defaultsAllow := append([]string(nil), entry.defaults.Allow...)
+
+			svs := make([]sourceValue, 0, len(entry.sources))
+			for _, s := range entry.sources {
+				svs = append(svs, sourceValue{value: reflect.ValueOf(s)})
+			}
+			out, err := compose(&entry.defaults, svs)
+			if err != nil {
+				t.Fatalf("failed to compose: %s", err)
+			}
+			if !reflect.DeepEqual(out, &entry.expected) {
+				t.Errorf("got: %#v, expected: %#v", out, &entry.expected)
+			}
+			// the defaults must not be modified
+			if !reflect.DeepEqual(entry.defaults.Allow, defaultsAllow) {
+				t.Errorf("defaults modified: %#v", entry.defaults.Allow)
+			}
+		})
+	}
+}
+
+func TestOverlayAppendNonSlice(t *testing.T) {
+	three := 3
+	base := &struct {
+		K int `dialsmerge:"append"`
+	}{}
+	overlay := struct {
+		K *int `dialsmerge:"append"`
+	}{K: &three}
+	err := newOverlayer().overlayStruct(reflect.ValueOf(base).Elem(), reflect.ValueOf(overlay))
+	if err == nil {
+		t.Fatal("unexpected success appending an int")
+	}
+	const expectedErr = `failed to set field "K" (number 0): dialsmerge:"append" is only supported on slices, not int`
+	if err.Error() != expectedErr {
+		t.Errorf("unexpected error: got %q, expected %q", err, expectedErr)
+	}
+}
+
+func TestComposeMergeMaps(t *testing.T) {
+	type conf struct {
+		Labels  map[string]string `dialsmerge:"merge"`
+		Replace map[string]string
+	}
+
+	for name, inst := range map[string]struct {
+		defaults conf
+		sources  []conf
+		expected conf
+	}{
+		"two_sources": {
+			sources: []conf{
+				{Labels: map[string]string{"a": "1", "b": "1"}, Replace: map[string]string{"a": "1", "b": "1"}},
+				{Labels: map[string]string{"b": "2", "c": "2"}, Replace: map[string]string{"b": "2", "c": "2"}},
+			},
+			expected: conf{
+				Labels:  map[string]string{"a": "1", "b": "2", "c": "2"},
+				Replace: map[string]string{"b": "2", "c": "2"},
+			},
+		},
+		"three_sources_with_defaults": {
+			defaults: conf{Labels: map[string]string{"default": "0", "a": "0"}},
+			sources: []conf{
+				{Labels: map[string]string{"a": "1"}},
+				{Labels: nil},
+				{Labels: map[string]string{"b": "3"}},
+			},
+			expected: conf{Labels: map[string]string{"default": "0", "a": "1", "b": "3"}},
+		},
+		"empty_does_not_clear": {
+			sources: []conf{
+				{Labels: map[string]string{"a": "1"}, Replace: map[string]string{"a": "1"}},
+				{Labels: map[string]string{}, Replace: map[string]string{}},
+			},
+			expected: conf{Labels: map[string]string{"a": "1"}, Replace: map[string]string{}},
+		},
+		"empty_over_nil": {
+			sources:  []conf{{}, {Labels: map[string]string{}}},
+			expected: conf{Labels: map[string]string{}},
+		},
+	} {
+		entry := inst
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			defaultsLabels := map[string]string{}
+			for k, v := range entry.defaults.Labels {
+				defaultsLabels[k] = v
+			}
+
+			svs := make([]sourceValue, 0, len(entry.sources))
+			for _, s := range entry.sources {
+				svs = append(svs, sourceValue{value: reflect.ValueOf(s)})
+			}
+			out, err := compose(&entry.defaults, svs)
+			if err != nil {
+				t.Fatalf("failed to compose: %s", err)
+			}
+			if !reflect.DeepEqual(out, &entry.expected) {
+				t.Errorf("got: %#v, expected: %#v", out, &entry.expected)
+			}
+			// the defaults must not be modified
+			if entry.defaults.Labels != nil && !reflect.DeepEqual(entry.defaults.Labels, defaultsLabels) {
+				t.Errorf("defaults modified: %#v", entry.defaults.Labels)
+			}
+		})
+	}
+}
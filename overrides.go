@@ -0,0 +1,132 @@
+package dials
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	"github.com/vimeo/dials/common"
+	"github.com/vimeo/dials/ptrify"
+)
+
+// OverrideHandler is called (if set in [Params].OnOverride) for each field
+// that several sources provided conflicting values for, with the field's
+// path (its dials tags or names joined by [FlatMapKeySeparator], as with
+// [Dials.RegisterCallbackForPaths]), the source whose value was used and
+// the lower-precedence sources whose (differing) values it overrode, in
+// precedence order.
+type OverrideHandler func(ctx context.Context, path string, winner Source, overridden []Source)
+
+// fieldOverride describes a field whose value from one source overrode
+// conflicting values from others.
+type fieldOverride struct {
+	path       string
+	winner     Source
+	overridden []Source
+}
+
+// providedValue is a (non-nil) value provided for a field by a source.
+type providedValue struct {
+	source Source
+	value  reflect.Value
+}
+
+// findOverrides returns the leaf fields of the composed configuration for
+// which a source overrode conflicting values from lower-precedence sources,
+// in field order. Values sources may not set (see NamedSource) are ignored,
+// as are fields whose values are merged (see the dialsmerge tag) rather
+// than replaced.
+func findOverrides(sources []sourceValue) []fieldOverride {
+	provided := map[string][]providedValue{}
+	paths := []string{}
+	for _, source := range sources {
+		s := source.value
+		if !s.IsValid() {
+			continue
+		}
+		if s.Kind() == reflect.Ptr {
+			if s.IsNil() {
+				continue
+			}
+			s = s.Elem()
+		}
+		o := newOverlayer()
+		sv := o.dc.deepCopyValue(s)
+		clearRestrictedFields(sv, sourceName(source.source))
+		walkProvidedLeaves(sv, nil, func(path string, v reflect.Value) {
+			if _, ok := provided[path]; !ok {
+				paths = append(paths, path)
+			}
+			provided[path] = append(provided[path], providedValue{source: source.source, value: v})
+		})
+	}
+
+	out := []fieldOverride{}
+	for _, path := range paths {
+		pvs := provided[path]
+		if len(pvs) < 2 {
+			continue
+		}
+		winner := pvs[len(pvs)-1]
+		overridden := []Source{}
+		for _, pv := range pvs[:len(pvs)-1] {
+			if !reflect.DeepEqual(pv.value.Interface(), winner.value.Interface()) {
+				overridden = append(overridden, pv.source)
+			}
+		}
+		if len(overridden) > 0 {
+			out = append(out, fieldOverride{path: path, winner: winner.source, overridden: overridden})
+		}
+	}
+	return out
+}
+
+// walkProvidedLeaves calls fn with the path and value of each non-nil leaf
+// field within the pointerified struct v, recursing into nested structs.
+func walkProvidedLeaves(v reflect.Value, prefix []string, fn func(path string, v reflect.Value)) {
+	for i := 0; i < v.NumField(); i++ {
+		sf := v.Type().Field(i)
+		if !sf.IsExported() || sf.Tag.Get(common.DialsMergeTag) != "" {
+			continue
+		}
+		f := v.Field(i)
+		switch f.Kind() {
+		case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+			if f.IsNil() {
+				continue
+			}
+		case reflect.Chan, reflect.Func:
+			continue
+		default:
+		}
+		name, tagged := sf.Tag.Lookup(common.DialsTagName)
+		if !tagged || name == "" {
+			name = sf.Name
+		}
+		fieldPrefix := append(prefix[:len(prefix):len(prefix)], name)
+
+		inner := f
+		if inner.Kind() == reflect.Ptr {
+			inner = inner.Elem()
+		}
+		if inner.Kind() == reflect.Struct && !ptrify.IsUnmarshalerStruct(inner.Type()) {
+			if sf.Anonymous && !tagged {
+				fieldPrefix = prefix
+			}
+			walkProvidedLeaves(inner, fieldPrefix, fn)
+			continue
+		}
+		fn(strings.Join(fieldPrefix, FlatMapKeySeparator), f)
+	}
+}
+
+// reportOverrides calls OnOverride (if set) for each field in which
+// conflicting values were overridden while composing sourceValues.
+func (p *Params[T]) reportOverrides(ctx context.Context, sourceValues []sourceValue) {
+	if p.OnOverride == nil {
+		return
+	}
+	for _, o := range findOverrides(sourceValues) {
+		p.OnOverride(ctx, o.path, o.winner, o.overridden)
+	}
+}
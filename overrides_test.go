@@ -0,0 +1,127 @@
+package dials
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type overrideDB struct {
+	Host string `dials:"host"`
+	Port int    `dials:"port"`
+}
+
+type overrideConfig struct {
+	Name   string `dials:"name"`
+	Level  int
+	DB     overrideDB `dials:"db"`
+	Tags   []string   `dialsmerge:"append"`
+	Secret string     `dialssource:"env"`
+}
+
+// overridePtrDB is an alias, since pointerify doesn't preserve the name of
+// the nested struct type
+type overridePtrDB = struct {
+	Host *string `dials:"host"`
+	Port *int    `dials:"port"`
+}
+
+type overridePtrConfig struct {
+	Name   *string `dials:"name"`
+	Level  *int
+	DB     *overridePtrDB `dials:"db"`
+	Tags   []string       `dialsmerge:"append"`
+	Secret *string        `dialssource:"env"`
+}
+
+// override records the arguments to an OnOverride call
+type override struct {
+	path       string
+	winner     Source
+	overridden []Source
+}
+
+type overrideRecorder struct {
+	mu        sync.Mutex
+	overrides []override
+}
+
+func (o *overrideRecorder) record(ctx context.Context, path string, winner Source, overridden []Source) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.overrides = append(o.overrides, override{path: path, winner: winner, overridden: overridden})
+}
+
+func (o *overrideRecorder) take() []override {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	out := o.overrides
+	o.overrides = nil
+	return out
+}
+
+func TestOnOverride(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	str := func(s string) *string { return &s }
+	num := func(i int) *int { return &i }
+
+	file := &namedFakeSource{name: "file", fakeSource: fakeSource{outVal: overridePtrConfig{
+		Name:   str("file-name"),
+		Level:  num(3),
+		DB:     &overridePtrDB{Host: str("file.db"), Port: num(5432)},
+		Tags:   []string{"a"},
+		Secret: str("ignored"),
+	}}}
+	env := &namedFakeSource{name: "env", fakeSource: fakeSource{outVal: overridePtrConfig{
+		// same value as the file, so not a conflict
+		Level:  num(3),
+		DB:     &overridePtrDB{Host: str("env.db")},
+		Tags:   []string{"b"},
+		Secret: str("secret"),
+	}}}
+	w := &fakeWatchingSource{fakeSource: fakeSource{outVal: overridePtrConfig{}}}
+
+	rec := overrideRecorder{}
+	d, err := Params[overrideConfig]{OnOverride: rec.record}.Config(
+		ctx, &overrideConfig{Name: "template"}, file, env, w)
+	require.NoError(t, err)
+	assert.Equal(t, "env.db", d.View().DB.Host)
+	assert.Equal(t, []override{{path: "db.host", winner: env, overridden: []Source{file}}}, rec.take())
+
+	// a new value from the watching source is reported on the callback
+	// goroutine
+	_, serial := d.ViewVersion()
+	w.send(ctx, reflect.ValueOf(overridePtrConfig{Name: str("watched"), DB: &overridePtrDB{Host: str("env.db")}}))
+	_, _, waitErr := d.WaitForVersionAfter(ctx, serial)
+	require.NoError(t, waitErr)
+	require.Eventually(t, func() bool {
+		rec.mu.Lock()
+		defer rec.mu.Unlock()
+		return len(rec.overrides) == 2
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, []override{
+		{path: "name", winner: w, overridden: []Source{file}},
+		{path: "db.host", winner: w, overridden: []Source{file}},
+	}, rec.take())
+}
+
+func TestOnOverrideValidate(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	lower, higher := "lower", "higher"
+	lowerSrc := &fakeSource{outVal: reloadTestPtrConfig{Foo: &lower, Bar: &lower}}
+	higherSrc := &fakeSource{outVal: reloadTestPtrConfig{Foo: &higher}}
+	rec := overrideRecorder{}
+	require.NoError(t, Params[reloadTestConfig]{OnOverride: rec.record}.Validate(
+		ctx, &reloadTestConfig{}, lowerSrc, higherSrc))
+	assert.Equal(t, []override{{path: "Foo", winner: higherSrc, overridden: []Source{lowerSrc}}}, rec.take())
+}
@@ -0,0 +1,24 @@
+package parse
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// BoolSlice splits on commas and parses into a slice of bools
+// Parses with strconv.ParseBool, so "1", "t", "TRUE", etc. are all accepted.
+// Whitespace is trimmed around the values before parsing to allow for reasonable separtion (shell word-splitting aside)
+func BoolSlice(s string) ([]bool, error) {
+	parts := strings.Split(s, ",")
+	out := make([]bool, len(parts))
+
+	for i, p := range parts {
+		val, parseErr := strconv.ParseBool(strings.TrimSpace(p))
+		if parseErr != nil {
+			return nil, fmt.Errorf("failed to parse bool index %d: %w", i, parseErr)
+		}
+		out[i] = val
+	}
+	return out, nil
+}
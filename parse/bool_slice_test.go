@@ -0,0 +1,56 @@
+package parse
+
+import "testing"
+
+func TestBoolSlice(t *testing.T) {
+	for _, tbl := range []struct {
+		name   string
+		in     string
+		expOut []bool
+		expErr bool
+	}{
+		{
+			name:   "good_1_bool",
+			in:     "true",
+			expOut: []bool{true},
+			expErr: false,
+		}, {
+			name:   "good_4_bool_mixed_forms",
+			in:     "true, f,1,FALSE",
+			expOut: []bool{true, false, true, false},
+			expErr: false,
+		}, {
+			name:   "bad_1_bool_not_a_bool",
+			in:     "true,maybe",
+			expOut: nil,
+			expErr: true,
+		}, {
+			name:   "bad_empty",
+			in:     "",
+			expOut: nil,
+			expErr: true,
+		},
+	} {
+		t.Run(tbl.name, func(t *testing.T) {
+			out, outErr := BoolSlice(tbl.in)
+			if outErr != nil {
+				if !tbl.expErr {
+					t.Errorf("unexpected error for input %q: %s", tbl.in, outErr)
+				}
+				t.Logf("error: %s", outErr)
+				return
+			}
+			if tbl.expErr {
+				t.Errorf("expected error for input %q; got %v", tbl.in, out)
+			}
+			if len(out) != len(tbl.expOut) {
+				t.Errorf("mismatched lengths: got %d; want %d", len(out), len(tbl.expOut))
+			}
+			for i, v := range out {
+				if tbl.expOut[i] != v {
+					t.Errorf("unexpected value at output index %d: got %t; want %t", i, v, tbl.expOut[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package parse
+
+// Delimiters configures the separators used when parsing slices, sets and
+// maps. Zero-valued fields fall back to the defaults (see
+// DefaultDelimiters), so callers only need to set the ones they want to
+// override.
+type Delimiters struct {
+	// Element separates the elements of slices and sets, as well as the
+	// key-value pairs of maps (default ',').
+	Element rune
+	// KeyValue separates keys from values within a map's key-value pair
+	// (default ':').
+	KeyValue rune
+}
+
+// DefaultDelimiters returns the delimiters used by String and the other
+// parsing functions that don't take a Delimiters argument.
+func DefaultDelimiters() Delimiters {
+	return Delimiters{Element: ',', KeyValue: ':'}
+}
+
+// WithDefaults returns a copy of d with any unset delimiters set to their
+// default values.
+func (d Delimiters) WithDefaults() Delimiters {
+	def := DefaultDelimiters()
+	if d.Element == 0 {
+		d.Element = def.Element
+	}
+	if d.KeyValue == 0 {
+		d.KeyValue = def.KeyValue
+	}
+	return d
+}
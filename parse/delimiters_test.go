@@ -0,0 +1,88 @@
+package parse
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStringWithDelimiters(t *testing.T) {
+	for _, itbl := range []struct {
+		name        string
+		input       string
+		delims      Delimiters
+		typ         reflect.Type
+		expected    any
+		expectedErr string
+	}{
+		{
+			name:     "semicolon_string_slice",
+			input:    `a,b; "c;d" ;e`,
+			delims:   Delimiters{Element: ';'},
+			typ:      reflect.TypeOf([]string{}),
+			expected: []string{"a,b", "c;d", "e"},
+		},
+		{
+			name:     "semicolon_int_slice",
+			input:    `1;2;3`,
+			delims:   Delimiters{Element: ';'},
+			typ:      reflect.TypeOf([]int{}),
+			expected: []int{1, 2, 3},
+		},
+		{
+			name:     "semicolon_string_set",
+			input:    `a,b;c`,
+			delims:   Delimiters{Element: ';'},
+			typ:      reflect.TypeOf(map[string]struct{}{}),
+			expected: map[string]struct{}{"a,b": {}, "c": {}},
+		},
+		{
+			name:     "equals_map",
+			input:    `a:1=x,y; b=z`,
+			delims:   Delimiters{Element: ';', KeyValue: '='},
+			typ:      reflect.TypeOf(map[string]string{}),
+			expected: map[string]string{"a:1": "x,y", "b": "z"},
+		},
+		{
+			name:     "equals_string_slice_map",
+			input:    `a=x, a=y, b=z`,
+			delims:   Delimiters{KeyValue: '='},
+			typ:      reflect.TypeOf(map[string][]string{}),
+			expected: map[string][]string{"a": {"x", "y"}, "b": {"z"}},
+		},
+		{
+			name:     "equals_int_slice_map",
+			input:    `a=1; a=2; b=3`,
+			delims:   Delimiters{Element: ';', KeyValue: '='},
+			typ:      reflect.TypeOf(map[string][]int{}),
+			expected: map[string][]int{"a": {1, 2}, "b": {3}},
+		},
+		{
+			name:     "defaults",
+			input:    `a:1,b:2`,
+			typ:      reflect.TypeOf(map[string]int{}),
+			expected: map[string]int{"a": 1, "b": 2},
+		},
+		{
+			name:        "unexpected_key_value_delimiter",
+			input:       `=a`,
+			delims:      Delimiters{KeyValue: '='},
+			typ:         reflect.TypeOf(map[string]string{}),
+			expectedErr: `unexpected key-value delimiter '='`,
+		},
+	} {
+		tbl := itbl
+		t.Run(tbl.name, func(t *testing.T) {
+			t.Parallel()
+			v, err := StringWithDelimiters(tbl.input, tbl.typ, tbl.delims)
+			if tbl.expectedErr != "" {
+				assert.EqualError(t, err, tbl.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tbl.expected, v.Interface())
+		})
+	}
+}
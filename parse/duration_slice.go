@@ -0,0 +1,24 @@
+package parse
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DurationSlice splits on commas and parses into a slice of durations
+// Parses with time.ParseDuration, so values must have units (e.g. "100ms,1s,5s").
+// Whitespace is trimmed around the durations before parsing to allow for reasonable separtion (shell word-splitting aside)
+func DurationSlice(s string) ([]time.Duration, error) {
+	parts := strings.Split(s, ",")
+	out := make([]time.Duration, len(parts))
+
+	for i, p := range parts {
+		val, parseErr := time.ParseDuration(strings.TrimSpace(p))
+		if parseErr != nil {
+			return nil, fmt.Errorf("failed to parse duration index %d: %w", i, parseErr)
+		}
+		out[i] = val
+	}
+	return out, nil
+}
@@ -0,0 +1,59 @@
+package parse
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDurationSlice(t *testing.T) {
+	for _, tbl := range []struct {
+		name   string
+		in     string
+		expOut []time.Duration
+		expErr bool
+	}{
+		{
+			name:   "good_1_duration",
+			in:     "100ms",
+			expOut: []time.Duration{100 * time.Millisecond},
+			expErr: false,
+		}, {
+			name:   "good_3_duration_interstitial_whitespace",
+			in:     "100ms, 1s,1m5s",
+			expOut: []time.Duration{100 * time.Millisecond, time.Second, time.Minute + 5*time.Second},
+			expErr: false,
+		}, {
+			name:   "bad_1_duration_no_units",
+			in:     "1s,5",
+			expOut: nil,
+			expErr: true,
+		}, {
+			name:   "bad_empty",
+			in:     "",
+			expOut: nil,
+			expErr: true,
+		},
+	} {
+		t.Run(tbl.name, func(t *testing.T) {
+			out, outErr := DurationSlice(tbl.in)
+			if outErr != nil {
+				if !tbl.expErr {
+					t.Errorf("unexpected error for input %q: %s", tbl.in, outErr)
+				}
+				t.Logf("error: %s", outErr)
+				return
+			}
+			if tbl.expErr {
+				t.Errorf("expected error for input %q; got %v", tbl.in, out)
+			}
+			if len(out) != len(tbl.expOut) {
+				t.Errorf("mismatched lengths: got %d; want %d", len(out), len(tbl.expOut))
+			}
+			for i, v := range out {
+				if tbl.expOut[i] != v {
+					t.Errorf("unexpected value at output index %d: got %s; want %s", i, v, tbl.expOut[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package parse
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// FloatSlice splits on commas and parses into a slice of floats
+// Parses with strconv.ParseFloat with the bitsize of the element type, so
+// values that overflow float32 are rejected.
+// Whitespace is trimmed around the floats before parsing to allow for reasonable separtion (shell word-splitting aside)
+func FloatSlice[F float32 | float64](s string) ([]F, error) {
+	parts := strings.Split(s, ",")
+	out := make([]F, len(parts))
+
+	bitSize := int(unsafe.Sizeof(F(0)) * 8)
+
+	for i, p := range parts {
+		val, parseErr := strconv.ParseFloat(strings.TrimSpace(p), bitSize)
+		if parseErr != nil {
+			return nil, fmt.Errorf("failed to parse float index %d: %w", i, parseErr)
+		}
+		out[i] = F(val)
+	}
+	return out, nil
+}
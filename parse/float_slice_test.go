@@ -0,0 +1,114 @@
+package parse
+
+import "testing"
+
+func TestFloatSliceFloat64s(t *testing.T) {
+	for _, tbl := range []struct {
+		name   string
+		in     string
+		expOut []float64
+		expErr bool
+	}{
+		{
+			name:   "good_1_float",
+			in:     "1.5",
+			expOut: []float64{1.5},
+			expErr: false,
+		}, {
+			name:   "good_2_float_interstitial_whitespace",
+			in:     "1.5, -2.25",
+			expOut: []float64{1.5, -2.25},
+			expErr: false,
+		}, {
+			name:   "good_3_float_exponent",
+			in:     "1e3,2E-2,7",
+			expOut: []float64{1e3, 2e-2, 7},
+			expErr: false,
+		}, {
+			name:   "good_1_float_beyond_float32",
+			in:     "1e300",
+			expOut: []float64{1e300},
+			expErr: false,
+		}, {
+			name:   "bad_1_float_not_a_number",
+			in:     "1.5,fizzbuzz",
+			expOut: nil,
+			expErr: true,
+		}, {
+			name:   "bad_empty",
+			in:     "",
+			expOut: nil,
+			expErr: true,
+		}, {
+			name:   "bad_1_float_overflow",
+			in:     "1e400",
+			expOut: nil,
+			expErr: true,
+		},
+	} {
+		t.Run(tbl.name, func(t *testing.T) {
+			out, outErr := FloatSlice[float64](tbl.in)
+			if outErr != nil {
+				if !tbl.expErr {
+					t.Errorf("unexpected error for input %q: %s", tbl.in, outErr)
+				}
+				t.Logf("error: %s", outErr)
+				return
+			}
+			if tbl.expErr {
+				t.Errorf("expected error for input %q; got %v", tbl.in, out)
+			}
+			if len(out) != len(tbl.expOut) {
+				t.Errorf("mismatched lengths: got %d; want %d", len(out), len(tbl.expOut))
+			}
+			for i, v := range out {
+				if tbl.expOut[i] != v {
+					t.Errorf("unexpected value at output index %d: got %g; want %g", i, v, tbl.expOut[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFloatSliceFloat32s(t *testing.T) {
+	for _, tbl := range []struct {
+		name   string
+		in     string
+		expOut []float32
+		expErr bool
+	}{
+		{
+			name:   "good_2_float",
+			in:     "1.5,-2.25",
+			expOut: []float32{1.5, -2.25},
+			expErr: false,
+		}, {
+			name:   "bad_1_float_overflow",
+			in:     "1.5,1e300",
+			expOut: nil,
+			expErr: true,
+		},
+	} {
+		t.Run(tbl.name, func(t *testing.T) {
+			out, outErr := FloatSlice[float32](tbl.in)
+			if outErr != nil {
+				if !tbl.expErr {
+					t.Errorf("unexpected error for input %q: %s", tbl.in, outErr)
+				}
+				t.Logf("error: %s", outErr)
+				return
+			}
+			if tbl.expErr {
+				t.Errorf("expected error for input %q; got %v", tbl.in, out)
+			}
+			if len(out) != len(tbl.expOut) {
+				t.Errorf("mismatched lengths: got %d; want %d", len(out), len(tbl.expOut))
+			}
+			for i, v := range out {
+				if tbl.expOut[i] != v {
+					t.Errorf("unexpected value at output index %d: got %g; want %g", i, v, tbl.expOut[i])
+				}
+			}
+		})
+	}
+}
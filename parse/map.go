@@ -8,13 +8,19 @@ import (
 // Map converts a string representation of a map with concrete values as
 // keys and vals into a reflect.Value representing that map.
 func Map(s string, mapType reflect.Type) (reflect.Value, error) {
+	return MapWithDelimiters(s, mapType, DefaultDelimiters())
+}
+
+// MapWithDelimiters is like Map, but splits key-value pairs on d.Element
+// and keys from values on d.KeyValue.
+func MapWithDelimiters(s string, mapType reflect.Type, d Delimiters) (reflect.Value, error) {
 	m := reflect.MakeMap(mapType)
 	keyType := mapType.Key()
 	valType := mapType.Elem()
 
-	splitErr := splitMap(s,
+	splitErr := splitMap(s, d,
 		func(newKeyStr, newValStr string) error {
-			newKeyCast, err := String(newKeyStr, keyType)
+			newKeyCast, err := StringWithDelimiters(newKeyStr, keyType, d)
 			if err != nil {
 				return fmt.Errorf("Error casting map key")
 			}
@@ -24,7 +30,7 @@ func Map(s string, mapType reflect.Type) (reflect.Value, error) {
 				return fmt.Errorf("duplicate key %q, already has value %q", newKeyCast.Elem(), val)
 			}
 
-			newValCast, err := String(newValStr, valType)
+			newValCast, err := StringWithDelimiters(newValStr, valType, d)
 			if err != nil {
 				return fmt.Errorf("Error casting map val")
 			}
@@ -40,3 +46,46 @@ func Map(s string, mapType reflect.Type) (reflect.Value, error) {
 
 	return m, nil
 }
+
+// SliceValuedMap converts a string representation of a map with concrete
+// values as keys and slices of concrete values as vals into a reflect.Value
+// representing that map.  As with StringStringSliceMap, repeating a key
+// appends to that key's slice (e.g. `"a": 1, "a": 2, "b": 3`).
+func SliceValuedMap(s string, mapType reflect.Type) (reflect.Value, error) {
+	return SliceValuedMapWithDelimiters(s, mapType, DefaultDelimiters())
+}
+
+// SliceValuedMapWithDelimiters is like SliceValuedMap, but splits key-value
+// pairs on d.Element and keys from values on d.KeyValue.
+func SliceValuedMapWithDelimiters(s string, mapType reflect.Type, d Delimiters) (reflect.Value, error) {
+	m := reflect.MakeMap(mapType)
+	keyType := mapType.Key()
+	elemType := mapType.Elem().Elem()
+
+	splitErr := splitMap(s, d,
+		func(newKeyStr, newValStr string) error {
+			newKeyCast, err := StringWithDelimiters(newKeyStr, keyType, d)
+			if err != nil {
+				return fmt.Errorf("Error casting map key")
+			}
+
+			newValCast, err := StringWithDelimiters(newValStr, elemType, d)
+			if err != nil {
+				return fmt.Errorf("Error casting map val %q for key %q: %s", newValStr, newKeyStr, err)
+			}
+
+			cur := m.MapIndex(newKeyCast.Elem())
+			if !cur.IsValid() {
+				cur = reflect.MakeSlice(mapType.Elem(), 0, 1)
+			}
+			m.SetMapIndex(newKeyCast.Elem(), reflect.Append(cur, newValCast.Elem()))
+
+			return nil
+		})
+
+	if splitErr != nil {
+		return reflect.Value{}, splitErr
+	}
+
+	return m, nil
+}
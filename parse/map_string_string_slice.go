@@ -4,8 +4,14 @@ package parse
 // strings as keys and string slices as values, and converts it to a
 // map[string][]string.
 func StringStringSliceMap(s string) (map[string][]string, error) {
+	return StringStringSliceMapWithDelimiters(s, DefaultDelimiters())
+}
+
+// StringStringSliceMapWithDelimiters is like StringStringSliceMap, but
+// splits key-value pairs on d.Element and keys from values on d.KeyValue.
+func StringStringSliceMapWithDelimiters(s string, d Delimiters) (map[string][]string, error) {
 	ss := map[string][]string{}
-	splitErr := splitMap(s,
+	splitErr := splitMap(s, d,
 		func(k, v string) error {
 			ss[k] = append(ss[k], v)
 			return nil
@@ -124,3 +124,43 @@ func TestParseMapForStringStringMaps(t *testing.T) {
 		})
 	}
 }
+
+func TestSliceValuedMap(t *testing.T) {
+	for _, itbl := range []struct {
+		name        string
+		input       string
+		mapType     reflect.Type
+		expected    any
+		expectedErr error
+	}{
+		{
+			name:     "string_int_slice",
+			input:    `"tenant-a": 1, "tenant-b": 2, "tenant-a": 3`,
+			mapType:  reflect.TypeOf(map[string][]int{}),
+			expected: map[string][]int{"tenant-a": {1, 3}, "tenant-b": {2}},
+		},
+		{
+			name:     "uint8_bool_slice",
+			input:    `1: true, 1: false, 7: t`,
+			mapType:  reflect.TypeOf(map[uint8][]bool{}),
+			expected: map[uint8][]bool{1: {true, false}, 7: {true}},
+		},
+		{
+			name:        "overflowing_element",
+			input:       `"a": 1, "a": 300`,
+			mapType:     reflect.TypeOf(map[string][]int8{}),
+			expectedErr: fmt.Errorf("map parsing failed on key \"a\": Error casting map val \"300\" for key \"a\": overflow of int8 type: 300"),
+		},
+	} {
+		tbl := itbl
+		t.Run(tbl.name, func(t *testing.T) {
+			m, err := SliceValuedMap(tbl.input, tbl.mapType)
+			if tbl.expectedErr != nil {
+				assert.EqualError(t, err, tbl.expectedErr.Error())
+				return
+			}
+			require.NoError(t, err)
+			assert.EqualValues(t, tbl.expected, m.Interface())
+		})
+	}
+}
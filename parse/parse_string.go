@@ -9,6 +9,12 @@ import (
 // String casts the provided string into the provided type, returning the
 // result in a reflect.Value.
 func String(str string, t reflect.Type) (reflect.Value, error) {
+	return StringWithDelimiters(str, t, DefaultDelimiters())
+}
+
+// StringWithDelimiters is like String, but uses the separators in d when
+// parsing slices, sets and maps.
+func StringWithDelimiters(str string, t reflect.Type, d Delimiters) (reflect.Value, error) {
 	switch t.Kind() {
 	case reflect.String:
 		return reflect.ValueOf(&str), nil
@@ -24,7 +30,7 @@ func String(str string, t reflect.Type) (reflect.Value, error) {
 		reflect.Complex64, reflect.Complex128:
 		return parseNumber(str, t)
 	case reflect.Slice:
-		converted, err := StringSlice(str)
+		converted, err := StringSliceWithDelimiters(str, d)
 		if err != nil {
 			return reflect.Value{}, err
 		}
@@ -34,7 +40,7 @@ func String(str string, t reflect.Type) (reflect.Value, error) {
 		}
 		castSlice := reflect.MakeSlice(t, 0, len(converted))
 		for idx, strVal := range converted {
-			castVal, parseErr := String(strVal, t.Elem())
+			castVal, parseErr := StringWithDelimiters(strVal, t.Elem(), d)
 			if parseErr != nil {
 				return reflect.Value{}, fmt.Errorf("parse error of item %d %q: %s", idx, strVal, parseErr)
 			}
@@ -45,13 +51,13 @@ func String(str string, t reflect.Type) (reflect.Value, error) {
 	case reflect.Map:
 		switch t {
 		case reflect.TypeOf(map[string][]string{}):
-			converted, err := StringStringSliceMap(str)
+			converted, err := StringStringSliceMapWithDelimiters(str, d)
 			if err != nil {
 				return reflect.Value{}, err
 			}
 			return reflect.ValueOf(converted), nil
 		case reflect.TypeOf(map[string]struct{}{}):
-			converted, err := StringSet(str)
+			converted, err := StringSetWithDelimiters(str, d)
 			if err != nil {
 				return reflect.Value{}, err
 			}
@@ -59,11 +65,17 @@ func String(str string, t reflect.Type) (reflect.Value, error) {
 		default:
 			keyKind := t.Key().Kind()
 			valKind := t.Elem().Kind()
+			if valKind == reflect.Slice {
+				if err := checkKindsSupported(keyKind, t.Elem().Elem().Kind()); err != nil {
+					return reflect.Value{}, fmt.Errorf("unsupported map type: %v", t)
+				}
+				return SliceValuedMapWithDelimiters(str, t, d)
+			}
 			err := checkKindsSupported(keyKind, valKind)
 			if err != nil {
 				return reflect.Value{}, fmt.Errorf("unsupported map type: %v", t)
 			}
-			converted, err := Map(str, t)
+			converted, err := MapWithDelimiters(str, t, d)
 			if err != nil {
 				return reflect.Value{}, err
 			}
@@ -9,8 +9,9 @@ import (
 )
 
 // splitMap splits the values that are csv key:value-pairs of strings in a
-// string into a map[string][]string.
-func splitMap(s string, addKV func(k, v string) error) error {
+// string into a map[string][]string. Non-default separators may be set in d.
+func splitMap(s string, d Delimiters, addKV func(k, v string) error) error {
+	d = d.WithDefaults()
 	errs := map[scanner.Position]string{}
 
 	// initialize the scanner (note: sc.Init() blindly overwrites fields with sane-defaults)
@@ -24,13 +25,16 @@ func splitMap(s string, addKV func(k, v string) error) error {
 		errs[s.Pos()] = msg
 	}
 	// Override the IsIdentRune callback. Note that this differs from
-	// similar callback in splitStringsSlice() by the presence of colon
-	// (`:`) in the disallow-list rather than the allow-list, as maps use
-	// colons to separate keys and values, while they have no meaning for
-	// string-sets and string-slices.
+	// similar callback in splitStringsSlice() by the presence of the
+	// key-value delimiter (colon `:` by default) in the disallow-list
+	// rather than the allow-list, as maps use it to separate keys and
+	// values, while it has no meaning for string-sets and string-slices.
 	sc.IsIdentRune = func(ch rune, i int) bool {
+		if ch == d.Element || ch == d.KeyValue {
+			return false
+		}
 		switch ch {
-		case '\\', ',', '"', '\'', '`', '\000', ':':
+		case '\\', '"', '\'', '`', '\000':
 			return false
 		case '.', '/', '+', '-', '$', '%':
 			// A few special characters we want to guarantee are
@@ -74,7 +78,7 @@ func splitMap(s string, addKV func(k, v string) error) error {
 				return fmt.Errorf("unexpected string literal: %s",
 					sc.TokenText())
 			}
-		case ',':
+		case d.Element:
 			if curKey != "" {
 				if addErr := addKV(curKey, curVal); addErr != nil {
 					return fmt.Errorf("map parsing failed on key %q: %s",
@@ -86,8 +90,11 @@ func splitMap(s string, addKV func(k, v string) error) error {
 			curVal = ""
 			inKey = true
 			inValue = false
-		case ':':
+		case d.KeyValue:
 			if inValue || curKey == "" {
+				if d.KeyValue != ':' {
+					return fmt.Errorf("unexpected key-value delimiter %q", d.KeyValue)
+				}
 				return fmt.Errorf("unexpected colon")
 			}
 			inKey = false
@@ -8,12 +8,14 @@ import (
 	"unicode"
 )
 
-// splitStringsSlice splits up a string composed of comma-separated values.
+// splitStringsSlice splits up a string composed of comma-separated values
+// (or separated by d.Element if set).
 // Destination type determined by func passed in.
-func splitStringsSlice(s string, addVal func(val string) error) error {
+func splitStringsSlice(s string, d Delimiters, addVal func(val string) error) error {
 	if len(s) == 0 {
 		return nil
 	}
+	d = d.WithDefaults()
 	errs := map[scanner.Position]string{}
 
 	inValue := true
@@ -28,8 +30,11 @@ func splitStringsSlice(s string, addVal func(val string) error) error {
 		errs[s.Pos()] = msg
 	}
 	sc.IsIdentRune = func(ch rune, i int) bool {
+		if ch == d.Element {
+			return false
+		}
 		switch ch {
-		case '\\', ',', '"', '\'', '`', '\000':
+		case '\\', '"', '\'', '`', '\000':
 			return false
 		case '.', ':', '/', '+', '-', '$', '%':
 			// A few special characters we want to guarantee are
@@ -69,7 +74,7 @@ func splitStringsSlice(s string, addVal func(val string) error) error {
 					sc.TokenText())
 			}
 			inValue = false
-		case ',':
+		case d.Element:
 			inValue = true
 		default:
 			return fmt.Errorf("unexpected token %s with value: %q",
@@ -7,9 +7,15 @@ import (
 // StringSet splits a comma separated string and constructs a
 // map[string]struct{} to represent a set
 func StringSet(s string) (map[string]struct{}, error) {
+	return StringSetWithDelimiters(s, DefaultDelimiters())
+}
+
+// StringSetWithDelimiters splits a string on d.Element and constructs a
+// map[string]struct{} to represent a set
+func StringSetWithDelimiters(s string, d Delimiters) (map[string]struct{}, error) {
 	ss := map[string]struct{}{}
 
-	splitErr := splitStringsSlice(s, func(val string) error {
+	splitErr := splitStringsSlice(s, d, func(val string) error {
 		if _, present := ss[val]; present {
 			return fmt.Errorf("%q already present in set", val)
 		}
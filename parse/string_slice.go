@@ -2,9 +2,15 @@ package parse
 
 // StringSlice splits a comma separated string and constructs a slice
 func StringSlice(s string) ([]string, error) {
+	return StringSliceWithDelimiters(s, DefaultDelimiters())
+}
+
+// StringSliceWithDelimiters splits a string on d.Element and constructs a
+// slice
+func StringSliceWithDelimiters(s string, d Delimiters) ([]string, error) {
 	ss := []string{}
 
-	splitErr := splitStringsSlice(s, func(val string) error {
+	splitErr := splitStringsSlice(s, d, func(val string) error {
 		ss = append(ss, val)
 		return nil
 	})
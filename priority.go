@@ -0,0 +1,115 @@
+package dials
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// sourcePriority is a request to move source to index in the list of
+// sources.
+type sourcePriority struct {
+	source Source
+	index  int
+}
+
+// SetSourcePriority moves source (one of the Sources passed to Config) to
+// position index in the precedence order, shifting the sources in between
+// accordingly, and re-stacks the configuration with the same source values
+// (no source is re-read). Index 0 has the lowest precedence, and
+// len(Sources())-1 the highest. e.g. to temporarily let a remote source
+// override flags, move it to the end, and move it back to its original
+// index later.
+//
+// If the resulting configuration differs from the current one (and passes
+// verification, if enabled), it's installed as a new version, with the
+// usual notifications on the Events channel and to callbacks. If
+// re-stacking or verification fails, the previous order is restored, and
+// the error is returned.
+//
+// SetSourcePriority is safe to call concurrently with other methods: like
+// Reload, it's handled by the goroutine handling updates from watching
+// sources (if there is one), so reordering is serialized with new values
+// from sources.
+func (d *Dials[T]) SetSourcePriority(ctx context.Context, source Source, index int) error {
+	req := reloadRequest[T]{priority: &sourcePriority{source: source, index: index}}
+	if _, handled, err := d.sendReloadRequest(ctx, req); handled {
+		return err
+	}
+
+	rs := d.reload
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	oldConfig := d.View()
+	newConfig, warnings, err := d.reprioritize(ctx, rs.base, rs.skipVerify, rs.computed, *req.priority)
+	if err != nil {
+		return err
+	}
+	if newConfig != oldConfig {
+		d.notifyDirect(ctx, oldConfig, newConfig, warnings, rs.skipVerify, rs.computed)
+	}
+	return nil
+}
+
+// reprioritize moves the requested source within sourceValues (and the
+// list of sources), re-stacks and installs the result if it differs from
+// the current version. It returns the current version if nothing changed.
+func (d *Dials[T]) reprioritize(
+	ctx context.Context,
+	t *T,
+	skipVerify bool,
+	sourceValues []sourceValue,
+	p sourcePriority,
+) (*T, []error, error) {
+	from := -1
+	for i, sv := range sourceValues {
+		if sv.source == p.source {
+			from = i
+			break
+		}
+	}
+	if from < 0 {
+		return nil, nil, errors.New("unknown source")
+	}
+	if p.index < 0 || p.index >= len(sourceValues) {
+		return nil, nil, fmt.Errorf("index %d out of range [0, %d)", p.index, len(sourceValues))
+	}
+	if from == p.index {
+		return d.View(), nil, nil
+	}
+
+	prev := append([]sourceValue(nil), sourceValues...)
+	moveElem(sourceValues, from, p.index)
+	newVers, warnings, err := d.restack(ctx, t, skipVerify, sourceValues)
+	if err != nil {
+		copy(sourceValues, prev)
+		d.params.log(ctx, LogLevelWarn, "rejected reprioritized configuration", "error", err)
+		return nil, nil, fmt.Errorf("failed to restack with source of type %T at index %d: %w",
+			p.source, p.index, err)
+	}
+
+	rs := d.reload
+	rs.sourcesMu.Lock()
+	for i, sv := range sourceValues {
+		rs.sources[i] = sv.source
+	}
+	rs.sourcesMu.Unlock()
+
+	if cur := d.View(); reflect.DeepEqual(cur, newVers) {
+		return cur, nil, nil
+	}
+	d.install(newVers)
+	return newVers, warnings, nil
+}
+
+// moveElem moves s[from] to s[to], shifting the elements in between.
+func moveElem[E any](s []E, from, to int) {
+	e := s[from]
+	if from < to {
+		copy(s[from:to], s[from+1:to+1])
+	} else {
+		copy(s[to+1:from+1], s[to:from])
+	}
+	s[to] = e
+}
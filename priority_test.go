@@ -0,0 +1,92 @@
+package dials
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetSourcePriority(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	a, b, bad := "a", "b", "bad"
+	srcA := fakeSource{outVal: reloadTestPtrConfig{Foo: &a, Bar: &a}}
+	srcB := fakeSource{outVal: reloadTestPtrConfig{Foo: &b}}
+	srcBad := fakeSource{outVal: reloadTestPtrConfig{Foo: &bad}}
+	d, err := Config(ctx, &reloadTestConfig{}, &srcBad, &srcA, &srcB)
+	require.NoError(t, err)
+	assert.Equal(t, &reloadTestConfig{Foo: "b", Bar: "a"}, d.View())
+
+	require.NoError(t, d.SetSourcePriority(ctx, &srcA, 2))
+	assert.Equal(t, []Source{&srcBad, &srcB, &srcA}, d.Sources())
+	assert.Equal(t, &reloadTestConfig{Foo: "a", Bar: "a"}, d.View())
+	assert.Equal(t, &reloadTestConfig{Foo: "a", Bar: "a"}, <-d.Events())
+
+	// moving to the current position is a no-op
+	_, serial := d.ViewVersion()
+	require.NoError(t, d.SetSourcePriority(ctx, &srcA, 2))
+	_, newSerial := d.ViewVersion()
+	assert.Equal(t, serial, newSerial)
+
+	// the order is retained for reloads
+	cfg, reloadErr := d.Reload(ctx)
+	require.NoError(t, reloadErr)
+	assert.Equal(t, &reloadTestConfig{Foo: "a", Bar: "a"}, cfg)
+
+	// verification failures leave the order unchanged
+	assert.EqualError(t, d.SetSourcePriority(ctx, &srcBad, 2),
+		"failed to restack with source of type *dials.fakeSource at index 2: bad foo")
+	assert.Equal(t, []Source{&srcBad, &srcB, &srcA}, d.Sources())
+	assert.Equal(t, &reloadTestConfig{Foo: "a", Bar: "a"}, d.View())
+
+	assert.EqualError(t, d.SetSourcePriority(ctx, &fakeSource{}, 0), "unknown source")
+	assert.EqualError(t, d.SetSourcePriority(ctx, &srcA, 3), "index 3 out of range [0, 3)")
+	assert.EqualError(t, d.SetSourcePriority(ctx, &srcA, -1), "index -1 out of range [0, 3)")
+}
+
+func TestSetSourcePriorityWithWatcher(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	a, w := "a", "w"
+	src := fakeSource{outVal: reloadTestPtrConfig{Foo: &a}}
+	watcher := fakeWatchingSource{fakeSource: fakeSource{outVal: reloadTestPtrConfig{Foo: &w, Bar: &w}}}
+	newConfs := make(chan *reloadTestConfig, 1)
+	d, err := Params[reloadTestConfig]{
+		OnNewConfig: func(ctx context.Context, oldConfig, newConfig *reloadTestConfig) {
+			newConfs <- newConfig
+		},
+	}.Config(ctx, &reloadTestConfig{}, &watcher, &src)
+	require.NoError(t, err)
+	assert.Equal(t, &reloadTestConfig{Foo: "a", Bar: "w"}, d.View())
+
+	require.NoError(t, d.SetSourcePriority(ctx, &watcher, 1))
+	assert.Equal(t, []Source{&src, &watcher}, d.Sources())
+	assert.Equal(t, &reloadTestConfig{Foo: "w", Bar: "w"}, <-newConfs)
+
+	require.NoError(t, d.SetSourcePriority(ctx, &watcher, 0))
+	assert.Equal(t, []Source{&watcher, &src}, d.Sources())
+	assert.Equal(t, &reloadTestConfig{Foo: "a", Bar: "w"}, <-newConfs)
+}
+
+func TestMoveElem(t *testing.T) {
+	t.Parallel()
+	for _, tbl := range []struct {
+		from, to int
+		expected []int
+	}{
+		{from: 0, to: 3, expected: []int{1, 2, 3, 0}},
+		{from: 3, to: 0, expected: []int{3, 0, 1, 2}},
+		{from: 1, to: 2, expected: []int{0, 2, 1, 3}},
+		{from: 2, to: 2, expected: []int{0, 1, 2, 3}},
+	} {
+		s := []int{0, 1, 2, 3}
+		moveElem(s, tbl.from, tbl.to)
+		assert.Equal(t, tbl.expected, s, "from %d to %d", tbl.from, tbl.to)
+	}
+}
@@ -12,9 +12,12 @@ import (
 // pointer here then take the element type, otherwise you get a nil type and
 // that's not useful (it actually generates a panic when it's used further down).
 var textUnmarshaler = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+var binaryUnmarshaler = reflect.TypeOf((*encoding.BinaryUnmarshaler)(nil)).Elem()
 
 // Pointerify takes a type and returns another type with all its members
-// set to pointers of their respective types
+// set to pointers of their respective types. Nested structs are
+// pointerified recursively, as are the elements of arrays of structs (which
+// become pointers to arrays of pointers to pointerified structs).
 func Pointerify(original reflect.Type, tmpl reflect.Value) reflect.Type {
 	newFields := make([]reflect.StructField, 0, original.NumField())
 
@@ -124,12 +127,13 @@ func pointerifyField(originalField reflect.StructField, tmplFieldVal reflect.Val
 		fallthrough
 	case reflect.Struct:
 		// first check whether this implements
-		// `TextUnmarshaler`, in which case we'll pointerify
-		// this field and leave its type alone.
-		if IsTextUnmarshalerStruct(ft) {
+		// `TextUnmarshaler` or `BinaryUnmarshaler`, in which case
+		// we'll pointerify this field and leave its type alone.
+		if IsUnmarshalerStruct(ft) {
 			return &sf
 		}
-		// It's a struct without an UnmarshalText method, we
+		// It's a struct without an UnmarshalText (or
+		// UnmarshalBinary) method, we
 		// need to recursively pointerify the component fields.
 		pointeredStruct := Pointerify(ft, tmplFieldVal)
 		return &reflect.StructField{
@@ -138,6 +142,14 @@ func pointerifyField(originalField reflect.StructField, tmplFieldVal reflect.Val
 			Tag:       originalField.Tag,
 			Anonymous: originalField.Anonymous,
 		}
+	case reflect.Array:
+		// The elements of arrays of structs are pointerified (as
+		// pointers, so sources may leave them unset), so values from
+		// several sources may be overlaid element by element.
+		if elemT, ok := pointerifiedArrayElem(ft); ok {
+			sf.Type = reflect.PtrTo(reflect.ArrayOf(ft.Len(), elemT))
+		}
+		return &sf
 	case reflect.Chan, reflect.Func:
 		// channels are not configuration, and defaulting to
 		// setting the capacity based on the config value would
@@ -150,6 +162,21 @@ func pointerifyField(originalField reflect.StructField, tmplFieldVal reflect.Val
 	}
 }
 
+// pointerifiedArrayElem returns the pointerified element type for the array
+// type t, if its elements are structs (or pointers to structs) that don't
+// implement encoding.TextUnmarshaler or encoding.BinaryUnmarshaler: a
+// pointer to the pointerified struct.
+func pointerifiedArrayElem(t reflect.Type) (reflect.Type, bool) {
+	elemT := t.Elem()
+	if elemT.Kind() == reflect.Ptr {
+		elemT = elemT.Elem()
+	}
+	if elemT.Kind() != reflect.Struct || IsUnmarshalerStruct(elemT) {
+		return nil, false
+	}
+	return reflect.PtrTo(Pointerify(elemT, reflect.Value{})), true
+}
+
 // IsTextUnmarshalerStruct indicates whether a struct-type implements
 // encoding.TextUnmarshaler either directly or via its pointer-type
 func IsTextUnmarshalerStruct(t reflect.Type) bool {
@@ -159,3 +186,21 @@ func IsTextUnmarshalerStruct(t reflect.Type) bool {
 	return (t.Implements(textUnmarshaler) ||
 		reflect.PtrTo(t).Implements(textUnmarshaler))
 }
+
+// IsBinaryUnmarshalerStruct indicates whether a struct-type implements
+// encoding.BinaryUnmarshaler either directly or via its pointer-type
+func IsBinaryUnmarshalerStruct(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	return (t.Implements(binaryUnmarshaler) ||
+		reflect.PtrTo(t).Implements(binaryUnmarshaler))
+}
+
+// IsUnmarshalerStruct indicates whether a struct-type implements either
+// encoding.TextUnmarshaler or encoding.BinaryUnmarshaler (directly or via
+// its pointer-type). Such structs are treated as leaves: Pointerify
+// pointerifies them whole, rather than descending into their fields.
+func IsUnmarshalerStruct(t reflect.Type) bool {
+	return IsTextUnmarshalerStruct(t) || IsBinaryUnmarshalerStruct(t)
+}
@@ -8,6 +8,17 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+// binU implements encoding.BinaryUnmarshaler (but not TextUnmarshaler)
+type binU struct {
+	N    int
+	Data []byte
+}
+
+func (b *binU) UnmarshalBinary(data []byte) error {
+	b.Data = append([]byte(nil), data...)
+	return nil
+}
+
 func TestPointerify(t *testing.T) {
 	type sInt struct{ J int }
 	type sIntPtr struct{ J *int }
@@ -113,6 +124,26 @@ func TestPointerify(t *testing.T) {
 				T *time.Time
 			}{},
 		},
+		"one_deep_with_binary_unmarshaler": {
+			i: struct {
+				I sInt
+				B binU
+			}{},
+			expected: struct {
+				I *struct{ J *int }
+				B *binU
+			}{},
+		},
+		"one_deep_with_binary_unmarshaler_ptr": {
+			i: struct {
+				I sInt
+				B *binU
+			}{},
+			expected: struct {
+				I *struct{ J *int }
+				B *binU
+			}{},
+		},
 		"one_deep_with_slice": {
 			i: struct {
 				I sInt
@@ -133,6 +164,18 @@ func TestPointerify(t *testing.T) {
 				M *[3]int
 			}{},
 		},
+		"arrays_of_structs": {
+			i: struct {
+				A [2]struct{ J int }
+				B [2]*struct{ L string }
+				T [2]time.Time
+			}{},
+			expected: struct {
+				A *[2]*struct{ J *int }
+				B *[2]*struct{ L *string }
+				T *[2]time.Time
+			}{},
+		},
 		"one_deep_with_chan_func": {
 			i: struct {
 				I sInt
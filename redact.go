@@ -0,0 +1,73 @@
+package dials
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/vimeo/dials/transform"
+)
+
+// Redacted returns a deep copy of cfg with any fields tagged
+// `dialssecret:"true"` redacted (see transform.RedactMangler), suitable for
+// logging or dumping (see Dials.Dump). cfg itself is left unmodified.
+// It returns an error if T can't be translated for redaction.
+func Redacted[T any](cfg *T) (*T, error) {
+	return redactWith(cfg, &transform.RedactMangler{})
+}
+
+// zeroSecrets returns a deep copy of cfg with any fields tagged
+// `dialssecret:"true"` zeroed.
+func zeroSecrets[T any](cfg *T) (*T, error) {
+	return redactWith(cfg, &transform.RedactMangler{Zero: true})
+}
+
+// callbackConfig returns cfg as it should be passed to callbacks: with any
+// secrets zeroed if ZeroSecretsInCallbacks is set, and unmodified otherwise.
+// If the secrets can't be zeroed, the error is logged and cfg is returned
+// unmodified, rather than failing the callback (or the goroutine calling
+// it).
+func (p *Params[T]) callbackConfig(cfg *T) *T {
+	if !p.ZeroSecretsInCallbacks {
+		return cfg
+	}
+	zeroed, err := zeroSecrets(cfg)
+	if err != nil {
+		p.log(context.Background(), LogLevelError, "failed to zero secrets in configuration passed to callbacks",
+			"error", err)
+		return cfg
+	}
+	return zeroed
+}
+
+func redactWith[T any](cfg *T, rm *transform.RedactMangler) (*T, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	cp := realDeepCopy(cfg)
+	tfmr := transform.NewTransformer(cp.Type().Elem(), rm)
+	mangledType, typeErr := tfmr.TranslateType()
+	if typeErr != nil {
+		return nil, fmt.Errorf("failed to translate type %s for redaction: %w", cp.Type().Elem(), typeErr)
+	}
+	// RedactMangler leaves the fields alone, so the translated type
+	// consists of the (exported) fields of T with the same names and
+	// types.
+	mangled := reflect.New(mangledType).Elem()
+	for i := 0; i < mangledType.NumField(); i++ {
+		mangled.Field(i).Set(cp.Elem().FieldByName(mangledType.Field(i).Name))
+	}
+	redacted, redactErr := tfmr.ReverseTranslate(mangled)
+	if redactErr != nil {
+		return nil, fmt.Errorf("failed to redact value of type %s: %w", cp.Type().Elem(), redactErr)
+	}
+	// copy the redacted exported fields over the copy, so any unexported
+	// fields are retained.
+	for i := 0; i < redacted.NumField(); i++ {
+		if !redacted.Type().Field(i).IsExported() {
+			continue
+		}
+		cp.Elem().Field(i).Set(redacted.Field(i))
+	}
+	return cp.Interface().(*T), nil
+}
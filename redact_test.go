@@ -0,0 +1,177 @@
+package dials
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedacted(t *testing.T) {
+	t.Parallel()
+
+	type token string
+	type credentials struct {
+		User     string
+		Password string `dialssecret:"true"`
+	}
+	type config struct {
+		Name     string
+		APIKey   token          `dialssecret:"true"`
+		Secret   *string        `dialssecret:"true"`
+		Unset    *string        `dialssecret:"true"`
+		Pin      int            `dialssecret:"true"`
+		Keys     []string       `dialssecret:"true"`
+		Timeout  time.Duration  `dialssecret:"false"`
+		DB       credentials    `dials:"db"`
+		Replicas []*credentials `dials:"replicas"`
+		ByName   map[string]credentials
+		Any      interface{}
+		private  string
+	}
+
+	secret := "hunter2"
+	cfg := config{
+		Name:     "app",
+		APIKey:   "abc123",
+		Secret:   &secret,
+		Pin:      1234,
+		Keys:     []string{"a", "b"},
+		Timeout:  time.Second,
+		DB:       credentials{User: "admin", Password: "swordfish"},
+		Replicas: []*credentials{{User: "r1", Password: "p1"}, nil},
+		ByName:   map[string]credentials{"x": {User: "x", Password: "px"}},
+		Any:      &credentials{User: "iface", Password: "pi"},
+		private:  "kept",
+	}
+
+	redacted, err := Redacted(&cfg)
+	require.NoError(t, err)
+	assert.Equal(t, &config{
+		Name:     "app",
+		APIKey:   "****",
+		Secret:   func() *string { s := "****"; return &s }(),
+		Timeout:  time.Second,
+		DB:       credentials{User: "admin", Password: "****"},
+		Replicas: []*credentials{{User: "r1", Password: "****"}, nil},
+		ByName:   map[string]credentials{"x": {User: "x", Password: "****"}},
+		Any:      &credentials{User: "iface", Password: "****"},
+		private:  "kept",
+	}, redacted)
+
+	// the original is untouched
+	assert.Equal(t, "hunter2", *cfg.Secret)
+	assert.Equal(t, "swordfish", cfg.DB.Password)
+	assert.Equal(t, "p1", cfg.Replicas[0].Password)
+	assert.Equal(t, "px", cfg.ByName["x"].Password)
+	assert.Equal(t, "pi", cfg.Any.(*credentials).Password)
+
+	nilRedacted, err := Redacted[config](nil)
+	require.NoError(t, err)
+	assert.Nil(t, nilRedacted)
+}
+
+func TestZeroSecretsInCallbacks(t *testing.T) {
+	t.Parallel()
+	type testConfig struct {
+		User     string
+		Password string `dialssecret:"true"`
+	}
+	type ptrifiedConfig struct {
+		User     *string
+		Password *string `dialssecret:"true"`
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	base := testConfig{User: "foo", Password: "hunter2"}
+	oldConf := make(chan *testConfig, 1)
+	newConf := make(chan *testConfig, 1)
+	w := fakeWatchingSource{fakeSource: fakeSource{outVal: ptrifiedConfig{}}}
+	d, err := Params[testConfig]{
+		OnNewConfig: func(ctx context.Context, oldConfig, newConfig *testConfig) {
+			oldConf <- oldConfig
+			newConf <- newConfig
+		},
+		ZeroSecretsInCallbacks: true,
+	}.Config(ctx, &base, &w)
+	require.NoError(t, err)
+
+	user, password := "bar", "correct horse"
+	w.send(ctx, reflect.ValueOf(ptrifiedConfig{User: &user, Password: &password}))
+
+	assert.Equal(t, &testConfig{User: "foo"}, <-oldConf)
+	assert.Equal(t, &testConfig{User: "bar"}, <-newConf)
+	assert.Equal(t, &testConfig{User: "bar"}, <-d.Events())
+	assert.Equal(t, &testConfig{User: "bar", Password: "correct horse"}, d.View())
+}
+
+type secretWarnConfig struct {
+	User     string
+	Password string `dialssecret:"true"`
+}
+
+func (s *secretWarnConfig) VerifyWithWarnings() ([]error, error) {
+	if s.User == "bad" {
+		return nil, errors.New("bad user")
+	}
+	return []error{errors.New("weak password")}, nil
+}
+
+func TestZeroSecretsInAllCallbacks(t *testing.T) {
+	t.Parallel()
+	type ptrifiedConfig struct {
+		User     *string
+		Password *string `dialssecret:"true"`
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	warned := make(chan *secretWarnConfig, 2)
+	watchErrs := make(chan [2]*secretWarnConfig, 1)
+	w := fakeWatchingSource{fakeSource: fakeSource{outVal: ptrifiedConfig{}}}
+	d, err := Params[secretWarnConfig]{
+		OnVerifyWarnings: func(ctx context.Context, warnings []error, cfg *secretWarnConfig) {
+			warned <- cfg
+		},
+		OnWatchedError: func(ctx context.Context, err error, oldConfig, newConfig *secretWarnConfig) {
+			watchErrs <- [2]*secretWarnConfig{oldConfig, newConfig}
+		},
+		ZeroSecretsInCallbacks: true,
+	}.Config(ctx, &secretWarnConfig{User: "foo", Password: "hunter2"}, &w)
+	require.NoError(t, err)
+	assert.Equal(t, &secretWarnConfig{User: "foo"}, <-warned)
+
+	_, serial := d.ViewVersion()
+	registered := make(chan *secretWarnConfig, 1)
+	unregister := d.RegisterCallback(ctx, serial, func(ctx context.Context, oldConfig, newConfig *secretWarnConfig) {
+		registered <- newConfig
+	})
+	require.NotNil(t, unregister)
+	defer unregister(ctx)
+	// a change to only the secret is still detected, though the
+	// callback doesn't get to see it
+	pathChanged := make(chan *secretWarnConfig, 1)
+	unregisterPaths := d.RegisterCallbackForPaths(ctx, serial, []string{"Password"}, func(ctx context.Context, oldConfig, newConfig *secretWarnConfig) {
+		pathChanged <- newConfig
+	})
+	require.NotNil(t, unregisterPaths)
+	defer unregisterPaths(ctx)
+
+	user, password := "foo", "correct horse"
+	w.send(ctx, reflect.ValueOf(ptrifiedConfig{User: &user, Password: &password}))
+	assert.Equal(t, &secretWarnConfig{User: "foo"}, <-warned)
+	assert.Equal(t, &secretWarnConfig{User: "foo"}, <-registered)
+	assert.Equal(t, &secretWarnConfig{User: "foo"}, <-pathChanged)
+
+	bad := "bad"
+	w.send(ctx, reflect.ValueOf(ptrifiedConfig{User: &bad, Password: &password}))
+	assert.Equal(t, [2]*secretWarnConfig{{User: "foo"}, {User: "bad"}}, <-watchErrs)
+	assert.Equal(t, &secretWarnConfig{User: "foo", Password: "correct horse"}, d.View())
+}
@@ -0,0 +1,245 @@
+package dials
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// reloadState retains everything Reload needs to re-stack the configuration
+// after Config returns.
+type reloadState[T any] struct {
+	// sourcesMu protects sources, which may be reordered by
+	// SetSourcePriority.
+	sourcesMu sync.Mutex
+	// sources are the sources passed to Config, in (current) order
+	sources      []Source
+	typeInstance *Type
+	base         *T
+
+	// reqs and monDone are only set if the monitor goroutine was started
+	// (there's at least one watching source)
+	reqs    chan<- reloadRequest[T]
+	monDone <-chan struct{}
+
+	// mu protects computed and skipVerify, which are only accessed
+	// directly if the monitor goroutine was never started, or has exited.
+	mu         sync.Mutex
+	computed   []sourceValue
+	skipVerify bool
+}
+
+// reloadedValue is a new value from a non-watching source.
+type reloadedValue struct {
+	source Source
+	value  reflect.Value
+}
+
+type reloadResp[T any] struct {
+	cfg *T
+	err error
+}
+
+// reloadRequest is the payload type for the channel used to ask the monitor
+// goroutine to install new values from non-watching sources.
+type reloadRequest[T any] struct {
+	values []reloadedValue
+	// priority, if non-nil, requests that a source be moved, rather than
+	// that values be replaced (see SetSourcePriority)
+	priority *sourcePriority
+	// resp must have capacity 1
+	resp chan<- reloadResp[T]
+}
+
+// Reload forces a re-read of the non-watching sources passed to Config,
+// calling Value on each of them and re-stacking the configuration. If the
+// resulting configuration differs from the current one (and passes
+// verification, if enabled), it is installed as a new version, with the
+// usual notifications on the Events channel and to callbacks.
+//
+// Watching sources are not re-read; their most recent values are used.
+// (Sources reporting themselves as static are re-read; see StaticSource.)
+//
+// If there are no watching sources (or the context passed to Config has
+// expired), OnVerifyWarnings, OnOverride and OnNewConfig are called
+// synchronously, before Reload returns. Callbacks registered with RegisterCallback are only
+// called while watching.
+//
+// Reload returns the (possibly unchanged) current configuration, or an error
+// if any source's Value method fails, re-stacking fails, or verification of
+// the new configuration fails (in which case the current configuration is
+// left installed).
+func (d *Dials[T]) Reload(ctx context.Context) (*T, error) {
+	rs := d.reload
+	vals, err := d.readReloadedValues(ctx, rs.sourceList())
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg, handled, err := d.sendReloadRequest(ctx, reloadRequest[T]{values: vals}); handled {
+		return cfg, err
+	}
+
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	oldConfig := d.View()
+	newConfig, warnings, err := d.reloadValues(ctx, rs.base, rs.skipVerify, rs.computed, vals)
+	if err != nil {
+		return nil, err
+	}
+	if newConfig != oldConfig {
+		d.notifyDirect(ctx, oldConfig, newConfig, warnings, rs.skipVerify, rs.computed)
+	}
+	return newConfig, nil
+}
+
+// readReloadedValues calls Value on each of the non-watching sources. As
+// with Config, the context passed to Value is canceled once all the sources
+// have been read.
+func (d *Dials[T]) readReloadedValues(ctx context.Context, sources []Source) ([]reloadedValue, error) {
+	valueCtx, cancelValues := context.WithCancel(ctx)
+	defer cancelValues()
+
+	vals := make([]reloadedValue, 0, len(sources))
+	for _, s := range sources {
+		if _, ok := watcher(s); ok {
+			continue
+		}
+		readStart := time.Now()
+		v, err := d.params.readSource(valueCtx, s, d.reload.typeInstance)
+		if err != nil {
+			d.params.log(ctx, LogLevelError, "failed to reload source value",
+				"source_type", sourceType(s), "duration", time.Since(readStart), "error", err)
+			d.params.metrics().SourceError(sourceType(s))
+			return nil, fmt.Errorf("failed to reload source of type %T: %w", s, err)
+		}
+		d.params.log(ctx, LogLevelDebug, "reloaded source value",
+			"source_type", sourceType(s), "duration", time.Since(readStart))
+		vals = append(vals, reloadedValue{source: s, value: v})
+	}
+	return vals, nil
+}
+
+// notifyDirect calls the OnVerifyWarnings, OnOverride and OnNewConfig
+// callbacks (if set) for a new version, composed from sourceValues,
+// installed while there's no goroutine handling callback events (either
+// because there are no watching sources, or because the context passed to
+// Config has expired).
+func (d *Dials[T]) notifyDirect(
+	ctx context.Context,
+	oldConfig, newConfig *T,
+	warnings []error,
+	skipVerify bool,
+	sourceValues []sourceValue,
+) {
+	if len(warnings) > 0 && d.params.OnVerifyWarnings != nil {
+		d.params.OnVerifyWarnings(ctx, warnings, d.params.callbackConfig(newConfig))
+	}
+	d.params.reportOverrides(ctx, sourceValues)
+	if d.params.OnNewConfig == nil || (skipVerify && d.params.CallGlobalCallbacksAfterVerificationEnabled) {
+		return
+	}
+	d.params.OnNewConfig(ctx, d.params.callbackConfig(oldConfig), d.params.callbackConfig(newConfig))
+}
+
+// sourceList returns a copy of the sources, in their current order.
+func (rs *reloadState[T]) sourceList() []Source {
+	rs.sourcesMu.Lock()
+	defer rs.sourcesMu.Unlock()
+	return append([]Source(nil), rs.sources...)
+}
+
+// sendReloadRequest sends req (filling in its resp channel) to the monitor
+// goroutine, if it's running, and returns its response. If handled is
+// false, the monitor goroutine was never started or has exited, and the
+// caller must handle the request itself (with rs.mu held).
+func (d *Dials[T]) sendReloadRequest(ctx context.Context, req reloadRequest[T]) (cfg *T, handled bool, err error) {
+	rs := d.reload
+	if rs.reqs == nil {
+		return nil, false, nil
+	}
+	// must have capacity 1
+	resp := make(chan reloadResp[T], 1)
+	req.resp = resp
+	select {
+	case rs.reqs <- req:
+		select {
+		case r := <-resp:
+			return r.cfg, true, r.err
+		case <-ctx.Done():
+			return nil, true, fmt.Errorf("context expired while awaiting reload: %w", ctx.Err())
+		}
+	case <-rs.monDone:
+		// The monitor goroutine has exited, so the caller owns the
+		// source-values now.
+		return nil, false, nil
+	case <-ctx.Done():
+		return nil, true, fmt.Errorf("context expired while signaling reload: %w", ctx.Err())
+	}
+}
+
+// reloadValues replaces the values of the reloaded sources, re-stacks and
+// installs the result if it differs from the current version. It returns
+// the current version if nothing changed.
+func (d *Dials[T]) reloadValues(
+	ctx context.Context,
+	t *T,
+	skipVerify bool,
+	sourceValues []sourceValue,
+	vals []reloadedValue,
+) (*T, []error, error) {
+	for _, rv := range vals {
+		for i, sv := range sourceValues {
+			if rv.source == sv.source {
+				sourceValues[i].value = rv.value
+				break
+			}
+		}
+	}
+	newVers, warnings, err := d.restack(ctx, t, skipVerify, sourceValues)
+	if err != nil {
+		d.params.log(ctx, LogLevelWarn, "rejected reloaded configuration", "error", err)
+		return nil, nil, fmt.Errorf("failed to reload configuration: %w", err)
+	}
+	if cur := d.View(); reflect.DeepEqual(cur, newVers) {
+		return cur, nil, nil
+	}
+	d.install(newVers)
+	return newVers, warnings, nil
+}
+
+// monitorReload handles a reload request within the monitor goroutine.
+func (d *Dials[T]) monitorReload(
+	ctx context.Context,
+	t *T,
+	skipVerify bool,
+	sourceValues []sourceValue,
+	r reloadRequest[T],
+) {
+	oldConfig, oldSerial := d.ViewVersion()
+	var newConfig *T
+	var warnings []error
+	var err error
+	if r.priority != nil {
+		newConfig, warnings, err = d.reprioritize(ctx, t, skipVerify, sourceValues, *r.priority)
+	} else {
+		newConfig, warnings, err = d.reloadValues(ctx, t, skipVerify, sourceValues, r.values)
+	}
+	r.resp <- reloadResp[T]{cfg: newConfig, err: err}
+	if err != nil || newConfig == oldConfig {
+		return
+	}
+	if len(warnings) > 0 {
+		d.submitEvent(ctx, &verifyWarningsEvent[T]{warnings: warnings, cfg: newConfig})
+	}
+	d.submitOverrides(ctx, sourceValues)
+	d.submitEvent(ctx, &newConfigEvent[T]{
+		oldConfig: oldConfig,
+		newConfig: newConfig,
+		serial:    oldSerial.s + 1,
+		globalCBsSuppressed: skipVerify &&
+			d.params.CallGlobalCallbacksAfterVerificationEnabled,
+	})
+}
@@ -0,0 +1,201 @@
+package dials
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type reloadTestConfig struct {
+	Foo string
+	Bar string
+}
+
+func (r *reloadTestConfig) Verify() error {
+	if r.Foo == "bad" {
+		return errors.New("bad foo")
+	}
+	return nil
+}
+
+type reloadTestPtrConfig struct {
+	Foo *string
+	Bar *string
+}
+
+func TestReloadNoWatchers(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	foo := "foo"
+	src := fakeSource{outVal: reloadTestPtrConfig{Foo: &foo}}
+	d, err := Config(ctx, &reloadTestConfig{Bar: "bar"}, &src)
+	require.NoError(t, err)
+	assert.Equal(t, &reloadTestConfig{Foo: "foo", Bar: "bar"}, d.View())
+
+	// nothing changed, so the current version should be retained
+	_, origSerial := d.ViewVersion()
+	cfg, reloadErr := d.Reload(ctx)
+	require.NoError(t, reloadErr)
+	assert.Same(t, d.View(), cfg)
+	_, serial := d.ViewVersion()
+	assert.Equal(t, origSerial, serial)
+
+	fim := "fim"
+	src.outVal = reloadTestPtrConfig{Foo: &fim}
+	cfg, reloadErr = d.Reload(ctx)
+	require.NoError(t, reloadErr)
+	assert.Equal(t, &reloadTestConfig{Foo: "fim", Bar: "bar"}, cfg)
+	assert.Same(t, cfg, d.View())
+	assert.Same(t, cfg, <-d.Events())
+
+	bad := "bad"
+	src.outVal = reloadTestPtrConfig{Foo: &bad}
+	_, reloadErr = d.Reload(ctx)
+	assert.EqualError(t, reloadErr, "failed to reload configuration: bad foo")
+	assert.Equal(t, &reloadTestConfig{Foo: "fim", Bar: "bar"}, d.View())
+}
+
+func TestReloadNoWatchersCallbacks(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	type newConfig struct {
+		old, new *reloadTestConfig
+	}
+	calls := []newConfig{}
+	overridden := []string{}
+	p := Params[reloadTestConfig]{
+		OnNewConfig: func(_ context.Context, oldCfg, newCfg *reloadTestConfig) {
+			calls = append(calls, newConfig{old: oldCfg, new: newCfg})
+		},
+		OnOverride: func(_ context.Context, path string, _ Source, _ []Source) {
+			overridden = append(overridden, path)
+		},
+	}
+
+	foo := "foo"
+	lower := fakeSource{outVal: reloadTestPtrConfig{Foo: &foo}}
+	src := ctxRecordingSource{fakeSource: fakeSource{outVal: reloadTestPtrConfig{Foo: &foo}}}
+	d, err := p.Config(ctx, &reloadTestConfig{Bar: "bar"}, &lower, &src)
+	require.NoError(t, err)
+	orig := d.View()
+	assert.Empty(t, overridden)
+
+	// nothing changed, so there's no new version to report
+	_, reloadErr := d.Reload(ctx)
+	require.NoError(t, reloadErr)
+	assert.Empty(t, calls)
+
+	fim := "fim"
+	src.outVal = reloadTestPtrConfig{Foo: &fim}
+	cfg, reloadErr := d.Reload(ctx)
+	require.NoError(t, reloadErr)
+	// OnOverride and OnNewConfig are called before Reload returns
+	require.Len(t, calls, 1)
+	assert.Same(t, orig, calls[0].old)
+	assert.Same(t, cfg, calls[0].new)
+	assert.Equal(t, []string{"Foo"}, overridden)
+
+	// the contexts passed to Value are canceled once the sources are read
+	require.Len(t, src.ctxs, 3)
+	for _, valueCtx := range src.ctxs {
+		assert.Error(t, valueCtx.Err())
+	}
+	assert.NoError(t, ctx.Err())
+}
+
+// ctxRecordingSource records the contexts passed to its Value method.
+type ctxRecordingSource struct {
+	fakeSource
+	ctxs []context.Context
+}
+
+func (c *ctxRecordingSource) Value(ctx context.Context, t *Type) (reflect.Value, error) {
+	c.ctxs = append(c.ctxs, ctx)
+	return c.fakeSource.Value(ctx, t)
+}
+
+func TestReloadWithWatcher(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	foo, bar := "foo", "bar"
+	src := fakeSource{outVal: reloadTestPtrConfig{Foo: &foo}}
+	w := fakeWatchingSource{fakeSource: fakeSource{outVal: reloadTestPtrConfig{Bar: &bar}}}
+	newConfs := make(chan *reloadTestConfig, 1)
+	d, err := Params[reloadTestConfig]{
+		OnNewConfig: func(ctx context.Context, oldConfig, newConfig *reloadTestConfig) {
+			newConfs <- newConfig
+		},
+	}.Config(ctx, &reloadTestConfig{}, &src, &w)
+	require.NoError(t, err)
+	assert.Equal(t, &reloadTestConfig{Foo: "foo", Bar: "bar"}, d.View())
+
+	// update the watching source's value, which should be retained by
+	// the reload.
+	baz := "baz"
+	w.send(ctx, reflect.ValueOf(reloadTestPtrConfig{Bar: &baz}))
+	assert.Equal(t, &reloadTestConfig{Foo: "foo", Bar: "baz"}, <-newConfs)
+	<-d.Events()
+
+	fim := "fim"
+	src.outVal = reloadTestPtrConfig{Foo: &fim}
+	cfg, reloadErr := d.Reload(ctx)
+	require.NoError(t, reloadErr)
+	assert.Equal(t, &reloadTestConfig{Foo: "fim", Bar: "baz"}, cfg)
+	assert.Same(t, cfg, d.View())
+	assert.Same(t, cfg, <-newConfs)
+	assert.Same(t, cfg, <-d.Events())
+}
+
+type fakeStaticSource struct {
+	fakeWatchingSource
+	static bool
+}
+
+func (f *fakeStaticSource) Static() bool {
+	return f.static
+}
+
+func TestStaticSource(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	foo := "foo"
+	src := fakeStaticSource{
+		fakeWatchingSource: fakeWatchingSource{fakeSource: fakeSource{outVal: reloadTestPtrConfig{Foo: &foo}}},
+		static:             true,
+	}
+	d, err := Config(ctx, &reloadTestConfig{Bar: "bar"}, &src)
+	require.NoError(t, err)
+	assert.Equal(t, &reloadTestConfig{Foo: "foo", Bar: "bar"}, d.View())
+
+	// the static source wasn't watched, so there's no monitor goroutine
+	assert.Nil(t, src.args)
+	assert.Nil(t, d.reload.monDone)
+
+	// static sources are re-read by Reload
+	fim := "fim"
+	src.outVal = reloadTestPtrConfig{Foo: &fim}
+	cfg, reloadErr := d.Reload(ctx)
+	require.NoError(t, reloadErr)
+	assert.Equal(t, &reloadTestConfig{Foo: "fim", Bar: "bar"}, cfg)
+
+	// a source reporting that it isn't static is watched as usual
+	nonStatic := fakeStaticSource{
+		fakeWatchingSource: fakeWatchingSource{fakeSource: fakeSource{outVal: reloadTestPtrConfig{Foo: &foo}}},
+	}
+	d, err = Config(ctx, &reloadTestConfig{Bar: "bar"}, &nonStatic)
+	require.NoError(t, err)
+	assert.NotNil(t, nonStatic.args)
+	assert.NotNil(t, d.reload.monDone)
+}
@@ -0,0 +1,93 @@
+package dials
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/vimeo/dials/common"
+	"github.com/vimeo/dials/ptrify"
+)
+
+// MissingRequiredFieldsError is returned when verifying a configuration in
+// which fields tagged `dialsrequired:"true"` are still set to their zero
+// value after all sources have been applied.
+//
+// Required fields are checked whenever the configuration would be verified
+// (see VerifiedConfig), so they're not enforced while verification is
+// skipped or delayed (see Params.SkipInitialVerification and
+// Params.DelayInitialVerification).
+//
+// Required fields nested within a nil pointer-to-struct field are not
+// checked (tag the pointer field itself to require it to be set).
+type MissingRequiredFieldsError struct {
+	// Paths contains the dot-separated path (Go field names, outermost
+	// first) to each missing field, in field order.
+	Paths []string
+}
+
+func (m *MissingRequiredFieldsError) Error() string {
+	return "required fields not set by any source: " + strings.Join(m.Paths, ", ")
+}
+
+// fieldRequired indicates whether the field has a true `dialsrequired` tag
+func fieldRequired(sf reflect.StructField) bool {
+	tagVal, ok := sf.Tag.Lookup(common.DialsRequiredTag)
+	if !ok {
+		return false
+	}
+	req, err := strconv.ParseBool(tagVal)
+	return err == nil && req
+}
+
+// checkRequired returns a *MissingRequiredFieldsError if any required fields
+// in the struct v (or a pointer to one) are unset.
+func checkRequired(v reflect.Value) error {
+	missing := missingRequired(v, nil, nil)
+	if len(missing) == 0 {
+		return nil
+	}
+	return &MissingRequiredFieldsError{Paths: missing}
+}
+
+// verifyRequired checks that the required fields of cfg are set, before
+// verifying it with verify.
+func verifyRequired(ctx context.Context, cfg any) ([]error, error) {
+	if reqErr := checkRequired(reflect.ValueOf(cfg)); reqErr != nil {
+		return nil, reqErr
+	}
+	return verify(ctx, cfg)
+}
+
+// missingRequired walks v, appending the paths of any unset required fields
+// to missing.
+func missingRequired(v reflect.Value, path []string, missing []string) []string {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return missing
+		}
+		return missingRequired(v.Elem(), path, missing)
+	case reflect.Struct:
+	default:
+		return missing
+	}
+	if ptrify.IsUnmarshalerStruct(v.Type()) {
+		return missing
+	}
+	for i := 0; i < v.NumField(); i++ {
+		sf := v.Type().Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		fieldPath := append(path[:len(path):len(path)], sf.Name)
+		f := v.Field(i)
+		if fieldRequired(sf) && f.IsZero() {
+			missing = append(missing, strings.Join(fieldPath, "."))
+			continue
+		}
+		missing = missingRequired(f, fieldPath, missing)
+	}
+	return missing
+}
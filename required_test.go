@@ -0,0 +1,72 @@
+package dials
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type requiredDB struct {
+	Host string `dialsrequired:"true"`
+	Port int
+}
+
+type requiredConfig struct {
+	Name     string `dialsrequired:"true"`
+	Optional string `dialsrequired:"false"`
+	DB       requiredDB
+	Extra    *requiredDB
+}
+
+type requiredPtrDB = struct {
+	Host *string `dialsrequired:"true"`
+	Port *int
+}
+
+type requiredPtrConfig struct {
+	Name     *string `dialsrequired:"true"`
+	Optional *string `dialsrequired:"false"`
+	DB       *requiredPtrDB
+	Extra    *requiredPtrDB
+}
+
+func TestRequiredFields(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, err := Config(ctx, &requiredConfig{})
+	missingErr := &MissingRequiredFieldsError{}
+	require.True(t, errors.As(err, &missingErr))
+	// Extra is nil, so its nested required field isn't checked.
+	assert.Equal(t, []string{"Name", "DB.Host"}, missingErr.Paths)
+	assert.EqualError(t, err, "required fields not set by any source: Name, DB.Host")
+
+	name, host := "svc", "db.example"
+	w := fakeWatchingSource{fakeSource: fakeSource{outVal: requiredPtrConfig{
+		Name: &name,
+		DB:   &requiredPtrDB{Host: &host},
+	}}}
+	watchErrs := make(chan error, 1)
+	d, err := Params[requiredConfig]{
+		OnWatchedError: func(ctx context.Context, err error, oldConfig, newConfig *requiredConfig) {
+			watchErrs <- err
+		},
+	}.Config(ctx, &requiredConfig{}, &w)
+	require.NoError(t, err)
+	assert.Equal(t, &requiredConfig{Name: "svc", DB: requiredDB{Host: "db.example"}}, d.View())
+
+	// A new value missing a required nested field (now that Extra is
+	// non-nil) is rejected.
+	w.send(ctx, reflect.ValueOf(requiredPtrConfig{
+		Name:  &name,
+		DB:    &requiredPtrDB{Host: &host},
+		Extra: &requiredPtrDB{},
+	}))
+	assert.EqualError(t, <-watchErrs, "required fields not set by any source: Extra.Host")
+	assert.Equal(t, &requiredConfig{Name: "svc", DB: requiredDB{Host: "db.example"}}, d.View())
+}
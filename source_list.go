@@ -0,0 +1,34 @@
+package dials
+
+// SourceList is an ordered list of Sources, for building the set of sources
+// passed to Config at runtime (e.g. when some are feature-flagged or
+// optional). Since it's a []Source, it can be passed directly as
+//
+//	dials.Config(ctx, cfg, dials.Sources(defaults).
+//		MaybeAdd(cfgPath != "", fileSrc).
+//		Add(envSrc, flagSrc)...)
+//
+// Sources are kept in the order they're added, which is the order in which
+// they're stacked (later sources take precedence). As with append, the lists
+// returned by Add and MaybeAdd may share a backing array with the receiver,
+// so only the returned list should be used after adding to it.
+type SourceList []Source
+
+// Sources constructs a SourceList containing srcs.
+func Sources(srcs ...Source) SourceList {
+	return append(make(SourceList, 0, len(srcs)), srcs...)
+}
+
+// Add returns a SourceList with srcs appended.
+func (s SourceList) Add(srcs ...Source) SourceList {
+	return append(s, srcs...)
+}
+
+// MaybeAdd returns a SourceList with src appended if cond is true, and the
+// list unchanged otherwise.
+func (s SourceList) MaybeAdd(cond bool, src Source) SourceList {
+	if !cond {
+		return s
+	}
+	return append(s, src)
+}
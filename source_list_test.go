@@ -0,0 +1,70 @@
+package dials
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSourceList(t *testing.T) {
+	t.Parallel()
+	s1 := &fakeSource{}
+	s2 := &fakeSource{}
+	s3 := &fakeSource{}
+	s4 := &fakeSource{}
+
+	for name, itbl := range map[string]struct {
+		list   SourceList
+		expect []Source
+	}{
+		"empty": {
+			list:   Sources(),
+			expect: []Source{},
+		},
+		"all_added": {
+			list:   Sources(s1).MaybeAdd(true, s2).Add(s3, s4),
+			expect: []Source{s1, s2, s3, s4},
+		},
+		"skipped": {
+			list:   Sources(s1, s2).MaybeAdd(false, s3).Add(s4),
+			expect: []Source{s1, s2, s4},
+		},
+		"only_conditional": {
+			list:   Sources().MaybeAdd(false, s1).MaybeAdd(true, s2).MaybeAdd(true, s3),
+			expect: []Source{s2, s3},
+		},
+	} {
+		tbl := itbl
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tbl.expect, []Source(tbl.list))
+		})
+	}
+}
+
+func TestSourceListConfigOrdering(t *testing.T) {
+	t.Parallel()
+	type testConfig struct {
+		Foo string
+		Bar string
+	}
+	type ptrifiedConfig struct {
+		Foo *string
+		Bar *string
+	}
+	foo1, foo2, bar := "foo1", "foo2", "bar"
+	base := Sources(&fakeSource{outVal: ptrifiedConfig{Foo: &foo1, Bar: &bar}})
+
+	d, err := Config(context.Background(), &testConfig{},
+		base.MaybeAdd(true, &fakeSource{outVal: ptrifiedConfig{Foo: &foo2}})...)
+	require.NoError(t, err)
+	// later sources take precedence
+	assert.Equal(t, &testConfig{Foo: "foo2", Bar: "bar"}, d.View())
+
+	d, err = Config(context.Background(), &testConfig{},
+		base.MaybeAdd(false, &fakeSource{outVal: ptrifiedConfig{Foo: &foo2}})...)
+	require.NoError(t, err)
+	assert.Equal(t, &testConfig{Foo: "foo1", Bar: "bar"}, d.View())
+}
@@ -0,0 +1,59 @@
+package dials
+
+import "context"
+
+// SourceOptionKey identifies a typed value that can be attached to the
+// context passed to Config (or Reload) with WithSourceOptions, for use by
+// Sources that need request-scoped values (such as auth tokens or tracing
+// spans) that don't belong in the configuration struct.
+//
+// The context passed to Config is the parent of the contexts passed to each
+// Source's Value and Watch methods, so Sources can retrieve the values with
+// Value. Keys are compared by identity, so each package defining options
+// should create its keys once (generally as package-level variables), and
+// keys from different packages never collide, regardless of their names.
+type SourceOptionKey[V any] struct {
+	name string
+}
+
+// NewSourceOptionKey creates a new, unique key for a value of type V. The
+// name is only used for debugging.
+func NewSourceOptionKey[V any](name string) *SourceOptionKey[V] {
+	return &SourceOptionKey[V]{name: name}
+}
+
+// String implements fmt.Stringer
+func (k *SourceOptionKey[V]) String() string {
+	return "dials source option " + k.name
+}
+
+// Option returns a SourceOption setting this key to v, for use with
+// WithSourceOptions.
+func (k *SourceOptionKey[V]) Option(v V) SourceOption {
+	return SourceOption{key: k, val: v}
+}
+
+// Value returns the value associated with this key in ctx, and whether one
+// was set.
+func (k *SourceOptionKey[V]) Value(ctx context.Context) (V, bool) {
+	v, ok := ctx.Value(k).(V)
+	return v, ok
+}
+
+// SourceOption is a key and value to attach to a context with
+// WithSourceOptions (see SourceOptionKey.Option).
+type SourceOption struct {
+	key any
+	val any
+}
+
+// WithSourceOptions returns a copy of ctx carrying opts, which Sources may
+// retrieve with the corresponding SourceOptionKey's Value method. Later
+// options take precedence over earlier ones (and over those already in ctx)
+// with the same key.
+func WithSourceOptions(ctx context.Context, opts ...SourceOption) context.Context {
+	for _, o := range opts {
+		ctx = context.WithValue(ctx, o.key, o.val)
+	}
+	return ctx
+}
@@ -0,0 +1,74 @@
+package dials
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testTokenOpt = NewSourceOptionKey[string]("test token")
+	testRetryOpt = NewSourceOptionKey[int]("test retries")
+)
+
+// optionRecordingSource records the testTokenOpt values in the contexts
+// passed to Value.
+type optionRecordingSource struct {
+	fakeSource
+	tokens []string
+}
+
+func (o *optionRecordingSource) Value(ctx context.Context, t *Type) (reflect.Value, error) {
+	tok, ok := testTokenOpt.Value(ctx)
+	if !ok {
+		tok = "<unset>"
+	}
+	o.tokens = append(o.tokens, tok)
+	return o.fakeSource.Value(ctx, t)
+}
+
+func TestSourceOptions(t *testing.T) {
+	t.Parallel()
+	ctx := WithSourceOptions(context.Background(), testTokenOpt.Option("s3cr3t"), testRetryOpt.Option(3))
+
+	tok, ok := testTokenOpt.Value(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, "s3cr3t", tok)
+	retries, ok := testRetryOpt.Value(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, 3, retries)
+
+	// keys are distinct even with the same name and type
+	_, ok = NewSourceOptionKey[string]("test token").Value(ctx)
+	assert.False(t, ok)
+	assert.Equal(t, "dials source option test token", testTokenOpt.String())
+
+	// later options override earlier ones
+	overridden := WithSourceOptions(ctx, testTokenOpt.Option("a"), testTokenOpt.Option("b"))
+	tok, _ = testTokenOpt.Value(overridden)
+	assert.Equal(t, "b", tok)
+}
+
+func TestSourceOptionsConfig(t *testing.T) {
+	t.Parallel()
+	foo := "foo"
+	src := optionRecordingSource{fakeSource: fakeSource{outVal: reloadTestPtrConfig{Foo: &foo}}}
+	w := fakeWatchingSource{fakeSource: fakeSource{outVal: reloadTestPtrConfig{}}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	d, err := Config(WithSourceOptions(ctx, testTokenOpt.Option("config")), &reloadTestConfig{}, &src, &w)
+	require.NoError(t, err)
+	assert.Equal(t, "foo", d.View().Foo)
+
+	// Reload uses its own context
+	_, reloadErr := d.Reload(WithSourceOptions(ctx, testTokenOpt.Option("reload")))
+	require.NoError(t, reloadErr)
+	_, reloadErr = d.Reload(ctx)
+	require.NoError(t, reloadErr)
+
+	assert.Equal(t, []string{"config", "reload", "<unset>"}, src.tokens)
+}
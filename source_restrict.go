@@ -0,0 +1,86 @@
+package dials
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/vimeo/dials/common"
+	"github.com/vimeo/dials/ptrify"
+)
+
+// NamedSource is an optional interface that a Source may implement to
+// identify what kind of source it is (e.g. "env", "file" or "flag").
+//
+// Fields tagged with a `dialssource` tag (e.g. `dialssource:"env"` or
+// `dialssource:"env,flag"`) only accept values from Sources whose SourceName
+// appears in the tag's comma-separated list. Values for those fields provided
+// by any other Source (including Sources that don't implement NamedSource) are
+// ignored while composing, rather than merged. Precedence among the permitted
+// Sources follows the usual ordering of the Sources passed to Config.
+//
+// Tagging a struct-typed field restricts all of the fields nested within it.
+type NamedSource interface {
+	Source
+	SourceName() string
+}
+
+// sourceName returns the name a Source identifies itself by, or the empty
+// string if it does not implement NamedSource.
+func sourceName(s Source) string {
+	if ns, ok := s.(NamedSource); ok {
+		return ns.SourceName()
+	}
+	return ""
+}
+
+// sourceAllowed indicates whether a field with the specified tag may be set by
+// the source named name.
+func sourceAllowed(sf reflect.StructField, name string) bool {
+	tagVal, ok := sf.Tag.Lookup(common.DialsSourceTag)
+	if !ok {
+		return true
+	}
+	for _, allowed := range strings.Split(tagVal, ",") {
+		if name != "" && strings.TrimSpace(allowed) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// clearRestrictedFields zeroes any fields within the (addressable) pointerified
+// struct v that carry a `dialssource` tag not listing the source named name.
+func clearRestrictedFields(v reflect.Value, name string) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return
+		}
+		clearRestrictedFields(v.Elem(), name)
+		return
+	case reflect.Array:
+		// the elements of arrays of structs are pointerified
+		for i := 0; i < v.Len(); i++ {
+			clearRestrictedFields(v.Index(i), name)
+		}
+		return
+	case reflect.Struct:
+	default:
+		return
+	}
+	if ptrify.IsUnmarshalerStruct(v.Type()) {
+		return
+	}
+	for i := 0; i < v.NumField(); i++ {
+		sf := v.Type().Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		f := v.Field(i)
+		if !sourceAllowed(sf, name) {
+			f.Set(reflect.Zero(f.Type()))
+			continue
+		}
+		clearRestrictedFields(f, name)
+	}
+}
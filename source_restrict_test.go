@@ -0,0 +1,114 @@
+package dials
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type namedFakeSource struct {
+	fakeSource
+	name string
+}
+
+func (n *namedFakeSource) SourceName() string {
+	return n.name
+}
+
+func TestRestrictedSourceFields(t *testing.T) {
+	t.Parallel()
+
+	type inner struct {
+		Token string `dialssource:"env"`
+		User  string
+	}
+	type config struct {
+		Name     string
+		Password string `dialssource:"env"`
+		Key      string `dialssource:"env, flag"`
+		Inner    inner
+		// Token within Secrets can never be set, since the tags conflict
+		Secrets *inner `dialssource:"file"`
+	}
+	// the pointerified inner struct is anonymous, so use an alias for it
+	type ptrInner = struct {
+		Token *string `dialssource:"env"`
+		User  *string
+	}
+	type ptrifiedConfig struct {
+		Name     *string
+		Password *string `dialssource:"env"`
+		Key      *string `dialssource:"env, flag"`
+		Inner    *ptrInner
+		Secrets  *ptrInner `dialssource:"file"`
+	}
+
+	strPtr := func(s string) *string { return &s }
+	fullVal := func(prefix string) ptrifiedConfig {
+		return ptrifiedConfig{
+			Name:     strPtr(prefix + "-name"),
+			Password: strPtr(prefix + "-password"),
+			Key:      strPtr(prefix + "-key"),
+			Inner:    &ptrInner{Token: strPtr(prefix + "-token"), User: strPtr(prefix + "-user")},
+			Secrets:  &ptrInner{Token: strPtr(prefix + "-stoken"), User: strPtr(prefix + "-suser")},
+		}
+	}
+
+	for _, tbl := range []struct {
+		name     string
+		sources  []Source
+		expected config
+	}{
+		{
+			name: "env_after_file",
+			sources: []Source{
+				&namedFakeSource{fakeSource: fakeSource{outVal: fullVal("file")}, name: "file"},
+				&namedFakeSource{fakeSource: fakeSource{outVal: fullVal("env")}, name: "env"},
+			},
+			expected: config{
+				Name:     "env-name",
+				Password: "env-password",
+				Key:      "env-key",
+				Inner:    inner{Token: "env-token", User: "env-user"},
+				Secrets:  &inner{User: "file-suser"},
+			},
+		},
+		{
+			name: "file_after_env",
+			sources: []Source{
+				&namedFakeSource{fakeSource: fakeSource{outVal: fullVal("env")}, name: "env"},
+				&namedFakeSource{fakeSource: fakeSource{outVal: fullVal("file")}, name: "file"},
+			},
+			expected: config{
+				Name:     "file-name",
+				Password: "env-password",
+				Key:      "env-key",
+				Inner:    inner{Token: "env-token", User: "file-user"},
+				Secrets:  &inner{User: "file-suser"},
+			},
+		},
+		{
+			name: "flag_and_unnamed",
+			sources: []Source{
+				&namedFakeSource{fakeSource: fakeSource{outVal: fullVal("flag")}, name: "flag"},
+				&fakeSource{outVal: fullVal("unnamed")},
+			},
+			expected: config{
+				Name:     "unnamed-name",
+				Password: "default-password",
+				Key:      "flag-key",
+				Inner:    inner{User: "unnamed-user"},
+			},
+		},
+	} {
+		tbl := tbl
+		t.Run(tbl.name, func(t *testing.T) {
+			t.Parallel()
+			d, err := Config(context.Background(), &config{Password: "default-password"}, tbl.sources...)
+			require.NoError(t, err)
+			assert.Equal(t, &tbl.expected, d.View())
+		})
+	}
+}
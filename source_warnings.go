@@ -0,0 +1,46 @@
+package dials
+
+import (
+	"context"
+	"reflect"
+)
+
+// SourceWithWarnings is an optional interface for Sources that can produce
+// a usable value even when some fields can't be read (e.g. a single
+// malformed, but optional, environment variable).
+type SourceWithWarnings interface {
+	Source
+	// ValueWithWarnings is called in place of Value. A non-nil error is
+	// fatal, exactly as if it had been returned by Value. Otherwise, the
+	// returned value is used, and any non-fatal warnings (generally
+	// describing fields that were left unset) are passed to
+	// [Params].OnSourceWarning.
+	ValueWithWarnings(context.Context, *Type) (reflect.Value, []error, error)
+}
+
+// SourceWarningsHandler is a callback that's called with the non-fatal
+// warnings returned by a [SourceWithWarnings] implementation's
+// ValueWithWarnings method.
+type SourceWarningsHandler func(ctx context.Context, src Source, warnings []error)
+
+// readSource calls ValueWithWarnings on s if it implements
+// SourceWithWarnings, and Value otherwise. Any warnings are logged and
+// passed to OnSourceWarning.
+func (p *Params[T]) readSource(ctx context.Context, s Source, t *Type) (reflect.Value, error) {
+	sw, ok := s.(SourceWithWarnings)
+	if !ok {
+		return s.Value(ctx, t)
+	}
+	v, warnings, err := sw.ValueWithWarnings(ctx, t)
+	if err != nil || len(warnings) == 0 {
+		return v, err
+	}
+	for _, w := range warnings {
+		p.log(ctx, LogLevelWarn, "source value has non-fatal error",
+			"source_type", sourceType(s), "warning", w)
+	}
+	if p.OnSourceWarning != nil {
+		p.OnSourceWarning(ctx, s, warnings)
+	}
+	return v, nil
+}
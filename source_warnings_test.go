@@ -0,0 +1,72 @@
+package dials
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type warningSource struct {
+	fakeSource
+	warnings []error
+	err      error
+}
+
+func (w *warningSource) ValueWithWarnings(ctx context.Context, t *Type) (reflect.Value, []error, error) {
+	if w.err != nil {
+		return reflect.Value{}, nil, w.err
+	}
+	v, err := w.Value(ctx, t)
+	return v, w.warnings, err
+}
+
+func TestSourceWithWarnings(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	foo := "foo"
+	badBar := errors.New(`invalid value for "Bar"`)
+	src := warningSource{
+		fakeSource: fakeSource{outVal: reloadTestPtrConfig{Foo: &foo}},
+		warnings:   []error{badBar},
+	}
+
+	type warned struct {
+		src      Source
+		warnings []error
+	}
+	var got []warned
+	p := Params[reloadTestConfig]{
+		OnSourceWarning: func(ctx context.Context, s Source, warnings []error) {
+			got = append(got, warned{src: s, warnings: warnings})
+		},
+	}
+	d, err := p.Config(ctx, &reloadTestConfig{Bar: "default"}, &src)
+	require.NoError(t, err)
+	assert.Equal(t, &reloadTestConfig{Foo: "foo", Bar: "default"}, d.View())
+	assert.Equal(t, []warned{{src: &src, warnings: []error{badBar}}}, got)
+
+	// no warnings, no callback
+	got = nil
+	fim := "fim"
+	src.outVal = reloadTestPtrConfig{Foo: &fim}
+	src.warnings = nil
+	cfg, reloadErr := d.Reload(ctx)
+	require.NoError(t, reloadErr)
+	assert.Equal(t, &reloadTestConfig{Foo: "fim", Bar: "default"}, cfg)
+	assert.Empty(t, got)
+
+	// errors are still fatal
+	src.err = errors.New("unreadable")
+	_, reloadErr = d.Reload(ctx)
+	assert.ErrorIs(t, reloadErr, src.err)
+	assert.Empty(t, got)
+
+	_, err = p.Config(ctx, &reloadTestConfig{}, &src)
+	assert.ErrorIs(t, err, src.err)
+}
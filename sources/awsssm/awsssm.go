@@ -0,0 +1,156 @@
+// Package awsssm provides a dials.Source that reads configuration from the
+// AWS Systems Manager (SSM) Parameter Store.
+package awsssm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/vimeo/dials"
+	"github.com/vimeo/dials/common"
+	"github.com/vimeo/dials/tagformat"
+	"github.com/vimeo/dials/tagformat/caseconversion"
+	"github.com/vimeo/dials/transform"
+)
+
+// SSMTagName is the name of the struct tag used to override the name of
+// the parameter (relative to Source.Path) for a field.
+const SSMTagName = "dialsssm"
+
+// Parameter is a single SSM parameter.
+type Parameter struct {
+	// Name is the full name of the parameter (e.g. "/myapp/prod/db/host")
+	Name string
+	// Value is the parameter's value. SecureString values must already
+	// be decrypted.
+	Value string
+}
+
+// Client is the subset of the SSM API used by Source. It's an interface so
+// that dials doesn't depend on a particular version of the AWS SDK (and so
+// it can be faked in tests). An adapter for the aws-sdk-go-v2 ssm.Client
+// looks something like
+//
+//	type ssmClient struct{ c *ssm.Client }
+//
+//	func (s ssmClient) GetParametersByPath(ctx context.Context, path string) ([]awsssm.Parameter, error) {
+//		out := []awsssm.Parameter{}
+//		p := ssm.NewGetParametersByPathPaginator(s.c, &ssm.GetParametersByPathInput{
+//			Path: &path, Recursive: aws.Bool(true), WithDecryption: aws.Bool(true),
+//		})
+//		for p.HasMorePages() {
+//			page, err := p.NextPage(ctx)
+//			if err != nil {
+//				return nil, err
+//			}
+//			for _, param := range page.Parameters {
+//				out = append(out, awsssm.Parameter{Name: *param.Name, Value: *param.Value})
+//			}
+//		}
+//		return out, nil
+//	}
+type Client interface {
+	// GetParametersByPath returns all the parameters under path
+	// (recursively, and following any pagination), with SecureString
+	// values decrypted.
+	GetParametersByPath(ctx context.Context, path string) ([]Parameter, error)
+	// GetParameter returns the parameter named name, with a SecureString
+	// value decrypted.
+	GetParameter(ctx context.Context, name string) (Parameter, error)
+}
+
+// Source implements dials.Source, reading configuration from the SSM
+// Parameter Store in one of two modes.
+//
+// If Decoder is nil, each parameter under Path corresponds to a single
+// field. The parameter name relative to Path is matched against the
+// (flattened) field's `dialsssm` tag if present, then its `dials` tag, and
+// finally its name converted to lower_snake_case. When matching, slashes
+// and hyphens are treated as underscores and case is ignored, so both
+// "/myapp/prod/database/host" and "/myapp/prod/DATABASE_HOST" set the
+// field Database.Host with a Path of "/myapp/prod". Parameters that don't
+// correspond to any field are ignored. Values are parsed the same way as
+// environment variables (see transform.StringCastingMangler).
+//
+// If Decoder is non-nil, Path is the name of a single parameter whose value
+// is a document (e.g. JSON or YAML) that's decoded by Decoder.
+type Source struct {
+	Client  Client
+	Path    string
+	Decoder dials.Decoder
+}
+
+var _ dials.Source = (*Source)(nil)
+var _ dials.NamedSource = (*Source)(nil)
+
+// SourceName implements dials.NamedSource, returning
+// common.AWSSSMSourceName.
+func (s *Source) SourceName() string {
+	return common.AWSSSMSourceName
+}
+
+// Value fetches the parameters from SSM and fills in the config struct.
+func (s *Source) Value(ctx context.Context, t *dials.Type) (reflect.Value, error) {
+	if s.Decoder != nil {
+		return s.documentValue(ctx, t)
+	}
+
+	params, err := s.Client.GetParametersByPath(ctx, s.Path)
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("failed to get SSM parameters under %q: %w", s.Path, err)
+	}
+	byName := make(map[string]string, len(params))
+	for _, p := range params {
+		byName[normalizeName(strings.TrimPrefix(p.Name, s.Path))] = p.Value
+	}
+
+	flattenMangler := transform.NewFlattenMangler(common.DialsTagName, caseconversion.EncodeUpperCamelCase, caseconversion.EncodeUpperCamelCase)
+	reformatTagMangler := tagformat.NewTagReformattingMangler(common.DialsTagName, caseconversion.DecodeGoTags, caseconversion.EncodeLowerSnakeCase)
+	tagCopyingMangler := &tagformat.TagCopyingMangler{SrcTag: common.DialsTagName, NewTag: SSMTagName}
+	stringCastingMangler := &transform.StringCastingMangler{}
+	aliasMangler := transform.NewAliasMangler(common.DialsTagName, SSMTagName)
+	tfmr := transform.NewTransformer(t.Type(), aliasMangler, flattenMangler, reformatTagMangler, tagCopyingMangler, stringCastingMangler)
+
+	val, err := tfmr.Translate()
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	valType := val.Type()
+	for i := 0; i < val.NumField(); i++ {
+		sf := valType.Field(i)
+		name := sf.Tag.Get(SSMTagName)
+		if name == "" || name == "-" {
+			continue
+		}
+		if paramVal, ok := byName[normalizeName(name)]; ok {
+			// The StringCastingMangler has converted all the fields to
+			// *string.
+			val.Field(i).Set(reflect.ValueOf(&paramVal))
+		}
+	}
+
+	return tfmr.ReverseTranslate(val)
+}
+
+func (s *Source) documentValue(ctx context.Context, t *dials.Type) (reflect.Value, error) {
+	p, err := s.Client.GetParameter(ctx, s.Path)
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("failed to get SSM parameter %q: %w", s.Path, err)
+	}
+	v, err := s.Decoder.Decode(strings.NewReader(p.Value), t)
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("failed to decode SSM parameter %q: %w", s.Path, err)
+	}
+	return v, nil
+}
+
+// normalizeName converts a (relative) parameter name or tag value into the
+// form used for matching them against each other.
+func normalizeName(name string) string {
+	name = strings.Trim(name, "/")
+	name = strings.NewReplacer("/", "_", "-", "_").Replace(name)
+	return strings.ToLower(name)
+}
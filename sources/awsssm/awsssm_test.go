@@ -0,0 +1,171 @@
+package awsssm
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vimeo/dials"
+	"github.com/vimeo/dials/decoders/json"
+)
+
+type fakeClient struct {
+	mu     sync.Mutex
+	params map[string]string
+	err    error
+	// calls counts the calls to GetParametersByPath
+	calls int
+}
+
+func (f *fakeClient) callCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls
+}
+
+func (f *fakeClient) set(name, value string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.params[name] = value
+}
+
+func (f *fakeClient) GetParametersByPath(ctx context.Context, path string) ([]Parameter, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	out := []Parameter{}
+	for name, val := range f.params {
+		if strings.HasPrefix(name, path+"/") {
+			out = append(out, Parameter{Name: name, Value: val})
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeClient) GetParameter(ctx context.Context, name string) (Parameter, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return Parameter{}, f.err
+	}
+	val, ok := f.params[name]
+	if !ok {
+		return Parameter{}, errors.New("parameter not found")
+	}
+	return Parameter{Name: name, Value: val}, nil
+}
+
+type dbConfig struct {
+	Host    string
+	Port    int
+	Timeout time.Duration `dials:"conn_timeout"`
+}
+
+type testConfig struct {
+	Name     string
+	Database dbConfig
+	Secret   string `dialsssm:"creds/api-key"`
+	Unset    string
+}
+
+func TestSourcePerField(t *testing.T) {
+	t.Parallel()
+	client := &fakeClient{params: map[string]string{
+		"/myapp/prod/name":                  "svc",
+		"/myapp/prod/database/host":         "db.example",
+		"/myapp/prod/DATABASE_PORT":         "5432",
+		"/myapp/prod/database/conn-timeout": "3s",
+		"/myapp/prod/creds/api-key":         "hunter2",
+		"/myapp/prod/unrelated":             "ignored",
+		"/otherapp/prod/name":               "other",
+	}}
+
+	d, err := dials.Config(context.Background(), &testConfig{Unset: "default"},
+		&Source{Client: client, Path: "/myapp/prod"})
+	require.NoError(t, err)
+	assert.Equal(t, &testConfig{
+		Name: "svc",
+		Database: dbConfig{
+			Host:    "db.example",
+			Port:    5432,
+			Timeout: 3 * time.Second,
+		},
+		Secret: "hunter2",
+		Unset:  "default",
+	}, d.View())
+}
+
+func TestSourceErrors(t *testing.T) {
+	t.Parallel()
+	client := &fakeClient{params: map[string]string{
+		"/myapp/prod/database/port": "not a number",
+	}}
+	_, err := dials.Config(context.Background(), &testConfig{},
+		&Source{Client: client, Path: "/myapp/prod"})
+	assert.Error(t, err)
+
+	client = &fakeClient{err: errors.New("access denied")}
+	_, err = dials.Config(context.Background(), &testConfig{},
+		&Source{Client: client, Path: "/myapp/prod"})
+	assert.ErrorContains(t, err, `failed to get SSM parameters under "/myapp/prod": access denied`)
+}
+
+func TestSourceDocument(t *testing.T) {
+	t.Parallel()
+	client := &fakeClient{params: map[string]string{
+		"/myapp/prod/config": `{"Name": "svc", "Database": {"Host": "db.example", "Port": 5432}}`,
+	}}
+	d, err := dials.Config(context.Background(), &testConfig{},
+		&Source{Client: client, Path: "/myapp/prod/config", Decoder: &json.Decoder{}})
+	require.NoError(t, err)
+	assert.Equal(t, &testConfig{
+		Name:     "svc",
+		Database: dbConfig{Host: "db.example", Port: 5432},
+	}, d.View())
+}
+
+func TestWatchingSource(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := &fakeClient{params: map[string]string{
+		"/myapp/prod/name": "svc",
+	}}
+	src := &WatchingSource{
+		Source:       Source{Client: client, Path: "/myapp/prod"},
+		PollInterval: time.Millisecond,
+	}
+	d, err := dials.Config(ctx, &testConfig{}, src)
+	require.NoError(t, err)
+	assert.Equal(t, "svc", d.View().Name)
+
+	client.set("/myapp/prod/name", "svc2")
+	assert.Equal(t, "svc2", (<-d.Events()).Name)
+
+	cancel()
+	src.WG.Wait()
+
+	// Watch starts from the value read by Config, rather than reading
+	// the parameters again.
+	slowClient := &fakeClient{params: map[string]string{"/myapp/prod/name": "svc"}}
+	slowCtx, slowCancel := context.WithCancel(context.Background())
+	defer slowCancel()
+	slowSrc := &WatchingSource{
+		Source:       Source{Client: slowClient, Path: "/myapp/prod"},
+		PollInterval: time.Hour,
+	}
+	_, err = dials.Config(slowCtx, &testConfig{}, slowSrc)
+	require.NoError(t, err)
+	assert.Equal(t, 1, slowClient.callCount())
+	slowCancel()
+	slowSrc.WG.Wait()
+}
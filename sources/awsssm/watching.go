@@ -0,0 +1,47 @@
+package awsssm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/vimeo/dials"
+	"github.com/vimeo/dials/sources/pollhelper"
+)
+
+// WatchingSource wraps Source, implementing dials.Watcher by polling SSM
+// every PollInterval, and reporting a new value if anything relevant
+// changed. Errors while polling are reported via dials.WatchArgs, and the
+// previous value is retained.
+type WatchingSource struct {
+	Source
+	// PollInterval is the period between re-reads of the parameters. It
+	// must be positive.
+	PollInterval time.Duration
+	// WG is incremented while the background goroutine is running.
+	WG sync.WaitGroup
+
+	poller pollhelper.Poller
+}
+
+var _ dials.Source = (*WatchingSource)(nil)
+var _ dials.Watcher = (*WatchingSource)(nil)
+var _ dials.NamedSource = (*WatchingSource)(nil)
+
+// Value implements the dials.Source interface, reading the parameters. The
+// value is retained, so Watch starts polling from it, rather than making
+// another round-trip to SSM.
+func (w *WatchingSource) Value(ctx context.Context, t *dials.Type) (reflect.Value, error) {
+	return w.poller.Value(ctx, t, w.Source.Value)
+}
+
+// Watch starts a background goroutine that polls SSM. The goroutine exits
+// when ctx is canceled.
+func (w *WatchingSource) Watch(ctx context.Context, t *dials.Type, args dials.WatchArgs) error {
+	if w.PollInterval <= 0 {
+		return fmt.Errorf("non-positive PollInterval %s", w.PollInterval)
+	}
+	return w.poller.Start(ctx, t, args, &w.WG, pollhelper.Every(w.PollInterval), w.Source.Value)
+}
@@ -0,0 +1,115 @@
+// Package cmd provides a dials.Source that runs a command and decodes its
+// output (e.g. a helper binary that fetches secrets).
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/vimeo/dials"
+	"github.com/vimeo/dials/common"
+)
+
+// maxStderrLen is the number of bytes of the command's stderr included in
+// errors.
+const maxStderrLen = 4096
+
+// Source implements dials.Source by running a command, and decoding its
+// standard output with Decoder.
+//
+// The command is run directly (not via a shell), so Args are passed
+// verbatim, with no expansion or quoting to worry about. Its standard input
+// is empty.
+type Source struct {
+	// Path is the command to run. If it contains no path separators, it's
+	// looked up in $PATH (see exec.Command).
+	Path string
+	// Args are the arguments passed to the command (not including the
+	// command name).
+	Args []string
+	// Env, if non-nil, is the command's entire environment (as
+	// "key=value" strings). If nil, the command inherits the current
+	// process's environment.
+	Env []string
+	// Dir is the command's working directory. If empty, it inherits the
+	// current process's working directory.
+	Dir string
+	// Timeout, if positive, limits how long the command may run before
+	// it's killed (in addition to any deadline on the context passed to
+	// Value). Only the command itself is killed, so any processes it
+	// started that hold its output open will delay Value's return.
+	Timeout time.Duration
+	// Decoder decodes the command's standard output.
+	Decoder dials.Decoder
+}
+
+var _ dials.Source = (*Source)(nil)
+var _ dials.NamedSource = (*Source)(nil)
+
+// SourceName implements dials.NamedSource, returning common.CmdSourceName.
+func (s *Source) SourceName() string {
+	return common.CmdSourceName
+}
+
+// Value runs the command, and decodes its output. If the command fails
+// (exits with a non-zero status, can't be started, or is killed because
+// its context expired), the returned error includes the (possibly
+// truncated) contents of its standard error.
+func (s *Source) Value(ctx context.Context, t *dials.Type) (reflect.Value, error) {
+	if s.Decoder == nil {
+		return reflect.Value{}, errors.New("nil Decoder")
+	}
+	if s.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.Timeout)
+		defer cancel()
+	}
+
+	c := exec.CommandContext(ctx, s.Path, s.Args...)
+	c.Env = s.Env
+	c.Dir = s.Dir
+	stdout, stderr := bytes.Buffer{}, bytes.Buffer{}
+	c.Stdout = &stdout
+	c.Stderr = &stderr
+	if err := c.Run(); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			err = fmt.Errorf("%w (%s)", ctxErr, err)
+		}
+		return reflect.Value{}, &runErr{cmd: s.Path, err: err, stderr: stderr.String()}
+	}
+
+	v, err := s.Decoder.Decode(&stdout, t)
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("failed to decode output of command %q: %w", s.Path, err)
+	}
+	return v, nil
+}
+
+// runErr is returned when the command fails.
+type runErr struct {
+	cmd    string
+	err    error
+	stderr string
+}
+
+func (r *runErr) Error() string {
+	msg := fmt.Sprintf("command %q failed: %s", r.cmd, r.err)
+	stderr := strings.TrimSpace(r.stderr)
+	if stderr == "" {
+		return msg
+	}
+	if len(stderr) > maxStderrLen {
+		stderr = stderr[:maxStderrLen] + "..."
+	}
+	return msg + "; stderr: " + stderr
+}
+
+func (r *runErr) Unwrap() error {
+	return r.err
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vimeo/dials"
+	"github.com/vimeo/dials/decoders/json"
+)
+
+type testConfig struct {
+	Name   string
+	Secret string
+}
+
+// shSource returns a Source running script with sh
+func shSource(t testing.TB, script string) Source {
+	t.Helper()
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	return Source{Path: "sh", Args: []string{"-c", script}, Decoder: &json.Decoder{}}
+}
+
+func TestSource(t *testing.T) {
+	t.Parallel()
+	src := shSource(t, `echo "{\"Secret\": \"$1\"}"`)
+	src.Args = append(src.Args, "sh", "hunter2")
+	d, err := dials.Config(context.Background(), &testConfig{Name: "svc"}, &src)
+	require.NoError(t, err)
+	assert.Equal(t, &testConfig{Name: "svc", Secret: "hunter2"}, d.View())
+}
+
+func TestSourceErrors(t *testing.T) {
+	t.Parallel()
+	for _, itbl := range []struct {
+		name      string
+		script    string
+		timeout   time.Duration
+		expectErr string
+	}{
+		{
+			name:      "exit_status",
+			script:    "echo 'permission denied' >&2; exit 3",
+			expectErr: `command "sh" failed: exit status 3; stderr: permission denied`,
+		},
+		{
+			name:      "timeout",
+			script:    "echo 'fetching' >&2; exec sleep 5",
+			timeout:   50 * time.Millisecond,
+			expectErr: `command "sh" failed: context deadline exceeded (signal: killed); stderr: fetching`,
+		},
+		{
+			name:      "bad_output",
+			script:    "echo 'not json'",
+			expectErr: `failed to decode output of command "sh"`,
+		},
+	} {
+		tbl := itbl
+		t.Run(tbl.name, func(t *testing.T) {
+			t.Parallel()
+			src := shSource(t, tbl.script)
+			src.Timeout = tbl.timeout
+			_, err := dials.Config(context.Background(), &testConfig{}, &src)
+			assert.ErrorContains(t, err, tbl.expectErr)
+		})
+	}
+
+	_, err := dials.Config(context.Background(), &testConfig{}, &Source{Path: "true"})
+	assert.EqualError(t, err, "nil Decoder")
+}
+
+func TestWatchingSource(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	path := filepath.Join(t.TempDir(), "secret.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"Secret": "hunter2"}`), 0o600))
+
+	src := &WatchingSource{
+		Source:       shSource(t, `cat "$1"`),
+		PollInterval: time.Millisecond,
+	}
+	src.Args = append(src.Args, "sh", path)
+	d, err := dials.Config(ctx, &testConfig{}, src)
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2", d.View().Secret)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"Secret": "hunter3"}`), 0o600))
+	assert.Equal(t, "hunter3", (<-d.Events()).Secret)
+
+	cancel()
+	src.WG.Wait()
+
+	_, err = dials.Config(context.Background(), &testConfig{},
+		&WatchingSource{Source: shSource(t, "echo '{}'")})
+	assert.ErrorContains(t, err, "non-positive PollInterval 0s")
+}
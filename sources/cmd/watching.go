@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/vimeo/dials"
+	"github.com/vimeo/dials/sources/pollhelper"
+)
+
+// WatchingSource wraps Source, implementing dials.Watcher by re-running the
+// command every PollInterval, and reporting a new value if anything
+// relevant changed. Errors from the command are reported via
+// dials.WatchArgs, and the previous value is retained.
+type WatchingSource struct {
+	Source
+	// PollInterval is the period between runs of the command. It must be
+	// positive.
+	PollInterval time.Duration
+	// WG is incremented while the background goroutine is running.
+	WG sync.WaitGroup
+
+	poller pollhelper.Poller
+}
+
+var _ dials.Source = (*WatchingSource)(nil)
+var _ dials.Watcher = (*WatchingSource)(nil)
+var _ dials.NamedSource = (*WatchingSource)(nil)
+
+// Value implements the dials.Source interface, running the command. The
+// value is retained, so Watch can compare the command's later output
+// against it without running the command again.
+func (w *WatchingSource) Value(ctx context.Context, t *dials.Type) (reflect.Value, error) {
+	return w.poller.Value(ctx, t, w.Source.Value)
+}
+
+// Watch starts a background goroutine that periodically re-runs the
+// command. The goroutine exits when ctx is canceled.
+func (w *WatchingSource) Watch(ctx context.Context, t *dials.Type, args dials.WatchArgs) error {
+	if w.PollInterval <= 0 {
+		return fmt.Errorf("non-positive PollInterval %s", w.PollInterval)
+	}
+	return w.poller.Start(ctx, t, args, &w.WG, pollhelper.Every(w.PollInterval), w.Source.Value)
+}
@@ -0,0 +1,81 @@
+// Package defaults provides a dials.Source that populates fields from the
+// default values declared in their `dialsdefault` struct tags.
+package defaults
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/vimeo/dials"
+	"github.com/vimeo/dials/common"
+	"github.com/vimeo/dials/parse"
+	"github.com/vimeo/dials/tagformat/caseconversion"
+	"github.com/vimeo/dials/transform"
+)
+
+// Source implements the dials.Source interface to set fields to the values
+// declared in their `dialsdefault` struct tags, keeping the defaults
+// alongside the field definitions rather than in the code constructing the
+// template passed to dials.Config. e.g.
+//
+//	type Config struct {
+//		Port    int           `dialsdefault:"8080"`
+//		Timeout time.Duration `dialsdefault:"30s"`
+//		Hosts   []string      `dialsdefault:"a.local,b.local"`
+//	}
+//
+// Tag values are parsed in the same way as environment variables (see the
+// parse package), so any field that may be set from an environment variable
+// may have a default. Fields without a `dialsdefault` tag are left unset.
+//
+// Source should generally be the first Source passed to dials.Config, so
+// the defaults have the lowest precedence. Values in the template passed to
+// dials.Config have lower precedence still.
+type Source struct {
+	// Delimiters overrides the separators used when parsing slices, sets
+	// and maps from tags. Unset delimiters retain their defaults (see
+	// parse.DefaultDelimiters).
+	Delimiters parse.Delimiters
+}
+
+var _ dials.Source = (*Source)(nil)
+var _ dials.NamedSource = (*Source)(nil)
+
+// SourceName implements dials.NamedSource, returning
+// common.DefaultsSourceName.
+func (s *Source) SourceName() string {
+	return common.DefaultsSourceName
+}
+
+// Value returns a value of the type t, with each field that has a
+// `dialsdefault` tag set to the parsed tag value (including the fields of
+// nested structs).
+func (s *Source) Value(_ context.Context, t *dials.Type) (reflect.Value, error) {
+	// flatten the nested fields, so tags on nested struct fields are
+	// visible at the top level
+	flattenMangler := transform.NewFlattenMangler(common.DialsTagName, caseconversion.EncodeUpperCamelCase, caseconversion.EncodeUpperCamelCase)
+	// convert all the fields to strings, so they can be set directly
+	// from the tags
+	stringCastingMangler := transform.NewStringCastingMangler(s.Delimiters)
+	tfmr := transform.NewTransformer(t.Type(), flattenMangler, stringCastingMangler)
+
+	val, err := tfmr.Translate()
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	valType := val.Type()
+	for i := 0; i < val.NumField(); i++ {
+		sf := valType.Field(i)
+		if def, ok := sf.Tag.Lookup(common.DialsDefaultTag); ok {
+			val.Field(i).Set(reflect.ValueOf(&def))
+		}
+	}
+
+	out, err := tfmr.ReverseTranslate(val)
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("failed to parse %s tags: %w", common.DialsDefaultTag, err)
+	}
+	return out, nil
+}
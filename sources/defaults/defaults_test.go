@@ -0,0 +1,83 @@
+package defaults
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vimeo/dials"
+	"github.com/vimeo/dials/parse"
+	"github.com/vimeo/dials/sources/env"
+)
+
+type dbConfig struct {
+	Host string `dialsdefault:"localhost"`
+	Port int    `dialsdefault:"5432"`
+}
+
+type testConfig struct {
+	Name     string
+	Port     int               `dialsdefault:"8080"`
+	Timeout  time.Duration     `dialsdefault:"30s"`
+	Verbose  bool              `dialsdefault:"true"`
+	Hosts    []string          `dialsdefault:"a.local,b.local"`
+	Labels   map[string]string `dialsdefault:"env:prod,team:video"`
+	Ratio    *float64          `dialsdefault:"0.5"`
+	Empty    string            `dialsdefault:""`
+	DB       dbConfig
+	Replica  *dbConfig `dials:"replica"`
+	Unparsed chan int
+}
+
+func TestSource(t *testing.T) {
+	t.Parallel()
+	d, err := dials.Config(context.Background(), &testConfig{Name: "template", Empty: "template"}, &Source{})
+	require.NoError(t, err)
+
+	ratio := 0.5
+	assert.Equal(t, &testConfig{
+		Name:    "template",
+		Port:    8080,
+		Timeout: 30 * time.Second,
+		Verbose: true,
+		Hosts:   []string{"a.local", "b.local"},
+		Labels:  map[string]string{"env": "prod", "team": "video"},
+		Ratio:   &ratio,
+		// an empty default is still a default
+		Empty:   "",
+		DB:      dbConfig{Host: "localhost", Port: 5432},
+		Replica: &dbConfig{Host: "localhost", Port: 5432},
+	}, d.View())
+}
+
+func TestSourceLowestPrecedence(t *testing.T) {
+	t.Setenv("PORT", "9090")
+	d, err := dials.Config(context.Background(), &testConfig{}, &Source{}, &env.Source{})
+	require.NoError(t, err)
+	assert.Equal(t, 9090, d.View().Port)
+	assert.Equal(t, 30*time.Second, d.View().Timeout)
+}
+
+func TestSourceDelimiters(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		Hosts []string `dialsdefault:"a,b;c"`
+	}
+	d, err := dials.Config(context.Background(), &config{},
+		&Source{Delimiters: parse.Delimiters{Element: ';'}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a,b", "c"}, d.View().Hosts)
+}
+
+func TestSourceBadDefault(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		Port int `dialsdefault:"eighty"`
+	}
+	_, err := dials.Config(context.Background(), &config{}, &Source{})
+	assert.ErrorContains(t, err, "failed to parse dialsdefault tags")
+	assert.ErrorContains(t, err, `"eighty"`)
+}
@@ -0,0 +1,90 @@
+// Package embedfs provides a dials.Source reading a config file from an
+// fs.FS, such as an embed.FS populated by a //go:embed directive.
+package embedfs
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"reflect"
+
+	"github.com/vimeo/dials"
+	"github.com/vimeo/dials/common"
+)
+
+// NewSource returns a Source reading the file at path within fsys with
+// decoder. path must be valid according to fs.ValidPath (slash-separated and
+// unrooted, e.g. "config/default.yaml").
+//
+// e.g. to stack a default config file embedded in the binary below an
+// optional file on disk:
+//
+//	//go:embed default.yaml
+//	var defaultFS embed.FS
+//
+//	...
+//	def, err := embedfs.NewSource(defaultFS, "default.yaml", &yaml.Decoder{})
+//	...
+//	d, err := dials.Config(ctx, &Config{}, def, fileSrc)
+func NewSource(fsys fs.FS, path string, decoder dials.Decoder) (*Source, error) {
+	if fsys == nil {
+		return nil, fmt.Errorf("nil fs.FS for path %q", path)
+	}
+	if decoder == nil {
+		return nil, fmt.Errorf("nil decoder for path %q", path)
+	}
+	if !fs.ValidPath(path) {
+		return nil, fmt.Errorf("invalid fs.FS path %q", path)
+	}
+	return &Source{fsys: fsys, path: path, decoder: decoder}, nil
+}
+
+// Source reads a config file from an fs.FS. Since the contents of an
+// embed.FS can't change, it doesn't implement dials.Watcher.
+// Errors reported by the decoder are wrapped in a DecoderErr with the path
+// populated.
+type Source struct {
+	fsys    fs.FS
+	path    string
+	decoder dials.Decoder
+}
+
+var _ dials.Source = (*Source)(nil)
+var _ dials.NamedSource = (*Source)(nil)
+
+// DecoderErr wraps another error returned by the decoder
+type DecoderErr struct {
+	Err     error
+	Path    string
+	Decoder dials.Decoder
+}
+
+func (d *DecoderErr) Error() string {
+	return fmt.Sprintf("decoder (type %T) error on %q: %s",
+		d.Decoder, d.Path, d.Err.Error())
+}
+
+func (d *DecoderErr) Unwrap() error {
+	return d.Err
+}
+
+// SourceName implements dials.NamedSource, returning
+// common.EmbedFSSourceName.
+func (s *Source) SourceName() string {
+	return common.EmbedFSSourceName
+}
+
+// Value opens the file and decodes its contents.
+func (s *Source) Value(_ context.Context, t *dials.Type) (reflect.Value, error) {
+	f, openErr := s.fsys.Open(s.path)
+	if openErr != nil {
+		return reflect.Value{}, fmt.Errorf("failed to open %q: %w", s.path, openErr)
+	}
+	defer f.Close()
+
+	v, decErr := s.decoder.Decode(f, t)
+	if decErr != nil {
+		return reflect.Value{}, &DecoderErr{Err: decErr, Path: s.path, Decoder: s.decoder}
+	}
+	return v, nil
+}
@@ -0,0 +1,62 @@
+package embedfs
+
+import (
+	"context"
+	"embed"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vimeo/dials"
+	"github.com/vimeo/dials/decoders/json"
+	"github.com/vimeo/dials/decoders/yaml"
+	"github.com/vimeo/dials/sources/static"
+)
+
+//go:embed testdata/default.yaml
+var testdataFS embed.FS
+
+type testConfig struct {
+	Name string `dials:"name"`
+	Port int    `dials:"port"`
+}
+
+func TestSourceEmbedFS(t *testing.T) {
+	t.Parallel()
+	src, err := NewSource(testdataFS, "testdata/default.yaml", &yaml.Decoder{})
+	require.NoError(t, err)
+
+	// the embedded defaults are overridden by later sources
+	override := &static.StringSource{Data: `{"port": 9090}`, Decoder: &json.Decoder{}}
+	d, err := dials.Config(context.Background(), &testConfig{}, src, override)
+	require.NoError(t, err)
+	assert.Equal(t, &testConfig{Name: "fim", Port: 9090}, d.View())
+}
+
+func TestSourceErrors(t *testing.T) {
+	t.Parallel()
+	fsys := fstest.MapFS{
+		"bad.json": &fstest.MapFile{Data: []byte(`{"port": "eighty"}`)},
+	}
+
+	_, err := NewSource(fsys, "/bad.json", &json.Decoder{})
+	assert.EqualError(t, err, `invalid fs.FS path "/bad.json"`)
+
+	_, err = NewSource(fsys, "bad.json", nil)
+	assert.EqualError(t, err, `nil decoder for path "bad.json"`)
+
+	missing, err := NewSource(fsys, "missing.json", &json.Decoder{})
+	require.NoError(t, err)
+	_, err = dials.Config(context.Background(), &testConfig{}, missing)
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	bad, err := NewSource(fsys, "bad.json", &json.Decoder{})
+	require.NoError(t, err)
+	_, err = dials.Config(context.Background(), &testConfig{}, bad)
+	decErr := &DecoderErr{}
+	require.ErrorAs(t, err, &decErr)
+	assert.Equal(t, "bad.json", decErr.Path)
+}
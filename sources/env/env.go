@@ -5,9 +5,12 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"strings"
+	"sync/atomic"
 
 	"github.com/vimeo/dials"
 	"github.com/vimeo/dials/common"
+	"github.com/vimeo/dials/parse"
 	"github.com/vimeo/dials/tagformat"
 	"github.com/vimeo/dials/tagformat/caseconversion"
 	"github.com/vimeo/dials/transform"
@@ -17,9 +20,50 @@ import (
 // environment variables.
 type Source struct {
 	Prefix string
+	// Delimiters overrides the separators used when parsing slices, sets
+	// and maps from environment variables.  Unset delimiters retain their
+	// defaults (see parse.DefaultDelimiters).
+	Delimiters parse.Delimiters
+	// EmptyBoolIsTrue enables Unix-style presence semantics for boolean
+	// fields: a variable that's set, but empty (e.g. `VERBOSE=`), sets
+	// the field to true rather than failing to parse. Non-empty values
+	// are parsed as usual, so VERBOSE=false still sets the field to
+	// false, and unset variables leave the field unset.
+	EmptyBoolIsTrue bool
+
+	// consumed holds the []ConsumedVar recorded by the most recent call
+	// to Value
+	consumed atomic.Value
+}
+
+// ConsumedVar records an environment variable that was set, and used to
+// populate a field (see Source.Consumed).
+type ConsumedVar struct {
+	// Name is the name of the environment variable (including any
+	// Prefix)
+	Name string
+	// Field is the path to the field it populated, with the names of
+	// enclosing struct fields joined with "." (e.g. "Database.Port")
+	Field string
+}
+
+// Consumed returns the environment variables that were set and used to
+// populate fields in the most recent call to Value (in field order), or nil
+// if Value hasn't been called. It's intended for debugging precedence issues
+// (e.g. did PORT actually come from the environment?), and doesn't affect
+// the returned configuration.
+func (e *Source) Consumed() []ConsumedVar {
+	c, _ := e.consumed.Load().([]ConsumedVar)
+	return append([]ConsumedVar(nil), c...)
 }
 
 var _ dials.Source = (*Source)(nil)
+var _ dials.NamedSource = (*Source)(nil)
+
+// SourceName implements dials.NamedSource, returning common.EnvSourceName.
+func (e *Source) SourceName() string {
+	return common.EnvSourceName
+}
 
 // Value fills in the user-provided config struct using environment variables.
 // It looks up the environment variable to read into a given struct field by
@@ -28,6 +72,14 @@ var _ dials.Source = (*Source)(nil)
 // assumes the name is in Go-style camelCase (e.g., "JSONFilePath") and converts
 // it to UPPER_SNAKE_CASE. (The casing of `dialsenv` and `dials` tags is left
 // unchanged.)
+//
+// A `dialsenv` tag binds the field directly to the named environment
+// variable, regardless of any enclosing structs' names. Prefix is still
+// prepended, unless the tag includes the "noprefix" option (e.g.
+// `dialsenv:"DATABASE_URL,noprefix"`), for variables whose names are
+// mandated externally. A `dialsenv:"-"` tag excludes the field (or, on a
+// struct-typed field, all of its nested fields) from the environment
+// entirely.
 func (e *Source) Value(_ context.Context, t *dials.Type) (reflect.Value, error) {
 	// flatten the nested fields
 	flattenMangler := transform.NewFlattenMangler(common.DialsTagName, caseconversion.EncodeUpperCamelCase, caseconversion.EncodeUpperCamelCase)
@@ -36,7 +88,7 @@ func (e *Source) Value(_ context.Context, t *dials.Type) (reflect.Value, error)
 	// copy tags from "dials" to "dialsenv" tag
 	tagCopyingMangler := &tagformat.TagCopyingMangler{SrcTag: common.DialsTagName, NewTag: common.DialsEnvTagName}
 	// convert all the fields in the flattened struct to string type so the environment variables can be set
-	stringCastingMangler := &transform.StringCastingMangler{}
+	stringCastingMangler := transform.NewStringCastingMangler(e.Delimiters)
 	// allow aliasing to migrate from one name to another
 	aliasMangler := transform.NewAliasMangler(common.DialsTagName, common.DialsEnvTagName)
 	tfmr := transform.NewTransformer(t.Type(), aliasMangler, flattenMangler, reformatTagMangler, tagCopyingMangler, stringCastingMangler)
@@ -46,28 +98,116 @@ func (e *Source) Value(_ context.Context, t *dials.Type) (reflect.Value, error)
 		return reflect.Value{}, err
 	}
 
+	consumed := []ConsumedVar{}
 	valType := val.Type()
 	for i := 0; i < val.NumField(); i++ {
 		sf := valType.Field(i)
-		envTagVal := sf.Tag.Get(common.DialsEnvTagName)
+		envTagVal, envTagOpts := splitEnvTag(sf.Tag.Get(common.DialsEnvTagName))
 		if envTagVal == "" {
 			// dialsenv tag should be populated because dials tag is populated
 			// after flatten mangler and we copy from dials to dialsenv tag
 			panic(fmt.Errorf("empty %s tag for field name %s", common.DialsEnvTagName, sf.Name))
 		}
+		if envTagVal == envIgnoreTagVal || parentExcluded(t.Type(), transform.FieldPath(sf)) {
+			continue
+		}
 
-		if e.Prefix != "" {
+		if e.Prefix != "" && !hasEnvTagOpt(envTagOpts, envNoPrefixOpt) {
 			envTagVal = e.Prefix + "_" + envTagVal
 		}
 
 		if envVarVal, ok := os.LookupEnv(envTagVal); ok {
+			if envVarVal == "" && e.EmptyBoolIsTrue && isBoolField(t.Type(), transform.FieldPath(sf)) {
+				envVarVal = "true"
+			}
 			// The StringCastingMangler has transformed all the fields on the
 			// dials.Type into *string types, so that they can be set here as
 			// strings (and when ReverseTranslate is called, cast into the
 			// original types on the StructFields.)
 			val.Field(i).Set(reflect.ValueOf(&envVarVal))
+			consumed = append(consumed, ConsumedVar{
+				Name:  envTagVal,
+				Field: strings.Join(transform.FieldPath(sf), "."),
+			})
 		}
 	}
 
-	return tfmr.ReverseTranslate(val)
+	out, err := tfmr.ReverseTranslate(val)
+	if err != nil {
+		return out, err
+	}
+	e.consumed.Store(consumed)
+	return out, nil
+}
+
+const (
+	// envIgnoreTagVal is the dialsenv tag value that excludes a field
+	envIgnoreTagVal = "-"
+	// envNoPrefixOpt is the dialsenv tag option that suppresses the prefix
+	envNoPrefixOpt = "noprefix"
+)
+
+// splitEnvTag splits a dialsenv tag value into the variable name and any
+// comma-separated options.
+func splitEnvTag(tagVal string) (string, []string) {
+	parts := strings.Split(tagVal, ",")
+	return parts[0], parts[1:]
+}
+
+func hasEnvTagOpt(opts []string, opt string) bool {
+	for _, o := range opts {
+		if o == opt {
+			return true
+		}
+	}
+	return false
+}
+
+// parentExcluded walks the (pre-flattening) field path through t, and
+// indicates whether any of the enclosing struct fields are tagged
+// `dialsenv:"-"`.
+func parentExcluded(t reflect.Type, fieldPath []string) bool {
+	if len(fieldPath) < 2 {
+		return false
+	}
+	for _, fname := range fieldPath[:len(fieldPath)-1] {
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			return false
+		}
+		f, ok := t.FieldByName(fname)
+		if !ok {
+			// aliased fields have synthetic names
+			return false
+		}
+		if name, _ := splitEnvTag(f.Tag.Get(common.DialsEnvTagName)); name == envIgnoreTagVal {
+			return true
+		}
+		t = f.Type
+	}
+	return false
+}
+
+// isBoolField walks the (pre-flattening) field path through t, and
+// indicates whether the field it leads to is a bool (or pointer to one).
+func isBoolField(t reflect.Type, fieldPath []string) bool {
+	for _, fname := range fieldPath {
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			return false
+		}
+		f, ok := t.FieldByName(fname)
+		if !ok {
+			return false
+		}
+		t = f.Type
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return len(fieldPath) > 0 && t.Kind() == reflect.Bool
 }
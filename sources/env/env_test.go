@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/vimeo/dials"
+	"github.com/vimeo/dials/parse"
 )
 
 func testSafeDialsRet[T any](d *dials.Dials[T], err error) (any, error) {
@@ -18,6 +19,12 @@ func testSafeDialsRet[T any](d *dials.Dials[T], err error) (any, error) {
 	return d.View(), err
 }
 
+type endpoint struct {
+	Host    string
+	Port    int
+	Timeout time.Duration
+}
+
 func TestEnv(t *testing.T) {
 	type Embed struct {
 		Foo      int
@@ -139,6 +146,26 @@ func TestEnv(t *testing.T) {
 			Source:      Source{Prefix: "PREFIX"},
 			Expected:    &struct{ EnvVar string }{EnvVar: "asdf"},
 		},
+		"semicolon_delimited_string_slice": {
+			Run: func(ctx context.Context, src *Source) (any, error) {
+				cfg := struct{ EnvVar []string }{}
+				return testSafeDialsRet(dials.Config(context.Background(), &cfg, src))
+			},
+			EnvVarName:  "ENV_VAR",
+			EnvVarValue: `a,b;c`,
+			Source:      Source{Delimiters: parse.Delimiters{Element: ';'}},
+			Expected:    &struct{ EnvVar []string }{EnvVar: []string{"a,b", "c"}},
+		},
+		"custom_delimited_map": {
+			Run: func(ctx context.Context, src *Source) (any, error) {
+				cfg := struct{ EnvVar map[string]int }{}
+				return testSafeDialsRet(dials.Config(context.Background(), &cfg, src))
+			},
+			EnvVarName:  "ENV_VAR",
+			EnvVarValue: `a:b=1;c=2`,
+			Source:      Source{Delimiters: parse.Delimiters{Element: ';', KeyValue: '='}},
+			Expected:    &struct{ EnvVar map[string]int }{EnvVar: map[string]int{"a:b": 1, "c": 2}},
+		},
 		"zero_value_string": {
 			Run: func(ctx context.Context, src *Source) (any, error) {
 				cfg := struct{ EnvVar string }{}
@@ -266,6 +293,111 @@ func TestEnv(t *testing.T) {
 				},
 			},
 		},
+		"nested_explicit_env_tag": {
+			Run: func(ctx context.Context, src *Source) (any, error) {
+				cfg := struct {
+					DB struct {
+						URL string `dialsenv:"DATABASE_URL"`
+					}
+				}{}
+				return testSafeDialsRet(dials.Config(context.Background(), &cfg, src))
+			},
+			EnvVarName:  "DATABASE_URL",
+			EnvVarValue: "postgres://db",
+			Expected: &struct {
+				DB struct{ URL string }
+			}{DB: struct{ URL string }{URL: "postgres://db"}},
+		},
+		"prefixed_explicit_env_tag": {
+			Run: func(ctx context.Context, src *Source) (any, error) {
+				cfg := struct {
+					URL string `dialsenv:"DATABASE_URL"`
+				}{}
+				return testSafeDialsRet(dials.Config(context.Background(), &cfg, src))
+			},
+			EnvVarName:  "PREFIX_DATABASE_URL",
+			EnvVarValue: "postgres://db",
+			Source:      Source{Prefix: "PREFIX"},
+			Expected:    &struct{ URL string }{URL: "postgres://db"},
+		},
+		"prefixed_explicit_env_tag_noprefix": {
+			Run: func(ctx context.Context, src *Source) (any, error) {
+				cfg := struct {
+					URL string `dialsenv:"DATABASE_URL,noprefix"`
+				}{}
+				return testSafeDialsRet(dials.Config(context.Background(), &cfg, src))
+			},
+			EnvVarName:  "DATABASE_URL",
+			EnvVarValue: "postgres://db",
+			Source:      Source{Prefix: "PREFIX"},
+			Expected:    &struct{ URL string }{URL: "postgres://db"},
+		},
+		"excluded_field": {
+			Run: func(ctx context.Context, src *Source) (any, error) {
+				cfg := struct {
+					EnvVar string `dials:"ENV_VAR" dialsenv:"-"`
+				}{}
+				return testSafeDialsRet(dials.Config(context.Background(), &cfg, src))
+			},
+			EnvVarName:  "ENV_VAR",
+			EnvVarValue: "asdf",
+			Expected:    &struct{ EnvVar string }{EnvVar: ""},
+		},
+		"excluded_struct": {
+			Run: func(ctx context.Context, src *Source) (any, error) {
+				cfg := struct {
+					Inner struct {
+						EnvVar string
+					} `dialsenv:"-"`
+				}{}
+				return testSafeDialsRet(dials.Config(context.Background(), &cfg, src))
+			},
+			EnvVarName:  "INNER_ENV_VAR",
+			EnvVarValue: "asdf",
+			Expected: &struct {
+				Inner struct{ EnvVar string }
+			}{},
+		},
+		"delimited_struct_slice": {
+			Run: func(ctx context.Context, src *Source) (any, error) {
+				cfg := struct {
+					Endpoints []endpoint
+				}{}
+				return testSafeDialsRet(dials.Config(context.Background(), &cfg, src))
+			},
+			EnvVarName:  "ENDPOINTS",
+			EnvVarValue: "host1:8080:5s,host2:9090",
+			Expected: &struct {
+				Endpoints []endpoint
+			}{Endpoints: []endpoint{
+				{Host: "host1", Port: 8080, Timeout: 5 * time.Second},
+				{Host: "host2", Port: 9090},
+			}},
+		},
+		"delimited_struct_slice_custom_delim": {
+			Run: func(ctx context.Context, src *Source) (any, error) {
+				cfg := struct {
+					Endpoints []*endpoint `dialsstructdelim:"/"`
+				}{}
+				return testSafeDialsRet(dials.Config(context.Background(), &cfg, src))
+			},
+			EnvVarName:  "ENDPOINTS",
+			EnvVarValue: "host1/8080",
+			Expected: &struct {
+				Endpoints []*endpoint `dialsstructdelim:"/"`
+			}{Endpoints: []*endpoint{{Host: "host1", Port: 8080}}},
+		},
+		"delimited_struct_slice_invalid": {
+			Run: func(ctx context.Context, src *Source) (any, error) {
+				cfg := struct {
+					Endpoints []endpoint
+				}{}
+				return testSafeDialsRet(dials.Config(context.Background(), &cfg, src))
+			},
+			EnvVarName:  "ENDPOINTS",
+			EnvVarValue: "host1:http",
+			ExpectedErr: `parse error of field Port of item 0 "host1:http"`,
+		},
 	}
 
 	ctx := context.Background()
@@ -283,3 +415,113 @@ func TestEnv(t *testing.T) {
 		})
 	}
 }
+
+func TestWatchingSource(t *testing.T) {
+	type config struct {
+		WatchedVar   string
+		UnwatchedVar int
+	}
+
+	os.Setenv("WATCHED_VAR", "before")
+	defer os.Unsetenv("WATCHED_VAR")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	trigger := make(chan struct{})
+	src := WatchingSource{Trigger: trigger}
+	d, err := dials.Config(ctx, &config{UnwatchedVar: 3}, &src)
+	require.NoError(t, err)
+	assert.Equal(t, &config{WatchedVar: "before", UnwatchedVar: 3}, d.View())
+
+	_, serial := d.ViewVersion()
+	// nothing changed yet, so this trigger shouldn't report a new value
+	trigger <- struct{}{}
+
+	os.Setenv("WATCHED_VAR", "after")
+	trigger <- struct{}{}
+
+	waitCtx, waitCancel := context.WithTimeout(ctx, 5*time.Second)
+	defer waitCancel()
+	newCfg, _, waitErr := d.WaitForVersionAfter(waitCtx, serial)
+	require.NoError(t, waitErr)
+	assert.Equal(t, &config{WatchedVar: "after", UnwatchedVar: 3}, newCfg)
+
+	cancel()
+	src.WG.Wait()
+}
+
+func TestConsumed(t *testing.T) {
+	type database struct {
+		Host string
+		Port int
+	}
+	type config struct {
+		LogLevel string
+		Name     string `dialsalias:"OLD_NAME"`
+		Unset    string
+		Database database
+	}
+
+	t.Setenv("APP_LOG_LEVEL", "debug")
+	t.Setenv("APP_OLD_NAME", "fimbat")
+	t.Setenv("APP_DATABASE_PORT", "5432")
+	t.Setenv("DATABASE_HOST", "unprefixed, so unused")
+
+	src := Source{Prefix: "APP"}
+	assert.Nil(t, src.Consumed())
+
+	d, err := dials.Config(context.Background(), &config{Unset: "default"}, &src)
+	require.NoError(t, err)
+	assert.Equal(t, &config{
+		LogLevel: "debug",
+		Name:     "fimbat",
+		Unset:    "default",
+		Database: database{Port: 5432},
+	}, d.View())
+
+	assert.Equal(t, []ConsumedVar{
+		{Name: "APP_LOG_LEVEL", Field: "LogLevel"},
+		{Name: "APP_OLD_NAME", Field: "Name"},
+		{Name: "APP_DATABASE_PORT", Field: "Database.Port"},
+	}, src.Consumed())
+}
+
+func TestEmptyBoolIsTrue(t *testing.T) {
+	type logging struct {
+		Color bool
+	}
+	type config struct {
+		Verbose bool
+		Debug   *bool
+		Quiet   bool
+		Trace   bool
+		Name    string
+		Logging logging
+	}
+
+	t.Setenv("VERBOSE", "")
+	t.Setenv("DEBUG", "")
+	t.Setenv("QUIET", "false")
+	t.Setenv("NAME", "")
+	t.Setenv("LOGGING_COLOR", "")
+
+	d, err := dials.Config(context.Background(), &config{Quiet: true, Name: "default"},
+		&Source{EmptyBoolIsTrue: true})
+	require.NoError(t, err)
+	tru := true
+	assert.Equal(t, &config{
+		Verbose: true,
+		Debug:   &tru,
+		Quiet:   false,
+		// unset, so left alone
+		Trace: false,
+		// only bools are affected
+		Name:    "",
+		Logging: logging{Color: true},
+	}, d.View())
+
+	// empty values are still invalid without EmptyBoolIsTrue
+	_, err = dials.Config(context.Background(), &config{}, &Source{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,89 @@
+package env
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/vimeo/dials"
+	"github.com/vimeo/dials/sources/pollhelper"
+)
+
+// WatchingSource wraps Source, implementing dials.Watcher by re-reading the
+// environment whenever triggered, and reporting a new value if anything
+// relevant changed.
+//
+// It may be triggered by a receive on Trigger, by SIGHUP (if ReloadOnSIGHUP is
+// set), or on a fixed PollInterval.
+type WatchingSource struct {
+	Source
+	// Trigger, if non-nil, causes the environment to be re-read every time
+	// a value is received.
+	Trigger <-chan struct{}
+	// ReloadOnSIGHUP causes the environment to be re-read every time the
+	// process receives a SIGHUP. Note that this prevents SIGHUP from
+	// terminating the process.
+	ReloadOnSIGHUP bool
+	// PollInterval, if positive, causes the environment to be re-read on
+	// a ticker with that period.
+	PollInterval time.Duration
+	// WG is incremented while the background goroutine is running.
+	WG sync.WaitGroup
+
+	poller pollhelper.Poller
+}
+
+var _ dials.Source = (*WatchingSource)(nil)
+var _ dials.Watcher = (*WatchingSource)(nil)
+var _ dials.NamedSource = (*WatchingSource)(nil)
+
+// Value implements the dials.Source interface, reading the environment. The
+// value is retained, so Watch starts comparing re-read values against it.
+func (w *WatchingSource) Value(ctx context.Context, t *dials.Type) (reflect.Value, error) {
+	return w.poller.Value(ctx, t, w.Source.Value)
+}
+
+// Watch starts a background goroutine that re-reads the environment when
+// triggered. The goroutine exits when ctx is canceled.
+func (w *WatchingSource) Watch(ctx context.Context, t *dials.Type, args dials.WatchArgs) error {
+	var sigCh chan os.Signal
+	if w.ReloadOnSIGHUP {
+		sigCh = make(chan os.Signal, 1)
+		signal.Notify(sigCh, syscall.SIGHUP)
+	}
+	var ticker *time.Ticker
+	wait := func(ctx context.Context) bool {
+		var tickerChan <-chan time.Time
+		if w.PollInterval > 0 {
+			if ticker == nil {
+				ticker = time.NewTicker(w.PollInterval)
+			}
+			tickerChan = ticker.C
+		}
+		select {
+		case <-w.Trigger:
+		case <-sigCh:
+		case <-tickerChan:
+		case <-ctx.Done():
+			if ticker != nil {
+				ticker.Stop()
+			}
+			if sigCh != nil {
+				signal.Stop(sigCh)
+			}
+			return false
+		}
+		return true
+	}
+	if err := w.poller.Start(ctx, t, args, &w.WG, wait, w.Source.Value); err != nil {
+		if sigCh != nil {
+			signal.Stop(sigCh)
+		}
+		return err
+	}
+	return nil
+}
@@ -19,10 +19,22 @@ import (
 
 	"github.com/fsnotify/fsnotify"
 	"github.com/vimeo/dials"
+	"github.com/vimeo/dials/common"
 )
 
+// StdinPath is a sentinel path, which, when passed to NewSource or
+// NewSourceWithDecoderFactory, reads the config from os.Stdin rather than a
+// file.
+// Since stdin can only be read once, its contents are buffered on the first
+// call to Value, and it cannot be watched.
+const StdinPath = "-"
+
 // NewSource converts path to an absolute path and returns a source for that file.
+// If path is StdinPath, the returned Source reads from os.Stdin instead.
 func NewSource(path string, decoder dials.Decoder) (*Source, error) {
+	if path == StdinPath {
+		return &Source{path: StdinPath, decoder: decoder, stdin: os.Stdin}, nil
+	}
 	absPath, absErr := filepath.Abs(path)
 	if absErr != nil {
 		return nil, fmt.Errorf("failed to make path %q absolute: %s", path, absErr)
@@ -30,12 +42,40 @@ func NewSource(path string, decoder dials.Decoder) (*Source, error) {
 	return &Source{path: absPath, decoder: decoder}, nil
 }
 
+// DecoderFactory returns an appropriate decoder for the file at the path
+// passed as its argument (usually based on the file's extension), or nil if
+// there is no appropriate decoder.
+type DecoderFactory func(path string) dials.Decoder
+
+// NewSourceWithDecoderFactory converts path to an absolute path and returns a
+// source for that file, which picks its decoder by calling df with the
+// symlink-resolved path every time the file is read.
+// If path is StdinPath, the returned Source reads from os.Stdin instead, and df
+// is called with StdinPath.
+func NewSourceWithDecoderFactory(path string, df DecoderFactory) (*Source, error) {
+	if df == nil {
+		return nil, fmt.Errorf("nil DecoderFactory for path %q", path)
+	}
+	if path == StdinPath {
+		return &Source{path: StdinPath, decoderFactory: df, stdin: os.Stdin}, nil
+	}
+	absPath, absErr := filepath.Abs(path)
+	if absErr != nil {
+		return nil, fmt.Errorf("failed to make path %q absolute: %s", path, absErr)
+	}
+	return &Source{path: absPath, decoderFactory: df}, nil
+}
+
 // Source is a raw file source.
 // Errors reported by the wrapped decoder will be reported wrapped in a
 // DecoderErr with the error and file-path populated.
 type Source struct {
 	path    string
 	decoder dials.Decoder
+	// decoderFactory, if non-nil, takes precedence over decoder, and is
+	// called with the symlink-resolved path on every read, so the decoder
+	// tracks the extension of the file that's actually being read.
+	decoderFactory DecoderFactory
 	// We use a random HMAC key for each run since it's not much more
 	// expensive and avoids issues with both preimage and collision attacks
 	// on our digest-function
@@ -44,9 +84,23 @@ type Source struct {
 	hmacKey        []byte
 	lastHMACSHA256 []byte
 	hmacMu         sync.Mutex
+
+	// stdin is non-nil if this source reads from stdin (see StdinPath),
+	// in which case its contents are buffered in stdinData on first use.
+	stdin     io.Reader
+	stdinOnce sync.Once
+	stdinData []byte
+	stdinErr  error
+
+	// resolvedPath and modTime describe the file most-recently opened by
+	// Value.
+	statMu       sync.Mutex
+	resolvedPath string
+	modTime      time.Time
 }
 
 var _ dials.Source = (*Source)(nil)
+var _ dials.NamedSource = (*Source)(nil)
 
 func (s *Source) initKey() error {
 	s.hmacMu.Lock()
@@ -107,18 +161,120 @@ func (d *DecoderErr) Unwrap() error {
 	return d.Err
 }
 
+// getDecoder returns the decoder to use for the current contents of the
+// file.
+func (s *Source) getDecoder() (dials.Decoder, error) {
+	if s.decoderFactory == nil {
+		return s.decoder, nil
+	}
+	// fall back to the unresolved path if resolution fails; if the file
+	// doesn't exist, opening it will fail shortly anyway.
+	resolvedPath := s.path
+	if s.stdin == nil {
+		if rp, symlinkErr := filepath.EvalSymlinks(s.path); symlinkErr == nil {
+			resolvedPath = rp
+		}
+	}
+	dec := s.decoderFactory(resolvedPath)
+	if dec == nil {
+		return nil, fmt.Errorf("DecoderFactory provided a nil decoder for path %q (resolved to %q)",
+			s.path, resolvedPath)
+	}
+	return dec, nil
+}
+
+// SourceName implements dials.NamedSource, returning common.FileSourceName.
+func (s *Source) SourceName() string {
+	return common.FileSourceName
+}
+
+// open returns a reader for the file's current contents.
+func (s *Source) open() (io.ReadCloser, error) {
+	if s.stdin == nil {
+		return os.Open(s.path)
+	}
+	s.stdinOnce.Do(func() {
+		s.stdinData, s.stdinErr = io.ReadAll(s.stdin)
+	})
+	if s.stdinErr != nil {
+		return nil, fmt.Errorf("failed to read stdin: %w", s.stdinErr)
+	}
+	return io.NopCloser(bytes.NewReader(s.stdinData)), nil
+}
+
+// recordStat updates the values returned by Path and LastModTime after
+// opening f.
+func (s *Source) recordStat(f io.Reader) {
+	osf, ok := f.(*os.File)
+	if !ok {
+		// stdin; there's nothing useful to record.
+		return
+	}
+	resolvedPath, symlinkErr := filepath.EvalSymlinks(s.path)
+	if symlinkErr != nil {
+		resolvedPath = s.path
+	}
+	fi, statErr := osf.Stat()
+	s.statMu.Lock()
+	defer s.statMu.Unlock()
+	s.resolvedPath = resolvedPath
+	if statErr == nil {
+		s.modTime = fi.ModTime()
+	}
+}
+
+// Path returns the symlink-resolved absolute path of the file most recently
+// read by Value (or the absolute path that was passed to the constructor, if
+// it has not been read yet). It returns StdinPath for a Source reading from
+// stdin.
+func (s *Source) Path() string {
+	s.statMu.Lock()
+	defer s.statMu.Unlock()
+	if s.resolvedPath == "" {
+		return s.path
+	}
+	return s.resolvedPath
+}
+
+// LastModTime returns the modification time of the file most recently read
+// by Value. It returns the zero time.Time if the file has not been read yet,
+// or if this Source reads from stdin.
+func (s *Source) LastModTime() time.Time {
+	s.statMu.Lock()
+	defer s.statMu.Unlock()
+	return s.modTime
+}
+
 // Value opens the file and passes it to the Decoder.
-func (s *Source) Value(_ context.Context, t *dials.Type) (reflect.Value, error) {
-	f, openErr := os.Open(s.path)
+func (s *Source) Value(ctx context.Context, t *dials.Type) (reflect.Value, error) {
+	v, err := s.value(ctx, t)
+	if _, unchanged := err.(*unchangedCSumErr); unchanged {
+		// An unchanged file is only interesting to the watching loop
+		// (which skips reporting it); anyone else gets the value.
+		return v, nil
+	}
+	return v, err
+}
+
+// value is like Value, but returns an *unchangedCSumErr (along with the
+// decoded value) if the file's contents haven't changed since the last read.
+func (s *Source) value(_ context.Context, t *dials.Type) (reflect.Value, error) {
+	f, openErr := s.open()
 	if openErr != nil {
 		return reflect.Value{}, openErr
 	}
 	defer f.Close()
+	s.recordStat(f)
+
+	decoder, decoderErr := s.getDecoder()
+	if decoderErr != nil {
+		return reflect.Value{}, decoderErr
+	}
 
 	r, csummer := s.hmacReader(f)
-	decoded, decErr := s.decoder.Decode(r, t)
+	decoded, decErr := decoder.Decode(r, t)
 	if decErr != nil {
-		return decoded, &DecoderErr{Err: decErr, Path: s.path, Decoder: s.decoder}
+		return decoded, &DecoderErr{Err: decErr, Path: s.path, Decoder: decoder}
 	}
 	csum := csummer.Sum(nil)
 	if s.lastHMACNew(csum) {
@@ -127,13 +283,29 @@ func (s *Source) Value(_ context.Context, t *dials.Type) (reflect.Value, error)
 	return decoded, nil
 }
 
+var errWatchStdin = errors.New("cannot watch stdin (path \"-\"); use NewSource for a non-watching source instead")
+
 // WatchOpts contains options, which can be mutated by a WatchOpt
 type WatchOpts struct {
-	logger       StdLogger
-	pollInterval time.Duration
-	sigCh        chan os.Signal
+	logger           StdLogger
+	pollInterval     time.Duration
+	sigCh            chan os.Signal
+	debounceInterval time.Duration
+	rewatchTimeout   time.Duration
 }
 
+// DefaultDebounceInterval is the default DebounceInterval for
+// WatchingSources constructed with NewWatchingSource and
+// NewWatchingSourceWithDecoderFactory.
+const DefaultDebounceInterval = 100 * time.Millisecond
+
+// DefaultRewatchTimeout is the default RewatchTimeout for WatchingSources.
+const DefaultRewatchTimeout = 10 * time.Second
+
+// rewatchRetryInterval is the interval between attempts to re-establish a
+// broken watch.
+const rewatchRetryInterval = 250 * time.Millisecond
+
 // WatchOpt functions mutate the state of a WatchOpts, providing optional
 // arguments to NewWatchingSource
 type WatchOpt func(*WatchOpts)
@@ -153,6 +325,24 @@ func WithPollInterval(pollInterval time.Duration) WatchOpt {
 	}
 }
 
+// WithDebounceInterval overrides the window over which the new WatchingSource
+// coalesces filesystem events before rereading the file. A non-positive
+// interval disables debouncing.
+func WithDebounceInterval(interval time.Duration) WatchOpt {
+	return func(o *WatchOpts) {
+		o.debounceInterval = interval
+	}
+}
+
+// WithRewatchTimeout overrides how long the new WatchingSource may fail to
+// re-establish its watch on a deleted or replaced file before reporting an
+// error.
+func WithRewatchTimeout(timeout time.Duration) WatchOpt {
+	return func(o *WatchOpts) {
+		o.rewatchTimeout = timeout
+	}
+}
+
 // WithSignalChannel configures the new WatchingSource to use the provided
 // channel as a manual trigger for rereading the config file (useful with SIGHUP).
 func WithSignalChannel(sigCh chan os.Signal) WatchOpt {
@@ -163,18 +353,53 @@ func WithSignalChannel(sigCh chan os.Signal) WatchOpt {
 
 // NewWatchingSource creates a new file watching source that will reload and
 // notify if the file is updated.
+// StdinPath is not supported, as stdin cannot be re-read.
 func NewWatchingSource(
 	path string,
 	decoder dials.Decoder,
 	opts ...WatchOpt,
 ) (*WatchingSource, error) {
+	if path == StdinPath {
+		return nil, errWatchStdin
+	}
 	absPath, err := filepath.Abs(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to convert path (%q) to an absolute path: %s",
 			path, err)
 	}
 
-	o := WatchOpts{}
+	return newWatchingSource(absPath, decoder, nil, opts), nil
+}
+
+// NewWatchingSourceWithDecoderFactory creates a new file watching source that
+// will reload and notify if the file is updated.
+// Rather than using a fixed decoder, it calls df with the symlink-resolved
+// path on every reload, so if the target of a symlink changes to a file with
+// a different format (e.g. from config.json to config.yaml) the appropriate
+// decoder is used.
+// StdinPath is not supported, as stdin cannot be re-read.
+func NewWatchingSourceWithDecoderFactory(
+	path string,
+	df DecoderFactory,
+	opts ...WatchOpt,
+) (*WatchingSource, error) {
+	if df == nil {
+		return nil, fmt.Errorf("nil DecoderFactory for path %q", path)
+	}
+	if path == StdinPath {
+		return nil, errWatchStdin
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert path (%q) to an absolute path: %s",
+			path, err)
+	}
+
+	return newWatchingSource(absPath, nil, df, opts), nil
+}
+
+func newWatchingSource(absPath string, decoder dials.Decoder, df DecoderFactory, opts []WatchOpt) *WatchingSource {
+	o := WatchOpts{debounceInterval: DefaultDebounceInterval}
 
 	for _, opt := range opts {
 		opt(&o)
@@ -182,13 +407,16 @@ func NewWatchingSource(
 
 	return &WatchingSource{
 		Source: Source{
-			path:    absPath,
-			decoder: decoder,
+			path:           absPath,
+			decoder:        decoder,
+			decoderFactory: df,
 		},
-		PollInterval: o.pollInterval,
-		Reload:       o.sigCh,
-		logger:       logWrapper{log: o.logger},
-	}, nil
+		PollInterval:     o.pollInterval,
+		DebounceInterval: o.debounceInterval,
+		RewatchTimeout:   o.rewatchTimeout,
+		Reload:           o.sigCh,
+		logger:           logWrapper{log: o.logger},
+	}
 }
 
 // WatchingSource uses fsnotify (inotify, dtrace, etc) to watch for changes to a file
@@ -198,13 +426,25 @@ type WatchingSource struct {
 	Source
 	Reload       chan os.Signal
 	PollInterval time.Duration
-	WG           sync.WaitGroup
-	watcher      *fsnotify.Watcher
-	logger       logWrapper
+	// DebounceInterval, if positive, is the window over which filesystem
+	// events are coalesced before the file is reread. (so an editor's
+	// write + rename only triggers one reload)
+	// NewWatchingSource defaults this to DefaultDebounceInterval.
+	DebounceInterval time.Duration
+	// RewatchTimeout bounds how long the watch on the file may remain
+	// broken (e.g. because the file was deleted, or replaced by a
+	// symlink-swap) before an error is reported via ReportError. Watches
+	// are retried regardless. A non-positive value is treated as
+	// DefaultRewatchTimeout.
+	RewatchTimeout time.Duration
+	WG             sync.WaitGroup
+	watcher        *fsnotify.Watcher
+	logger         logWrapper
 }
 
 var _ dials.Source = (*WatchingSource)(nil)
 var _ dials.Watcher = (*WatchingSource)(nil)
+var _ dials.NamedSource = (*WatchingSource)(nil)
 
 // Watch Sets up an fsnotify Watcher and starts a background goroutine for watching changes.
 func (ws *WatchingSource) Watch(
@@ -286,7 +526,19 @@ func (ws *WatchingSource) watchLoop(
 		defer ticker.Stop()
 	}
 
+	var debounceTimer *time.Timer
+	var debounceChan <-chan time.Time
+	defer func() {
+		if debounceTimer != nil {
+			debounceTimer.Stop()
+		}
+	}()
+
+	rw := rewatcher{timeout: ws.RewatchTimeout}
+	defer rw.stop()
+
 	watchingFile := true
+	fileWatchStale := false
 	eventNumber := 0
 	cleanedPathDir := filepath.Dir(cleanedPath)
 	cleanedPathDirPlusDir := filepath.Join(cleanedPathDir, k8sIntermediateSymlinkDir)
@@ -309,6 +561,35 @@ MAINLOOP:
 			default:
 				continue MAINLOOP
 			}
+			if ev.Op&(fsnotify.Remove|fsnotify.Rename) != 0 &&
+				(ev.Name == cleanedPath || ev.Name == resolvedCfgPath) {
+				// The inode we were watching is gone (inotify
+				// follows symlinks), so the watch on the file
+				// needs to be re-established.
+				fileWatchStale = true
+			}
+			if ws.DebounceInterval > 0 {
+				// Coalesce this event with any others that
+				// arrive before the debounce window closes.
+				// The window isn't extended by later events, so
+				// a steady trickle of writes can't starve us.
+				if debounceChan == nil {
+					if debounceTimer == nil {
+						debounceTimer = time.NewTimer(ws.DebounceInterval)
+					} else {
+						// the timer has already fired and been
+						// drained, so it's safe to Reset.
+						debounceTimer.Reset(ws.DebounceInterval)
+					}
+					debounceChan = debounceTimer.C
+				}
+				continue MAINLOOP
+			}
+		case <-debounceChan:
+			debounceChan = nil
+		case <-rw.c:
+			// retry re-establishing the watch (and reread the
+			// file in case we missed something in the interim)
 		case _, ok := <-ws.watcher.Errors:
 			if !ok {
 				return
@@ -319,7 +600,7 @@ MAINLOOP:
 			return
 		}
 
-		newVal, parseErr := ws.Value(ctx, t)
+		newVal, parseErr := ws.value(ctx, t)
 
 		configExists := !os.IsNotExist(parseErr)
 		if !configExists {
@@ -332,26 +613,48 @@ MAINLOOP:
 				}
 				watchingFile = false
 			}
-
-			// TODO: if the parent directory no longer exists,
-			// setup a watch for the first parent that does, so we
-			// can re-set the appropriate watches as it gets
-			// recreated if necessary.
-
-			// the config doesn't exist; just resume the loop.
+			fileWatchStale = false
+
+			// The config doesn't exist; poll until it reappears,
+			// (in case the parent directory was removed as well,
+			// taking its watch along with it) and resume the loop.
+			rw.start()
+			if rw.expired() {
+				args.ReportError(ctx, fmt.Errorf(
+					"config file %q still missing after %s; unable to re-establish watch",
+					cleanedPath, rw.timeout))
+			}
 			continue
 		}
 		oldResolvedCfgDir := filepath.Dir(resolvedCfgPath)
 		// If the config exists, update the new symlink-path
 		if newResolvedPath, symlinkErr := filepath.EvalSymlinks(cleanedPath); symlinkErr == nil {
+			if newResolvedPath != resolvedCfgPath {
+				// the symlink has been swapped to a new target
+				fileWatchStale = true
+			}
 			resolvedCfgPath = newResolvedPath
 		}
+		if watchingFile && fileWatchStale {
+			// Drop the old watch (which may already be gone)
+			// so we can set up a new one on the current target.
+			ws.watcher.Remove(cleanedPath)
+			watchingFile = false
+		}
+		fileWatchStale = false
 		if !watchingFile {
-			if addErr := ws.watcher.Add(cleanedPath); addErr != nil {
+			if addErr := ws.addWatches(cleanedPath, cleanedPathDir); addErr != nil {
 				ws.logger.Printf("failed to add watcher for path %q: %s",
 					cleanedPath, addErr)
+				rw.start()
+				if rw.expired() {
+					args.ReportError(ctx, fmt.Errorf(
+						"unable to re-establish watch on config file %q after %s: %w",
+						cleanedPath, rw.timeout, addErr))
+				}
 			} else {
 				watchingFile = true
+				rw.stop()
 			}
 		}
 		ws.updateDirWatches(oldResolvedCfgDir, filepath.Dir(resolvedCfgPath))
@@ -375,6 +678,62 @@ MAINLOOP:
 
 }
 
+// addWatches (re-)adds watches on the config file and its parent directory.
+// The parent directory's watch is implicitly removed if the directory is
+// removed, so it may need to be re-established along with the file's.
+func (ws *WatchingSource) addWatches(cleanedPath, cleanedPathDir string) error {
+	if addErr := ws.watcher.Add(cleanedPathDir); addErr != nil {
+		return addErr
+	}
+	return ws.watcher.Add(cleanedPath)
+}
+
+// rewatcher tracks retries of a broken watch within watchLoop.
+type rewatcher struct {
+	timeout time.Duration
+	ticker  *time.Ticker
+	// c is non-nil while retrying
+	c        <-chan time.Time
+	deadline time.Time
+	reported bool
+}
+
+// start begins retrying (if not already doing so)
+func (r *rewatcher) start() {
+	if r.c != nil {
+		return
+	}
+	if r.timeout <= 0 {
+		r.timeout = DefaultRewatchTimeout
+	}
+	if r.ticker == nil {
+		r.ticker = time.NewTicker(rewatchRetryInterval)
+	} else {
+		r.ticker.Reset(rewatchRetryInterval)
+	}
+	r.c = r.ticker.C
+	r.deadline = time.Now().Add(r.timeout)
+	r.reported = false
+}
+
+// stop ends any ongoing retries.
+func (r *rewatcher) stop() {
+	if r.ticker != nil {
+		r.ticker.Stop()
+	}
+	r.c = nil
+}
+
+// expired returns true (once per retry-sequence) if the deadline for
+// re-establishing the watch has passed.
+func (r *rewatcher) expired() bool {
+	if r.c == nil || r.reported || time.Now().Before(r.deadline) {
+		return false
+	}
+	r.reported = true
+	return true
+}
+
 func (ws *WatchingSource) updateDirWatches(oldResolvedCfgDir, resolvedCfgDir string) {
 	if oldResolvedCfgDir == resolvedCfgDir {
 		return
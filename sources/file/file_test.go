@@ -6,7 +6,9 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"reflect"
 	"runtime"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -16,6 +18,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/vimeo/dials"
 	"github.com/vimeo/dials/decoders/json"
+	"github.com/vimeo/dials/decoders/yaml"
 )
 
 type testStdLogger struct {
@@ -156,6 +159,125 @@ func TestWatchingFileWithRelativePathAndChdir(t *testing.T) {
 	assert.Equal(t, 4, c.NumBeatles)
 }
 
+func TestWatchingFileDebounce(t *testing.T) {
+	t.Parallel()
+
+	dir := tmpDir(t)
+	defer os.RemoveAll(dir)
+
+	cfgPath := writeTestConfig(t, dir, `{"secretOfLife": 42, "numBeatles": 4}`)
+	defer os.Remove(cfgPath)
+
+	const debounce = 250 * time.Millisecond
+	watchingFile, watchingErr := NewWatchingSource(cfgPath, &json.Decoder{},
+		WithLogger(&testStdLogger{t}), WithDebounceInterval(debounce))
+	require.NoError(t, watchingErr, "construction failure")
+	assert.Equal(t, debounce, watchingFile.DebounceInterval)
+	defer watchingFile.WG.Wait()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d, err := dials.Config(ctx, &config{}, watchingFile)
+	require.NoError(t, err)
+
+	var versions int32
+	_, serial := d.ViewVersion()
+	d.RegisterCallback(ctx, serial, func(ctx context.Context, oldCfg, newCfg *config) {
+		atomic.AddInt32(&versions, 1)
+	})
+
+	// a burst of valid writes in quick succession should only be read
+	// once the window closes, by which point it holds the last value.
+	for _, secret := range []int{1, 2, 3} {
+		require.NoError(t, os.WriteFile(cfgPath,
+			[]byte(fmt.Sprintf(`{"secretOfLife": %d, "numBeatles": 4}`, secret)), 0640))
+	}
+	select {
+	case c := <-d.Events():
+		assert.Equal(t, 3, c.SecretOfLife)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for new config")
+	}
+	time.Sleep(2 * debounce)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&versions))
+
+	// a genuine second edit still triggers another reload
+	require.NoError(t, os.WriteFile(cfgPath, []byte(`{"secretOfLife": 47, "numBeatles": 4}`), 0640))
+	select {
+	case c := <-d.Events():
+		assert.Equal(t, 47, c.SecretOfLife)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for new config")
+	}
+}
+
+func TestWatchingFileDeletedAndRecreated(t *testing.T) {
+	t.Parallel()
+
+	dir := tmpDir(t)
+	defer os.RemoveAll(dir)
+
+	// put the config in a subdirectory, so we can remove that as well
+	cfgDir := filepath.Join(dir, "conf")
+	require.NoError(t, os.Mkdir(cfgDir, 0750))
+	cfgPath := filepath.Join(cfgDir, "config.json")
+	require.NoError(t, os.WriteFile(cfgPath, []byte(`{"secretOfLife": 42, "numBeatles": 4}`), 0640))
+
+	const rewatchTimeout = 500 * time.Millisecond
+	watchingFile, watchingErr := NewWatchingSource(cfgPath, &json.Decoder{},
+		WithLogger(&testStdLogger{t}), WithRewatchTimeout(rewatchTimeout))
+	require.NoError(t, watchingErr, "construction failure")
+	defer watchingFile.WG.Wait()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	d, err := dials.Params[config]{
+		OnWatchedError: func(ctx context.Context, err error, oldConfig, newConfig *config) {
+			select {
+			case errCh <- err:
+			default:
+			}
+		},
+	}.Config(ctx, &config{}, watchingFile)
+	require.NoError(t, err)
+
+	// remove the file and its directory, taking both watches with them.
+	start := time.Now()
+	require.NoError(t, os.RemoveAll(cfgDir))
+
+	// the error is only reported once the rewatch timeout elapses
+	select {
+	case watchErr := <-errCh:
+		assert.GreaterOrEqual(t, time.Since(start), rewatchTimeout)
+		assert.Contains(t, watchErr.Error(), "still missing")
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for error")
+	}
+
+	// recreate the directory and file; the retries should notice it.
+	require.NoError(t, os.Mkdir(cfgDir, 0750))
+	require.NoError(t, os.WriteFile(cfgPath, []byte(`{"secretOfLife": 47, "numBeatles": 4}`), 0640))
+	select {
+	case c := <-d.Events():
+		assert.Equal(t, 47, c.SecretOfLife)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for new config")
+	}
+
+	// the watches have been re-established, so an in-place edit is
+	// picked up as well
+	require.NoError(t, os.WriteFile(cfgPath, []byte(`{"secretOfLife": 11, "numBeatles": 4}`), 0640))
+	select {
+	case c := <-d.Events():
+		assert.Equal(t, 11, c.SecretOfLife)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for new config")
+	}
+}
+
 func TestWatchingFileWithRemove(t *testing.T) {
 	t.Parallel()
 
@@ -391,6 +513,186 @@ func TestWatchingFileWithK8SEmulatedAtomicWriter(t *testing.T) {
 	assert.Equal(t, 4, c.NumBeatles)
 }
 
+func TestWatchingFileDecoderFactoryExtensionChange(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks are more complicated on windows skipping for now.")
+	}
+	t.Parallel()
+
+	dir := tmpDir(t)
+	defer os.RemoveAll(dir)
+
+	jsonPath := filepath.Join(dir, "config.json")
+	require.NoError(t, ioutil.WriteFile(jsonPath, []byte(`{
+        "secretOfLife": 42,
+        "numBeatles": 4
+    }`), 0600))
+	yamlPath := filepath.Join(dir, "config.yaml")
+	require.NoError(t, ioutil.WriteFile(yamlPath, []byte("secretoflife: 47\nnumbeatles: 5\n"), 0600))
+
+	configPath := filepath.Join(dir, "config")
+	require.NoError(t, os.Symlink(jsonPath, configPath))
+
+	df := func(path string) dials.Decoder {
+		switch filepath.Ext(path) {
+		case ".json":
+			return &json.Decoder{}
+		case ".yaml":
+			return &yaml.Decoder{}
+		default:
+			return nil
+		}
+	}
+
+	watchingFile, watchingErr := NewWatchingSourceWithDecoderFactory(configPath, df, WithLogger(&testStdLogger{t}))
+	require.NoError(t, watchingErr, "construction failure")
+	defer watchingFile.WG.Wait()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	d, err := dials.Config(ctx, &config{}, watchingFile)
+	require.NoError(t, err)
+
+	c := d.View()
+	assert.Equal(t, 42, c.SecretOfLife)
+	assert.Equal(t, 4, c.NumBeatles)
+
+	// atomically swap the symlink over to point at the YAML file.
+	tmpLink := filepath.Join(dir, "config.tmp")
+	require.NoError(t, os.Symlink(yamlPath, tmpLink))
+	require.NoError(t, os.Rename(tmpLink, configPath))
+
+	select {
+	case c = <-d.Events():
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for new config")
+	}
+	assert.Equal(t, 47, c.SecretOfLife)
+	assert.Equal(t, 5, c.NumBeatles)
+}
+
+func TestNewSourceWithDecoderFactoryNilDecoder(t *testing.T) {
+	t.Parallel()
+
+	dir := tmpDir(t)
+	defer os.RemoveAll(dir)
+
+	cfgPath := writeTestConfig(t, dir, `{"secretOfLife": 42}`)
+
+	src, srcErr := NewSourceWithDecoderFactory(cfgPath, func(string) dials.Decoder { return nil })
+	require.NoError(t, srcErr)
+
+	_, err := dials.Config(context.Background(), &config{}, src)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "nil decoder")
+}
+
+func TestSourcePathAndModTime(t *testing.T) {
+	t.Parallel()
+
+	dir := tmpDir(t)
+	defer os.RemoveAll(dir)
+
+	firstConfig := writeTestConfig(t, dir, `{"secretOfLife": 42}`)
+	secondConfig := writeTestConfig(t, dir, `{"secretOfLife": 47}`)
+	firstMTime := time.Date(2020, time.January, 2, 3, 4, 5, 0, time.UTC)
+	secondMTime := firstMTime.Add(time.Hour)
+	require.NoError(t, os.Chtimes(firstConfig, firstMTime, firstMTime))
+	require.NoError(t, os.Chtimes(secondConfig, secondMTime, secondMTime))
+
+	link := filepath.Join(dir, "config.json")
+	require.NoError(t, os.Symlink(firstConfig, link))
+
+	src, srcErr := NewSource(link, &json.Decoder{})
+	require.NoError(t, srcErr)
+	// nothing's been read yet
+	assert.Equal(t, link, src.Path())
+	assert.True(t, src.LastModTime().IsZero())
+
+	_, err := dials.Config(context.Background(), &config{}, src)
+	require.NoError(t, err)
+
+	resolvedFirst, evalErr := filepath.EvalSymlinks(firstConfig)
+	require.NoError(t, evalErr)
+	assert.Equal(t, resolvedFirst, src.Path())
+	assert.True(t, firstMTime.Equal(src.LastModTime()), "unexpected mtime %s", src.LastModTime())
+
+	// swap the symlink over to the second file
+	tmpLink := filepath.Join(dir, "config.tmp")
+	require.NoError(t, os.Symlink(secondConfig, tmpLink))
+	require.NoError(t, os.Rename(tmpLink, link))
+
+	_, valErr := src.Value(context.Background(), dials.NewType(reflect.TypeOf(struct {
+		SecretOfLife *int
+		NumBeatles   *int
+	}{})))
+	require.NoError(t, valErr)
+
+	resolvedSecond, evalErr := filepath.EvalSymlinks(secondConfig)
+	require.NoError(t, evalErr)
+	assert.Equal(t, resolvedSecond, src.Path())
+	assert.True(t, secondMTime.Equal(src.LastModTime()), "unexpected mtime %s", src.LastModTime())
+}
+
+func TestSourceReload(t *testing.T) {
+	t.Parallel()
+
+	dir := tmpDir(t)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config.json")
+	require.NoError(t, ioutil.WriteFile(path, []byte(`{"secretOfLife": 42}`), 0o600))
+
+	src, srcErr := NewSource(path, &json.Decoder{})
+	require.NoError(t, srcErr)
+
+	d, err := dials.Config(context.Background(), &config{NumBeatles: 4}, src)
+	require.NoError(t, err)
+	assert.Equal(t, &config{SecretOfLife: 42, NumBeatles: 4}, d.View())
+
+	// reloading an unchanged file keeps the current configuration
+	reloaded, reloadErr := d.Reload(context.Background())
+	require.NoError(t, reloadErr)
+	assert.Same(t, d.View(), reloaded)
+	assert.Equal(t, &config{SecretOfLife: 42, NumBeatles: 4}, reloaded)
+
+	require.NoError(t, ioutil.WriteFile(path, []byte(`{"secretOfLife": 47}`), 0o600))
+	reloaded, reloadErr = d.Reload(context.Background())
+	require.NoError(t, reloadErr)
+	assert.Equal(t, &config{SecretOfLife: 47, NumBeatles: 4}, reloaded)
+	assert.Equal(t, reloaded, d.View())
+}
+
+func TestStdinSource(t *testing.T) {
+	t.Parallel()
+
+	src, srcErr := NewSource(StdinPath, &json.Decoder{})
+	require.NoError(t, srcErr)
+	assert.Equal(t, os.Stdin, src.stdin)
+	assert.Equal(t, StdinPath, src.Path())
+
+	// swap in a reader we control, rather than mucking with os.Stdin
+	src.stdin = strings.NewReader(`{"secretOfLife": 42}`)
+
+	d, err := dials.Config(context.Background(), &config{NumBeatles: 4}, src)
+	require.NoError(t, err)
+	assert.Equal(t, &config{SecretOfLife: 42, NumBeatles: 4}, d.View())
+
+	// stdin is only read once; later calls decode the buffered contents
+	// (reporting them as unchanged)
+	_, valErr := src.value(context.Background(), dials.NewType(reflect.TypeOf(struct {
+		SecretOfLife *int
+		NumBeatles   *int
+	}{})))
+	var csumErr *unchangedCSumErr
+	assert.ErrorAs(t, valErr, &csumErr)
+
+	_, watchErr := NewWatchingSource(StdinPath, &json.Decoder{})
+	assert.ErrorIs(t, watchErr, errWatchStdin)
+	_, watchDFErr := NewWatchingSourceWithDecoderFactory(StdinPath, func(string) dials.Decoder { return &json.Decoder{} })
+	assert.ErrorIs(t, watchDFErr, errWatchStdin)
+}
+
 const watchingFilePattern = "watching-file"
 
 func writeTestConfig(t testing.TB, dir, data string) string {
@@ -401,3 +703,53 @@ func writeTestConfig(t testing.TB, dir, data string) string {
 	f.WriteString(data)
 	return f.Name()
 }
+
+func TestInterfaceField(t *testing.T) {
+	t.Parallel()
+
+	type rawConfig struct {
+		Name string      `dials:"name"`
+		Raw  interface{} `dials:"raw"`
+	}
+
+	dir := tmpDir(t)
+	defer os.RemoveAll(dir)
+
+	rawPath := filepath.Join(dir, "raw.json")
+	require.NoError(t, os.WriteFile(rawPath, []byte(`{"name": "fim", "raw": {"a": [1, 2]}}`), 0o600))
+	namePath := filepath.Join(dir, "name.yaml")
+	require.NoError(t, os.WriteFile(namePath, []byte("name: bat\n"), 0o600))
+	scalarPath := filepath.Join(dir, "scalar.yaml")
+	require.NoError(t, os.WriteFile(scalarPath, []byte("raw: plain\n"), 0o600))
+
+	// sources can't be reused across calls to Config (they only return
+	// a value when the file's changed), so construct new ones each time
+	src := func(path string, dec dials.Decoder) dials.Source {
+		s, srcErr := NewSource(path, dec)
+		require.NoError(t, srcErr)
+		return s
+	}
+
+	// a later source leaving the field nil doesn't clobber it
+	d, err := dials.Config(context.Background(), &rawConfig{},
+		src(rawPath, &json.Decoder{}), src(namePath, &yaml.Decoder{}))
+	require.NoError(t, err)
+	assert.Equal(t, &rawConfig{
+		Name: "bat",
+		Raw:  map[string]interface{}{"a": []interface{}{1.0, 2.0}},
+	}, d.View())
+
+	// later non-nil values replace earlier ones, regardless of type
+	d, err = dials.Config(context.Background(), &rawConfig{},
+		src(rawPath, &json.Decoder{}), src(scalarPath, &yaml.Decoder{}))
+	require.NoError(t, err)
+	assert.Equal(t, &rawConfig{Name: "fim", Raw: "plain"}, d.View())
+
+	d, err = dials.Config(context.Background(), &rawConfig{},
+		src(scalarPath, &yaml.Decoder{}), src(rawPath, &json.Decoder{}), src(namePath, &yaml.Decoder{}))
+	require.NoError(t, err)
+	assert.Equal(t, &rawConfig{
+		Name: "bat",
+		Raw:  map[string]interface{}{"a": []interface{}{1.0, 2.0}},
+	}, d.View())
+}
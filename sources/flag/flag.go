@@ -7,10 +7,13 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/vimeo/dials"
 	"github.com/vimeo/dials/common"
+	"github.com/vimeo/dials/parse"
 	"github.com/vimeo/dials/ptrify"
 	"github.com/vimeo/dials/sources/flag/flaghelper"
 	"github.com/vimeo/dials/tagformat/caseconversion"
@@ -63,6 +66,12 @@ var (
 	uint64SliceType  = reflect.SliceOf(uint64Type)
 	uintptrSliceType = reflect.SliceOf(uintptrType)
 
+	float32SliceType = reflect.SliceOf(float32Type)
+	float64SliceType = reflect.SliceOf(float64Type)
+
+	boolSliceType     = reflect.SliceOf(boolType)
+	durationSliceType = reflect.SliceOf(timeDuration)
+
 	// Verify that Set implements the dials.Source interface
 	_ dials.Source = (*Set)(nil)
 )
@@ -73,6 +82,75 @@ type NameConfig struct {
 	FieldNameEncodeCasing caseconversion.EncodeCasingFunc
 	// TagEncodeCasing is for the tag names used by the flatten mangler
 	TagEncodeCasing caseconversion.EncodeCasingFunc
+	// Separator, if non-empty, separates the components of flag-names for
+	// nested fields (e.g. "." for flags like "--db.host"). TagEncodeCasing
+	// is then applied to each component individually, so the separator is
+	// independent of the casing within each component. If empty, the
+	// separator is whatever TagEncodeCasing uses between words (e.g. "-"
+	// for kebab-case).
+	Separator string
+
+	// OnUnhandledType, if non-nil, is called with the flag-name and type of
+	// each field that can't be registered as a flag (and would otherwise be
+	// silently skipped).
+	OnUnhandledType func(flagName string, t reflect.Type)
+	// ErrorOnUnhandledTypes makes flag registration fail with an error
+	// listing all fields that can't be registered as flags.
+	ErrorOnUnhandledTypes bool
+
+	// Delimiters overrides the separators used when parsing string-slice,
+	// string-set and string-map flags. Unset delimiters retain their
+	// defaults (see parse.DefaultDelimiters).
+	Delimiters parse.Delimiters
+
+	// EnvFallbackName, if non-nil, derives the name of an environment
+	// variable from each flag's name (see EnvNameFromFlag). After the
+	// command-line is parsed, any flags that weren't set on the
+	// command-line are set from the corresponding environment variable
+	// (if it's set), so command-line flags take precedence. The variable
+	// name is included in each flag's usage text. Returning an empty
+	// string skips the fallback for that flag.
+	//
+	// The environment is consulted once, after the first parse.
+	EnvFallbackName func(flagName string) string
+
+	// ResponseFilePrefix, if non-empty, enables "response files": before
+	// parsing, each argument beginning with the prefix (conventionally
+	// "@", as in "@flags.txt") is replaced by the arguments read from the
+	// named file, which may contain quoting and comments (see
+	// flaghelper.ExpandResponseFiles). It applies to the arguments parsed
+	// by the Set's default ParseFunc (the args passed to NewSetWithArgs,
+	// or the process's arguments), not to a custom ParseFunc.
+	ResponseFilePrefix string
+
+	// ValueFilePrefix, if non-empty, lets the value of any flag be read
+	// from a file (e.g. to keep secrets out of the output of ps): a value
+	// beginning with the prefix (conventionally "@", as in
+	// "-password=@/run/secrets/password" or "-password @secrets.txt") is
+	// replaced by the contents of the named file, less a single trailing
+	// newline. A value beginning with the prefix twice (e.g. "@@admin") is
+	// used with one prefix removed, for values that legitimately begin
+	// with the prefix. Like ResponseFilePrefix, it only applies to the
+	// arguments parsed by the Set's default ParseFunc. If both use the
+	// same prefix, response files are expanded first, so the
+	// "-name=@path" form must be used for value files.
+	ValueFilePrefix string
+}
+
+// EnvNameFromFlag returns a function suitable for
+// NameConfig.EnvFallbackName, which converts flag-names to
+// SCREAMING_SNAKE_CASE (replacing hyphens and dots with underscores) with
+// prefix (if non-empty) and an underscore prepended. e.g. with prefix
+// "MYAPP", "--db-port" falls back to MYAPP_DB_PORT.
+func EnvNameFromFlag(prefix string) func(flagName string) string {
+	r := strings.NewReplacer("-", "_", ".", "_")
+	return func(flagName string) string {
+		name := strings.ToUpper(r.Replace(flagName))
+		if prefix == "" {
+			return name
+		}
+		return prefix + "_" + name
+	}
 }
 
 // TODO(@sachi): update FieldNameEncodeCasing to EncodeGoCamelCase once it exists
@@ -114,12 +192,13 @@ func NewCmdLineSet(cfg *NameConfig, template interface{}) (*Set, error) {
 
 	s := Set{
 		Flags:           flag.CommandLine,
-		ParseFunc:       func() error { flag.Parse(); return nil },
 		ptrType:         ptyp,
 		flagsRegistered: true,
 		NameCfg:         cfg,
 		flagFieldName:   map[string]string{},
 	}
+	// equivalent to flag.Parse(), with response files expanded
+	s.ParseFunc = func() error { return s.parseArgs(os.Args[1:]) }
 
 	if err := s.registerFlags(pval, ptyp); err != nil {
 		return nil, err
@@ -139,12 +218,12 @@ func NewSetWithArgs(cfg *NameConfig, template interface{}, args []string) (*Set,
 
 	s := Set{
 		Flags:           fs,
-		ParseFunc:       func() error { return fs.Parse(args) },
 		ptrType:         ptyp,
 		flagsRegistered: true,
 		NameCfg:         cfg,
 		flagFieldName:   map[string]string{},
 	}
+	s.ParseFunc = func() error { return s.parseArgs(args) }
 
 	if err := s.registerFlags(pval, ptyp); err != nil {
 		return nil, err
@@ -184,10 +263,18 @@ type Set struct {
 	NameCfg *NameConfig
 
 	flagsRegistered bool
-	tfmr            *transform.Transformer
-	trnslVal        reflect.Value
+	// envFallbackApplied is set once flags have been set from their
+	// environment variable fallbacks (see NameConfig.EnvFallbackName)
+	envFallbackApplied bool
+	// regType is the (pointerified) type flags were registered for
+	regType  reflect.Type
+	tfmr     *transform.Transformer
+	trnslVal reflect.Value
 	// Map to store the flag name (key) and field name (value)
 	flagFieldName map[string]string
+	// shorthands maps the shorthand flag names registered for fields with
+	// a dialsflagshort tag to the corresponding full flag names
+	shorthands map[string]string
 }
 
 func (s *Set) parse() error {
@@ -200,14 +287,33 @@ func (s *Set) parse() error {
 	return nil
 }
 
+// parseArgs parses args with the Set's FlagSet, after expanding any response
+// files and value files (see NameConfig.ResponseFilePrefix and
+// NameConfig.ValueFilePrefix).
+func (s *Set) parseArgs(args []string) error {
+	if s.NameCfg != nil {
+		expanded, err := flaghelper.ExpandResponseFiles(args, s.NameCfg.ResponseFilePrefix)
+		if err != nil {
+			return err
+		}
+		args = expanded
+		if args, err = expandValueFiles(s.Flags, args, s.NameCfg.ValueFilePrefix); err != nil {
+			return err
+		}
+	}
+	return s.Flags.Parse(args)
+}
+
 func (s *Set) registerFlags(tmpl reflect.Value, ptyp reflect.Type) error {
-	fm := transform.NewFlattenMangler(common.DialsTagName, s.NameCfg.FieldNameEncodeCasing, s.NameCfg.TagEncodeCasing)
+	fm := transform.NewFlattenManglerWithSeparator(common.DialsTagName,
+		s.NameCfg.FieldNameEncodeCasing, s.NameCfg.TagEncodeCasing, s.NameCfg.Separator)
 	tfmr := transform.NewTransformer(ptyp, transform.NewAliasMangler(common.DialsTagName, common.DialsFlagTagName), fm)
 	val, TrnslErr := tfmr.Translate()
 	if TrnslErr != nil {
 		return TrnslErr
 	}
 
+	s.regType = ptyp
 	s.tfmr = tfmr
 	s.trnslVal = val
 
@@ -219,6 +325,7 @@ func (s *Set) registerFlags(tmpl reflect.Value, ptyp reflect.Type) error {
 		k = t.Kind()
 	}
 
+	unhandled := []string{}
 	// the input kind will be struct after calling Translate on it
 	for i := 0; i < t.NumField(); i++ {
 		sf := t.Field(i)
@@ -228,6 +335,9 @@ func (s *Set) registerFlags(tmpl reflect.Value, ptyp reflect.Type) error {
 		}
 
 		name := s.mkname(sf)
+		if envName := s.envFallbackName(name); envName != "" {
+			help += " (env $" + envName + ")"
+		}
 		s.flagFieldName[name] = sf.Name
 
 		// if the flag already exists, don't register so the user can override
@@ -280,6 +390,9 @@ func (s *Set) registerFlags(tmpl reflect.Value, ptyp reflect.Type) error {
 		case fieldVal.Type() == timeDuration:
 			s.Flags.Duration(name, fieldVal.Interface().(time.Duration), help)
 			continue
+		case isJSONFlagType(ft):
+			s.Flags.Var(flaghelper.NewJSONFlag(fieldVal.Addr().Interface()), name, help)
+			continue
 		default:
 		}
 
@@ -321,13 +434,13 @@ func (s *Set) registerFlags(tmpl reflect.Value, ptyp reflect.Type) error {
 		case reflect.Slice, reflect.Map:
 			switch ft {
 			case stringSlice:
-				s.Flags.Var(flaghelper.NewStringSliceFlag(fieldVal.Addr().Interface().(*[]string)), name, help)
+				s.Flags.Var(flaghelper.NewStringSliceFlagWithDelimiters(fieldVal.Addr().Interface().(*[]string), s.NameCfg.Delimiters), name, help)
 			case mapStringStringSlice:
-				s.Flags.Var(flaghelper.NewMapStringStringSliceFlag(fieldVal.Addr().Interface().(*map[string][]string)), name, help)
+				s.Flags.Var(flaghelper.NewMapStringStringSliceFlagWithDelimiters(fieldVal.Addr().Interface().(*map[string][]string), s.NameCfg.Delimiters), name, help)
 			case mapStringString:
-				s.Flags.Var(flaghelper.NewMapStringStringFlag(fieldVal.Addr().Interface().(*map[string]string)), name, help)
+				s.Flags.Var(flaghelper.NewMapStringStringFlagWithDelimiters(fieldVal.Addr().Interface().(*map[string]string), s.NameCfg.Delimiters), name, help)
 			case stringSet:
-				s.Flags.Var(flaghelper.NewStringSetFlag(fieldVal.Addr().Interface().(*map[string]struct{})), name, help)
+				s.Flags.Var(flaghelper.NewStringSetFlagWithDelimiters(fieldVal.Addr().Interface().(*map[string]struct{}), s.NameCfg.Delimiters), name, help)
 
 			case intSliceType:
 				s.Flags.Var(flaghelper.NewSignedIntegralSlice(fieldVal.Addr().Interface().(*[]int)), name, help)
@@ -353,23 +466,154 @@ func (s *Set) registerFlags(tmpl reflect.Value, ptyp reflect.Type) error {
 			case uintptrSliceType:
 				s.Flags.Var(flaghelper.NewUnsignedIntegralSlice(fieldVal.Addr().Interface().(*[]uintptr)), name, help)
 
+			case float32SliceType:
+				s.Flags.Var(flaghelper.NewFloatSlice(fieldVal.Addr().Interface().(*[]float32)), name, help)
+			case float64SliceType:
+				s.Flags.Var(flaghelper.NewFloatSlice(fieldVal.Addr().Interface().(*[]float64)), name, help)
+
+			case boolSliceType:
+				s.Flags.Var(flaghelper.NewBoolSliceFlag(fieldVal.Addr().Interface().(*[]bool)), name, help)
+			case durationSliceType:
+				s.Flags.Var(flaghelper.NewDurationSliceFlag(fieldVal.Addr().Interface().(*[]time.Duration)), name, help)
+
 			default:
-				// Unhandled type. Just keep going.
+				unhandled = append(unhandled, s.unhandledType(name, ft))
 				continue
 			}
 		default:
-			// Unhandled type. Just keep going.
+			unhandled = append(unhandled, s.unhandledType(name, ft))
+			continue
+		}
+	}
+	if err := s.registerShorthands(t); err != nil {
+		return err
+	}
+	if s.NameCfg.ErrorOnUnhandledTypes && len(unhandled) > 0 {
+		return fmt.Errorf("unable to register flags for fields with unsupported types: %s",
+			strings.Join(unhandled, ", "))
+	}
+	return nil
+}
+
+// registerShorthands registers an additional flag sharing the flag.Value of
+// each field's flag for fields (of the flattened type t) with a
+// dialsflagshort tag, so e.g. both -v and -verbose set the same field.
+//
+// Shorthands that collide with each other, or with the flags of other fields,
+// are errors. As with full flag names, a shorthand that's already registered
+// in the FlagSet by other means is left alone.
+func (s *Set) registerShorthands(t reflect.Type) error {
+	if s.shorthands == nil {
+		s.shorthands = map[string]string{}
+	}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		short, ok := sf.Tag.Lookup(common.DialsFlagShortTag)
+		if !ok || short == "" {
+			continue
+		}
+		name := s.mkname(sf)
+		if dft, ok := sf.Tag.Lookup(common.DialsFlagTagName); ok && dft == "-" {
+			continue
+		}
+		f := s.Flags.Lookup(name)
+		if f == nil {
+			// the field's type couldn't be registered as a flag
+			continue
+		}
+		if utf8.RuneCountInString(short) != 1 {
+			return fmt.Errorf("shorthand %q for flag %q is not a single character", short, name)
+		}
+		if other, dup := s.shorthands[short]; dup {
+			return fmt.Errorf("shorthand %q for flag %q is already the shorthand for flag %q",
+				short, name, other)
+		}
+		if _, isField := s.flagFieldName[short]; isField {
+			return fmt.Errorf("shorthand %q for flag %q collides with the flag for another field",
+				short, name)
+		}
+		if s.Flags.Lookup(short) != nil {
 			continue
 		}
+		s.Flags.Var(f.Value, short, "shorthand for -"+name)
+		s.shorthands[short] = name
 	}
 	return nil
 }
 
+// fullFlagName returns the full flag name for a flag registered as a
+// shorthand, and name unchanged otherwise.
+func (s *Set) fullFlagName(name string) string {
+	if full, ok := s.shorthands[name]; ok {
+		return full
+	}
+	return name
+}
+
+// envFallbackName returns the name of the environment variable to fall back
+// to for the flag named flagName, or an empty string if there isn't one.
+func (s *Set) envFallbackName(flagName string) string {
+	if s.NameCfg == nil || s.NameCfg.EnvFallbackName == nil {
+		return ""
+	}
+	return s.NameCfg.EnvFallbackName(flagName)
+}
+
+// applyEnvFallback sets any flags (that were registered by this Set) that
+// weren't set on the command-line from their fallback environment
+// variables.
+func (s *Set) applyEnvFallback() error {
+	if s.envFallbackApplied || s.NameCfg.EnvFallbackName == nil {
+		return nil
+	}
+	s.envFallbackApplied = true
+
+	setOnCmdLine := map[string]struct{}{}
+	s.Flags.Visit(func(f *flag.Flag) {
+		setOnCmdLine[s.fullFlagName(f.Name)] = struct{}{}
+	})
+	for name := range s.flagFieldName {
+		if _, ok := setOnCmdLine[name]; ok || s.Flags.Lookup(name) == nil {
+			continue
+		}
+		envName := s.envFallbackName(name)
+		if envName == "" {
+			continue
+		}
+		envVal, ok := os.LookupEnv(envName)
+		if !ok {
+			continue
+		}
+		if err := s.Flags.Set(name, envVal); err != nil {
+			return fmt.Errorf("failed to set flag %q from environment variable %s: %w",
+				name, envName, err)
+		}
+	}
+	return nil
+}
+
+// unhandledType reports a field that can't be registered as a flag to the
+// OnUnhandledType hook (if set) and returns a description for use in errors.
+func (s *Set) unhandledType(name string, t reflect.Type) string {
+	if s.NameCfg.OnUnhandledType != nil {
+		s.NameCfg.OnUnhandledType(name, t)
+	}
+	return fmt.Sprintf("%q (%s)", name, t)
+}
+
+// SourceName implements dials.NamedSource, returning common.FlagSourceName.
+func (s *Set) SourceName() string {
+	return common.FlagSourceName
+}
+
 // Value fills in the user-provided config struct using flags. It looks up the
 // flags to bind into a given struct field by using that field's `dialsflag`
 // struct tag if present, then its `dials` tag if present, and finally its name.
 // If the struct has nested fields, Value will flatten the fields so flags can
 // be defined for nested fields.
+// A field's `dialsflagshort` tag registers a single-character shorthand
+// flag, which sets the same field (e.g. `dialsflagshort:"v"` for -v as well
+// as -verbose).
 func (s *Set) Value(_ context.Context, t *dials.Type) (reflect.Value, error) {
 	// Check whether we've gone through the exercise of parsing flags yet
 	// (and types are compatible).
@@ -388,7 +632,7 @@ func (s *Set) Value(_ context.Context, t *dials.Type) (reflect.Value, error) {
 		// TODO: remove this fallback
 		s.Flags = flag.NewFlagSet("", flag.ContinueOnError)
 		if s.ParseFunc == nil {
-			s.ParseFunc = func() error { return s.Flags.Parse(os.Args[1:]) }
+			s.ParseFunc = func() error { return s.parseArgs(os.Args[1:]) }
 		}
 	}
 
@@ -414,13 +658,24 @@ func (s *Set) Value(_ context.Context, t *dials.Type) (reflect.Value, error) {
 			return reflect.Value{}, fmt.Errorf("failed to parse: %s", err)
 		}
 	}
+	if err := s.applyEnvFallback(); err != nil {
+		return reflect.Value{}, err
+	}
 	var setErr error
 	val := reflect.New(t.Type())
+	// a field's flag and its shorthand share a flag.Value, so only handle
+	// the first one set.
+	visited := map[string]struct{}{}
 	s.Flags.Visit(func(f *flag.Flag) {
-		fieldName, ok := s.flagFieldName[f.Name]
+		fullName := s.fullFlagName(f.Name)
+		fieldName, ok := s.flagFieldName[fullName]
 		if !ok {
 			return
 		}
+		if _, dup := visited[fullName]; dup {
+			return
+		}
+		visited[fullName] = struct{}{}
 
 		ffield := s.trnslVal.FieldByName(fieldName)
 		if !ffield.IsNil() {
@@ -471,6 +726,16 @@ func (s *Set) Value(_ context.Context, t *dials.Type) (reflect.Value, error) {
 	return s.tfmr.ReverseTranslate(s.trnslVal)
 }
 
+// isJSONFlagType returns true for struct and slice-of-struct types, which
+// don't have a natural command-line representation and are decoded as JSON
+// instead.
+func isJSONFlagType(t reflect.Type) bool {
+	if t.Kind() == reflect.Slice {
+		t = stripTypePtr(t.Elem())
+	}
+	return t.Kind() == reflect.Struct
+}
+
 func stripTypePtr(t reflect.Type) reflect.Type {
 	switch t.Kind() {
 	case reflect.Ptr:
@@ -4,6 +4,10 @@ import (
 	"bytes"
 	"context"
 	"flag"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 	"time"
 
@@ -11,6 +15,8 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/vimeo/dials"
+	"github.com/vimeo/dials/parse"
+	"github.com/vimeo/dials/sources/static"
 	"github.com/vimeo/dials/tagformat/caseconversion"
 )
 
@@ -284,6 +290,100 @@ func TestTable(t *testing.T) {
 			expected: nil,
 			expErr:   "failed to parse: failed to parse flags: invalid value \"1000000\" for flag -a: failed to parse integer index 0: strconv.ParseInt: parsing \"1000000\": value out of range",
 		},
+		{
+			name: "basic_float32_slice_default",
+			tmplCB: func() (any, func(ctx context.Context, src *Set) (any, error)) {
+				cfg := struct{ A []float32 }{A: []float32{1.5}}
+				return &cfg, testWrapDials(&cfg)
+			},
+			args:     []string{},
+			expected: &struct{ A []float32 }{A: []float32{1.5}},
+		},
+		{
+			name: "basic_float32_slice_set_multiple_flag",
+			tmplCB: func() (any, func(ctx context.Context, src *Set) (any, error)) {
+				cfg := struct{ A []float32 }{A: []float32{1.5}}
+				return &cfg, testWrapDials(&cfg)
+			},
+			args:     []string{"--a=2.5,-3", "--a=4e2"},
+			expected: &struct{ A []float32 }{A: []float32{2.5, -3, 400}},
+		},
+		{
+			name: "basic_float32_slice_set_overflow",
+			tmplCB: func() (any, func(ctx context.Context, src *Set) (any, error)) {
+				cfg := struct{ A []float32 }{A: []float32{1.5}}
+				return &cfg, testWrapDials(&cfg)
+			},
+			args:     []string{"--a=1,1e300"},
+			expected: nil,
+			expErr:   "failed to parse: failed to parse flags: invalid value \"1,1e300\" for flag -a: failed to parse float index 1: strconv.ParseFloat: parsing \"1e300\": value out of range",
+		},
+		{
+			name: "basic_float64_slice_set",
+			tmplCB: func() (any, func(ctx context.Context, src *Set) (any, error)) {
+				cfg := struct{ A []float64 }{A: []float64{1.5}}
+				return &cfg, testWrapDials(&cfg)
+			},
+			args:     []string{"--a=1e300, 0.25"},
+			expected: &struct{ A []float64 }{A: []float64{1e300, 0.25}},
+		},
+		{
+			name: "basic_float64_slice_set_parse_error",
+			tmplCB: func() (any, func(ctx context.Context, src *Set) (any, error)) {
+				cfg := struct{ A []float64 }{A: []float64{1.5}}
+				return &cfg, testWrapDials(&cfg)
+			},
+			args:     []string{"--a=1,fish"},
+			expected: nil,
+			expErr:   "failed to parse: failed to parse flags: invalid value \"1,fish\" for flag -a: failed to parse float index 1: strconv.ParseFloat: parsing \"fish\": invalid syntax",
+		},
+		{
+			name: "bool_slice_set_multiple_flag",
+			tmplCB: func() (any, func(ctx context.Context, src *Set) (any, error)) {
+				cfg := struct{ A []bool }{A: []bool{true}}
+				return &cfg, testWrapDials(&cfg)
+			},
+			args:     []string{"--a=false,t", "--a=0"},
+			expected: &struct{ A []bool }{A: []bool{false, true, false}},
+		},
+		{
+			name: "bool_slice_set_parse_error",
+			tmplCB: func() (any, func(ctx context.Context, src *Set) (any, error)) {
+				cfg := struct{ A []bool }{}
+				return &cfg, testWrapDials(&cfg)
+			},
+			args:     []string{"--a=true,maybe"},
+			expected: nil,
+			expErr:   "failed to parse: failed to parse flags: invalid value \"true,maybe\" for flag -a: failed to parse bool index 1: strconv.ParseBool: parsing \"maybe\": invalid syntax",
+		},
+		{
+			name: "duration_slice_default",
+			tmplCB: func() (any, func(ctx context.Context, src *Set) (any, error)) {
+				cfg := struct{ A []time.Duration }{A: []time.Duration{time.Second}}
+				return &cfg, testWrapDials(&cfg)
+			},
+			args:     []string{},
+			expected: &struct{ A []time.Duration }{A: []time.Duration{time.Second}},
+		},
+		{
+			name: "duration_slice_set",
+			tmplCB: func() (any, func(ctx context.Context, src *Set) (any, error)) {
+				cfg := struct{ A []time.Duration }{A: []time.Duration{time.Second}}
+				return &cfg, testWrapDials(&cfg)
+			},
+			args:     []string{"--a=100ms,1s,5s"},
+			expected: &struct{ A []time.Duration }{A: []time.Duration{100 * time.Millisecond, time.Second, 5 * time.Second}},
+		},
+		{
+			name: "duration_slice_set_parse_error",
+			tmplCB: func() (any, func(ctx context.Context, src *Set) (any, error)) {
+				cfg := struct{ A []time.Duration }{}
+				return &cfg, testWrapDials(&cfg)
+			},
+			args:     []string{"--a=1s,5"},
+			expected: nil,
+			expErr:   "failed to parse: failed to parse flags: invalid value \"1s,5\" for flag -a: failed to parse duration index 1: time: missing unit in duration \"5\"",
+		},
 		{
 			name: "basic_int32_set_nooverflow",
 			tmplCB: func() (any, func(ctx context.Context, src *Set) (any, error)) {
@@ -1020,6 +1120,31 @@ func TestTable(t *testing.T) {
 			}{T: tu{
 				Text: "Hello", //shouldn't change since it's non-pointer
 			}},
+		}, {
+			name: "struct_slice_json_set",
+			tmplCB: func() (any, func(ctx context.Context, src *Set) (any, error)) {
+				cfg := struct{ A []struct{ B, C int } }{A: []struct{ B, C int }{{B: 1}}}
+				return &cfg, testWrapDials(&cfg)
+			},
+			args:     []string{`--a=[{"B":2,"C":3},{"B":4}]`},
+			expected: &struct{ A []struct{ B, C int } }{A: []struct{ B, C int }{{B: 2, C: 3}, {B: 4}}},
+		}, {
+			name: "struct_slice_json_defaulted",
+			tmplCB: func() (any, func(ctx context.Context, src *Set) (any, error)) {
+				cfg := struct{ A []struct{ B, C int } }{A: []struct{ B, C int }{{B: 1}}}
+				return &cfg, testWrapDials(&cfg)
+			},
+			args:     []string{},
+			expected: &struct{ A []struct{ B, C int } }{A: []struct{ B, C int }{{B: 1}}},
+		}, {
+			name: "struct_slice_json_invalid",
+			tmplCB: func() (any, func(ctx context.Context, src *Set) (any, error)) {
+				cfg := struct{ A []struct{ B, C int } }{}
+				return &cfg, testWrapDials(&cfg)
+			},
+			args:     []string{`--a={"B":2}`},
+			expected: nil,
+			expErr:   `failed to parse: failed to parse flags: invalid value "{\"B\":2}" for flag -a: json: cannot unmarshal object into Go value of type []struct { B int; C int }`,
 		},
 	} {
 		tbl := itbl
@@ -1074,3 +1199,431 @@ func TestMust(t *testing.T) {
 		t.Errorf("expected World to be true, got %t", got.World)
 	}
 }
+
+func TestTextMarshalerDefaultUsage(t *testing.T) {
+	type Config struct {
+		Addr net.IP
+	}
+
+	cfg := Config{Addr: net.ParseIP("10.0.0.1")}
+	src, setupErr := NewSetWithArgs(DefaultFlagNameConfig(), &cfg, []string{})
+	require.NoError(t, setupErr)
+
+	buf := &bytes.Buffer{}
+	src.Flags.SetOutput(buf)
+	src.Flags.PrintDefaults()
+	assert.Contains(t, buf.String(), "(default 10.0.0.1)")
+
+	d, err := dials.Config(context.Background(), &cfg, src)
+	require.NoError(t, err)
+	assert.Equal(t, net.ParseIP("10.0.0.1"), d.View().Addr)
+}
+
+func TestUnhandledTypes(t *testing.T) {
+	type Config struct {
+		Hello string
+		S     [][]string
+		M     map[int]bool
+	}
+
+	reported := map[string]reflect.Type{}
+	cfg := DefaultFlagNameConfig()
+	cfg.OnUnhandledType = func(flagName string, t reflect.Type) {
+		reported[flagName] = t
+	}
+
+	_, setupErr := NewSetWithArgs(cfg, &Config{}, []string{})
+	require.NoError(t, setupErr)
+	assert.Equal(t, map[string]reflect.Type{
+		"s": reflect.TypeOf([][]string{}),
+		"m": reflect.TypeOf(map[int]bool{}),
+	}, reported)
+
+	cfg.ErrorOnUnhandledTypes = true
+	_, setupErr = NewSetWithArgs(cfg, &Config{}, []string{})
+	assert.EqualError(t, setupErr,
+		`unable to register flags for fields with unsupported types: "s" ([][]string), "m" (map[int]bool)`)
+}
+
+func TestDelimiters(t *testing.T) {
+	type Config struct {
+		Hosts  []string
+		Labels map[string]string
+	}
+
+	cfg := DefaultFlagNameConfig()
+	cfg.Delimiters = parse.Delimiters{Element: ';', KeyValue: '='}
+
+	src, setupErr := NewSetWithArgs(cfg, &Config{}, []string{"-hosts=a,b;c", "-labels=team=core;csv=x,y"})
+	require.NoError(t, setupErr)
+
+	d, err := dials.Config(context.Background(), &Config{}, src)
+	require.NoError(t, err)
+	assert.Equal(t, &Config{
+		Hosts:  []string{"a,b", "c"},
+		Labels: map[string]string{"team": "core", "csv": "x,y"},
+	}, d.View())
+}
+
+func TestWriteGroupedUsage(t *testing.T) {
+	type ConnPool struct {
+		MaxIdle int `dialsdesc:"maximum idle connections"`
+	}
+	type DB struct {
+		Addr     string `dialsdesc:"database address"`
+		ConnPool ConnPool
+	}
+	type Config struct {
+		Verbose bool `dialsdesc:"verbose logging"`
+		DB      DB   `dialsdesc:"database settings"`
+		Server  struct {
+			Port int `dialsdesc:"port to listen on"`
+		}
+	}
+
+	cfg := Config{DB: DB{Addr: "localhost"}}
+	src, setupErr := NewSetWithArgs(DefaultFlagNameConfig(), &cfg, []string{"-db-addr=remote"})
+	require.NoError(t, setupErr)
+	src.Flags.String("extra", "", "some other flag")
+
+	_, err := dials.Config(context.Background(), &cfg, src)
+	require.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	src.WriteGroupedUsage(buf)
+	assert.Equal(t, `  -verbose
+    	verbose logging
+
+DB: database settings
+  -db-addr string
+    	database address (default "localhost")
+  -db-conn-pool-max-idle int
+    	maximum idle connections
+
+Server:
+  -server-port int
+    	port to listen on
+
+Other flags:
+  -extra string
+    	some other flag
+`, buf.String())
+}
+
+func TestArrayOfStructFlags(t *testing.T) {
+	type Endpoint struct {
+		Host string
+		Port int
+	}
+	type Config struct {
+		Endpoints [2]Endpoint
+	}
+
+	cfg := Config{Endpoints: [2]Endpoint{{Host: "a", Port: 80}}}
+	src, setupErr := NewSetWithArgs(DefaultFlagNameConfig(), &cfg,
+		[]string{"-endpoints-1-host=b", "-endpoints-1-port=8080"})
+	require.NoError(t, setupErr)
+	require.NotNil(t, src.Flags.Lookup("endpoints-0-host"))
+	assert.Equal(t, "a", src.Flags.Lookup("endpoints-0-host").DefValue)
+
+	d, err := dials.Config(context.Background(), &cfg, src)
+	require.NoError(t, err)
+	// only the element whose fields were set is replaced
+	assert.Equal(t, [2]Endpoint{{Host: "a", Port: 80}, {Host: "b", Port: 8080}}, d.View().Endpoints)
+
+	// Values from lower-precedence sources are overlaid element by
+	// element (and field by field), so setting one element's Host
+	// leaves the other element, and that element's Port, alone.
+	lower, lowerErr := static.NewValueSource(Config{Endpoints: [2]Endpoint{{Host: "file", Port: 81}, {Port: 9090}}})
+	require.NoError(t, lowerErr)
+	src, setupErr = NewSetWithArgs(DefaultFlagNameConfig(), &cfg, []string{"-endpoints-1-host=b"})
+	require.NoError(t, setupErr)
+	d, err = dials.Config(context.Background(), &cfg, lower, src)
+	require.NoError(t, err)
+	assert.Equal(t, [2]Endpoint{{Host: "file", Port: 81}, {Host: "b", Port: 9090}}, d.View().Endpoints)
+}
+
+func TestEnvFallback(t *testing.T) {
+	type Config struct {
+		Port     int    `dialsdesc:"port to listen on"`
+		Host     string `dialsdesc:"host to bind"`
+		LogLevel string
+	}
+
+	t.Setenv("MYAPP_PORT", "8080")
+	t.Setenv("MYAPP_HOST", "example.com")
+	t.Setenv("MYAPP_LOG_LEVEL", "debug")
+
+	nc := DefaultFlagNameConfig()
+	nc.EnvFallbackName = EnvNameFromFlag("MYAPP")
+	cfg := Config{Port: 80, Host: "localhost"}
+	src, setupErr := NewSetWithArgs(nc, &cfg, []string{"-host=cmdline.example"})
+	require.NoError(t, setupErr)
+	assert.Equal(t, "port to listen on (env $MYAPP_PORT)", src.Flags.Lookup("port").Usage)
+
+	d, err := dials.Config(context.Background(), &cfg, src)
+	require.NoError(t, err)
+	// the command-line flag takes precedence over the environment
+	assert.Equal(t, &Config{Port: 8080, Host: "cmdline.example", LogLevel: "debug"}, d.View())
+
+	t.Setenv("MYAPP_PORT", "not a number")
+	badSrc, setupErr := NewSetWithArgs(nc, &cfg, []string{})
+	require.NoError(t, setupErr)
+	_, err = dials.Config(context.Background(), &cfg, badSrc)
+	assert.ErrorContains(t, err, `failed to set flag "port" from environment variable MYAPP_PORT`)
+}
+
+func TestSeparator(t *testing.T) {
+	type Conn struct {
+		MaxIdle int
+		Host    string `dials:"hostname"`
+	}
+	type Config struct {
+		LogLevel string
+		Database struct {
+			Primary Conn
+			Replica Conn `dials:"read_replica"`
+		}
+	}
+
+	nc := DefaultFlagNameConfig()
+	nc.Separator = "."
+	src, setupErr := NewSetWithArgs(nc, &Config{},
+		[]string{"-log-level=debug", "-database.primary.max-idle=3", "-database.read_replica.hostname=replica.example"})
+	require.NoError(t, setupErr)
+
+	names := []string{}
+	src.Flags.VisitAll(func(f *flag.Flag) { names = append(names, f.Name) })
+	assert.ElementsMatch(t, []string{
+		"log-level",
+		"database.primary.max-idle",
+		"database.primary.hostname",
+		"database.read_replica.max-idle",
+		"database.read_replica.hostname",
+	}, names)
+
+	d, err := dials.Config(context.Background(), &Config{}, src)
+	require.NoError(t, err)
+	expected := Config{LogLevel: "debug"}
+	expected.Database.Primary.MaxIdle = 3
+	expected.Database.Replica.Host = "replica.example"
+	assert.Equal(t, &expected, d.View())
+}
+
+func TestShorthand(t *testing.T) {
+	type DB struct {
+		Host string `dialsflagshort:"H" dialsdesc:"database host"`
+	}
+	type Config struct {
+		Verbose bool   `dialsflagshort:"v" dialsdesc:"verbose logging"`
+		Name    string `dialsflagshort:"n" dialsdesc:"name"`
+		DB      DB
+		Count   int `dialsdesc:"count"`
+	}
+
+	for _, tbl := range []struct {
+		name   string
+		args   []string
+		expect Config
+	}{
+		{name: "short", args: []string{"-v", "-n=fim", "-H", "db.example"},
+			expect: Config{Verbose: true, Name: "fim", DB: DB{Host: "db.example"}}},
+		{name: "long", args: []string{"--verbose", "--name=fim", "--db-host=db.example"},
+			expect: Config{Verbose: true, Name: "fim", DB: DB{Host: "db.example"}}},
+		// both set the same flag.Value, so the last one wins
+		{name: "both", args: []string{"-n=foo", "--name=bar"}, expect: Config{Name: "bar"}},
+	} {
+		src, setupErr := NewSetWithArgs(DefaultFlagNameConfig(), &Config{}, tbl.args)
+		require.NoError(t, setupErr, tbl.name)
+		d, err := dials.Config(context.Background(), &Config{}, src)
+		require.NoError(t, err, tbl.name)
+		assert.Equal(t, &tbl.expect, d.View(), tbl.name)
+	}
+
+	src, setupErr := NewSetWithArgs(DefaultFlagNameConfig(), &Config{}, []string{})
+	require.NoError(t, setupErr)
+	buf := &bytes.Buffer{}
+	src.WriteGroupedUsage(buf)
+	assert.Equal(t, `  -verbose
+    	verbose logging
+  -v	shorthand for -verbose
+  -name string
+    	name
+  -n string
+    	shorthand for -name
+  -count int
+    	count
+
+DB:
+  -db-host string
+    	database host
+  -H string
+    	shorthand for -db-host
+`, buf.String())
+}
+
+func TestShorthandCollisions(t *testing.T) {
+	type dupShort struct {
+		Verbose bool `dialsflagshort:"v"`
+		Version bool `dialsflagshort:"v"`
+	}
+	_, err := NewSetWithArgs(DefaultFlagNameConfig(), &dupShort{}, []string{})
+	assert.EqualError(t, err, `shorthand "v" for flag "version" is already the shorthand for flag "verbose"`)
+
+	type fieldCollision struct {
+		Verbose bool `dialsflagshort:"x"`
+		X       int
+	}
+	_, err = NewSetWithArgs(DefaultFlagNameConfig(), &fieldCollision{}, []string{})
+	assert.EqualError(t, err, `shorthand "x" for flag "verbose" collides with the flag for another field`)
+
+	type longShort struct {
+		Verbose bool `dialsflagshort:"vv"`
+	}
+	_, err = NewSetWithArgs(DefaultFlagNameConfig(), &longShort{}, []string{})
+	assert.EqualError(t, err, `shorthand "vv" for flag "verbose" is not a single character`)
+
+	// a shorthand that's already registered by other means is left alone
+	type Config struct {
+		Verbose bool `dialsflagshort:"v"`
+	}
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	glogV := fs.Int("v", 0, "log verbosity")
+	src := Set{Flags: fs, ParseFunc: func() error { return fs.Parse([]string{"-v=2", "-verbose"}) }}
+	d, err := dials.Config(context.Background(), &Config{}, &src)
+	require.NoError(t, err)
+	assert.Equal(t, &Config{Verbose: true}, d.View())
+	assert.Equal(t, 2, *glogV)
+}
+
+func TestResponseFiles(t *testing.T) {
+	t.Parallel()
+	type Config struct {
+		Name  string
+		Port  int
+		Tags  []string
+		Motd  string
+		Debug bool
+	}
+
+	dir := t.TempDir()
+	inner := filepath.Join(dir, "inner.txt")
+	require.NoError(t, os.WriteFile(inner, []byte("-debug\n"), 0o600))
+	outer := filepath.Join(dir, "flags.txt")
+	require.NoError(t, os.WriteFile(outer, []byte(`# connection settings
+-name=fim -port 8080
+-tags 'a b' # trailing comment
+-motd "say \"hi\"\\"
+@`+inner+`
+`), 0o600))
+
+	nc := DefaultFlagNameConfig()
+	nc.ResponseFilePrefix = "@"
+	src, setupErr := NewSetWithArgs(nc, &Config{}, []string{"@" + outer, "-port=9090", "-tags", "@@c"})
+	require.NoError(t, setupErr)
+	d, err := dials.Config(context.Background(), &Config{}, src)
+	require.NoError(t, err)
+	// later arguments override those from the file
+	assert.Equal(t, &Config{
+		Name:  "fim",
+		Port:  9090,
+		Tags:  []string{"a b", "@c"},
+		Motd:  `say "hi"\`,
+		Debug: true,
+	}, d.View())
+
+	for name, tbl := range map[string]struct {
+		contents string
+		expErr   string
+	}{
+		"unterminated_quote": {
+			contents: "-name=fim\n-motd 'hello\n",
+			expErr:   `failed to parse response file "` + filepath.Join(dir, "unterminated_quote") + `": line 2: unterminated ' quote`,
+		},
+		"self_reference": {
+			contents: "@" + filepath.Join(dir, "self_reference"),
+			expErr:   `response file "` + filepath.Join(dir, "self_reference") + `" references itself`,
+		},
+	} {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(tbl.contents), 0o600))
+		badSrc, setupErr := NewSetWithArgs(nc, &Config{}, []string{"@" + path})
+		require.NoError(t, setupErr)
+		_, err := dials.Config(context.Background(), &Config{}, badSrc)
+		assert.ErrorContains(t, err, tbl.expErr, name)
+	}
+
+	missingSrc, setupErr := NewSetWithArgs(nc, &Config{}, []string{"@" + filepath.Join(dir, "missing")})
+	require.NoError(t, setupErr)
+	_, err = dials.Config(context.Background(), &Config{}, missingSrc)
+	assert.ErrorContains(t, err, "failed to read response file: open ")
+
+	// without a prefix (or after "--"), arguments are left alone
+	noPrefixSrc, setupErr := NewSetWithArgs(DefaultFlagNameConfig(), &Config{}, []string{"-name=@" + outer})
+	require.NoError(t, setupErr)
+	d, err = dials.Config(context.Background(), &Config{}, noPrefixSrc)
+	require.NoError(t, err)
+	assert.Equal(t, "@"+outer, d.View().Name)
+
+	afterDashesSrc, setupErr := NewSetWithArgs(nc, &Config{}, []string{"-name=x", "--", "@" + outer})
+	require.NoError(t, setupErr)
+	_, err = dials.Config(context.Background(), &Config{}, afterDashesSrc)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"@" + outer}, afterDashesSrc.Flags.Args())
+}
+
+func TestValueFiles(t *testing.T) {
+	t.Parallel()
+	type Config struct {
+		Password string
+		Port     int
+		Tags     []string
+		Debug    bool
+		Admin    string
+	}
+
+	dir := t.TempDir()
+	password := filepath.Join(dir, "password")
+	require.NoError(t, os.WriteFile(password, []byte("hunter2\r\n"), 0o600))
+	port := filepath.Join(dir, "port")
+	require.NoError(t, os.WriteFile(port, []byte("8080\n\n"), 0o600))
+	tags := filepath.Join(dir, "tags")
+	require.NoError(t, os.WriteFile(tags, []byte("a,b"), 0o600))
+
+	nc := DefaultFlagNameConfig()
+	nc.ValueFilePrefix = "@"
+	src, setupErr := NewSetWithArgs(nc, &Config{}, []string{
+		"--password=@" + password, "-debug", "-tags", "@" + tags, "-admin", "@@root", "-port=@" + port,
+		"positional", "-name=@" + password})
+	require.NoError(t, setupErr)
+	_, err := dials.Config(context.Background(), &Config{}, src)
+	// only a single trailing newline is removed
+	assert.ErrorContains(t, err, `invalid value "8080\n" for flag -port`)
+
+	src, setupErr = NewSetWithArgs(nc, &Config{}, []string{
+		"--password=@" + password, "-debug", "-tags", "@" + tags, "-admin", "@@root",
+		"positional", "-name=@" + password})
+	require.NoError(t, setupErr)
+	d, err := dials.Config(context.Background(), &Config{}, src)
+	require.NoError(t, err)
+	assert.Equal(t, &Config{
+		Password: "hunter2",
+		Tags:     []string{"a", "b"},
+		Debug:    true,
+		Admin:    "@root",
+	}, d.View())
+	// arguments after the flags are left alone
+	assert.Equal(t, []string{"positional", "-name=@" + password}, src.Flags.Args())
+
+	missingSrc, setupErr := NewSetWithArgs(nc, &Config{}, []string{"-password", "@" + filepath.Join(dir, "missing")})
+	require.NoError(t, setupErr)
+	_, err = dials.Config(context.Background(), &Config{}, missingSrc)
+	assert.ErrorContains(t, err, `flag "password": failed to read flag value file: open `)
+
+	// without a prefix, values are left alone
+	noPrefixSrc, setupErr := NewSetWithArgs(DefaultFlagNameConfig(), &Config{}, []string{"-password=@" + password})
+	require.NoError(t, setupErr)
+	d, err = dials.Config(context.Background(), &Config{}, noPrefixSrc)
+	require.NoError(t, err)
+	assert.Equal(t, "@"+password, d.View().Password)
+}
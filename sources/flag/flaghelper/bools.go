@@ -0,0 +1,62 @@
+package flaghelper
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/vimeo/dials/parse"
+)
+
+// BoolSliceFlag is a wrapper around a bool slice
+type BoolSliceFlag struct {
+	s         *[]bool
+	defaulted bool
+}
+
+// NewBoolSliceFlag is a constructor for BoolSliceFlag
+func NewBoolSliceFlag(s *[]bool) *BoolSliceFlag {
+	return &BoolSliceFlag{s: s, defaulted: true}
+}
+
+// Set implements pflag.Value and flag.Value
+func (v *BoolSliceFlag) Set(s string) error {
+	parsed, err := parse.BoolSlice(s)
+	if err != nil {
+		return err
+	}
+	if v.defaulted {
+		*v.s = parsed
+		v.defaulted = false
+		return nil
+	}
+	*v.s = append(*v.s, parsed...)
+	return nil
+}
+
+// Get implements flag.Value
+func (v *BoolSliceFlag) Get() interface{} {
+	if v.s == nil {
+		return []bool{}
+	}
+	return *v.s
+}
+
+// String implements flag.Value and pflag.Value
+func (v *BoolSliceFlag) String() string {
+	if v.s == nil {
+		return ""
+	}
+	b := strings.Builder{}
+	for i, z := range *v.s {
+		b.WriteString(strconv.FormatBool(z))
+		if i < len(*v.s)-1 {
+			b.WriteRune(',')
+		}
+	}
+	return b.String()
+}
+
+// Type implements pflag.Value
+func (v *BoolSliceFlag) Type() string {
+	return "[]bool"
+}
@@ -0,0 +1,70 @@
+package flaghelper
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unsafe"
+
+	"github.com/vimeo/dials/parse"
+)
+
+// Float represents all floating-point types
+type Float interface {
+	float32 | float64
+}
+
+// FloatSliceFlag is a wrapper around a float-typed slice
+type FloatSliceFlag[F Float] struct {
+	s         *[]F
+	defaulted bool
+}
+
+// NewFloatSlice is a constructor for FloatSliceFlag
+func NewFloatSlice[F Float](s *[]F) *FloatSliceFlag[F] {
+	return &FloatSliceFlag[F]{s: s, defaulted: true}
+}
+
+// Set implements pflag.Value and flag.Value
+func (v *FloatSliceFlag[F]) Set(s string) error {
+	parsed, err := parse.FloatSlice[F](s)
+	if err != nil {
+		return err
+	}
+	if v.defaulted {
+		*v.s = parsed
+		v.defaulted = false
+		return nil
+	}
+	*v.s = append(*v.s, parsed...)
+	return nil
+}
+
+// Get implements flag.Value
+func (v *FloatSliceFlag[F]) Get() interface{} {
+	if v.s == nil {
+		return []F{}
+	}
+	return *v.s
+}
+
+// String implements flag.Value and pflag.Value
+func (v *FloatSliceFlag[F]) String() string {
+	if v.s == nil {
+		return ""
+	}
+	bitSize := int(unsafe.Sizeof(F(0)) * 8)
+	b := strings.Builder{}
+	for i, z := range *v.s {
+		b.WriteString(strconv.FormatFloat(float64(z), 'g', -1, bitSize))
+		if i < len(*v.s)-1 {
+			b.WriteRune(',')
+		}
+	}
+	return b.String()
+}
+
+// Type implements pflag.Value
+func (v *FloatSliceFlag[F]) Type() string {
+	return fmt.Sprintf("%T", v.s)
+}
@@ -0,0 +1,53 @@
+package flaghelper
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// JSONFlag wraps a pointer to an arbitrary value, decoding flag arguments
+// into it as JSON.
+//
+// This is useful for types that don't have a more natural command-line
+// representation, such as structs and slices of structs.
+type JSONFlag struct {
+	ptr reflect.Value
+}
+
+// NewJSONFlag is the constructor for JSONFlag. ptr must be a non-nil pointer.
+func NewJSONFlag(ptr any) *JSONFlag {
+	return &JSONFlag{ptr: reflect.ValueOf(ptr)}
+}
+
+// Set implements flag.Value and pflag.Value
+// Each call replaces the current value with the decoded argument.
+func (j *JSONFlag) Set(s string) error {
+	newVal := reflect.New(j.ptr.Type().Elem())
+	if err := json.Unmarshal([]byte(s), newVal.Interface()); err != nil {
+		return err
+	}
+	j.ptr.Elem().Set(newVal.Elem())
+	return nil
+}
+
+// Get implements flag.Value
+func (j *JSONFlag) Get() interface{} {
+	return j.ptr.Elem().Interface()
+}
+
+// String implements flag.Value and pflag.Value
+func (j *JSONFlag) String() string {
+	if !j.ptr.IsValid() || j.ptr.IsNil() || j.ptr.Elem().IsZero() {
+		return ""
+	}
+	b, err := json.Marshal(j.ptr.Interface())
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+// Type implements pflag.Value
+func (j *JSONFlag) Type() string {
+	return "json"
+}
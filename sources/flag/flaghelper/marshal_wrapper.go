@@ -17,6 +17,10 @@ func NewMarshalWrapper(v encoding.TextUnmarshaler) *MarshalWrapper {
 	}
 }
 
+// String implements flag.Value and pflag.Value. It returns the marshaled text
+// if the wrapped value also implements encoding.TextMarshaler (so defaults are
+// rendered correctly in usage output), falling back to fmt.Stringer if
+// marshaling isn't supported or fails.
 func (w MarshalWrapper) String() string {
 	if m, ok := w.v.(encoding.TextMarshaler); ok {
 		b, err := m.MarshalText()
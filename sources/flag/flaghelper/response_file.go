@@ -0,0 +1,154 @@
+package flaghelper
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// ExpandResponseFiles returns args with each argument beginning with prefix
+// (e.g. "@flags.txt" with prefix "@") replaced by the arguments read from
+// the named file (a "response file"), so long command-lines can be kept in
+// files. Response files may themselves reference other response files.
+// Expansion stops at a "--" argument, and an argument beginning with prefix
+// twice (e.g. "@@name") is passed through with one prefix removed, for
+// values that happen to begin with the prefix.
+//
+// Response files consist of arguments separated by whitespace (usually one
+// per line, e.g. "--name=fim" or "--name fim"). Arguments may be quoted
+// with single quotes (taken literally) or double quotes (within which
+// backslash escapes a double quote or backslash), and a backslash outside
+// quotes escapes the following character. A "#" at the beginning of an
+// argument starts a comment that runs to the end of the line.
+func ExpandResponseFiles(args []string, prefix string) ([]string, error) {
+	if prefix == "" {
+		return args, nil
+	}
+	e := responseFileExpander{prefix: prefix}
+	out := make([]string, 0, len(args))
+	if err := e.expand(args, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+type responseFileExpander struct {
+	prefix string
+	// files is the stack of response files currently being expanded, for
+	// detecting cycles.
+	files []string
+	// done is set after a "--" argument; subsequent arguments are passed
+	// through untouched.
+	done bool
+}
+
+func (e *responseFileExpander) expand(args []string, out *[]string) error {
+	for _, arg := range args {
+		switch {
+		case e.done || !strings.HasPrefix(arg, e.prefix) || arg == e.prefix:
+			if arg == "--" {
+				e.done = true
+			}
+			*out = append(*out, arg)
+		case strings.HasPrefix(arg, e.prefix+e.prefix):
+			*out = append(*out, strings.TrimPrefix(arg, e.prefix))
+		default:
+			if err := e.expandFile(strings.TrimPrefix(arg, e.prefix), out); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (e *responseFileExpander) expandFile(path string, out *[]string) error {
+	for _, f := range e.files {
+		if f == path {
+			return fmt.Errorf("response file %q references itself", path)
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read response file: %w", err)
+	}
+	args, err := splitResponseFile(string(data))
+	if err != nil {
+		return fmt.Errorf("failed to parse response file %q: %w", path, err)
+	}
+	e.files = append(e.files, path)
+	defer func() { e.files = e.files[:len(e.files)-1] }()
+	return e.expand(args, out)
+}
+
+// splitResponseFile splits the contents of a response file into arguments.
+func splitResponseFile(data string) ([]string, error) {
+	args := []string{}
+	arg := strings.Builder{}
+	inArg := false
+	// quote is the quote character of the quoted section we're in, if
+	// any, and quoteLine the line it started on
+	quote := rune(0)
+	quoteLine := 0
+	line := 1
+	runes := []rune(data)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if r == '\n' {
+			line++
+		}
+		switch {
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				arg.WriteRune(r)
+			}
+		case quote == '"':
+			switch {
+			case r == '"':
+				quote = 0
+			case r == '\\' && i+1 < len(runes) && (runes[i+1] == '"' || runes[i+1] == '\\'):
+				i++
+				arg.WriteRune(runes[i])
+			default:
+				arg.WriteRune(r)
+			}
+		case unicode.IsSpace(r):
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		case r == '#' && !inArg:
+			for i+1 < len(runes) && runes[i+1] != '\n' {
+				i++
+			}
+		case r == '\'' || r == '"':
+			quote, quoteLine = r, line
+			inArg = true
+		case r == '\\':
+			inArg = true
+			if i+1 < len(runes) {
+				i++
+				if runes[i] == '\n' {
+					// an escaped newline continues the argument
+					// on the next line
+					line++
+					continue
+				}
+				arg.WriteRune(runes[i])
+			}
+		default:
+			inArg = true
+			arg.WriteRune(r)
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("line %d: unterminated %c quote", quoteLine, quote)
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+	return args, nil
+}
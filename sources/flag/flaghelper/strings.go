@@ -14,6 +14,7 @@ import (
 type StringSliceFlag struct {
 	s         *[]string
 	defaulted bool
+	delims    parse.Delimiters
 }
 
 // NewStringSliceFlag is a constructor for StringSliceFlag
@@ -21,19 +22,24 @@ func NewStringSliceFlag(s *[]string) *StringSliceFlag {
 	return &StringSliceFlag{s: s, defaulted: true}
 }
 
+// NewStringSliceFlagWithDelimiters is a constructor for StringSliceFlag that
+// splits values on d.Element rather than commas.
+func NewStringSliceFlagWithDelimiters(s *[]string, d parse.Delimiters) *StringSliceFlag {
+	return &StringSliceFlag{s: s, defaulted: true, delims: d}
+}
+
 // Set implement pflag.Value and flag.Value
 func (v *StringSliceFlag) Set(s string) error {
-	parsed, err := parse.StringSlice(s)
+	parsed, err := parse.StringSliceWithDelimiters(s, v.delims)
 	if err != nil {
 		return err
 	}
 	if v.defaulted {
-		v.s = &parsed
+		*v.s = parsed
 		v.defaulted = false
 		return nil
 	}
-	newSlice := append(*v.s, parsed...)
-	v.s = &newSlice
+	*v.s = append(*v.s, parsed...)
 	return nil
 }
 
@@ -54,16 +60,22 @@ func (v *StringSliceFlag) String() string {
 	for i, z := range *v.s {
 		b.WriteString(strconv.Quote(z))
 		if i < len(*v.s)-1 {
-			b.WriteRune(',')
+			b.WriteRune(v.delims.WithDefaults().Element)
 		}
 	}
 	return b.String()
 }
 
+// Type implements pflag.Value
+func (v *StringSliceFlag) Type() string {
+	return fmt.Sprintf("%T", v.s)
+}
+
 // StringSetFlag is a wrapper around map[string]struct used for implementing sets
 type StringSetFlag struct {
 	s         *map[string]struct{}
 	defaulted bool
+	delims    parse.Delimiters
 }
 
 // NewStringSetFlag is the constructor for StringSetFlags
@@ -71,9 +83,15 @@ func NewStringSetFlag(m *map[string]struct{}) *StringSetFlag {
 	return &StringSetFlag{s: m, defaulted: true}
 }
 
+// NewStringSetFlagWithDelimiters is a constructor for StringSetFlag that
+// splits values on d.Element rather than commas.
+func NewStringSetFlagWithDelimiters(m *map[string]struct{}, d parse.Delimiters) *StringSetFlag {
+	return &StringSetFlag{s: m, defaulted: true, delims: d}
+}
+
 // Set implement pflag.Value and flag.Value
 func (v *StringSetFlag) Set(s string) error {
-	parsed, err := parse.StringSet(s)
+	parsed, err := parse.StringSetWithDelimiters(s, v.delims)
 	if err != nil {
 		return err
 	}
@@ -113,7 +131,7 @@ func (v *StringSetFlag) String() string {
 	for i, z := range slc {
 		b.WriteString(strconv.Quote(z))
 		if i < len(*v.s)-1 {
-			b.WriteRune(',')
+			b.WriteRune(v.delims.WithDefaults().Element)
 		}
 	}
 	return b.String()
@@ -128,6 +146,7 @@ func (v *StringSetFlag) Type() string {
 type MapStringStringSliceFlag struct {
 	s         *map[string][]string
 	defaulted bool
+	delims    parse.Delimiters
 }
 
 // NewMapStringStringSliceFlag is the constructor for MapStringStringSliceFlag
@@ -135,9 +154,15 @@ func NewMapStringStringSliceFlag(m *map[string][]string) *MapStringStringSliceFl
 	return &MapStringStringSliceFlag{s: m, defaulted: true}
 }
 
+// NewMapStringStringSliceFlagWithDelimiters is a constructor for MapStringStringSliceFlag that
+// uses the separators in d rather than commas and colons.
+func NewMapStringStringSliceFlagWithDelimiters(m *map[string][]string, d parse.Delimiters) *MapStringStringSliceFlag {
+	return &MapStringStringSliceFlag{s: m, defaulted: true, delims: d}
+}
+
 // Set implement pflag.Value and flag.Value
 func (v *MapStringStringSliceFlag) Set(s string) error {
-	parsed, err := parse.StringStringSliceMap(s)
+	parsed, err := parse.StringStringSliceMapWithDelimiters(s, v.delims)
 	if err != nil {
 		return err
 	}
@@ -176,11 +201,11 @@ func (v *MapStringStringSliceFlag) String() string {
 		quotedKey := strconv.Quote(k)
 		for i, z := range vs {
 			b.WriteString(quotedKey)
-			b.WriteRune(':')
+			b.WriteRune(v.delims.WithDefaults().KeyValue)
 			b.WriteString(strconv.Quote(z))
 			// we want to omit the trailing comma on the last k-v pair.
 			if !(ki >= len(*v.s)-1 && i >= len(vs)-1) {
-				b.WriteRune(',')
+				b.WriteRune(v.delims.WithDefaults().Element)
 			}
 		}
 	}
@@ -196,6 +221,7 @@ func (v *MapStringStringSliceFlag) Type() string {
 type MapStringStringFlag struct {
 	s         *map[string]string
 	defaulted bool
+	delims    parse.Delimiters
 }
 
 // NewMapStringStringFlag is the constructor for MapStringStringFlag
@@ -203,9 +229,15 @@ func NewMapStringStringFlag(m *map[string]string) *MapStringStringFlag {
 	return &MapStringStringFlag{s: m, defaulted: true}
 }
 
+// NewMapStringStringFlagWithDelimiters is a constructor for MapStringStringFlag that
+// uses the separators in d rather than commas and colons.
+func NewMapStringStringFlagWithDelimiters(m *map[string]string, d parse.Delimiters) *MapStringStringFlag {
+	return &MapStringStringFlag{s: m, defaulted: true, delims: d}
+}
+
 // Set implement pflag.Value and flag.Value
 func (v *MapStringStringFlag) Set(s string) error {
-	parsed, err := parse.Map(s, reflect.TypeOf(map[string]string{}))
+	parsed, err := parse.MapWithDelimiters(s, reflect.TypeOf(map[string]string{}), v.delims)
 	if err != nil {
 		return err
 	}
@@ -243,10 +275,10 @@ func (v *MapStringStringFlag) String() string {
 	for i, k := range keyslc {
 		val := (*v.s)[k]
 		b.WriteString(strconv.Quote(k))
-		b.WriteRune(':')
+		b.WriteRune(v.delims.WithDefaults().KeyValue)
 		b.WriteString(strconv.Quote(val))
 		if i < len(keyslc)-1 {
-			b.WriteRune(',')
+			b.WriteRune(v.delims.WithDefaults().Element)
 		}
 	}
 	return b.String()
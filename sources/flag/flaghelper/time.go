@@ -1,7 +1,10 @@
 package flaghelper
 
 import (
+	"strings"
 	"time"
+
+	"github.com/vimeo/dials/parse"
 )
 
 // TimeWrapper wraps a time.Time
@@ -34,3 +37,57 @@ func (tw *TimeWrapper) String() string {
 	// This uses the same format as MarshalText but without the date range validation
 	return tw.t.Format(time.RFC3339Nano)
 }
+
+// DurationSliceFlag is a wrapper around a time.Duration slice
+type DurationSliceFlag struct {
+	s         *[]time.Duration
+	defaulted bool
+}
+
+// NewDurationSliceFlag is a constructor for DurationSliceFlag
+func NewDurationSliceFlag(s *[]time.Duration) *DurationSliceFlag {
+	return &DurationSliceFlag{s: s, defaulted: true}
+}
+
+// Set implements pflag.Value and flag.Value
+func (v *DurationSliceFlag) Set(s string) error {
+	parsed, err := parse.DurationSlice(s)
+	if err != nil {
+		return err
+	}
+	if v.defaulted {
+		*v.s = parsed
+		v.defaulted = false
+		return nil
+	}
+	*v.s = append(*v.s, parsed...)
+	return nil
+}
+
+// Get implements flag.Value
+func (v *DurationSliceFlag) Get() interface{} {
+	if v.s == nil {
+		return []time.Duration{}
+	}
+	return *v.s
+}
+
+// String implements flag.Value and pflag.Value
+func (v *DurationSliceFlag) String() string {
+	if v.s == nil {
+		return ""
+	}
+	b := strings.Builder{}
+	for i, z := range *v.s {
+		b.WriteString(z.String())
+		if i < len(*v.s)-1 {
+			b.WriteRune(',')
+		}
+	}
+	return b.String()
+}
+
+// Type implements pflag.Value
+func (v *DurationSliceFlag) Type() string {
+	return "[]time.Duration"
+}
@@ -0,0 +1,46 @@
+package flaghelper
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ReadValueFile returns the contents of the file at path for use as a flag's
+// value (e.g. for "--password=@/run/secrets/password"), so secrets needn't
+// appear on the command-line (and in the output of ps). A single trailing
+// newline ("\n" or "\r\n") is removed, since most editors (and echo) add
+// one.
+func ReadValueFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read flag value file: %w", err)
+	}
+	val := string(data)
+	if strings.HasSuffix(val, "\n") {
+		val = strings.TrimSuffix(strings.TrimSuffix(val, "\n"), "\r")
+	}
+	return val, nil
+}
+
+// ExpandValueFile returns val, the value of the flag name, replaced by the
+// contents of the file it names (see ReadValueFile) if it begins with prefix.
+// A value beginning with prefix twice is returned with one prefix removed
+// (e.g. "@@admin" becomes "@admin" with prefix "@"). val is returned as-is
+// if prefix is empty.
+func ExpandValueFile(name, val, prefix string) (string, error) {
+	switch {
+	case prefix == "":
+		return val, nil
+	case strings.HasPrefix(val, prefix+prefix):
+		return strings.TrimPrefix(val, prefix), nil
+	case strings.HasPrefix(val, prefix):
+		contents, err := ReadValueFile(strings.TrimPrefix(val, prefix))
+		if err != nil {
+			return "", fmt.Errorf("flag %q: %w", name, err)
+		}
+		return contents, nil
+	default:
+		return val, nil
+	}
+}
@@ -0,0 +1,144 @@
+package flag
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/vimeo/dials/transform"
+)
+
+// usageGroup is a set of flags registered for the fields nested within a
+// single top-level struct field.
+type usageGroup struct {
+	header string
+	flags  []*flag.Flag
+}
+
+// WriteGroupedUsage writes usage text for the flags in the Set to w, in the
+// same format as flag.FlagSet.PrintDefaults. Flags for top-level fields are
+// listed first, followed by the flags for fields nested within each
+// top-level struct field, grouped under a header with that field's name and
+// its `dialsdesc` tag (if any). Any flags registered in the underlying
+// FlagSet by other means are listed last, under "Other flags".
+//
+// This is suitable for use as the FlagSet's Usage function, e.g.
+//
+//	s.Flags.Usage = func() { s.WriteGroupedUsage(s.Flags.Output()) }
+//
+// If flags have not been registered yet, this falls back to PrintDefaults.
+func (s *Set) WriteGroupedUsage(w io.Writer) {
+	if s.Flags == nil {
+		return
+	}
+	if !s.flagsRegistered || !s.trnslVal.IsValid() {
+		printFlags(w, s.allFlags(nil))
+		return
+	}
+
+	topType := s.regType
+	for topType.Kind() == reflect.Ptr {
+		topType = topType.Elem()
+	}
+
+	seen := map[string]struct{}{}
+	ungrouped := []*flag.Flag{}
+	groups := []*usageGroup{}
+	groupIdx := map[string]*usageGroup{}
+
+	t := s.trnslVal.Type()
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		f := s.Flags.Lookup(s.mkname(sf))
+		if f == nil {
+			continue
+		}
+		if _, ok := seen[f.Name]; ok {
+			continue
+		}
+		seen[f.Name] = struct{}{}
+		// list any shorthand immediately after the flag
+		flags := []*flag.Flag{f}
+		if sh := s.shorthandFlag(f.Name); sh != nil {
+			seen[sh.Name] = struct{}{}
+			flags = append(flags, sh)
+		}
+
+		path := transform.FieldPath(sf)
+		if len(path) < 2 {
+			ungrouped = append(ungrouped, flags...)
+			continue
+		}
+		g, ok := groupIdx[path[0]]
+		if !ok {
+			g = &usageGroup{header: groupHeader(topType, path[0])}
+			groupIdx[path[0]] = g
+			groups = append(groups, g)
+		}
+		g.flags = append(g.flags, flags...)
+	}
+
+	printFlags(w, ungrouped)
+	for _, g := range groups {
+		fmt.Fprintf(w, "\n%s\n", g.header)
+		printFlags(w, g.flags)
+	}
+	if others := s.allFlags(seen); len(others) > 0 {
+		fmt.Fprintf(w, "\nOther flags:\n")
+		printFlags(w, others)
+	}
+}
+
+// groupHeader returns the header for the group of flags nested within the
+// top-level field named fieldName.
+func groupHeader(topType reflect.Type, fieldName string) string {
+	sf, ok := topType.FieldByName(fieldName)
+	if !ok {
+		return fieldName + ":"
+	}
+	if desc := sf.Tag.Get(HelpTextTag); desc != "" {
+		return fieldName + ": " + desc
+	}
+	return fieldName + ":"
+}
+
+// shorthandFlag returns the shorthand registered for the flag named name (see
+// the dialsflagshort tag), or nil if there isn't one.
+func (s *Set) shorthandFlag(name string) *flag.Flag {
+	for short, full := range s.shorthands {
+		if full == name {
+			return s.Flags.Lookup(short)
+		}
+	}
+	return nil
+}
+
+// allFlags returns all the flags in the FlagSet (in lexicographical order)
+// that aren't in exclude.
+func (s *Set) allFlags(exclude map[string]struct{}) []*flag.Flag {
+	out := []*flag.Flag{}
+	s.Flags.VisitAll(func(f *flag.Flag) {
+		if _, ok := exclude[f.Name]; !ok {
+			out = append(out, f)
+		}
+	})
+	return out
+}
+
+// printFlags writes the usage for each of flags to w, using
+// flag.FlagSet.PrintDefaults so the formatting matches the standard library.
+func printFlags(w io.Writer, flags []*flag.Flag) {
+	for _, f := range flags {
+		fs := flag.NewFlagSet("", flag.ContinueOnError)
+		fs.SetOutput(w)
+		fs.Var(f.Value, f.Name, f.Usage)
+		// Var records the current value as the default, which may
+		// differ from the original default after parsing.
+		fs.Lookup(f.Name).DefValue = f.DefValue
+		fs.PrintDefaults()
+	}
+}
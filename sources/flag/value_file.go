@@ -0,0 +1,58 @@
+package flag
+
+import (
+	"flag"
+	"strings"
+
+	"github.com/vimeo/dials/sources/flag/flaghelper"
+)
+
+// expandValueFiles returns args with the value of each flag beginning with
+// prefix (e.g. "-password=@secret.txt" or "-password @secret.txt" with prefix
+// "@") replaced by the contents of the named file (see
+// flaghelper.ExpandValueFile). Values beginning with prefix twice have one
+// prefix removed. As with flag.FlagSet.Parse, flags end at the first
+// non-flag argument or "--".
+func expandValueFiles(fs *flag.FlagSet, args []string, prefix string) ([]string, error) {
+	if prefix == "" {
+		return args, nil
+	}
+	out := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if len(arg) < 2 || arg[0] != '-' || arg == "--" {
+			// end of the flags
+			return append(out, args[i:]...), nil
+		}
+		name := strings.TrimPrefix(arg[1:], "-")
+		if eq := strings.IndexByte(name, '='); eq >= 0 {
+			val, err := flaghelper.ExpandValueFile(name[:eq], name[eq+1:], prefix)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, arg[:len(arg)-len(name)]+name[:eq+1]+val)
+			continue
+		}
+		out = append(out, arg)
+		f := fs.Lookup(name)
+		if f == nil || isBoolFlag(f.Value) || i+1 >= len(args) {
+			// bool flags don't take a separate value (and undefined
+			// flags are an error when parsing)
+			continue
+		}
+		i++
+		val, err := flaghelper.ExpandValueFile(name, args[i], prefix)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, val)
+	}
+	return out, nil
+}
+
+// isBoolFlag indicates whether v is the value of a boolean flag, which may
+// be set without a value.
+func isBoolFlag(v flag.Value) bool {
+	bf, ok := v.(interface{ IsBoolFlag() bool })
+	return ok && bf.IsBoolFlag()
+}
@@ -6,10 +6,12 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"strings"
 	"time"
 
 	"github.com/vimeo/dials"
 	"github.com/vimeo/dials/common"
+	"github.com/vimeo/dials/parse"
 	"github.com/vimeo/dials/ptrify"
 	"github.com/vimeo/dials/sources/flag/flaghelper"
 	"github.com/vimeo/dials/tagformat/caseconversion"
@@ -65,6 +67,12 @@ var (
 	uint64SliceType  = reflect.SliceOf(uint64Type)
 	uintptrSliceType = reflect.SliceOf(uintptrType)
 
+	float32SliceType = reflect.SliceOf(float32Type)
+	float64SliceType = reflect.SliceOf(float64Type)
+
+	boolSliceType     = reflect.SliceOf(boolType)
+	durationSliceType = reflect.SliceOf(timeDuration)
+
 	// Verify that Set implements the dials.Source interface
 	_ dials.Source = (*Set)(nil)
 )
@@ -81,6 +89,49 @@ type NameConfig struct {
 	FieldNameEncodeCasing caseconversion.EncodeCasingFunc
 	// TagEncodeCasing is for the tag names used by the flatten mangler
 	TagEncodeCasing caseconversion.EncodeCasingFunc
+	// Separator, if non-empty, separates the components of flag-names for
+	// nested fields (e.g. "." for flags like "--db.host"). TagEncodeCasing
+	// is then applied to each component individually, so the separator is
+	// independent of the casing within each component. If empty, the
+	// separator is whatever TagEncodeCasing uses between words (e.g. "-"
+	// for kebab-case).
+	Separator string
+
+	// OnUnhandledType, if non-nil, is called with the flag-name and type of
+	// each field that can't be registered as a flag (and would otherwise be
+	// silently skipped).
+	OnUnhandledType func(flagName string, t reflect.Type)
+	// ErrorOnUnhandledTypes makes flag registration fail with an error
+	// listing all fields that can't be registered as flags.
+	ErrorOnUnhandledTypes bool
+
+	// Delimiters overrides the separators used when parsing string-slice,
+	// string-set and string-map flags. Unset delimiters retain their
+	// defaults (see parse.DefaultDelimiters).
+	Delimiters parse.Delimiters
+
+	// ResponseFilePrefix, if non-empty, enables "response files": before
+	// parsing, each argument beginning with the prefix (conventionally
+	// "@", as in "@flags.txt") is replaced by the arguments read from the
+	// named file, which may contain quoting and comments (see
+	// flaghelper.ExpandResponseFiles). It applies to the arguments parsed
+	// by the Set's default ParseFunc (the args passed to NewSetWithArgs,
+	// or the process's arguments), not to a custom ParseFunc or a FlagSet
+	// parsed elsewhere (e.g. by cobra).
+	ResponseFilePrefix string
+
+	// ValueFilePrefix, if non-empty, lets the value of any flag be read
+	// from a file (e.g. to keep secrets out of the output of ps): a value
+	// beginning with the prefix (conventionally "@", as in
+	// "--password=@/run/secrets/password", "--password @secrets.txt" or
+	// "-p@secrets.txt") is replaced by the contents of the named file, less
+	// a single trailing newline. A value beginning with the prefix twice
+	// (e.g. "@@admin") is used with one prefix removed, for values that
+	// legitimately begin with the prefix. Like ResponseFilePrefix, it only
+	// applies to the arguments parsed by the Set's default ParseFunc. If
+	// both use the same prefix, response files are expanded first, so the
+	// "--name=@path" or "-n@path" forms must be used for value files.
+	ValueFilePrefix string
 }
 
 // TODO(@sachi): update FieldNameEncodeCasing to EncodeGoCamelCase once it exists
@@ -115,19 +166,23 @@ func ptrified(template interface{}) (reflect.Value, reflect.Type, error) {
 // pflag library. (or libraries using dials can register flags and let the
 // actual process's Main() call Parse())
 func NewCmdLineSet(cfg *NameConfig, template interface{}) (*Set, error) {
-	fs := pflag.CommandLine
-	parseFunc := func() error { pflag.Parse(); return nil }
-
-	return newSet(cfg, template, fs, parseFunc)
+	s, err := newSet(cfg, template, pflag.CommandLine, nil)
+	if err != nil {
+		return nil, err
+	}
+	// equivalent to pflag.Parse(), with response files expanded
+	s.ParseFunc = func() error { return s.parseArgs(os.Args[1:]) }
+	return s, nil
 }
 
 // NewSetWithArgs creates a new pflag FlagSet and registers flags in it
 func NewSetWithArgs(cfg *NameConfig, template interface{}, args []string) (*Set, error) {
-
-	fs := pflag.NewFlagSet("", pflag.ContinueOnError)
-	parseFunc := func() error { return fs.Parse(args) }
-
-	return newSet(cfg, template, fs, parseFunc)
+	s, err := newSet(cfg, template, pflag.NewFlagSet("", pflag.ContinueOnError), nil)
+	if err != nil {
+		return nil, err
+	}
+	s.ParseFunc = func() error { return s.parseArgs(args) }
+	return s, nil
 }
 
 // NewSetWithFlagSet uses the passed in pflag FlagSet and registers flags
@@ -208,8 +263,26 @@ func (s *Set) parse() error {
 	return nil
 }
 
+// parseArgs parses args with the Set's FlagSet, after expanding any response
+// files and value files (see NameConfig.ResponseFilePrefix and
+// NameConfig.ValueFilePrefix).
+func (s *Set) parseArgs(args []string) error {
+	if s.NameCfg != nil {
+		expanded, err := flaghelper.ExpandResponseFiles(args, s.NameCfg.ResponseFilePrefix)
+		if err != nil {
+			return err
+		}
+		args = expanded
+		if args, err = expandValueFiles(s.Flags, args, s.NameCfg.ValueFilePrefix); err != nil {
+			return err
+		}
+	}
+	return s.Flags.Parse(args)
+}
+
 func (s *Set) registerFlags(tmpl reflect.Value, ptyp reflect.Type) error {
-	fm := transform.NewFlattenMangler(common.DialsTagName, s.NameCfg.FieldNameEncodeCasing, s.NameCfg.TagEncodeCasing)
+	fm := transform.NewFlattenManglerWithSeparator(common.DialsTagName,
+		s.NameCfg.FieldNameEncodeCasing, s.NameCfg.TagEncodeCasing, s.NameCfg.Separator)
 	tfmr := transform.NewTransformer(ptyp, transform.NewAliasMangler(common.DialsTagName, common.DialsPFlagTag, common.DialsPFlagShortTag), fm)
 	val, TrnslErr := tfmr.Translate()
 	if TrnslErr != nil {
@@ -227,6 +300,7 @@ func (s *Set) registerFlags(tmpl reflect.Value, ptyp reflect.Type) error {
 		k = t.Kind()
 	}
 
+	unhandled := []string{}
 	// the input kind will be struct after calling Translate on it
 	for i := 0; i < t.NumField(); i++ {
 		sf := t.Field(i)
@@ -285,6 +359,10 @@ func (s *Set) registerFlags(tmpl reflect.Value, ptyp reflect.Type) error {
 			f = s.Flags.DurationP(name, shorthand, fieldVal.Interface().(time.Duration), help)
 			s.flagValues[name] = reflect.ValueOf(f)
 			continue
+		case isJSONFlagType(ft):
+			s.Flags.VarP(flaghelper.NewJSONFlag(fieldVal.Addr().Interface()), name, shorthand, help)
+			s.flagValues[name] = fieldVal.Addr()
+			continue
 		default:
 		}
 
@@ -328,16 +406,22 @@ func (s *Set) registerFlags(tmpl reflect.Value, ptyp reflect.Type) error {
 		case reflect.Slice, reflect.Map:
 			switch ft {
 			case stringSlice:
+				if s.NameCfg.Delimiters != (parse.Delimiters{}) {
+					// pflag's native string-slices are always comma-separated
+					f = fieldVal.Addr().Interface()
+					s.Flags.VarP(flaghelper.NewStringSliceFlagWithDelimiters(f.(*[]string), s.NameCfg.Delimiters), name, shorthand, help)
+					break
+				}
 				f = s.Flags.StringSliceP(name, shorthand, fieldVal.Interface().([]string), help)
 			case mapStringStringSlice:
 				f = fieldVal.Addr().Interface()
-				s.Flags.VarP(flaghelper.NewMapStringStringSliceFlag(fieldVal.Addr().Interface().(*map[string][]string)), name, shorthand, help)
+				s.Flags.VarP(flaghelper.NewMapStringStringSliceFlagWithDelimiters(fieldVal.Addr().Interface().(*map[string][]string), s.NameCfg.Delimiters), name, shorthand, help)
 			case mapStringString:
 				f = fieldVal.Addr().Interface()
-				s.Flags.VarP(flaghelper.NewMapStringStringFlag(fieldVal.Addr().Interface().(*map[string]string)), name, shorthand, help)
+				s.Flags.VarP(flaghelper.NewMapStringStringFlagWithDelimiters(fieldVal.Addr().Interface().(*map[string]string), s.NameCfg.Delimiters), name, shorthand, help)
 			case stringSet:
 				f = fieldVal.Addr().Interface()
-				s.Flags.VarP(flaghelper.NewStringSetFlag(fieldVal.Addr().Interface().(*map[string]struct{})), name, shorthand, help)
+				s.Flags.VarP(flaghelper.NewStringSetFlagWithDelimiters(fieldVal.Addr().Interface().(*map[string]struct{}), s.NameCfg.Delimiters), name, shorthand, help)
 
 				// signed integral slices
 			case intSliceType:
@@ -376,22 +460,55 @@ func (s *Set) registerFlags(tmpl reflect.Value, ptyp reflect.Type) error {
 				f = fieldVal.Addr().Interface()
 				s.Flags.VarP(flaghelper.NewUnsignedIntegralSlice(f.(*[]uintptr)), name, shorthand, help)
 
+				// float slices
+			case float32SliceType:
+				f = fieldVal.Addr().Interface()
+				s.Flags.VarP(flaghelper.NewFloatSlice(f.(*[]float32)), name, shorthand, help)
+			case float64SliceType:
+				f = fieldVal.Addr().Interface()
+				s.Flags.VarP(flaghelper.NewFloatSlice(f.(*[]float64)), name, shorthand, help)
+
+			case boolSliceType:
+				f = fieldVal.Addr().Interface()
+				s.Flags.VarP(flaghelper.NewBoolSliceFlag(f.(*[]bool)), name, shorthand, help)
+			case durationSliceType:
+				f = fieldVal.Addr().Interface()
+				s.Flags.VarP(flaghelper.NewDurationSliceFlag(f.(*[]time.Duration)), name, shorthand, help)
+
 			default:
-				// Unhandled type. Just keep going.
+				unhandled = append(unhandled, s.unhandledType(name, ft))
 				continue
 			}
 
 		default:
-			// Unhandled type. Just keep going.
+			unhandled = append(unhandled, s.unhandledType(name, ft))
 			continue
 		}
 
 		v := reflect.ValueOf(f)
 		s.flagValues[name] = v
 	}
+	if s.NameCfg.ErrorOnUnhandledTypes && len(unhandled) > 0 {
+		return fmt.Errorf("unable to register flags for fields with unsupported types: %s",
+			strings.Join(unhandled, ", "))
+	}
 	return nil
 }
 
+// unhandledType reports a field that can't be registered as a flag to the
+// OnUnhandledType hook (if set) and returns a description for use in errors.
+func (s *Set) unhandledType(name string, t reflect.Type) string {
+	if s.NameCfg.OnUnhandledType != nil {
+		s.NameCfg.OnUnhandledType(name, t)
+	}
+	return fmt.Sprintf("%q (%s)", name, t)
+}
+
+// SourceName implements dials.NamedSource, returning common.FlagSourceName.
+func (s *Set) SourceName() string {
+	return common.FlagSourceName
+}
+
 // Value fills in the user-provided config struct using flags. It looks up the
 // flags to bind into a given struct field by using that field's `dialspflag`
 // struct tag if present, then its `dials` tag if present, and finally its name.
@@ -419,7 +536,7 @@ func (s *Set) Value(_ context.Context, t *dials.Type) (reflect.Value, error) {
 		// TODO: remove this fallback
 		s.Flags = pflag.NewFlagSet("", pflag.ContinueOnError)
 		if s.ParseFunc == nil {
-			s.ParseFunc = func() error { return s.Flags.Parse(os.Args[1:]) }
+			s.ParseFunc = func() error { return s.parseArgs(os.Args[1:]) }
 		}
 	}
 
@@ -495,6 +612,16 @@ func (s *Set) Value(_ context.Context, t *dials.Type) (reflect.Value, error) {
 	return s.tfmr.ReverseTranslate(s.trnslVal)
 }
 
+// isJSONFlagType returns true for struct and slice-of-struct types, which
+// don't have a natural command-line representation and are decoded as JSON
+// instead.
+func isJSONFlagType(t reflect.Type) bool {
+	if t.Kind() == reflect.Slice {
+		t = stripTypePtr(t.Elem())
+	}
+	return t.Kind() == reflect.Struct
+}
+
 func stripTypePtr(t reflect.Type) reflect.Type {
 	switch t.Kind() {
 	case reflect.Ptr:
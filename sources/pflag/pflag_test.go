@@ -3,10 +3,15 @@ package pflag
 import (
 	"bytes"
 	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 	"time"
 
 	"github.com/vimeo/dials"
+	"github.com/vimeo/dials/parse"
 	"github.com/vimeo/dials/tagformat/caseconversion"
 
 	"github.com/spf13/pflag"
@@ -352,6 +357,100 @@ func TestPFlags(t *testing.T) {
 			expected: nil,
 			expErr:   "failed to parse pflags: invalid argument \"1000000\" for \"--a\" flag: failed to parse integer index 0: strconv.ParseUint: parsing \"1000000\": value out of range",
 		},
+		{
+			name: "basic_float32_slice_default",
+			tmplCB: func() (any, func(ctx context.Context, src *Set) (any, error)) {
+				cfg := struct{ A []float32 }{A: []float32{1.5}}
+				return &cfg, testWrapDials(&cfg)
+			},
+			args:     []string{},
+			expected: &struct{ A []float32 }{A: []float32{1.5}},
+		},
+		{
+			name: "basic_float32_slice_set_multiple_flag",
+			tmplCB: func() (any, func(ctx context.Context, src *Set) (any, error)) {
+				cfg := struct{ A []float32 }{A: []float32{1.5}}
+				return &cfg, testWrapDials(&cfg)
+			},
+			args:     []string{"--a=2.5,-3", "--a=4e2"},
+			expected: &struct{ A []float32 }{A: []float32{2.5, -3, 400}},
+		},
+		{
+			name: "basic_float32_slice_set_overflow",
+			tmplCB: func() (any, func(ctx context.Context, src *Set) (any, error)) {
+				cfg := struct{ A []float32 }{A: []float32{1.5}}
+				return &cfg, testWrapDials(&cfg)
+			},
+			args:     []string{"--a=1,1e300"},
+			expected: nil,
+			expErr:   "failed to parse pflags: invalid argument \"1,1e300\" for \"--a\" flag: failed to parse float index 1: strconv.ParseFloat: parsing \"1e300\": value out of range",
+		},
+		{
+			name: "basic_float64_slice_set",
+			tmplCB: func() (any, func(ctx context.Context, src *Set) (any, error)) {
+				cfg := struct{ A []float64 }{A: []float64{1.5}}
+				return &cfg, testWrapDials(&cfg)
+			},
+			args:     []string{"--a=1e300, 0.25"},
+			expected: &struct{ A []float64 }{A: []float64{1e300, 0.25}},
+		},
+		{
+			name: "basic_float64_slice_set_parse_error",
+			tmplCB: func() (any, func(ctx context.Context, src *Set) (any, error)) {
+				cfg := struct{ A []float64 }{A: []float64{1.5}}
+				return &cfg, testWrapDials(&cfg)
+			},
+			args:     []string{"--a=1,fish"},
+			expected: nil,
+			expErr:   "failed to parse pflags: invalid argument \"1,fish\" for \"--a\" flag: failed to parse float index 1: strconv.ParseFloat: parsing \"fish\": invalid syntax",
+		},
+		{
+			name: "bool_slice_set_multiple_flag",
+			tmplCB: func() (any, func(ctx context.Context, src *Set) (any, error)) {
+				cfg := struct{ A []bool }{A: []bool{true}}
+				return &cfg, testWrapDials(&cfg)
+			},
+			args:     []string{"--a=false,t", "--a=0"},
+			expected: &struct{ A []bool }{A: []bool{false, true, false}},
+		},
+		{
+			name: "bool_slice_set_parse_error",
+			tmplCB: func() (any, func(ctx context.Context, src *Set) (any, error)) {
+				cfg := struct{ A []bool }{}
+				return &cfg, testWrapDials(&cfg)
+			},
+			args:     []string{"--a=true,maybe"},
+			expected: nil,
+			expErr:   "failed to parse pflags: invalid argument \"true,maybe\" for \"--a\" flag: failed to parse bool index 1: strconv.ParseBool: parsing \"maybe\": invalid syntax",
+		},
+		{
+			name: "duration_slice_default",
+			tmplCB: func() (any, func(ctx context.Context, src *Set) (any, error)) {
+				cfg := struct{ A []time.Duration }{A: []time.Duration{time.Second}}
+				return &cfg, testWrapDials(&cfg)
+			},
+			args:     []string{},
+			expected: &struct{ A []time.Duration }{A: []time.Duration{time.Second}},
+		},
+		{
+			name: "duration_slice_set",
+			tmplCB: func() (any, func(ctx context.Context, src *Set) (any, error)) {
+				cfg := struct{ A []time.Duration }{A: []time.Duration{time.Second}}
+				return &cfg, testWrapDials(&cfg)
+			},
+			args:     []string{"--a=100ms,1s,5s"},
+			expected: &struct{ A []time.Duration }{A: []time.Duration{100 * time.Millisecond, time.Second, 5 * time.Second}},
+		},
+		{
+			name: "duration_slice_set_parse_error",
+			tmplCB: func() (any, func(ctx context.Context, src *Set) (any, error)) {
+				cfg := struct{ A []time.Duration }{}
+				return &cfg, testWrapDials(&cfg)
+			},
+			args:     []string{"--a=1s,5"},
+			expected: nil,
+			expErr:   "failed to parse pflags: invalid argument \"1s,5\" for \"--a\" flag: failed to parse duration index 1: time: missing unit in duration \"5\"",
+		},
 		{
 			name: "basic_uint32_set_nooverflow",
 			tmplCB: func() (any, func(ctx context.Context, src *Set) (any, error)) {
@@ -857,6 +956,31 @@ func TestPFlags(t *testing.T) {
 			}{T: tu{
 				Text: "Hello", //shouldn't change since it's non-pointer
 			}},
+		}, {
+			name: "struct_slice_json_set",
+			tmplCB: func() (any, func(ctx context.Context, src *Set) (any, error)) {
+				cfg := struct{ A []struct{ B, C int } }{A: []struct{ B, C int }{{B: 1}}}
+				return &cfg, testWrapDials(&cfg)
+			},
+			args:     []string{`--a=[{"B":2,"C":3},{"B":4}]`},
+			expected: &struct{ A []struct{ B, C int } }{A: []struct{ B, C int }{{B: 2, C: 3}, {B: 4}}},
+		}, {
+			name: "struct_slice_json_defaulted",
+			tmplCB: func() (any, func(ctx context.Context, src *Set) (any, error)) {
+				cfg := struct{ A []struct{ B, C int } }{A: []struct{ B, C int }{{B: 1}}}
+				return &cfg, testWrapDials(&cfg)
+			},
+			args:     []string{},
+			expected: &struct{ A []struct{ B, C int } }{A: []struct{ B, C int }{{B: 1}}},
+		}, {
+			name: "struct_slice_json_invalid",
+			tmplCB: func() (any, func(ctx context.Context, src *Set) (any, error)) {
+				cfg := struct{ A []struct{ B, C int } }{}
+				return &cfg, testWrapDials(&cfg)
+			},
+			args:     []string{`--a={"B":2}`},
+			expected: nil,
+			expErr:   `failed to parse pflags: invalid argument "{\"B\":2}" for "--a" flag: json: cannot unmarshal object into Go value of type []struct { B int; C int }`,
 		},
 	} {
 		tbl := itbl
@@ -910,3 +1034,213 @@ func TestMust(t *testing.T) {
 		t.Errorf("expected World to be true, got %t", got.World)
 	}
 }
+
+func TestTextMarshalerDefaultUsage(t *testing.T) {
+	type Config struct {
+		Addr net.IP
+	}
+
+	cfg := Config{Addr: net.ParseIP("10.0.0.1")}
+	src, setupErr := NewSetWithArgs(DefaultFlagNameConfig(), &cfg, []string{})
+	require.NoError(t, setupErr)
+
+	assert.Contains(t, src.Flags.FlagUsages(), "(default 10.0.0.1)")
+
+	d, err := dials.Config(context.Background(), &cfg, src)
+	require.NoError(t, err)
+	assert.Equal(t, net.ParseIP("10.0.0.1"), d.View().Addr)
+}
+
+func TestUnhandledTypes(t *testing.T) {
+	type Config struct {
+		Hello string
+		S     [][]string
+		M     map[int]bool
+	}
+
+	reported := map[string]reflect.Type{}
+	cfg := DefaultFlagNameConfig()
+	cfg.OnUnhandledType = func(flagName string, t reflect.Type) {
+		reported[flagName] = t
+	}
+
+	_, setupErr := NewSetWithArgs(cfg, &Config{}, []string{})
+	require.NoError(t, setupErr)
+	assert.Equal(t, map[string]reflect.Type{
+		"s": reflect.TypeOf([][]string{}),
+		"m": reflect.TypeOf(map[int]bool{}),
+	}, reported)
+
+	cfg.ErrorOnUnhandledTypes = true
+	_, setupErr = NewSetWithArgs(cfg, &Config{}, []string{})
+	assert.EqualError(t, setupErr,
+		`unable to register flags for fields with unsupported types: "s" ([][]string), "m" (map[int]bool)`)
+}
+
+func TestDelimiters(t *testing.T) {
+	type Config struct {
+		Hosts  []string
+		Labels map[string]string
+	}
+
+	cfg := DefaultFlagNameConfig()
+	cfg.Delimiters = parse.Delimiters{Element: ';', KeyValue: '='}
+
+	src, setupErr := NewSetWithArgs(cfg, &Config{}, []string{"--hosts=a,b;c", "--labels=team=core;csv=x,y"})
+	require.NoError(t, setupErr)
+
+	d, err := dials.Config(context.Background(), &Config{}, src)
+	require.NoError(t, err)
+	assert.Equal(t, &Config{
+		Hosts:  []string{"a,b", "c"},
+		Labels: map[string]string{"team": "core", "csv": "x,y"},
+	}, d.View())
+}
+
+func TestResponseFiles(t *testing.T) {
+	t.Parallel()
+	type Config struct {
+		Name string
+		Port int
+	}
+
+	path := filepath.Join(t.TempDir(), "flags.txt")
+	require.NoError(t, os.WriteFile(path, []byte("# comment\n--name 'fim bat'\n--port=8080\n"), 0o600))
+
+	nc := DefaultFlagNameConfig()
+	nc.ResponseFilePrefix = "@"
+	src, setupErr := NewSetWithArgs(nc, &Config{}, []string{"@" + path, "--port=9090"})
+	require.NoError(t, setupErr)
+	d, err := dials.Config(context.Background(), &Config{}, src)
+	require.NoError(t, err)
+	assert.Equal(t, &Config{Name: "fim bat", Port: 9090}, d.View())
+}
+
+func TestValueFiles(t *testing.T) {
+	t.Parallel()
+	type Config struct {
+		Password string
+		Port     int
+		Tags     []string
+		Debug    bool   `dialspflagshort:"d"`
+		Admin    string `dialspflagshort:"a"`
+		User     string `dialspflagshort:"u"`
+	}
+
+	dir := t.TempDir()
+	password := filepath.Join(dir, "password")
+	require.NoError(t, os.WriteFile(password, []byte("hunter2\r\n"), 0o600))
+	port := filepath.Join(dir, "port")
+	require.NoError(t, os.WriteFile(port, []byte("8080\n\n"), 0o600))
+	tags := filepath.Join(dir, "tags")
+	require.NoError(t, os.WriteFile(tags, []byte("a,b"), 0o600))
+	empty := filepath.Join(dir, "empty")
+	require.NoError(t, os.WriteFile(empty, nil, 0o600))
+
+	nc := DefaultFlagNameConfig()
+	nc.ValueFilePrefix = "@"
+	src, setupErr := NewSetWithArgs(nc, &Config{}, []string{"--port=@" + port})
+	require.NoError(t, setupErr)
+	_, err := dials.Config(context.Background(), &Config{}, src)
+	// only a single trailing newline is removed
+	assert.ErrorContains(t, err, `invalid argument "8080\n" for "--port" flag`)
+
+	for name, args := range map[string][]string{
+		"long": {
+			"--password=@" + password, "--debug", "--tags", "@" + tags, "--admin", "@@root",
+			"positional", "--user=@" + password, "--", "--admin=@" + password},
+		"shorthands": {
+			"--password", "@" + password, "--tags=@" + tags, "-a=@@root",
+			"positional", "-du@" + password, "--", "--admin=@" + password},
+		"separate_shorthand_value": {
+			"--password=@" + password, "--tags=@" + tags, "-da", "@@root",
+			"positional", "-u", "@" + password, "--", "--admin=@" + password},
+	} {
+		args := args
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			src, setupErr := NewSetWithArgs(nc, &Config{}, args)
+			require.NoError(t, setupErr)
+			d, err := dials.Config(context.Background(), &Config{}, src)
+			require.NoError(t, err)
+			assert.Equal(t, &Config{
+				Password: "hunter2",
+				Tags:     []string{"a", "b"},
+				Debug:    true,
+				Admin:    "@root",
+				User:     "hunter2",
+			}, d.View())
+			// arguments after "--" are left alone
+			assert.Equal(t, []string{"positional", "--admin=@" + password}, src.Flags.Args())
+		})
+	}
+
+	missingSrc, setupErr := NewSetWithArgs(nc, &Config{}, []string{"-u", "@" + filepath.Join(dir, "missing")})
+	require.NoError(t, setupErr)
+	_, err = dials.Config(context.Background(), &Config{}, missingSrc)
+	assert.ErrorContains(t, err, `flag "user": failed to read flag value file: open `)
+
+	// "-u=" sets an empty value, rather than expanding "=" (which is also
+	// the prefix here), as does a value naming an empty file
+	eqNC := DefaultFlagNameConfig()
+	eqNC.ValueFilePrefix = "="
+	emptySrc, setupErr := NewSetWithArgs(eqNC, &Config{}, []string{"-u=", "--password==" + password, "-a==" + empty})
+	require.NoError(t, setupErr)
+	d, err := dials.Config(context.Background(), &Config{User: "default", Admin: "root"}, emptySrc)
+	require.NoError(t, err)
+	assert.Equal(t, &Config{Password: "hunter2"}, d.View())
+	assert.Empty(t, emptySrc.Flags.Args())
+
+	// an attached value naming an empty file doesn't swallow the following
+	// argument
+	attachedSrc, setupErr := NewSetWithArgs(nc, &Config{}, []string{"-u@" + empty, "positional"})
+	require.NoError(t, setupErr)
+	d, err = dials.Config(context.Background(), &Config{User: "default"}, attachedSrc)
+	require.NoError(t, err)
+	assert.Equal(t, &Config{}, d.View())
+	assert.Equal(t, []string{"positional"}, attachedSrc.Flags.Args())
+
+	// without a prefix, values are left alone
+	noPrefixSrc, setupErr := NewSetWithArgs(DefaultFlagNameConfig(), &Config{}, []string{"--password=@" + password})
+	require.NoError(t, setupErr)
+	d, err = dials.Config(context.Background(), &Config{}, noPrefixSrc)
+	require.NoError(t, err)
+	assert.Equal(t, "@"+password, d.View().Password)
+}
+
+func TestSeparator(t *testing.T) {
+	type Conn struct {
+		MaxIdle int
+		Host    string `dials:"hostname"`
+	}
+	type Config struct {
+		LogLevel string
+		Database struct {
+			Primary Conn
+			Replica Conn `dials:"read_replica"`
+		}
+	}
+
+	nc := DefaultFlagNameConfig()
+	nc.Separator = "."
+	src, setupErr := NewSetWithArgs(nc, &Config{},
+		[]string{"--log-level=debug", "--database.primary.max-idle", "3", "--database.read_replica.hostname=replica.example"})
+	require.NoError(t, setupErr)
+
+	names := []string{}
+	src.Flags.VisitAll(func(f *pflag.Flag) { names = append(names, f.Name) })
+	assert.ElementsMatch(t, []string{
+		"log-level",
+		"database.primary.max-idle",
+		"database.primary.hostname",
+		"database.read_replica.max-idle",
+		"database.read_replica.hostname",
+	}, names)
+
+	d, err := dials.Config(context.Background(), &Config{}, src)
+	require.NoError(t, err)
+	expected := Config{LogLevel: "debug"}
+	expected.Database.Primary.MaxIdle = 3
+	expected.Database.Replica.Host = "replica.example"
+	assert.Equal(t, &expected, d.View())
+}
@@ -0,0 +1,130 @@
+package pflag
+
+import (
+	"strings"
+
+	"github.com/spf13/pflag"
+	"github.com/vimeo/dials/sources/flag/flaghelper"
+)
+
+// expandValueFiles returns args with the value of each flag beginning with
+// prefix (e.g. "--password=@secret.txt", "--password @secret.txt" or
+// "-p@secret.txt" with prefix "@") replaced by the contents of the named file
+// (see flaghelper.ExpandValueFile). Values beginning with prefix twice have
+// one prefix removed. As with pflag.FlagSet.Parse, flags may be interspersed
+// with other arguments, and end at "--".
+func expandValueFiles(fs *pflag.FlagSet, args []string, prefix string) ([]string, error) {
+	if prefix == "" {
+		return args, nil
+	}
+	out := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		var (
+			expanded []string
+			consumed bool
+			err      error
+		)
+		switch {
+		case arg == "--":
+			// end of the flags
+			return append(out, args[i:]...), nil
+		case strings.HasPrefix(arg, "--"):
+			expanded, consumed, err = expandLongValueFile(fs, arg, args[i+1:], prefix)
+		case len(arg) > 1 && arg[0] == '-':
+			expanded, consumed, err = expandShortValueFile(fs, arg, args[i+1:], prefix)
+		default:
+			// a positional argument; pflag continues parsing flags after it
+			expanded = []string{arg}
+		}
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, expanded...)
+		if consumed {
+			i++
+		}
+	}
+	return out, nil
+}
+
+// expandLongValueFile expands the value of the long flag arg ("--name=val" or
+// "--name val"), indicating whether its value was the following argument
+// (the first of rest).
+func expandLongValueFile(fs *pflag.FlagSet, arg string, rest []string, prefix string) ([]string, bool, error) {
+	name := arg[2:]
+	if eq := strings.IndexByte(name, '='); eq >= 0 {
+		val, err := flaghelper.ExpandValueFile(name[:eq], name[eq+1:], prefix)
+		if err != nil {
+			return nil, false, err
+		}
+		return []string{"--" + name[:eq+1] + val}, false, nil
+	}
+	f := fs.Lookup(name)
+	if f == nil || f.NoOptDefVal != "" || len(rest) == 0 {
+		// flags with a NoOptDefVal (e.g. bools) don't take a separate
+		// value (and undefined flags are an error when parsing)
+		return []string{arg}, false, nil
+	}
+	val, err := flaghelper.ExpandValueFile(name, rest[0], prefix)
+	if err != nil {
+		return nil, false, err
+	}
+	return []string{arg, val}, true, nil
+}
+
+// expandShortValueFile expands the value of the flag taking a value (if any)
+// among the shorthands in arg ("-abc", "-fval", "-f=val" or "-f val"),
+// indicating whether its value was the following argument (the first of
+// rest).
+func expandShortValueFile(fs *pflag.FlagSet, arg string, rest []string, prefix string) ([]string, bool, error) {
+	shorthands := arg[1:]
+	for j := 0; j < len(shorthands); j++ {
+		f := fs.ShorthandLookup(shorthands[j : j+1])
+		if f == nil {
+			// an error when parsing
+			return []string{arg}, false, nil
+		}
+		after := shorthands[j+1:]
+		switch {
+		case len(after) > 0 && after[0] == '=':
+			// strip the "=" before checking for a value, so "-f="
+			// sets an empty value
+			val, err := flaghelper.ExpandValueFile(f.Name, after[1:], prefix)
+			if err != nil {
+				return nil, false, err
+			}
+			if val == "" && f.NoOptDefVal == "" {
+				return emptyShortValue(arg[:j+2]), false, nil
+			}
+			return []string{arg[:j+3] + val}, false, nil
+		case f.NoOptDefVal != "":
+			// doesn't take a value; the next shorthand is another flag
+			continue
+		case after != "":
+			val, err := flaghelper.ExpandValueFile(f.Name, after, prefix)
+			if err != nil {
+				return nil, false, err
+			}
+			if val == "" {
+				return emptyShortValue(arg[:j+2]), false, nil
+			}
+			return []string{arg[:j+2] + val}, false, nil
+		case len(rest) > 0:
+			val, err := flaghelper.ExpandValueFile(f.Name, rest[0], prefix)
+			if err != nil {
+				return nil, false, err
+			}
+			return []string{arg, val}, true, nil
+		}
+	}
+	return []string{arg}, false, nil
+}
+
+// emptyShortValue returns the arguments setting the last of the shorthands in
+// arg to an empty value. pflag reads "-f=" as the value "=", and takes "-f"
+// alone to be followed by its value, so the value is passed as a separate
+// argument.
+func emptyShortValue(arg string) []string {
+	return []string{arg, ""}
+}
@@ -0,0 +1,125 @@
+// Package pollhelper contains the loop shared by the watching sources that
+// re-read their values whenever triggered (e.g. on a ticker), reporting a new
+// value whenever it changes.
+package pollhelper
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/vimeo/dials"
+)
+
+// ReadFunc reads a source's current value.
+type ReadFunc func(ctx context.Context, t *dials.Type) (reflect.Value, error)
+
+// WaitFunc blocks until the value should be re-read, returning true, or
+// until ctx is canceled (or the poller should otherwise stop), returning
+// false.
+type WaitFunc func(ctx context.Context) bool
+
+// Every returns a WaitFunc that waits for the next tick of a ticker with
+// the given period. The ticker is started by the first call, and stopped once
+// ctx is canceled.
+func Every(period time.Duration) WaitFunc {
+	var ticker *time.Ticker
+	return func(ctx context.Context) bool {
+		if ticker == nil {
+			ticker = time.NewTicker(period)
+		}
+		select {
+		case <-ticker.C:
+			return true
+		case <-ctx.Done():
+			ticker.Stop()
+			return false
+		}
+	}
+}
+
+// Poller re-reads a source's value whenever triggered, reporting the new
+// value if it differs (according to reflect.DeepEqual) from the previous one,
+// and reporting any errors (retaining the previous value).
+//
+// Its Value method should be called by the source's Value method, so the
+// value Config reads immediately before calling Watch is the one polling
+// starts from, rather than reading it again (which may be expensive, and
+// could produce a different value from the one the View reflects).
+//
+// The zero-value is ready to use. A Poller must not be copied after first
+// use.
+type Poller struct {
+	mu       sync.Mutex
+	lastType reflect.Type
+	lastVal  reflect.Value
+}
+
+// Value calls read, recording the value it returns (if successful) as the
+// one to start polling from.
+func (p *Poller) Value(ctx context.Context, t *dials.Type, read ReadFunc) (reflect.Value, error) {
+	v, err := read(ctx, t)
+	if err != nil {
+		return v, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.lastType, p.lastVal = t.Type(), v
+	return v, nil
+}
+
+// initialValue returns the value most recently recorded by Value for t,
+// falling back to calling read if there isn't one (e.g. if Watch was
+// called without a preceding call to Value).
+func (p *Poller) initialValue(ctx context.Context, t *dials.Type, read ReadFunc) (reflect.Value, error) {
+	p.mu.Lock()
+	lastType, lastVal := p.lastType, p.lastVal
+	p.mu.Unlock()
+	if lastType == t.Type() {
+		return lastVal, nil
+	}
+	return read(ctx, t)
+}
+
+// Start starts a background goroutine which calls read each time wait
+// returns true, reporting new values and errors via args, until wait
+// returns false. wg is incremented while the goroutine is running. Errors
+// from read are not reported once ctx is canceled (since they're most
+// likely caused by the cancellation); the goroutine exits instead.
+func (p *Poller) Start(
+	ctx context.Context,
+	t *dials.Type,
+	args dials.WatchArgs,
+	wg *sync.WaitGroup,
+	wait WaitFunc,
+	read ReadFunc,
+) error {
+	lastVal, err := p.initialValue(ctx, t, read)
+	if err != nil {
+		return err
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for wait(ctx) {
+			newVal, err := read(ctx, t)
+			if err != nil {
+				if ctx.Err() != nil {
+					// killed because we're shutting down
+					return
+				}
+				args.ReportError(ctx, err)
+				continue
+			}
+			if reflect.DeepEqual(lastVal.Interface(), newVal.Interface()) {
+				// nothing we care about changed
+				continue
+			}
+			lastVal = newVal
+			args.ReportNewValue(ctx, newVal)
+		}
+	}()
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/vimeo/dials"
+	"github.com/vimeo/dials/common"
 )
 
 // StringSource gets data from a string
@@ -15,6 +16,12 @@ type StringSource struct {
 }
 
 var _ dials.Source = (*StringSource)(nil)
+var _ dials.NamedSource = (*StringSource)(nil)
+
+// SourceName implements dials.NamedSource, returning common.StaticSourceName.
+func (s *StringSource) SourceName() string {
+	return common.StaticSourceName
+}
 
 // Value ...
 func (s *StringSource) Value(_ context.Context, t *dials.Type) (reflect.Value, error) {
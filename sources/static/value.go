@@ -0,0 +1,207 @@
+package static
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/vimeo/dials"
+	"github.com/vimeo/dials/common"
+	"github.com/vimeo/dials/ptrify"
+)
+
+// ValueSource provides a fixed, programmatically-constructed value as a
+// Source, allowing it to be layered between other Sources.
+//
+// Since the value is of the (un-pointerified) config type, fields with their
+// zero values are treated as unset, and don't override values from earlier
+// Sources (or the defaults passed to Config).
+type ValueSource struct {
+	val reflect.Value
+	// explicitZeros indicates that fields with zero values are set too
+	// (see NewExplicitValueSource)
+	explicitZeros bool
+}
+
+var _ dials.Source = (*ValueSource)(nil)
+var _ dials.NamedSource = (*ValueSource)(nil)
+
+// NewValueSource constructs a ValueSource from v, which should be a struct of
+// the config type, or a pointer to one.
+// v is deep-copied, so it may be freely modified after NewValueSource returns.
+// (reference cycles within v are not supported)
+func NewValueSource(v any) (*ValueSource, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, fmt.Errorf("nil %s passed to NewValueSource", rv.Type())
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("NewValueSource requires a struct or pointer to a struct; got %T", v)
+	}
+	return &ValueSource{val: deepCopy(rv)}, nil
+}
+
+// NewExplicitValueSource is like NewValueSource, but fields of v with zero
+// values are set too (rather than treated as unset), so they override values
+// from earlier Sources (and the defaults passed to Config). This allows
+// e.g. a bool to be set back to false.
+//
+// Nil pointers, slices, maps and interfaces are still treated as unset.
+func NewExplicitValueSource(v any) (*ValueSource, error) {
+	vs, err := NewValueSource(v)
+	if err != nil {
+		return nil, err
+	}
+	vs.explicitZeros = true
+	return vs, nil
+}
+
+// SourceName implements dials.NamedSource, returning common.StaticSourceName.
+func (s *ValueSource) SourceName() string {
+	return common.StaticSourceName
+}
+
+// Value returns a deep copy of the value passed to NewValueSource, converted
+// to the pointerified type t.
+func (s *ValueSource) Value(_ context.Context, t *dials.Type) (reflect.Value, error) {
+	out := reflect.New(t.Type())
+	if err := fillPtrified(out.Elem(), s.val, s.explicitZeros); err != nil {
+		return reflect.Value{}, err
+	}
+	return out, nil
+}
+
+// fillPtrified populates the pointerified struct dst with the non-zero fields
+// of src (which should be of the type dst was derived from), or with all its
+// non-nil fields if explicitZeros is true.
+func fillPtrified(dst, src reflect.Value, explicitZeros bool) error {
+	dt := dst.Type()
+	for i := 0; i < dt.NumField(); i++ {
+		dsf := dt.Field(i)
+		ssf, ok := src.Type().FieldByName(dsf.Name)
+		if !ok || len(ssf.Index) != 1 || ptrify.OmitField(ssf) {
+			return fmt.Errorf("field %q of %s not present in value of type %s",
+				dsf.Name, dt, src.Type())
+		}
+		sv := src.Field(ssf.Index[0])
+		if sv.IsZero() && (!explicitZeros || isNilable(sv.Kind())) {
+			continue
+		}
+		if err := fillPtrifiedField(dst.Field(i), sv, explicitZeros); err != nil {
+			return fmt.Errorf("failed to set field %q: %w", dsf.Name, err)
+		}
+	}
+	return nil
+}
+
+// isNilable indicates whether the zero value of kind k is nil.
+func isNilable(k reflect.Kind) bool {
+	switch k {
+	case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map, reflect.Chan, reflect.Func:
+		return true
+	default:
+		return false
+	}
+}
+
+func fillPtrifiedField(dst, sv reflect.Value, explicitZeros bool) error {
+	// pointerification devirtualizes non-nil interface values to their
+	// concrete type.
+	if sv.Kind() == reflect.Interface && dst.Kind() != reflect.Interface {
+		sv = sv.Elem()
+	}
+	dt := dst.Type()
+	switch {
+	case sv.Type().AssignableTo(dt):
+		// slices, maps, interfaces and non-struct pointers retain their
+		// types.
+		dst.Set(deepCopy(sv))
+	case dt.Kind() == reflect.Ptr && sv.Type().AssignableTo(dt.Elem()):
+		// most types are wrapped in a pointer.
+		dst.Set(reflect.New(dt.Elem()))
+		dst.Elem().Set(deepCopy(sv))
+	case dt.Kind() == reflect.Ptr && dt.Elem().Kind() == reflect.Struct:
+		// nested structs (or pointers to structs) are recursively
+		// pointerified.
+		if sv.Kind() == reflect.Ptr {
+			sv = sv.Elem()
+		}
+		if sv.Kind() != reflect.Struct {
+			return fmt.Errorf("type %s is not compatible with %s", sv.Type(), dt)
+		}
+		dst.Set(reflect.New(dt.Elem()))
+		return fillPtrified(dst.Elem(), sv, explicitZeros)
+	case dt.Kind() == reflect.Ptr && dt.Elem().Kind() == reflect.Array && sv.Kind() == reflect.Array:
+		// the elements of arrays of structs are pointerified
+		if dt.Elem().Len() != sv.Len() {
+			return fmt.Errorf("type %s is not compatible with %s", sv.Type(), dt)
+		}
+		dst.Set(reflect.New(dt.Elem()))
+		for i := 0; i < sv.Len(); i++ {
+			ev := sv.Index(i)
+			if ev.Kind() == reflect.Ptr && ev.IsNil() {
+				continue
+			}
+			if err := fillPtrifiedField(dst.Elem().Index(i), ev, explicitZeros); err != nil {
+				return fmt.Errorf("failed to set element %d: %w", i, err)
+			}
+		}
+	default:
+		return fmt.Errorf("type %s is not compatible with %s", sv.Type(), dt)
+	}
+	return nil
+}
+
+// deepCopy returns a copy of v that doesn't share any pointers, slices or
+// maps with v. Unexported fields are copied shallowly.
+func deepCopy(v reflect.Value) reflect.Value {
+	out := reflect.New(v.Type()).Elem()
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return out
+		}
+		out.Set(reflect.New(v.Type().Elem()))
+		out.Elem().Set(deepCopy(v.Elem()))
+	case reflect.Interface:
+		if v.IsNil() {
+			return out
+		}
+		out.Set(deepCopy(v.Elem()))
+	case reflect.Slice:
+		if v.IsNil() {
+			return out
+		}
+		out.Set(reflect.MakeSlice(v.Type(), v.Len(), v.Len()))
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(deepCopy(v.Index(i)))
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(deepCopy(v.Index(i)))
+		}
+	case reflect.Map:
+		if v.IsNil() {
+			return out
+		}
+		out.Set(reflect.MakeMapWithSize(v.Type(), v.Len()))
+		iter := v.MapRange()
+		for iter.Next() {
+			out.SetMapIndex(deepCopy(iter.Key()), deepCopy(iter.Value()))
+		}
+	case reflect.Struct:
+		out.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if !v.Type().Field(i).IsExported() {
+				continue
+			}
+			out.Field(i).Set(deepCopy(v.Field(i)))
+		}
+	default:
+		out.Set(v)
+	}
+	return out
+}
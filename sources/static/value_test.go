@@ -0,0 +1,108 @@
+package static
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vimeo/dials"
+)
+
+func TestValueSource(t *testing.T) {
+	t.Parallel()
+
+	type inner struct {
+		Timeout time.Duration
+		Hosts   []string
+	}
+	type config struct {
+		Name    string
+		Port    int
+		Enabled bool
+		IP      net.IP
+		Labels  map[string]string
+		Inner   inner
+		Opt     *inner
+		Skipped string `dials:"-"`
+	}
+
+	v := config{
+		Port:   8080,
+		IP:     net.ParseIP("10.0.0.1"),
+		Labels: map[string]string{"team": "core"},
+		Inner:  inner{Hosts: []string{"a", "b"}},
+		Opt:    &inner{Timeout: time.Second},
+	}
+	src, err := NewValueSource(&v)
+	require.NoError(t, err)
+
+	// mutations after construction must not affect the Source
+	v.Labels["team"] = "other"
+	v.Inner.Hosts[0] = "z"
+	v.Opt.Timeout = time.Minute
+
+	d, err := dials.Config(context.Background(), &config{
+		Name:    "default",
+		Port:    80,
+		Enabled: true,
+		Skipped: "skipped",
+	}, src)
+	require.NoError(t, err)
+	assert.Equal(t, &config{
+		// zero values don't override the defaults
+		Name:    "default",
+		Port:    8080,
+		Enabled: true,
+		IP:      net.ParseIP("10.0.0.1"),
+		Labels:  map[string]string{"team": "core"},
+		Inner:   inner{Hosts: []string{"a", "b"}},
+		Opt:     &inner{Timeout: time.Second},
+		Skipped: "skipped",
+	}, d.View())
+}
+
+func TestNewValueSourceErrors(t *testing.T) {
+	t.Parallel()
+
+	_, nilErr := NewValueSource((*struct{})(nil))
+	assert.EqualError(t, nilErr, "nil *struct {} passed to NewValueSource")
+
+	_, intErr := NewValueSource(3)
+	assert.EqualError(t, intErr, "NewValueSource requires a struct or pointer to a struct; got int")
+}
+
+func TestExplicitValueSource(t *testing.T) {
+	t.Parallel()
+
+	type inner struct {
+		Timeout time.Duration
+		Hosts   []string
+	}
+	type config struct {
+		Name    string
+		Port    int
+		Enabled bool
+		Inner   inner
+		Opt     *inner
+	}
+
+	src, err := NewExplicitValueSource(&config{Name: "svc"})
+	require.NoError(t, err)
+
+	d, err := dials.Config(context.Background(), &config{
+		Port:    80,
+		Enabled: true,
+		Inner:   inner{Timeout: time.Second, Hosts: []string{"a"}},
+		Opt:     &inner{Timeout: time.Second},
+	}, src)
+	require.NoError(t, err)
+	assert.Equal(t, &config{
+		// zero values override the defaults, except for nil ones
+		Name:  "svc",
+		Inner: inner{Hosts: []string{"a"}},
+		Opt:   &inner{Timeout: time.Second},
+	}, d.View())
+}
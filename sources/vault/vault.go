@@ -0,0 +1,218 @@
+// Package vault provides a dials.Source that reads configuration (usually
+// secrets) from a HashiCorp Vault KV secret.
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/vimeo/dials"
+	"github.com/vimeo/dials/common"
+	"github.com/vimeo/dials/tagformat"
+	"github.com/vimeo/dials/tagformat/caseconversion"
+	"github.com/vimeo/dials/transform"
+)
+
+// VaultTagName is the name of the struct tag used to override the secret
+// data key for a field.
+const VaultTagName = "dialsvault"
+
+// ErrPermissionDenied should be returned (or wrapped) by Client
+// implementations when Vault rejects a read due to the token's policies
+// (an HTTP 403 response), so it can be reported clearly.
+var ErrPermissionDenied = errors.New("permission denied")
+
+// Secret is the response to a read from Vault.
+type Secret struct {
+	// Data is the secret's data. For KV version 2 secrets, this is the
+	// envelope containing "data" and "metadata" keys; the envelope is
+	// unwrapped automatically.
+	Data map[string]any
+	// LeaseDuration is the duration of the secret's lease (zero if it
+	// doesn't have one, as is the case for KV secrets)
+	LeaseDuration time.Duration
+}
+
+// Client is the subset of the Vault API used by Source. It's an interface
+// so that dials doesn't depend on the Vault API package (and so it can be
+// faked in tests). An adapter for the github.com/hashicorp/vault/api client
+// looks something like
+//
+//	type vaultClient struct{ c *api.Client }
+//
+//	func (v vaultClient) Read(ctx context.Context, path string) (*vault.Secret, error) {
+//		s, err := v.c.Logical().ReadWithContext(ctx, path)
+//		var respErr *api.ResponseError
+//		if errors.As(err, &respErr) && respErr.StatusCode == http.StatusForbidden {
+//			return nil, fmt.Errorf("%w: %s", vault.ErrPermissionDenied, err)
+//		}
+//		if err != nil || s == nil {
+//			return nil, err
+//		}
+//		return &vault.Secret{
+//			Data:          s.Data,
+//			LeaseDuration: time.Duration(s.LeaseDuration) * time.Second,
+//		}, nil
+//	}
+type Client interface {
+	// Read reads the secret at path, returning a nil Secret (and nil
+	// error) if there's no secret there.
+	Read(ctx context.Context, path string) (*Secret, error)
+}
+
+// TokenOption may be attached to the context passed to dials.Config (or
+// Dials.Reload) with dials.WithSourceOptions to read secrets with a
+// specific Vault token (e.g. one obtained for the current request), rather
+// than whatever token the Client is configured with. The Client must
+// implement TokenClient for this to work.
+//
+//	ctx = dials.WithSourceOptions(ctx, vault.TokenOption.Option(token))
+var TokenOption = dials.NewSourceOptionKey[string]("vault token")
+
+// TokenClient is an optional interface for Clients that can read secrets
+// with an explicitly-provided token (see TokenOption). With the
+// github.com/hashicorp/vault/api client, this might clone the client and
+// call SetToken on the clone before reading.
+type TokenClient interface {
+	Client
+	ReadWithToken(ctx context.Context, path, token string) (*Secret, error)
+}
+
+// Source implements dials.Source, reading the secret at Path (for KV
+// version 2 secrets engines, this includes the "data/" component, e.g.
+// "secret/data/myapp") and mapping each of the secret's data keys to a
+// field.
+//
+// Keys are matched against the (flattened) field's `dialsvault` tag if
+// present, then its `dials` tag, and finally its name converted to
+// lower_snake_case. When matching, hyphens are treated as underscores and
+// case is ignored. Keys that don't correspond to any field are ignored.
+// Values are parsed the same way as environment variables (see
+// transform.StringCastingMangler), after formatting any non-string values
+// (e.g. numbers) with the %v verb.
+type Source struct {
+	Client Client
+	Path   string
+}
+
+var _ dials.Source = (*Source)(nil)
+var _ dials.NamedSource = (*Source)(nil)
+
+// SourceName implements dials.NamedSource, returning
+// common.VaultSourceName.
+func (s *Source) SourceName() string {
+	return common.VaultSourceName
+}
+
+// Value reads the secret from Vault and fills in the config struct.
+func (s *Source) Value(ctx context.Context, t *dials.Type) (reflect.Value, error) {
+	v, _, err := s.read(ctx, t)
+	return v, err
+}
+
+// read reads the secret, returning the populated value and the secret's
+// lease duration.
+func (s *Source) read(ctx context.Context, t *dials.Type) (reflect.Value, time.Duration, error) {
+	secret, err := s.readSecret(ctx)
+	if errors.Is(err, ErrPermissionDenied) {
+		return reflect.Value{}, 0, fmt.Errorf(
+			"permission denied reading Vault secret at %q (check the token's policies): %w", s.Path, err)
+	}
+	if err != nil {
+		return reflect.Value{}, 0, fmt.Errorf("failed to read Vault secret at %q: %w", s.Path, err)
+	}
+	if secret == nil {
+		return reflect.Value{}, 0, fmt.Errorf("no Vault secret found at %q", s.Path)
+	}
+
+	data := unwrapKVv2(secret.Data)
+	byKey := make(map[string]string, len(data))
+	for k, v := range data {
+		byKey[normalizeKey(k)] = stringify(v)
+	}
+
+	flattenMangler := transform.NewFlattenMangler(common.DialsTagName, caseconversion.EncodeUpperCamelCase, caseconversion.EncodeUpperCamelCase)
+	reformatTagMangler := tagformat.NewTagReformattingMangler(common.DialsTagName, caseconversion.DecodeGoTags, caseconversion.EncodeLowerSnakeCase)
+	tagCopyingMangler := &tagformat.TagCopyingMangler{SrcTag: common.DialsTagName, NewTag: VaultTagName}
+	stringCastingMangler := &transform.StringCastingMangler{}
+	aliasMangler := transform.NewAliasMangler(common.DialsTagName, VaultTagName)
+	tfmr := transform.NewTransformer(t.Type(), aliasMangler, flattenMangler, reformatTagMangler, tagCopyingMangler, stringCastingMangler)
+
+	val, err := tfmr.Translate()
+	if err != nil {
+		return reflect.Value{}, 0, err
+	}
+
+	valType := val.Type()
+	for i := 0; i < val.NumField(); i++ {
+		sf := valType.Field(i)
+		key := sf.Tag.Get(VaultTagName)
+		if key == "" || key == "-" {
+			continue
+		}
+		if dataVal, ok := byKey[normalizeKey(key)]; ok {
+			// The StringCastingMangler has converted all the fields to
+			// *string.
+			val.Field(i).Set(reflect.ValueOf(&dataVal))
+		}
+	}
+
+	out, err := tfmr.ReverseTranslate(val)
+	if err != nil {
+		return reflect.Value{}, 0, err
+	}
+	return out, secret.LeaseDuration, nil
+}
+
+// readSecret reads the secret at Path, using the token from TokenOption if
+// one's set in ctx.
+func (s *Source) readSecret(ctx context.Context) (*Secret, error) {
+	token, ok := TokenOption.Value(ctx)
+	if !ok {
+		return s.Client.Read(ctx, s.Path)
+	}
+	tc, ok := s.Client.(TokenClient)
+	if !ok {
+		return nil, fmt.Errorf("a token was provided with TokenOption, but Client type %T does not implement TokenClient", s.Client)
+	}
+	return tc.ReadWithToken(ctx, s.Path, token)
+}
+
+// unwrapKVv2 returns the inner data map if data is a KV version 2 envelope
+// (with "data" and "metadata" keys), and data otherwise.
+func unwrapKVv2(data map[string]any) map[string]any {
+	if len(data) != 2 {
+		return data
+	}
+	inner, ok := data["data"].(map[string]any)
+	if !ok {
+		return data
+	}
+	if _, ok := data["metadata"].(map[string]any); !ok {
+		return data
+	}
+	return inner
+}
+
+// stringify formats a value from the secret's data as a string
+func stringify(v any) string {
+	switch sv := v.(type) {
+	case string:
+		return sv
+	case json.Number:
+		return sv.String()
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// normalizeKey converts a data key or tag value into the form used for
+// matching them against each other.
+func normalizeKey(key string) string {
+	return strings.ToLower(strings.ReplaceAll(key, "-", "_"))
+}
@@ -0,0 +1,194 @@
+package vault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vimeo/dials"
+)
+
+type fakeClient struct {
+	mu     sync.Mutex
+	secret *Secret
+	err    error
+}
+
+func (f *fakeClient) set(s *Secret, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.secret, f.err = s, err
+}
+
+func (f *fakeClient) Read(ctx context.Context, path string) (*Secret, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.secret, f.err
+}
+
+type dbConfig struct {
+	User     string
+	Password string `dialsvault:"db-password"`
+}
+
+type testConfig struct {
+	APIKey   string `dials:"api_key"`
+	MaxConns int
+	Database dbConfig
+	Unset    string
+}
+
+func kvV2(data map[string]any) *Secret {
+	return &Secret{Data: map[string]any{
+		"data":     data,
+		"metadata": map[string]any{"version": 3},
+	}}
+}
+
+func TestSource(t *testing.T) {
+	t.Parallel()
+	for name, itbl := range map[string]struct {
+		secret *Secret
+		err    error
+		expect *testConfig
+		errMsg string
+	}{
+		"kv_v2": {
+			secret: kvV2(map[string]any{
+				"api_key":       "abc123",
+				"max_conns":     float64(10),
+				"database_user": "svc",
+				"DB-PASSWORD":   "hunter2",
+				"unrelated":     "ignored",
+			}),
+			expect: &testConfig{
+				APIKey:   "abc123",
+				MaxConns: 10,
+				Database: dbConfig{User: "svc", Password: "hunter2"},
+				Unset:    "default",
+			},
+		},
+		"kv_v1": {
+			secret: &Secret{Data: map[string]any{"api_key": "abc123"}},
+			expect: &testConfig{APIKey: "abc123", Unset: "default"},
+		},
+		"not_found": {
+			errMsg: `no Vault secret found at "secret/data/myapp"`,
+		},
+		"permission_denied": {
+			err:    fmt.Errorf("%w: Code: 403", ErrPermissionDenied),
+			errMsg: `permission denied reading Vault secret at "secret/data/myapp" (check the token's policies)`,
+		},
+		"other_error": {
+			err:    errors.New("connection refused"),
+			errMsg: `failed to read Vault secret at "secret/data/myapp": connection refused`,
+		},
+	} {
+		tbl := itbl
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			client := &fakeClient{secret: tbl.secret, err: tbl.err}
+			d, err := dials.Config(context.Background(), &testConfig{Unset: "default"},
+				&Source{Client: client, Path: "secret/data/myapp"})
+			if tbl.errMsg != "" {
+				assert.ErrorContains(t, err, tbl.errMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tbl.expect, d.View())
+		})
+	}
+}
+
+// tokenClient is a fakeClient that only returns the secret when read with
+// the expected token.
+type tokenClient struct {
+	fakeClient
+	token string
+}
+
+func (c *tokenClient) ReadWithToken(ctx context.Context, path, token string) (*Secret, error) {
+	if token != c.token {
+		return nil, fmt.Errorf("%w: bad token %q", ErrPermissionDenied, token)
+	}
+	return c.Read(ctx, path)
+}
+
+func TestSourceTokenOption(t *testing.T) {
+	t.Parallel()
+	secret := &Secret{Data: map[string]any{"api_key": "abc123"}}
+	src := &Source{Client: &tokenClient{fakeClient: fakeClient{secret: secret}, token: "s.good"}, Path: "secret/myapp"}
+
+	ctx := dials.WithSourceOptions(context.Background(), TokenOption.Option("s.good"))
+	d, err := dials.Config(ctx, &testConfig{}, src)
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", d.View().APIKey)
+
+	badCtx := dials.WithSourceOptions(context.Background(), TokenOption.Option("s.bad"))
+	_, err = dials.Config(badCtx, &testConfig{}, src)
+	assert.ErrorContains(t, err, `permission denied reading Vault secret at "secret/myapp"`)
+
+	// without the option, the client's own token is used
+	_, err = dials.Config(context.Background(), &testConfig{}, src)
+	require.NoError(t, err)
+
+	// a client that can't use the token is an error, rather than silently
+	// reading with the wrong token
+	_, err = dials.Config(ctx, &testConfig{}, &Source{Client: &fakeClient{secret: secret}, Path: "secret/myapp"})
+	assert.ErrorContains(t, err, "Client type *vault.fakeClient does not implement TokenClient")
+}
+
+func TestWatchingSource(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := &fakeClient{secret: kvV2(map[string]any{"api_key": "abc123"})}
+	src := &WatchingSource{
+		Source:       Source{Client: client, Path: "secret/data/myapp"},
+		PollInterval: time.Millisecond,
+	}
+	watchErrs := make(chan error, 1)
+	d, err := dials.Params[testConfig]{
+		OnWatchedError: func(ctx context.Context, err error, oldConfig, newConfig *testConfig) {
+			select {
+			case watchErrs <- err:
+			default:
+			}
+		},
+	}.Config(ctx, &testConfig{}, src)
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", d.View().APIKey)
+
+	// the secret is rotated
+	client.set(kvV2(map[string]any{"api_key": "def456"}), nil)
+	assert.Equal(t, "def456", (<-d.Events()).APIKey)
+
+	// the token loses access
+	client.set(nil, ErrPermissionDenied)
+	assert.ErrorIs(t, <-watchErrs, ErrPermissionDenied)
+	assert.Equal(t, "def456", d.View().APIKey)
+
+	cancel()
+	src.WG.Wait()
+}
+
+func TestWatchingSourceNextRead(t *testing.T) {
+	t.Parallel()
+	w := &WatchingSource{PollInterval: time.Minute}
+	assert.Equal(t, time.Minute, w.nextRead(0))
+
+	// leased secrets are re-read at about two-thirds of the lease, less
+	// up to 10% jitter
+	lease := 30 * time.Minute
+	for i := 0; i < 100; i++ {
+		wait := w.nextRead(lease)
+		assert.LessOrEqual(t, wait, 20*time.Minute)
+		assert.GreaterOrEqual(t, wait, 18*time.Minute)
+	}
+}
@@ -0,0 +1,102 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/vimeo/dials"
+)
+
+// WatchingSource wraps Source, implementing dials.Watcher by re-reading the
+// secret before its lease expires (so rotated secrets are picked up), or every
+// PollInterval if the secret has no lease (as is the case for KV secrets).
+// A new value is reported if anything relevant changed. Errors (including
+// permission errors, e.g. after the token's policies change) are reported
+// via dials.WatchArgs, and the previous value is retained.
+type WatchingSource struct {
+	Source
+	// PollInterval is the period between re-reads of secrets without a
+	// lease (or if a read fails). It must be positive.
+	PollInterval time.Duration
+	// WG is incremented while the background goroutine is running.
+	WG sync.WaitGroup
+}
+
+var _ dials.Source = (*WatchingSource)(nil)
+var _ dials.Watcher = (*WatchingSource)(nil)
+var _ dials.NamedSource = (*WatchingSource)(nil)
+
+// Watch starts a background goroutine that re-reads the secret. The
+// goroutine exits when ctx is canceled.
+func (w *WatchingSource) Watch(ctx context.Context, t *dials.Type, args dials.WatchArgs) error {
+	if w.PollInterval <= 0 {
+		return fmt.Errorf("non-positive PollInterval %s", w.PollInterval)
+	}
+	// Value is called by Config immediately before Watch, so this is what
+	// the View currently reflects.
+	lastVal, lease, err := w.read(ctx, t)
+	if err != nil {
+		return err
+	}
+
+	w.WG.Add(1)
+	go w.watchLoop(ctx, t, args, lastVal, lease)
+	return nil
+}
+
+// leaseJitterFraction is the maximum fraction of the wait before re-reading
+// a leased secret that's randomly subtracted, so that many watchers of the
+// same secret don't re-read in lockstep.
+const leaseJitterFraction = 0.1
+
+// nextRead returns how long to wait before re-reading a secret with the
+// specified lease duration. As with Vault's own lifetime watcher, leased
+// secrets are re-read after about two-thirds of the lease has elapsed (less
+// some jitter), leaving time to retry before the lease expires.
+func (w *WatchingSource) nextRead(lease time.Duration) time.Duration {
+	if lease <= 0 {
+		return w.PollInterval
+	}
+	wait := float64(lease) * 2 / 3
+	wait -= wait * leaseJitterFraction * rand.Float64()
+	return time.Duration(wait)
+}
+
+func (w *WatchingSource) watchLoop(
+	ctx context.Context,
+	t *dials.Type,
+	args dials.WatchArgs,
+	lastVal reflect.Value,
+	lease time.Duration,
+) {
+	defer w.WG.Done()
+
+	timer := time.NewTimer(w.nextRead(lease))
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return
+		}
+
+		newVal, newLease, err := w.read(ctx, t)
+		if err != nil {
+			args.ReportError(ctx, err)
+			timer.Reset(w.PollInterval)
+			continue
+		}
+		timer.Reset(w.nextRead(newLease))
+		if reflect.DeepEqual(lastVal.Interface(), newVal.Interface()) {
+			// nothing we care about changed
+			continue
+		}
+		lastVal = newVal
+		args.ReportNewValue(ctx, newVal)
+	}
+}
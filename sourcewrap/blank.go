@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"sort"
 	"sync"
+	"sync/atomic"
 
 	"github.com/vimeo/dials"
 )
@@ -27,6 +29,12 @@ import (
 // `params.Config` will call `Verify()` before the Blank-wrapped source has a
 // chance to have its inner Source updated. Therefore, this initial call to
 // `Verify()` will only have data provided by the non-Blank-wrapped source.
+//
+// Since a Blank needs the dials.WatchArgs to propagate the value of the
+// Source passed to SetSource, it's always watched (it never reports itself as
+// a dials.StaticSource). Call Done once the Blank will no longer be updated
+// so Dials can shut down its goroutine for handling updates (if there are no
+// other watching sources).
 type Blank struct {
 	inner dials.Source
 	mu    sync.Mutex
@@ -35,10 +43,15 @@ type Blank struct {
 	watchCtx context.Context
 	wa       dials.WatchArgs
 	t        *dials.Type
+	// name of the inner source (if it implements dials.NamedSource), kept
+	// separately from mu, since SetSource holds mu while the new value is
+	// composed.
+	name atomic.Value
 }
 
 var _ dials.Source = (*Blank)(nil)
 var _ dials.Watcher = (*Blank)(nil)
+var _ dials.NamedSource = (*Blank)(nil)
 
 func (b *Blank) getInner() dials.Source {
 	b.mu.Lock()
@@ -56,6 +69,13 @@ func (b *Blank) Value(ctx context.Context, t *dials.Type) (reflect.Value, error)
 	return reflect.New(t.Type()), nil
 }
 
+// SourceName implements the dials.NamedSource interface, delegating to the
+// wrapped source if present. (returning the empty string otherwise)
+func (b *Blank) SourceName() string {
+	name, _ := b.name.Load().(string)
+	return name
+}
+
 // Watch implements the dials.Watcher interface.
 // It is necessary so calls to SetSource can notify the containing View of a new value.
 func (b *Blank) Watch(ctx context.Context,
@@ -112,6 +132,7 @@ func (b *Blank) SetSource(ctx context.Context, s dials.Source) error {
 		return &wrappedErr{prefix: "initial call to Value failed: ", err: err}
 	}
 	b.inner = s
+	b.name.Store(sourceName(s))
 	if newValErr := b.wa.BlockingReportNewValue(ctx, v); newValErr != nil {
 		return fmt.Errorf("failed to propagate change: %w", newValErr)
 	}
@@ -125,6 +146,99 @@ func (b *Blank) SetSource(ctx context.Context, s dials.Source) error {
 	return nil
 }
 
+// Swap pairs a Blank with the Source to set as its inner Source, for use with
+// SetSources.
+type Swap struct {
+	Blank  *Blank
+	Source dials.Source
+}
+
+// SetSources is like calling SetSource on each of several Blanks, but the
+// new Sources' values are applied as a single unit: the configuration is
+// re-stacked (and verified) once with all of them, and if that fails, none of
+// the Blanks' inner Sources are replaced (and none of the new values are
+// retained). This is useful for sources whose values must change together,
+// such as files containing a TLS certificate and its key.
+//
+// All the Blanks must be distinct, and must have been passed to the same
+// call to dials.Config (not wrapped in another Source). As with SetSource,
+// once a Watcher is set as a Blank's inner Source, it cannot be replaced.
+func SetSources(ctx context.Context, swaps ...Swap) error {
+	// lock the Blanks in a consistent order, so concurrent calls with
+	// overlapping Blanks can't deadlock.
+	sorted := append([]Swap(nil), swaps...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return reflect.ValueOf(sorted[i].Blank).Pointer() < reflect.ValueOf(sorted[j].Blank).Pointer()
+	})
+	for i, sw := range sorted {
+		if sw.Blank == nil {
+			return fmt.Errorf("cannot pass a nil Blank to SetSources")
+		}
+		if i > 0 && sorted[i-1].Blank == sw.Blank {
+			return fmt.Errorf("the same Blank was passed to SetSources more than once")
+		}
+	}
+	for _, sw := range sorted {
+		sw.Blank.mu.Lock()
+		defer sw.Blank.mu.Unlock()
+	}
+
+	vals := make([]dials.SourceValue, len(swaps))
+	for i, sw := range swaps {
+		b := sw.Blank
+		if b.t == nil {
+			return fmt.Errorf("the Blank at index %d hasn't been passed to dials.Config", i)
+		}
+		if sw.Source == nil {
+			return fmt.Errorf("cannot pass a nil source to SetSources (index %d) with type %s",
+				i, b.t.Type())
+		}
+		if _, isWatcher := b.inner.(dials.Watcher); isWatcher {
+			return fmt.Errorf("disallowed attempt to replace Watcher Source: %T", b.inner)
+		}
+		v, err := sw.Source.Value(ctx, b.t)
+		if err != nil {
+			return &wrappedErr{prefix: fmt.Sprintf("initial call to Value failed (index %d): ", i), err: err}
+		}
+		vals[i] = dials.SourceValue{Args: b.wa, Value: v}
+	}
+
+	// The new names must be in place while the values are composed, so
+	// fields restricted to the new sources (see the dialssource tag) are
+	// retained.
+	oldNames := make([]string, len(swaps))
+	for i, sw := range swaps {
+		oldNames[i] = sw.Blank.SourceName()
+		sw.Blank.name.Store(sourceName(sw.Source))
+	}
+	if newValErr := dials.ReportNewValues(ctx, vals...); newValErr != nil {
+		for i, sw := range swaps {
+			sw.Blank.name.Store(oldNames[i])
+		}
+		return fmt.Errorf("failed to propagate change: %w", newValErr)
+	}
+
+	for i, sw := range swaps {
+		b := sw.Blank
+		b.inner = sw.Source
+		if w, ok := sw.Source.(dials.Watcher); ok {
+			if wErr := w.Watch(b.watchCtx, b.t, b.wa); wErr != nil {
+				return &wrappedErr{prefix: fmt.Sprintf("call to Watch failed (index %d): ", i), err: wErr}
+			}
+		}
+	}
+	return nil
+}
+
+// sourceName returns the name of s if it implements dials.NamedSource, and
+// the empty string otherwise.
+func sourceName(s dials.Source) string {
+	if ns, ok := s.(dials.NamedSource); ok {
+		return ns.SourceName()
+	}
+	return ""
+}
+
 // Done instructs Dials that this Blank source will never be used in a watching
 // mode ever again (allowing Dials to shutdown a goroutine once all other
 // sources implementing Watcher have called Done()).
@@ -3,9 +3,12 @@ package sourcewrap
 import (
 	"context"
 	"errors"
+	"fmt"
 	"reflect"
 	"runtime"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/vimeo/dials"
 )
@@ -274,3 +277,247 @@ func TestBlankSourceErrorWatcher(t *testing.T) {
 		}
 	}
 }
+
+// trivialStaticWatchingSource implements dials.Watcher, but reports that
+// it's static, so it's never watched.
+type trivialStaticWatchingSource struct {
+	trivalCountingWatchingSource
+}
+
+var _ dials.StaticSource = (*trivialStaticWatchingSource)(nil)
+
+func (t *trivialStaticWatchingSource) Static() bool {
+	return true
+}
+
+func TestBlankSourceDoneWithStaticSource(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	b := Blank{}
+	type basicConf struct {
+		A int
+	}
+	static := trivialStaticWatchingSource{}
+	d, err := dials.Config(ctx, &basicConf{A: 3}, &static, &b)
+	if err != nil {
+		t.Fatalf("failed to construct View: %s", err)
+	}
+	if static.watchcalled {
+		t.Errorf("Watch called on static source")
+	}
+
+	triv := trivalCountingSource{}
+	if setErr := b.SetSource(ctx, &triv); setErr != nil {
+		t.Errorf("b.SetSource() failed with trivial nop impl: %s", setErr)
+	}
+	if *d.View() != (basicConf{A: 3}) {
+		t.Errorf("unexpected new config: got %+v", *d.View())
+	}
+
+	// The Blank was the only watching source, so marking it done should
+	// shut down the monitor goroutine, after which any attempt to push a
+	// new value can only time out.
+	b.Done(ctx)
+
+	toCtx, toCancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer toCancel()
+	if setErr := b.SetSource(toCtx, &trivalCountingSource{}); !errors.Is(setErr, context.DeadlineExceeded) {
+		t.Errorf("unexpected error from SetSource after Done: %v", setErr)
+	}
+}
+
+type certConf struct {
+	Cert string
+	Key  string
+}
+
+// Verify requires that the cert and key have matching suffixes
+func (c *certConf) Verify() error {
+	if strings.TrimPrefix(c.Cert, "cert-") != strings.TrimPrefix(c.Key, "key-") {
+		return fmt.Errorf("mismatched cert %q and key %q", c.Cert, c.Key)
+	}
+	return nil
+}
+
+// fieldSource sets a single string field
+type fieldSource struct {
+	field, val string
+}
+
+func (f *fieldSource) Value(_ context.Context, typ *dials.Type) (reflect.Value, error) {
+	v := reflect.New(typ.Type())
+	val := f.val
+	v.Elem().FieldByName(f.field).Set(reflect.ValueOf(&val))
+	return v, nil
+}
+
+func TestSetSources(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	certBlank, keyBlank := Blank{}, Blank{}
+	d, err := dials.Config(ctx, &certConf{}, &certBlank, &keyBlank)
+	if err != nil {
+		t.Fatalf("failed to construct View: %s", err)
+	}
+
+	// setting either source on its own fails verification
+	if setErr := certBlank.SetSource(ctx, &fieldSource{field: "Cert", val: "cert-1"}); setErr == nil {
+		t.Errorf("unexpected success setting only the cert")
+	}
+
+	cert1, key1 := &fieldSource{field: "Cert", val: "cert-1"}, &fieldSource{field: "Key", val: "key-1"}
+	if setErr := SetSources(ctx, Swap{Blank: &certBlank, Source: cert1}, Swap{Blank: &keyBlank, Source: key1}); setErr != nil {
+		t.Fatalf("SetSources failed: %s", setErr)
+	}
+	if expected := (certConf{Cert: "cert-1", Key: "key-1"}); *d.View() != expected {
+		t.Errorf("unexpected config: got %+v; expected %+v", *d.View(), expected)
+	}
+
+	// a mismatched pair is rejected as a whole
+	setErr := SetSources(ctx,
+		Swap{Blank: &certBlank, Source: &fieldSource{field: "Cert", val: "cert-2"}},
+		Swap{Blank: &keyBlank, Source: &fieldSource{field: "Key", val: "key-3"}})
+	if setErr == nil || !strings.Contains(setErr.Error(), `mismatched cert "cert-2" and key "key-3"`) {
+		t.Errorf("unexpected error from SetSources with mismatched values: %v", setErr)
+	}
+	if expected := (certConf{Cert: "cert-1", Key: "key-1"}); *d.View() != expected {
+		t.Errorf("unexpected config after rejected swap: got %+v; expected %+v", *d.View(), expected)
+	}
+	if certBlank.getInner() != cert1 || keyBlank.getInner() != key1 {
+		t.Errorf("inner sources replaced by rejected swap")
+	}
+	// Neither new value was retained, so re-setting the cert's current
+	// value succeeds (which it wouldn't if key-3 were still in place).
+	if setErr := certBlank.SetSource(ctx, &fieldSource{field: "Cert", val: "cert-1"}); setErr != nil {
+		t.Errorf("SetSource failed after rejected swap: %s", setErr)
+	}
+
+	// Blanks from different Dials instances can't be swapped together
+	otherBlank := Blank{}
+	if _, otherErr := dials.Config(ctx, &certConf{}, &otherBlank); otherErr != nil {
+		t.Fatalf("failed to construct View: %s", otherErr)
+	}
+	setErr = SetSources(ctx,
+		Swap{Blank: &certBlank, Source: &fieldSource{field: "Cert", val: "cert-2"}},
+		Swap{Blank: &otherBlank, Source: &fieldSource{field: "Key", val: "key-2"}})
+	if setErr == nil || !strings.Contains(setErr.Error(), "different Dials instances") {
+		t.Errorf("unexpected error from SetSources across Dials instances: %v", setErr)
+	}
+
+	if setErr := SetSources(ctx, Swap{Blank: &certBlank, Source: cert1}, Swap{Blank: &certBlank, Source: cert1}); setErr == nil {
+		t.Errorf("unexpected success passing the same Blank twice")
+	}
+}
+
+type namedFieldSource struct {
+	fieldSource
+	name string
+}
+
+func (n *namedFieldSource) SourceName() string {
+	return n.name
+}
+
+func TestSetSourcesRestrictedFields(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	type restrictedConf struct {
+		Secret string `dialssource:"secrets"`
+		Other  string
+	}
+	b := Blank{}
+	d, err := dials.Config(ctx, &restrictedConf{}, &b)
+	if err != nil {
+		t.Fatalf("failed to construct View: %s", err)
+	}
+
+	if setErr := SetSources(ctx, Swap{Blank: &b, Source: &namedFieldSource{
+		fieldSource: fieldSource{field: "Secret", val: "hunter2"}, name: "secrets"}}); setErr != nil {
+		t.Fatalf("SetSources failed: %s", setErr)
+	}
+	if expected := (restrictedConf{Secret: "hunter2"}); *d.View() != expected {
+		t.Errorf("unexpected config: got %+v; expected %+v", *d.View(), expected)
+	}
+
+	// the names are restored if the new values are rejected
+	otherBlank := Blank{}
+	if _, otherErr := dials.Config(ctx, &restrictedConf{}, &otherBlank); otherErr != nil {
+		t.Fatalf("failed to construct View: %s", otherErr)
+	}
+	setErr := SetSources(ctx,
+		Swap{Blank: &b, Source: &namedFieldSource{fieldSource: fieldSource{field: "Other", val: "o"}, name: "other"}},
+		Swap{Blank: &otherBlank, Source: &fieldSource{field: "Other", val: "o"}})
+	if setErr == nil {
+		t.Fatalf("unexpected success swapping Blanks from different Dials instances")
+	}
+	if name := b.SourceName(); name != "secrets" {
+		t.Errorf("unexpected name after rejected swap: got %q; expected %q", name, "secrets")
+	}
+}
+
+func TestBlankSourceRequiredFieldsDelayedVerification(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	type reqConf struct {
+		A string `dialsrequired:"true"`
+		B string
+	}
+	b := Blank{}
+	d, err := dials.Params[reqConf]{DelayInitialVerification: true}.Config(ctx, &reqConf{B: "b"}, &b)
+	if err != nil {
+		t.Fatalf("failed to construct View with delayed verification: %s", err)
+	}
+
+	// required fields are only enforced once verification is enabled
+	if _, _, vfErr := d.EnableVerification(ctx); vfErr == nil {
+		t.Errorf("expected EnableVerification to fail with A unset")
+	}
+
+	if setErr := b.SetSource(ctx, &fieldSource{field: "A", val: "a"}); setErr != nil {
+		t.Fatalf("b.SetSource() failed: %s", setErr)
+	}
+	cfg, _, vfErr := d.EnableVerification(ctx)
+	if vfErr != nil {
+		t.Fatalf("EnableVerification failed: %s", vfErr)
+	}
+	if *cfg != (reqConf{A: "a", B: "b"}) {
+		t.Errorf("unexpected config: got %+v", *cfg)
+	}
+
+	// now that verification is enabled, values that unset A are rejected
+	if setErr := b.SetSource(ctx, &fieldSource{field: "B", val: "c"}); setErr == nil {
+		t.Errorf("expected SetSource to fail with A unset")
+	}
+	if *d.View() != (reqConf{A: "a", B: "b"}) {
+		t.Errorf("unexpected config after rejected update: got %+v", *d.View())
+	}
+}
+
+func TestBlankSourceRequiredFieldsSkippedVerification(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	type reqConf struct {
+		A string `dialsrequired:"true"`
+	}
+	b := Blank{}
+	d, err := dials.Params[reqConf]{SkipInitialVerification: true}.Config(ctx, &reqConf{}, &b)
+	if err != nil {
+		t.Fatalf("failed to construct View with skipped verification: %s", err)
+	}
+
+	// the first real re-stack enforces required fields
+	if setErr := b.SetSource(ctx, &trivalCountingSource{}); setErr == nil {
+		t.Errorf("expected SetSource to fail with A unset")
+	}
+	if setErr := b.SetSource(ctx, &fieldSource{field: "A", val: "a"}); setErr != nil {
+		t.Fatalf("b.SetSource() failed: %s", setErr)
+	}
+	if *d.View() != (reqConf{A: "a"}) {
+		t.Errorf("unexpected config: got %+v", *d.View())
+	}
+}
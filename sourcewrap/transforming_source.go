@@ -70,6 +70,39 @@ type transformingSourceNoWatch struct {
 }
 
 func (t *transformingSourceNoWatch) Value(ctx context.Context, typ *dials.Type) (reflect.Value, error) {
+	return t.value(ctx, typ, func(ctx context.Context, innerTyp *dials.Type) (reflect.Value, error) {
+		return t.src.Value(ctx, innerTyp)
+	})
+}
+
+// ValueWithWarnings implements dials.SourceWithWarnings, passing along any
+// warnings from the wrapped source (which is read with Value if it doesn't
+// implement dials.SourceWithWarnings).
+func (t *transformingSourceNoWatch) ValueWithWarnings(ctx context.Context, typ *dials.Type) (reflect.Value, []error, error) {
+	sw, ok := t.src.(dials.SourceWithWarnings)
+	if !ok {
+		v, err := t.Value(ctx, typ)
+		return v, nil, err
+	}
+	var warnings []error
+	v, err := t.value(ctx, typ, func(ctx context.Context, innerTyp *dials.Type) (reflect.Value, error) {
+		v, w, err := sw.ValueWithWarnings(ctx, innerTyp)
+		warnings = w
+		return v, err
+	})
+	if err != nil {
+		return reflect.Value{}, nil, err
+	}
+	return v, warnings, nil
+}
+
+// value translates typ, reads the wrapped source's value with read, and
+// reverse-translates the result.
+func (t *transformingSourceNoWatch) value(
+	ctx context.Context,
+	typ *dials.Type,
+	read func(context.Context, *dials.Type) (reflect.Value, error),
+) (reflect.Value, error) {
 	tfm := transform.NewTransformer(typ.Type(), t.manglers...)
 	transformedVal, transformErr := tfm.TranslateType()
 	if transformErr != nil {
@@ -77,7 +110,7 @@ func (t *transformingSourceNoWatch) Value(ctx context.Context, typ *dials.Type)
 	}
 	innerTyp := dials.NewType(transformedVal)
 
-	srcVal, srcErr := t.src.Value(ctx, innerTyp)
+	srcVal, srcErr := read(ctx, innerTyp)
 	if srcErr != nil {
 		return reflect.Value{}, &wrappedErr{prefix: "inner source failed: ", err: srcErr}
 	}
@@ -86,7 +119,15 @@ func (t *transformingSourceNoWatch) Value(ctx context.Context, typ *dials.Type)
 		return reflect.Value{}, &wrappedErr{prefix: "unmangle failed: ", err: unmangleErr}
 	}
 	return unmangledVal, nil
+}
 
+// SourceName implements dials.NamedSource, delegating to the wrapped source
+// (returning the empty string if it doesn't implement dials.NamedSource)
+func (t *transformingSourceNoWatch) SourceName() string {
+	if ns, ok := t.src.(dials.NamedSource); ok {
+		return ns.SourceName()
+	}
+	return ""
 }
 
 type wrappedWatchArgs struct {
@@ -108,6 +149,15 @@ type transformingSourceWithWatch struct {
 	src dials.Watcher
 }
 
+// Static implements dials.StaticSource, delegating to the wrapped source
+// (returning false if it doesn't implement dials.StaticSource)
+func (t *transformingSourceWithWatch) Static() bool {
+	if ss, ok := t.src.(dials.StaticSource); ok {
+		return ss.Static()
+	}
+	return false
+}
+
 func (t *transformingSourceWithWatch) Watch(ctx context.Context, typ *dials.Type, args dials.WatchArgs) error {
 	tfm := transform.NewTransformer(typ.Type(), t.manglers...)
 	transformedVal, transformErr := tfm.TranslateType()
@@ -3,10 +3,12 @@ package sourcewrap
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -62,3 +64,43 @@ func TestTransformingDecoder(t *testing.T) {
 	assert.Contains(t, theConf.Set, "b")
 	assert.Contains(t, theConf.Set, "c")
 }
+
+type jsonWarningSource struct {
+	data     string
+	warnings []error
+}
+
+func (j *jsonWarningSource) Value(ctx context.Context, t *dials.Type) (reflect.Value, error) {
+	return (&trivialJSONDecoder{}).Decode(strings.NewReader(j.data), t)
+}
+
+func (j *jsonWarningSource) ValueWithWarnings(ctx context.Context, t *dials.Type) (reflect.Value, []error, error) {
+	v, err := j.Value(ctx, t)
+	return v, j.warnings, err
+}
+
+func TestTransformingSourceWarnings(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	type conf struct {
+		Set map[string]struct{}
+	}
+
+	badField := errors.New("bad field")
+	src := NewTransformingSource(
+		&jsonWarningSource{data: `{"Set": ["a", "b"]}`, warnings: []error{badField}},
+		&transform.SetSliceMangler{})
+
+	var warnings []error
+	d, err := dials.Params[conf]{
+		OnSourceWarning: func(ctx context.Context, s dials.Source, w []error) {
+			assert.Same(t, src, s)
+			warnings = w
+		},
+	}.Config(ctx, &conf{}, src)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]struct{}{"a": {}, "b": {}}, d.View().Set)
+	assert.Equal(t, []error{badField}, warnings)
+}
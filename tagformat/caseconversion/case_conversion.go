@@ -23,7 +23,8 @@ type EncodeCasingFunc func(DecodedIdentifier) string
 // DecodedIdentifier is an slice of lowercase words (e.g., []string{"test",
 // "string"}) produced by a DecodeCasingFunc, which can be encoded by an
 // EncodeCasingFunc into a string in the specified case (e.g., with
-// EncodeLowerCamelCase, "testString").
+// EncodeLowerCamelCase, "testString"). (DecodeCasePreservingGoCamelCase is
+// the exception, retaining the case of each word)
 type DecodedIdentifier []string
 
 func decodeCamelCase(typeName, s string) (DecodedIdentifier, error) {
@@ -108,20 +109,67 @@ func lastCharOfInitialismAtEOS(s string, i int) bool {
 	return i+rl == len(s) && unicode.IsUpper(r)
 }
 
+// DigitBoundaryMode controls whether transitions between letters and digits
+// start new words when decoding Go-style identifiers (see
+// DecodeGoCamelCaseWithDigitBoundaries and DecodeGoTagsWithDigitBoundaries).
+// Modes may be combined with bitwise-or.
+type DigitBoundaryMode uint8
+
+const (
+	// DigitBoundaryNone keeps digits in the same word as any adjacent
+	// letters (e.g. "Ipv4Addr" decodes to "ipv4", "addr"). This is the
+	// behavior of DecodeGoCamelCase and DecodeGoTags.
+	DigitBoundaryNone DigitBoundaryMode = 0
+	// DigitBoundaryAfterLetter starts a new word at a digit following a
+	// letter (e.g. "Ipv4Addr" decodes to "ipv", "4", "addr").
+	DigitBoundaryAfterLetter DigitBoundaryMode = 1 << 0
+	// DigitBoundaryBeforeLetter starts a new word at a letter following a
+	// digit (e.g. "ipv4addr" decodes to "ipv4", "addr").
+	DigitBoundaryBeforeLetter DigitBoundaryMode = 1 << 1
+)
+
+// digitBoundary, as used in decodeGoCamelCase, detects when the indexed rune
+// starts a new word according to the digit-boundary mode.
+func digitBoundary(s string, i int, mode DigitBoundaryMode) bool {
+	if mode == DigitBoundaryNone || i == 0 {
+		return false
+	}
+	r1, _ := utf8.DecodeRuneInString(s[i:])
+	r2, _ := utf8.DecodeLastRuneInString(s[:i])
+	return (mode&DigitBoundaryAfterLetter != 0 && unicode.IsDigit(r1) && unicode.IsLetter(r2)) ||
+		(mode&DigitBoundaryBeforeLetter != 0 && unicode.IsLetter(r1) && unicode.IsDigit(r2))
+}
+
 // decodeGoCamelCase splits up a string in a slice of lower cased sub-string by
-// splitting after fully capitalized acronyms and after the characters that
-// signal word boundaries as specified in the passed isWordBoundary function
-func decodeGoCamelCase(s string, isWordBoundary func(rune) bool) (DecodedIdentifier, error) {
+// splitting after fully capitalized acronyms, at any letter/digit transitions
+// selected by digitMode, and after the characters that signal word boundaries
+// as specified in the passed isWordBoundary function. If preserveCase is
+// set, the sub-strings retain their original case instead.
+func decodeGoCamelCase(s string, digitMode DigitBoundaryMode, preserveCase bool, isWordBoundary func(rune) bool) (DecodedIdentifier, error) {
+	lower := strings.ToLower
+	initialisms := extractInitialisms
+	if preserveCase {
+		lower = func(w string) string { return w }
+		initialisms = func(w string) []string {
+			// the word was entirely upper-case, so restore the
+			// case of the extracted initialisms
+			words := extractInitialisms(w)
+			for i, iw := range words {
+				words[i] = strings.ToUpper(iw)
+			}
+			return words
+		}
+	}
 	words := []string{}
 	lastBoundary := 0
 	for i, char := range s {
-		if firstCharOfInitialism(s, i) || firstCharAfterInitialism(s, i) || isWordBoundary(char) {
+		if firstCharOfInitialism(s, i) || firstCharAfterInitialism(s, i) || digitBoundary(s, i, digitMode) || isWordBoundary(char) {
 			if lastBoundary < i {
 				word := s[lastBoundary:i]
 				if word == strings.ToUpper(word) {
-					words = append(words, extractInitialisms(word)...)
+					words = append(words, initialisms(word)...)
 				} else {
-					words = append(words, strings.ToLower(word))
+					words = append(words, lower(word))
 				}
 			}
 			switch {
@@ -135,7 +183,7 @@ func decodeGoCamelCase(s string, isWordBoundary func(rune) bool) (DecodedIdentif
 			if lastBoundary < i {
 				word := s[lastBoundary:]
 				if word == strings.ToUpper(word) {
-					words = append(words, extractInitialisms(word)...)
+					words = append(words, initialisms(word)...)
 					return words, nil
 				}
 			}
@@ -143,8 +191,8 @@ func decodeGoCamelCase(s string, isWordBoundary func(rune) bool) (DecodedIdentif
 		}
 	}
 
-	if last := strings.ToLower(s[lastBoundary:]); len(last) > 0 {
-		words = append(words, strings.ToLower(s[lastBoundary:]))
+	if last := lower(s[lastBoundary:]); len(last) > 0 {
+		words = append(words, last)
 	}
 
 	return words, nil
@@ -157,10 +205,35 @@ func decodeGoCamelCase(s string, isWordBoundary func(rune) bool) (DecodedIdentif
 // fully capitalized acronyms (e.g., "jsonAPIDocs") into a slice of lower-cased
 // sub-strings.
 func DecodeGoCamelCase(s string) (DecodedIdentifier, error) {
+	return DecodeGoCamelCaseWithDigitBoundaries(DigitBoundaryNone)(s)
+}
+
+// DecodeGoCamelCaseWithDigitBoundaries returns a DecodeCasingFunc that
+// behaves like DecodeGoCamelCase, but additionally splits words at the
+// letter/digit transitions selected by mode.
+func DecodeGoCamelCaseWithDigitBoundaries(mode DigitBoundaryMode) DecodeCasingFunc {
+	return func(s string) (DecodedIdentifier, error) {
+		if !token.IsIdentifier(s) {
+			return nil, fmt.Errorf("only characters of the Letter category or '_' can appear in strings")
+		}
+		return decodeGoCamelCase(s, mode, false, func(r rune) bool {
+			return r == '_'
+		})
+	}
+}
+
+// DecodeCasePreservingGoCamelCase splits Go identifiers at the same word
+// boundaries as DecodeGoCamelCase, but, unlike the other DecodeCasingFuncs,
+// leaves each word in its original case (e.g. "MaxRetries" decodes to "Max",
+// "Retries" and "jsonAPIDocs" to "json", "API", "Docs"). It's intended for
+// use with EncodeCasePreservingCamelCase, which rejoins the words verbatim;
+// other EncodeCasingFuncs may produce unexpected results, as they assume
+// lower-case words.
+func DecodeCasePreservingGoCamelCase(s string) (DecodedIdentifier, error) {
 	if !token.IsIdentifier(s) {
 		return nil, fmt.Errorf("only characters of the Letter category or '_' can appear in strings")
 	}
-	return decodeGoCamelCase(s, func(r rune) bool {
+	return decodeGoCamelCase(s, DigitBoundaryNone, true, func(r rune) bool {
 		return r == '_'
 	})
 }
@@ -168,9 +241,18 @@ func DecodeGoCamelCase(s string) (DecodedIdentifier, error) {
 // DecodeGoTags decodes CamelCase, snake_case, and kebab-case strings with fully
 // capitalized acronyms into a slice of lower cased strings.
 func DecodeGoTags(s string) (DecodedIdentifier, error) {
-	return decodeGoCamelCase(s, func(r rune) bool {
-		return r == '_' || r == '-'
-	})
+	return DecodeGoTagsWithDigitBoundaries(DigitBoundaryNone)(s)
+}
+
+// DecodeGoTagsWithDigitBoundaries returns a DecodeCasingFunc that behaves
+// like DecodeGoTags, but additionally splits words at the letter/digit
+// transitions selected by mode.
+func DecodeGoTagsWithDigitBoundaries(mode DigitBoundaryMode) DecodeCasingFunc {
+	return func(s string) (DecodedIdentifier, error) {
+		return decodeGoCamelCase(s, mode, false, func(r rune) bool {
+			return r == '_' || r == '-'
+		})
+	}
 }
 
 // List from https://github.com/golang/lint/blob/master/lint.go
@@ -238,26 +320,24 @@ func DecodeKebabCase(s string) (DecodedIdentifier, error) {
 	return decodeLowerCaseWithSplitChar('-', "kebab-case", s)
 }
 
-// DecodeUpperSnakeCase decodes UPPER_SNAKE_CASE (sometimes called
-// SCREAMING_SNAKE_CASE) into a slice of lower-cased sub-strings
-func DecodeUpperSnakeCase(s string) (DecodedIdentifier, error) {
+func decodeUpperCaseWithSplitChar(splitChar rune, typeName, s string) (DecodedIdentifier, error) {
 	// ignore the size of the rune
 	r, _ := utf8.DecodeRuneInString(s)
 	if r == utf8.RuneError || unicode.IsDigit(r) {
-		return nil, fmt.Errorf("converting case of %q: UPPER_SNAKE_CASE strings can't start with characters of the Decimal Digit category", s)
+		return nil, fmt.Errorf("converting case of %q: %s strings can't start with characters of the Decimal Digit category", s, typeName)
 	}
 
 	words := []string{}
 	lastBoundary := 0
 	for z, char := range s {
-		if char == '_' {
+		if char == splitChar {
 			// flush
 			if lastBoundary < z {
 				words = append(words, strings.ToLower(s[lastBoundary:z]))
 			}
-			lastBoundary = z + 1
+			lastBoundary = z + utf8.RuneLen(splitChar)
 		} else if (!unicode.IsLetter(char) && !unicode.IsDigit(char)) || (!unicode.IsUpper(char) && !unicode.IsDigit(char)) {
-			return nil, fmt.Errorf("converting case of %q: Only uppercase characters of the Letter category and '_' can appear in UPPER_SNAKE_CASE strings: %c in at byte-offset %d does not comply", s, char, z)
+			return nil, fmt.Errorf("converting case of %q: Only uppercase characters of the Letter category, digits, and '%c' can appear in %s strings: %c at byte-offset %d does not comply", s, splitChar, typeName, char, z)
 		}
 	}
 	// flush one last time to get the remainder of the string
@@ -267,6 +347,18 @@ func DecodeUpperSnakeCase(s string) (DecodedIdentifier, error) {
 	return words, nil
 }
 
+// DecodeUpperSnakeCase decodes UPPER_SNAKE_CASE (sometimes called
+// SCREAMING_SNAKE_CASE) into a slice of lower-cased sub-strings
+func DecodeUpperSnakeCase(s string) (DecodedIdentifier, error) {
+	return decodeUpperCaseWithSplitChar('_', "UPPER_SNAKE_CASE", s)
+}
+
+// DecodeScreamingKebabCase decodes SCREAMING-KEBAB-CASE (sometimes called
+// UPPER-KEBAB-CASE) into a slice of lower-cased sub-strings
+func DecodeScreamingKebabCase(s string) (DecodedIdentifier, error) {
+	return decodeUpperCaseWithSplitChar('-', "SCREAMING-KEBAB-CASE", s)
+}
+
 // DecodeCasePreservingSnakeCase decodes Case_Preserving_Snake_Case into a
 // slice of lower-cased sub-string
 func DecodeCasePreservingSnakeCase(s string) (DecodedIdentifier, error) {
@@ -364,7 +456,44 @@ func EncodeUpperSnakeCase(words DecodedIdentifier) string {
 	return b.String()
 }
 
+// EncodeScreamingKebabCase encodes a slice of words into SCREAMING-KEBAB-CASE
+// (AKA UPPER-KEBAB-CASE)
+func EncodeScreamingKebabCase(words DecodedIdentifier) string {
+	return strings.ToUpper(strings.Join(words, "-"))
+}
+
 // EncodeCasePreservingSnakeCase encodes a slice of words into case_Preserving_snake_case
 func EncodeCasePreservingSnakeCase(words DecodedIdentifier) string {
 	return strings.Join(words, "_")
 }
+
+// EncodeCasePreservingCamelCase concatenates a slice of words without
+// altering their case, so names decoded by DecodeCasePreservingGoCamelCase
+// are reproduced exactly (e.g. "MaxRetries" stays "MaxRetries").
+//
+// This is only lossless if the words were decoded by a case-preserving
+// DecodeCasingFunc: the other decoders lower-case every word, so their
+// output is concatenated into an all-lower-case name (e.g. "maxretries").
+// In particular, the TagReformattingMangler and FlattenMangler decode the
+// names of fields without tags with DecodeGoCamelCase, so such fields
+// should be given a tag (or, for the TagReformattingMangler, left out of
+// the reformatting, since decoders already fall back to the field name).
+func EncodeCasePreservingCamelCase(words DecodedIdentifier) string {
+	return strings.Join(words, "")
+}
+
+// RoundTripCheck decodes name with decode, re-encodes the result with encode,
+// and returns the re-encoded name along with whether it matches name. A
+// mismatch indicates that information was lost in decoding (e.g. the
+// "HTTP" initialism in "HTTPTimeout" re-encodes as "HttpTimeout" with
+// EncodeUpperCamelCase), so names derived from the decoded words may not
+// be what the author of name intended.
+// If name can't be decoded, the empty string and false are returned.
+func RoundTripCheck(decode DecodeCasingFunc, encode EncodeCasingFunc, name string) (string, bool) {
+	words, err := decode(name)
+	if err != nil {
+		return "", false
+	}
+	encoded := encode(words)
+	return encoded, encoded == name
+}
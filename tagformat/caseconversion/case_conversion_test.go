@@ -1,6 +1,7 @@
 package caseconversion
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -38,6 +39,14 @@ var decodeCases = []struct {
 	{"UPPER_SNAKE_CASE1", []string{"upper", "snake", "case1"}, DecodeUpperSnakeCase, false},
 	{"UPPER_SNAKE_CASE_U", []string{"upper", "snake", "case", "u"}, DecodeUpperSnakeCase, false},
 	{"UPPER_SNAKE_CASE_U_", []string{"upper", "snake", "case", "u"}, DecodeUpperSnakeCase, false},
+	{"UPPER_snake_CASE", []string{}, DecodeUpperSnakeCase, true},
+
+	{"SCREAMING-KEBAB-CASE", []string{"screaming", "kebab", "case"}, DecodeScreamingKebabCase, false},
+	{"1SCREAMING-KEBAB-CASE", []string{}, DecodeScreamingKebabCase, true},
+	{"SCREAMING-KEBAB-CASE1", []string{"screaming", "kebab", "case1"}, DecodeScreamingKebabCase, false},
+	{"SCREAMING-KEBAB-CASE-U-", []string{"screaming", "kebab", "case", "u"}, DecodeScreamingKebabCase, false},
+	{"SCREAMING-kebab-CASE", []string{}, DecodeScreamingKebabCase, true},
+	{"SCREAMING_KEBAB-CASE", []string{}, DecodeScreamingKebabCase, true},
 
 	{"lower_snake_case", []string{"lower", "snake", "case"}, DecodeLowerSnakeCase, false},
 	{"lower_snake_case_u", []string{"lower", "snake", "case", "u"}, DecodeLowerSnakeCase, false},
@@ -65,6 +74,14 @@ var decodeCases = []struct {
 	{"A", []string{"a"}, DecodeGoCamelCase, false},
 	{"EnvVarA", []string{"env", "var", "a"}, DecodeGoCamelCase, false},
 
+	{"MaxRetries", []string{"Max", "Retries"}, DecodeCasePreservingGoCamelCase, false},
+	{"jsonAPIDocs", []string{"json", "API", "Docs"}, DecodeCasePreservingGoCamelCase, false},
+	{"XMLJSONAPI", []string{"XML", "JSON", "API"}, DecodeCasePreservingGoCamelCase, false},
+	{"TestSOMEJSONAPI", []string{"Test", "SOMEJSONAPI"}, DecodeCasePreservingGoCamelCase, false},
+	{"decode_golangCamelCase", []string{"decode", "golang", "Camel", "Case"}, DecodeCasePreservingGoCamelCase, false},
+	{"EnvVarA", []string{"Env", "Var", "A"}, DecodeCasePreservingGoCamelCase, false},
+	{"not-an-identifier", nil, DecodeCasePreservingGoCamelCase, true},
+
 	{"jsonAPI", []string{"json", "api"}, DecodeGoTags, false},
 	{"value-3", []string{"value", "3"}, DecodeGoTags, false},
 	{"decode_golangCamelCase_try_", []string{"decode", "golang", "camel", "case", "try"}, DecodeGoTags, false},
@@ -88,6 +105,47 @@ func TestDecode(t *testing.T) {
 	}
 }
 
+func TestDecodeDigitBoundaries(t *testing.T) {
+	const both = DigitBoundaryAfterLetter | DigitBoundaryBeforeLetter
+	for _, tbl := range []struct {
+		original string
+		mode     DigitBoundaryMode
+		decoded  DecodedIdentifier
+	}{
+		{"Ipv4Addr", DigitBoundaryNone, []string{"ipv4", "addr"}},
+		{"Ipv4Addr", DigitBoundaryAfterLetter, []string{"ipv", "4", "addr"}},
+		{"Ipv4Addr", DigitBoundaryBeforeLetter, []string{"ipv4", "addr"}},
+		{"Ipv4Addr", both, []string{"ipv", "4", "addr"}},
+		{"ipv4addr", DigitBoundaryNone, []string{"ipv4addr"}},
+		{"ipv4addr", DigitBoundaryAfterLetter, []string{"ipv", "4addr"}},
+		{"ipv4addr", DigitBoundaryBeforeLetter, []string{"ipv4", "addr"}},
+		{"ipv4addr", both, []string{"ipv", "4", "addr"}},
+		{"V2Endpoint", DigitBoundaryNone, []string{"v2", "endpoint"}},
+		{"V2Endpoint", DigitBoundaryAfterLetter, []string{"v", "2", "endpoint"}},
+		{"V2Endpoint", DigitBoundaryBeforeLetter, []string{"v2", "endpoint"}},
+		{"V2Endpoint", both, []string{"v", "2", "endpoint"}},
+		{"Value3", DigitBoundaryNone, []string{"value3"}},
+		{"Value3", DigitBoundaryAfterLetter, []string{"value", "3"}},
+		{"Value3", DigitBoundaryBeforeLetter, []string{"value3"}},
+		{"Value3", both, []string{"value", "3"}},
+		{"HTTP2Server", DigitBoundaryNone, []string{"http", "2", "server"}},
+		{"HTTP2Server", both, []string{"http", "2", "server"}},
+	} {
+		tbl := tbl
+		t.Run(fmt.Sprintf("%s-%d", tbl.original, tbl.mode), func(t *testing.T) {
+			t.Parallel()
+
+			goCamel, err := DecodeGoCamelCaseWithDigitBoundaries(tbl.mode)(tbl.original)
+			require.NoError(t, err)
+			assert.Equal(t, tbl.decoded, goCamel)
+
+			goTags, err := DecodeGoTagsWithDigitBoundaries(tbl.mode)(tbl.original)
+			require.NoError(t, err)
+			assert.Equal(t, tbl.decoded, goTags)
+		})
+	}
+}
+
 var encodeCases = []struct {
 	decoded     DecodedIdentifier
 	encoded     string
@@ -103,8 +161,12 @@ var encodeCases = []struct {
 	{[]string{}, "", EncodeLowerSnakeCase},
 	{[]string{"upper", "snake", "case"}, "UPPER_SNAKE_CASE", EncodeUpperSnakeCase},
 	{[]string{}, "", EncodeUpperSnakeCase},
+	{[]string{"screaming", "kebab", "case"}, "SCREAMING-KEBAB-CASE", EncodeScreamingKebabCase},
+	{[]string{}, "", EncodeScreamingKebabCase},
 	{[]string{"case", "PRESERVING", "Snake"}, "case_PRESERVING_Snake", EncodeCasePreservingSnakeCase},
 	{[]string{}, "", EncodeCasePreservingSnakeCase},
+	{[]string{"case", "Preserving", "CAMEL"}, "casePreservingCAMEL", EncodeCasePreservingCamelCase},
+	{[]string{}, "", EncodeCasePreservingCamelCase},
 }
 
 func TestEncode(t *testing.T) {
@@ -138,3 +200,36 @@ func TestExtractInitialisms(t *testing.T) {
 		})
 	}
 }
+
+func TestRoundTripCheck(t *testing.T) {
+	t.Parallel()
+	for _, tbl := range []struct {
+		name    string
+		decode  DecodeCasingFunc
+		encode  EncodeCasingFunc
+		encoded string
+		ok      bool
+	}{
+		{"HTTPTimeout", DecodeGoCamelCase, EncodeUpperCamelCase, "HttpTimeout", false},
+		{"HttpTimeout", DecodeGoCamelCase, EncodeUpperCamelCase, "HttpTimeout", true},
+		{"jsonAPI", DecodeGoCamelCase, EncodeLowerCamelCase, "jsonApi", false},
+		{"jsonApi", DecodeGoCamelCase, EncodeLowerCamelCase, "jsonApi", true},
+		// lower-cased words survive snake case unchanged
+		{"json_api", DecodeLowerSnakeCase, EncodeLowerSnakeCase, "json_api", true},
+		{"TestSOMEJSONAPI", DecodeGoCamelCase, EncodeUpperCamelCase, "TestSomejsonapi", false},
+		{"UCCase", DecodeUpperCamelCase, EncodeUpperCamelCase, "UCCase", true},
+		{"kebab-case", DecodeKebabCase, EncodeKebabCase, "kebab-case", true},
+		{"kebab-case-", DecodeKebabCase, EncodeKebabCase, "kebab-case", false},
+		{"1kebab", DecodeKebabCase, EncodeKebabCase, "", false},
+		// case-preserving decoding and encoding is lossless
+		{"MaxRetries", DecodeCasePreservingGoCamelCase, EncodeCasePreservingCamelCase, "MaxRetries", true},
+		{"HTTPTimeout", DecodeCasePreservingGoCamelCase, EncodeCasePreservingCamelCase, "HTTPTimeout", true},
+		{"jsonAPI", DecodeCasePreservingGoCamelCase, EncodeCasePreservingCamelCase, "jsonAPI", true},
+		// but the other decoders lower-case the words
+		{"MaxRetries", DecodeGoCamelCase, EncodeCasePreservingCamelCase, "maxretries", false},
+	} {
+		encoded, ok := RoundTripCheck(tbl.decode, tbl.encode, tbl.name)
+		assert.Equal(t, tbl.encoded, encoded, tbl.name)
+		assert.Equal(t, tbl.ok, ok, tbl.name)
+	}
+}
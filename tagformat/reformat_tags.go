@@ -17,6 +17,9 @@ type TagReformattingMangler struct {
 	tag              string
 	decodeCasingFunc caseconversion.DecodeCasingFunc
 	encodeCasingFunc caseconversion.EncodeCasingFunc
+	// shouldReformat, if non-nil, indicates whether a field's tag should
+	// be reformatted.
+	shouldReformat func(sf reflect.StructField) bool
 }
 
 // NewTagReformattingMangler constructs a new TagReformattingMangler that can
@@ -30,9 +33,47 @@ func NewTagReformattingMangler(tagName string,
 	}
 }
 
+// NewFilteredTagReformattingMangler constructs a TagReformattingMangler that
+// only reformats the tags of fields for which shouldReformat returns true;
+// all other fields are left untouched (including their tags, so untagged
+// fields remain untagged). See SkipMapFields for a common predicate.
+//
+// Note that the predicate sees the fields as produced by any manglers
+// preceding this one in the Transformer. If a FlattenMangler runs first,
+// nested struct fields have already been flattened into the top-level struct
+// (with tags holding the combined, already-encoded names), so the predicate
+// is evaluated against those flattened fields. If this mangler runs before a
+// FlattenMangler, the predicate is evaluated for each field of each nested
+// struct as the Transformer recurses, and the FlattenMangler then combines
+// the (possibly reformatted) tags.
+func NewFilteredTagReformattingMangler(tagName string,
+	dec caseconversion.DecodeCasingFunc, enc caseconversion.EncodeCasingFunc,
+	shouldReformat func(sf reflect.StructField) bool) *TagReformattingMangler {
+	return &TagReformattingMangler{
+		tag:              tagName,
+		decodeCasingFunc: dec,
+		encodeCasingFunc: enc,
+		shouldReformat:   shouldReformat,
+	}
+}
+
+// SkipMapFields is a predicate for NewFilteredTagReformattingMangler which
+// excludes map-valued fields (including pointers to maps), so their tags are
+// retained verbatim.
+func SkipMapFields(sf reflect.StructField) bool {
+	t := sf.Type
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() != reflect.Map
+}
+
 // Mangle is called for every field in a struct, and returns the value
 // unchanged other than replacing the specified tag.
 func (k *TagReformattingMangler) Mangle(sf reflect.StructField) ([]reflect.StructField, error) {
+	if k.shouldReformat != nil && !k.shouldReformat(sf) {
+		return []reflect.StructField{sf}, nil
+	}
 	nameVal := sf.Tag.Get(k.tag)
 	dcf := k.decodeCasingFunc
 	if nameVal == "" {
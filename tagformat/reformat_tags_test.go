@@ -6,6 +6,7 @@ import (
 
 	"github.com/vimeo/dials/common"
 	"github.com/vimeo/dials/tagformat/caseconversion"
+	"github.com/vimeo/dials/transform"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -32,3 +33,29 @@ func TestNoTag(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, `dials:"field_name"`, string(newSFSlice[0].Tag))
 }
+
+func TestFilteredTagReformatting(t *testing.T) {
+	type config struct {
+		ListenAddr string            `dials:"listenAddr"`
+		Labels     map[string]string `dials:"myLabels"`
+		Limits     *map[string]int
+		Count      int
+	}
+	trm := NewFilteredTagReformattingMangler(common.DialsTagName,
+		caseconversion.DecodeLowerCamelCase, caseconversion.EncodeKebabCase, SkipMapFields)
+	tfmr := transform.NewTransformer(reflect.TypeOf(config{}), trm)
+	val, err := tfmr.Translate()
+	require.NoError(t, err)
+
+	expected := map[string]string{
+		"ListenAddr": "listen-addr",
+		"Labels":     "myLabels",
+		"Limits":     "",
+		"Count":      "count",
+	}
+	for name, tag := range expected {
+		sf, ok := val.Type().FieldByName(name)
+		require.True(t, ok, name)
+		assert.Equal(t, tag, sf.Tag.Get(common.DialsTagName), name)
+	}
+}
@@ -1,12 +1,17 @@
 package transform
 
 import (
+	"fmt"
 	"reflect"
 )
 
 // AnonymousFlattenMangler hoists the fields from the types of anonymous
 // struct-fields into the parent type. (working around decoders/sources that
 // are unaware of anonymous fields)
+// Pointer-to-struct embedded fields (e.g. `*Base`) are dereferenced and
+// flattened the same way, as are any anonymous fields nested within the
+// embedded types. On Unmangle, a pointer embed is allocated if any of its
+// hoisted fields are set, and left nil otherwise.
 // Note: this mangler is unaware of TextUnmarshaler implementations (it's tricky to do right when flattening).
 // It should be combined with the TextUnmarshalerMangler if the prefered
 // handling is to mask the other fields in that struct with the TextUnmarshaler
@@ -27,6 +32,11 @@ func (a AnonymousFlattenMangler) Mangle(sf reflect.StructField) ([]reflect.Struc
 	// anonymous/embedded fields can only be interfaces, pointers and structs
 	switch sf.Type.Kind() {
 	case reflect.Pointer:
+		if sf.Type.Elem().Kind() != reflect.Struct {
+			// there's nothing to promote from a pointer to a
+			// non-struct type
+			return []reflect.StructField{sf}, nil
+		}
 		// recurse with the pointer stripped off
 		sfInner := sf
 		sfInner.Type = sf.Type.Elem()
@@ -39,6 +49,18 @@ func (a AnonymousFlattenMangler) Mangle(sf reflect.StructField) ([]reflect.Struc
 				// skip unexported fields
 				continue
 			}
+			if innerField.Anonymous {
+				// Hoist the fields of nested embedded structs
+				// all the way up, since the transformer only
+				// recurses into the types of the fields we
+				// return, and wouldn't flatten them.
+				hoisted, err := a.Mangle(innerField)
+				if err != nil {
+					return nil, err
+				}
+				out = append(out, hoisted...)
+				continue
+			}
 			out = append(out, innerField)
 		}
 
@@ -52,16 +74,43 @@ func (a AnonymousFlattenMangler) Mangle(sf reflect.StructField) ([]reflect.Struc
 }
 
 // bool return value indicates whether all fields are nil (and as such, a nil value should be returned for pointer-types)
-func (a AnonymousFlattenMangler) unmangleStruct(sf reflect.StructField, fvs []FieldValueTuple) (reflect.Value, bool) {
+func (a AnonymousFlattenMangler) unmangleStruct(sf reflect.StructField, fvs []FieldValueTuple) (reflect.Value, bool, error) {
 	out := reflect.New(sf.Type).Elem()
 	if len(fvs) == 0 {
 		// no fields made it, just return out.
-		return out, true
+		return out, true, nil
 	}
 	fvsIdx := 0
 	allNil := true
-	for i := 0; i < sf.Type.NumField(); i++ {
+	for i := 0; i < sf.Type.NumField() && fvsIdx < len(fvs); i++ {
 		oft := sf.Type.Field(i)
+		if !oft.IsExported() {
+			continue
+		}
+		if oft.Anonymous {
+			// nested embedded field: figure out how many fields
+			// Mangle hoisted out of it and reassemble it from
+			// those.
+			hoisted, mangleErr := a.Mangle(oft)
+			if mangleErr != nil {
+				return reflect.Value{}, false, mangleErr
+			}
+			if fvsIdx+len(hoisted) > len(fvs) {
+				return reflect.Value{}, false, fmt.Errorf(
+					"embedded field %q of %s: expected %d fields, only %d remaining",
+					oft.Name, sf.Type, len(hoisted), len(fvs)-fvsIdx)
+			}
+			v, unmangleErr := a.Unmangle(oft, fvs[fvsIdx:fvsIdx+len(hoisted)])
+			if unmangleErr != nil {
+				return reflect.Value{}, false, unmangleErr
+			}
+			out.Field(i).Set(v)
+			if !v.IsZero() {
+				allNil = false
+			}
+			fvsIdx += len(hoisted)
+			continue
+		}
 		if oft.Name == fvs[fvsIdx].Field.Name {
 			out.Field(i).Set(fvs[fvsIdx].Value)
 			switch fvs[fvsIdx].Value.Kind() {
@@ -78,7 +127,7 @@ func (a AnonymousFlattenMangler) unmangleStruct(sf reflect.StructField, fvs []Fi
 			fvsIdx++
 		}
 	}
-	return out, allNil
+	return out, allNil, nil
 }
 
 // Unmangle is called for every source-field->mangled-field
@@ -96,13 +145,23 @@ func (a AnonymousFlattenMangler) Unmangle(sf reflect.StructField, fvs []FieldVal
 		// It's a pointer. check for nil; strip off the pointer and recurse
 		msf := sf
 		msf.Type = sf.Type.Elem()
-		v, allNil := a.unmangleStruct(msf, fvs)
+		if msf.Type.Kind() != reflect.Struct {
+			// pointer to a non-struct type; nothing was hoisted
+			return fvs[0].Value, nil
+		}
+		v, allNil, err := a.unmangleStruct(msf, fvs)
+		if err != nil {
+			return reflect.Value{}, err
+		}
 		if allNil {
 			return reflect.Zero(sf.Type), nil
 		}
 		return v.Addr(), nil
 	case reflect.Struct:
-		out, _ := a.unmangleStruct(sf, fvs)
+		out, _, err := a.unmangleStruct(sf, fvs)
+		if err != nil {
+			return reflect.Value{}, err
+		}
 		return out, nil
 	default:
 		// not a struct-typed anonymous field, just forward up the chain
@@ -187,3 +187,96 @@ func TestTopLevelAnonymousFlattenWithNils(t *testing.T) {
 		}
 	}
 }
+
+type PtrEmbedInner struct {
+	Deep string `dials:"deep"`
+}
+
+type PtrEmbedBase struct {
+	Port int `dials:"port"`
+	*PtrEmbedInner
+	hidden bool
+}
+
+func TestAnonymousFlattenPointerEmbeds(t *testing.T) {
+	t.Parallel()
+	type Config struct {
+		*PtrEmbedBase
+		Name string `dials:"name"`
+	}
+
+	typeInstance := ptrify.Pointerify(reflect.TypeOf(Config{}), reflect.ValueOf(Config{}))
+
+	for _, itbl := range []struct {
+		name string
+		// set maps flattened field names to values to set
+		set       map[string]any
+		expectNil bool
+		expect    PtrEmbedBase
+	}{
+		{
+			name:      "nothing_set",
+			set:       map[string]any{"Name": "fimbat"},
+			expectNil: true,
+		},
+		{
+			name:   "outer_only",
+			set:    map[string]any{"Port": 8080},
+			expect: PtrEmbedBase{Port: 8080},
+		},
+		{
+			name:   "nested_only",
+			set:    map[string]any{"Deep": "down"},
+			expect: PtrEmbedBase{PtrEmbedInner: &PtrEmbedInner{Deep: "down"}},
+		},
+		{
+			name:   "both",
+			set:    map[string]any{"Port": 8080, "Deep": "down"},
+			expect: PtrEmbedBase{Port: 8080, PtrEmbedInner: &PtrEmbedInner{Deep: "down"}},
+		},
+	} {
+		tbl := itbl
+		t.Run(tbl.name, func(t *testing.T) {
+			t.Parallel()
+			tfmr := NewTransformer(typeInstance, AnonymousFlattenMangler{})
+			val, err := tfmr.Translate()
+			require.NoError(t, err)
+
+			names := make([]string, val.NumField())
+			for i := range names {
+				names[i] = val.Type().Field(i).Name
+			}
+			assert.Equal(t, []string{"Port", "Deep", "Name"}, names)
+			assert.Equal(t, "deep", val.Type().Field(1).Tag.Get(common.DialsTagName))
+
+			for name, v := range tbl.set {
+				sv := reflect.New(reflect.TypeOf(v))
+				sv.Elem().Set(reflect.ValueOf(v))
+				val.FieldByName(name).Set(sv)
+			}
+
+			revVal, revErr := tfmr.ReverseTranslate(val)
+			require.NoError(t, revErr)
+
+			base := revVal.FieldByName("PtrEmbedBase")
+			if tbl.expectNil {
+				assert.True(t, base.IsNil())
+				return
+			}
+			require.False(t, base.IsNil())
+			port := base.Elem().FieldByName("Port")
+			if tbl.expect.Port == 0 {
+				assert.True(t, port.IsNil())
+			} else {
+				assert.Equal(t, tbl.expect.Port, port.Elem().Interface())
+			}
+			inner := base.Elem().FieldByName("PtrEmbedInner")
+			if tbl.expect.PtrEmbedInner == nil {
+				assert.True(t, inner.IsNil())
+			} else {
+				require.False(t, inner.IsNil())
+				assert.Equal(t, tbl.expect.Deep, inner.Elem().FieldByName("Deep").Elem().Interface())
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package transform
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// DialsEnumTag is the name of the struct tag containing the comma-separated
+// set of values that string-typed fields are restricted to when using the
+// EnumMangler. (e.g. `dialsenum:"debug,info,warn,error"`)
+const DialsEnumTag = "dialsenum"
+
+// EnumMangler validates string fields (and elements of string-slice fields)
+// against the set of allowed values in their `dialsenum` struct tag,
+// returning an error from Unmangle (naming the field and the allowed
+// values) if a populated value isn't in the set. Comparisons are
+// case-sensitive, and whitespace around each allowed value in the tag is
+// ignored.
+//
+// Fields that have been through the FlattenMangler are identified by their
+// full path (e.g. "Logging.Level") in errors.
+//
+// The zero-value is ready to use.
+type EnumMangler struct{}
+
+// enumValues parses the `dialsenum` tag value into the allowed set
+func enumValues(tagVal string) []string {
+	vals := strings.Split(tagVal, ",")
+	for i, v := range vals {
+		vals[i] = strings.TrimSpace(v)
+	}
+	return vals
+}
+
+func enumContains(allowed []string, s string) bool {
+	for _, a := range allowed {
+		if a == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Mangle checks that fields with a `dialsenum` tag are strings or
+// string-slices, and otherwise leaves the field unchanged.
+func (*EnumMangler) Mangle(sf reflect.StructField) ([]reflect.StructField, error) {
+	tagVal, ok := sf.Tag.Lookup(DialsEnumTag)
+	if !ok {
+		return []reflect.StructField{sf}, nil
+	}
+	switch {
+	case isStringishType(sf.Type):
+	case sf.Type.Kind() == reflect.Slice && isStringishType(sf.Type.Elem()):
+	default:
+		return nil, fmt.Errorf("%s tag on field %q of unsupported type %s; only strings and string-slices may be validated",
+			DialsEnumTag, fieldDisplayName(sf), sf.Type)
+	}
+	if strings.TrimSpace(tagVal) == "" {
+		return nil, fmt.Errorf("empty %s tag on field %q", DialsEnumTag, fieldDisplayName(sf))
+	}
+	return []reflect.StructField{sf}, nil
+}
+
+// Unmangle verifies that any populated values are in the field's allowed
+// set, returning the value unchanged if so.
+func (*EnumMangler) Unmangle(sf reflect.StructField, vs []FieldValueTuple) (reflect.Value, error) {
+	tagVal, ok := sf.Tag.Lookup(DialsEnumTag)
+	if !ok {
+		return vs[0].Value, nil
+	}
+	allowed := enumValues(tagVal)
+
+	v := vs[0].Value
+	if v.Kind() == reflect.Slice {
+		for i := 0; i < v.Len(); i++ {
+			s, set := stringVal(v.Index(i))
+			if set && !enumContains(allowed, s) {
+				return reflect.Value{}, fmt.Errorf("element %d (%q) of field %q is not one of the allowed values %q",
+					i, s, fieldDisplayName(sf), allowed)
+			}
+		}
+		return v, nil
+	}
+	if s, set := stringVal(v); set && !enumContains(allowed, s) {
+		return reflect.Value{}, fmt.Errorf("value %q for field %q is not one of the allowed values %q",
+			s, fieldDisplayName(sf), allowed)
+	}
+	return v, nil
+}
+
+// ShouldRecurse always returns true in order to walk nested structs.
+func (*EnumMangler) ShouldRecurse(reflect.StructField) bool {
+	return true
+}
@@ -0,0 +1,177 @@
+package transform
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vimeo/dials/ptrify"
+)
+
+func TestEnumValues(t *testing.T) {
+	t.Parallel()
+	for tagVal, expected := range map[string][]string{
+		"debug,info":        {"debug", "info"},
+		" fast , slow ":     {"fast", "slow"},
+		"single":            {"single"},
+		"a,\tb\n,c":         {"a", "b", "c"},
+		"with space,other ": {"with space", "other"},
+	} {
+		assert.Equal(t, expected, enumValues(tagVal), "tag %q", tagVal)
+	}
+}
+
+func TestEnumMangler(t *testing.T) {
+	type logging struct {
+		Level string `dialsenum:"debug,info,warn,error"`
+	}
+	type config struct {
+		Mode    string   `dialsenum:" fast , slow "`
+		Region  *string  `dialsenum:"us-east,US-West"`
+		Outputs []string `dialsenum:"stdout,stderr"`
+		Free    string
+		Logging logging
+	}
+
+	strPtr := func(s string) *string { return &s }
+
+	cases := map[string]struct {
+		populate    func(v reflect.Value)
+		flatten     bool
+		expectedErr string
+		expected    config
+	}{
+		"tag_whitespace_trimmed": {
+			populate: func(v reflect.Value) {
+				v.FieldByName("Mode").Set(reflect.ValueOf(strPtr("fast")))
+				v.FieldByName("Outputs").Set(reflect.ValueOf([]string{"stderr"}))
+				v.FieldByName("Free").Set(reflect.ValueOf(strPtr("anything")))
+			},
+			expected: config{
+				Mode:    "fast",
+				Outputs: []string{"stderr"},
+				Free:    "anything",
+			},
+		},
+		"value_whitespace_not_trimmed": {
+			populate: func(v reflect.Value) {
+				v.FieldByName("Mode").Set(reflect.ValueOf(strPtr(" slow")))
+			},
+			expectedErr: `value " slow" for field "Mode" is not one of the allowed values ["fast" "slow"]`,
+		},
+		"case_sensitive_lower": {
+			populate: func(v reflect.Value) {
+				v.FieldByName("Region").Set(reflect.ValueOf(strPtr("us-west")))
+			},
+			expectedErr: `value "us-west" for field "Region" is not one of the allowed values ["us-east" "US-West"]`,
+		},
+		"case_sensitive_exact": {
+			populate: func(v reflect.Value) {
+				v.FieldByName("Region").Set(reflect.ValueOf(strPtr("US-West")))
+			},
+			expected: config{Region: strPtr("US-West")},
+		},
+		"empty_string_not_allowed": {
+			populate: func(v reflect.Value) {
+				v.FieldByName("Mode").Set(reflect.ValueOf(strPtr("")))
+			},
+			expectedErr: `value "" for field "Mode" is not one of the allowed values ["fast" "slow"]`,
+		},
+		"unset": {
+			populate: func(v reflect.Value) {},
+			expected: config{},
+		},
+		"disallowed_slice_element": {
+			populate: func(v reflect.Value) {
+				v.FieldByName("Outputs").Set(reflect.ValueOf([]string{"stdout", "syslog"}))
+			},
+			expectedErr: `element 1 ("syslog") of field "Outputs" is not one of the allowed values ["stdout" "stderr"]`,
+		},
+		"disallowed_nested": {
+			populate: func(v reflect.Value) {
+				in := reflect.New(v.FieldByName("Logging").Type().Elem())
+				in.Elem().Field(0).Set(reflect.ValueOf(strPtr("trace")))
+				v.FieldByName("Logging").Set(in)
+			},
+			expectedErr: `value "trace" for field "Level" is not one of the allowed values ["debug" "info" "warn" "error"]`,
+		},
+		"disallowed_flattened": {
+			flatten: true,
+			populate: func(v reflect.Value) {
+				v.FieldByName("LoggingLevel").Set(reflect.ValueOf(strPtr("trace")))
+			},
+			expectedErr: `value "trace" for field "Logging.Level" is not one of the allowed values ["debug" "info" "warn" "error"]`,
+		},
+	}
+
+	for name, c := range cases {
+		testCase := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ptrifiedType := ptrify.Pointerify(reflect.TypeOf(config{}), reflect.ValueOf(config{}))
+
+			manglers := []Mangler{}
+			if testCase.flatten {
+				manglers = append(manglers, DefaultFlattenMangler())
+			}
+			manglers = append(manglers, &EnumMangler{})
+			tfmr := NewTransformer(ptrifiedType, manglers...)
+			val, err := tfmr.Translate()
+			require.NoError(t, err)
+
+			testCase.populate(val)
+
+			unmangled, err := tfmr.ReverseTranslate(val)
+			if testCase.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), testCase.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			out := config{}
+			if mode := unmangled.FieldByName("Mode"); !mode.IsNil() {
+				out.Mode = mode.Elem().String()
+			}
+			if region := unmangled.FieldByName("Region"); !region.IsNil() {
+				out.Region = region.Interface().(*string)
+			}
+			if free := unmangled.FieldByName("Free"); !free.IsNil() {
+				out.Free = free.Elem().String()
+			}
+			out.Outputs, _ = unmangled.FieldByName("Outputs").Interface().([]string)
+			assert.Equal(t, testCase.expected, out)
+		})
+	}
+}
+
+func TestEnumManglerMangleErrors(t *testing.T) {
+	for name, c := range map[string]struct {
+		sf          reflect.StructField
+		expectedErr string
+	}{
+		"empty_tag": {
+			sf:          reflect.StructField{Name: "Foo", Type: reflect.TypeOf(""), Tag: `dialsenum:" "`},
+			expectedErr: `empty dialsenum tag on field "Foo"`,
+		},
+		"unsupported_type": {
+			sf:          reflect.StructField{Name: "Foo", Type: reflect.TypeOf(0), Tag: `dialsenum:"1,2"`},
+			expectedErr: `dialsenum tag on field "Foo" of unsupported type int; only strings and string-slices may be validated`,
+		},
+		"unsupported_type_flattened": {
+			sf: reflect.StructField{
+				Name: "LoggingCount", Type: reflect.TypeOf(0),
+				Tag: `dialsenum:"1,2" dialsfieldpath:"Logging,Count"`,
+			},
+			expectedErr: `dialsenum tag on field "Logging.Count" of unsupported type int; only strings and string-slices may be validated`,
+		},
+	} {
+		testCase := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			_, err := (&EnumMangler{}).Mangle(testCase.sf)
+			assert.EqualError(t, err, testCase.expectedErr)
+		})
+	}
+}
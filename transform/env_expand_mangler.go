@@ -0,0 +1,73 @@
+package transform
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// EnvExpandMangler expands references to environment variables (e.g.
+// "${HOME}/data" or "$HOME/data") in string values on Unmangle, using
+// os.Expand. It applies to string and *string fields (including named
+// string types), and to the elements of string-slice fields.
+//
+// Its position relative to the StringCastingMangler determines which fields
+// may contain references: placed after it, every field's value is still a
+// string, so references are expanded before values are parsed (letting
+// "${PORT}" populate an int field); placed before it, only fields that are
+// strings in the original struct are expanded.
+type EnvExpandMangler struct {
+	// Lookup returns the value of the named variable, and whether it's
+	// defined. If nil, os.LookupEnv is used.
+	Lookup func(name string) (string, bool)
+	// Strict makes Unmangle return an error (naming the field and the
+	// variables) if a value references undefined variables. Otherwise,
+	// they expand to the empty string.
+	Strict bool
+}
+
+// NewEnvExpandMangler constructs an EnvExpandMangler. A nil lookup defaults
+// to os.LookupEnv.
+func NewEnvExpandMangler(lookup func(name string) (string, bool), strict bool) *EnvExpandMangler {
+	return &EnvExpandMangler{Lookup: lookup, Strict: strict}
+}
+
+// Mangle is a no-op; all the work happens in Unmangle.
+func (e *EnvExpandMangler) Mangle(sf reflect.StructField) ([]reflect.StructField, error) {
+	return []reflect.StructField{sf}, nil
+}
+
+// expand expands the references in str, appending the names of any
+// undefined variables to undefined.
+func (e *EnvExpandMangler) expand(str string, undefined *[]string) string {
+	lookup := e.Lookup
+	if lookup == nil {
+		lookup = os.LookupEnv
+	}
+	return os.Expand(str, func(name string) string {
+		v, ok := lookup(name)
+		if !ok {
+			*undefined = append(*undefined, name)
+		}
+		return v
+	})
+}
+
+// Unmangle expands references in string values and string-slice elements.
+func (e *EnvExpandMangler) Unmangle(sf reflect.StructField, vs []FieldValueTuple) (reflect.Value, error) {
+	undefined := []string{}
+	out := mapStrings(vs[0].Value, true, func(str string) string {
+		return e.expand(str, &undefined)
+	})
+	if e.Strict && len(undefined) > 0 {
+		return reflect.Value{}, fmt.Errorf("field %q references undefined variables: %s",
+			fieldDisplayName(sf), strings.Join(undefined, ", "))
+	}
+	return out, nil
+}
+
+// ShouldRecurse always returns true in order to walk nested structs.
+func (e *EnvExpandMangler) ShouldRecurse(reflect.StructField) bool {
+	return true
+}
@@ -0,0 +1,111 @@
+package transform
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vimeo/dials/ptrify"
+)
+
+func TestEnvExpandManglerUnmangle(t *testing.T) {
+	t.Parallel()
+	type path string
+	strPtr := func(s string) *string { return &s }
+	pathPtr := func(p path) *path { return &p }
+
+	vars := map[string]string{"HOME": "/home/fim", "APP": "dials", "EMPTY": ""}
+	lookup := func(name string) (string, bool) {
+		v, ok := vars[name]
+		return v, ok
+	}
+
+	cases := map[string]struct {
+		strict   bool
+		val      any
+		expected any
+		expErr   string
+	}{
+		"string": {
+			val:      "${HOME}/data",
+			expected: "/home/fim/data",
+		},
+		"string_ptr": {
+			val:      strPtr("$HOME/$APP"),
+			expected: strPtr("/home/fim/dials"),
+		},
+		"nil_string_ptr": {
+			val:      (*string)(nil),
+			expected: (*string)(nil),
+		},
+		"named_string_ptr": {
+			val:      pathPtr("${HOME}/${APP}.yaml"),
+			expected: pathPtr("/home/fim/dials.yaml"),
+		},
+		"slice": {
+			val:      []string{"${APP}", "$HOME", "plain"},
+			expected: []string{"dials", "/home/fim", "plain"},
+		},
+		"nil_slice": {
+			val:      []string(nil),
+			expected: []string(nil),
+		},
+		"int_ptr": {
+			val:      new(int),
+			expected: new(int),
+		},
+		"undefined_lenient": {
+			val:      strPtr("${NOPE}/$APP"),
+			expected: strPtr("/dials"),
+		},
+		"defined_empty_strict": {
+			strict:   true,
+			val:      strPtr("${EMPTY}/$APP"),
+			expected: strPtr("/dials"),
+		},
+		"undefined_strict": {
+			strict: true,
+			val:    []string{"${NOPE}", "$HOME", "$ALSO_NOPE"},
+			expErr: `field "Field" references undefined variables: NOPE, ALSO_NOPE`,
+		},
+	}
+
+	for n, c := range cases {
+		testCase := c
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+			m := NewEnvExpandMangler(lookup, testCase.strict)
+			v := reflect.ValueOf(testCase.val)
+			sf := reflect.StructField{Name: "Field", Type: v.Type()}
+			out, err := m.Unmangle(sf, []FieldValueTuple{{Field: sf, Value: v}})
+			if testCase.expErr != "" {
+				assert.EqualError(t, err, testCase.expErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, out.Interface())
+		})
+	}
+}
+
+func TestEnvExpandManglerChaining(t *testing.T) {
+	t.Setenv("DIALS_TEST_PORT", "8080")
+	type config struct {
+		Port int
+	}
+	ptrifiedType := ptrify.Pointerify(reflect.TypeOf(config{}), reflect.ValueOf(config{}))
+
+	// after the StringCastingMangler, references are expanded before
+	// the value is parsed
+	tfmr := NewTransformer(ptrifiedType, &StringCastingMangler{}, &EnvExpandMangler{Strict: true})
+	val, err := tfmr.Translate()
+	require.NoError(t, err)
+
+	port := "${DIALS_TEST_PORT}"
+	val.FieldByName("Port").Set(reflect.ValueOf(&port))
+
+	out, err := tfmr.ReverseTranslate(val)
+	require.NoError(t, err)
+	assert.EqualValues(t, 8080, out.FieldByName("Port").Elem().Int())
+}
@@ -4,6 +4,7 @@ import (
 	"encoding"
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
 
 	"github.com/fatih/structtag"
@@ -21,11 +22,52 @@ const (
 // textMReflectType is a reflect.Type of TextUnmarshaler
 var textMReflectType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
 
+// binMReflectType is a reflect.Type of BinaryUnmarshaler
+var binMReflectType = reflect.TypeOf((*encoding.BinaryUnmarshaler)(nil)).Elem()
+
+// implementsUnmarshaler indicates whether t (or a pointer to t) implements
+// either TextUnmarshaler or BinaryUnmarshaler, in which case it should be
+// treated as a leaf rather than flattened or recursed into.
+func implementsUnmarshaler(t reflect.Type) bool {
+	ptrT := reflect.PointerTo(t)
+	return t.Implements(textMReflectType) || ptrT.Implements(textMReflectType) ||
+		t.Implements(binMReflectType) || ptrT.Implements(binMReflectType)
+}
+
 // FlattenMangler implements the Mangler interface
 type FlattenMangler struct {
 	tag              string
 	nameEncodeCasing caseconversion.EncodeCasingFunc
 	tagEncodeCasing  caseconversion.EncodeCasingFunc
+	// tagSeparator, if non-empty, joins the separately-encoded tags of
+	// each level of nesting (see NewFlattenManglerWithSeparator)
+	tagSeparator string
+	// omitRootPrefix, if non-empty, is the name of a synthetic outermost
+	// field whose tag is omitted from the tags of the fields nested within
+	// it (see OmitRootPrefix)
+	omitRootPrefix string
+}
+
+// FlattenOption configures optional behavior of a FlattenMangler, for use
+// with NewFlattenMangler and NewFlattenManglerWithSeparator.
+type FlattenOption func(*FlattenMangler)
+
+// OmitRootPrefix configures the FlattenMangler to leave the tag (or name) of
+// the outermost field named root out of the tags of the fields nested within
+// it. This is useful when the outermost level is a synthetic wrapper around
+// the struct whose fields should be named. e.g. with root "ConfigField", the
+// Hello and DB.Host fields of a struct wrapped in a ConfigField field are
+// tagged "hello" and "db_host" rather than "config_field_hello" and
+// "config_field_db_host".
+//
+// Only the root field's own tag is omitted: other outermost fields keep
+// theirs (so DB.Host and Cache.Host remain distinct), as do the fields
+// nested within root. The names of the flattened fields, and the paths
+// returned by FieldPath, still include root, so the value round-trips.
+func OmitRootPrefix(root string) FlattenOption {
+	return func(f *FlattenMangler) {
+		f.omitRootPrefix = root
+	}
 }
 
 // DefaultFlattenMangler returns a FlattenMangler with preset values for tag,
@@ -39,12 +81,31 @@ func DefaultFlattenMangler() *FlattenMangler {
 }
 
 // NewFlattenMangler is the constructor for FlattenMangler
-func NewFlattenMangler(tag string, nameEnc, tagEnc caseconversion.EncodeCasingFunc) *FlattenMangler {
-	return &FlattenMangler{
+func NewFlattenMangler(tag string, nameEnc, tagEnc caseconversion.EncodeCasingFunc, opts ...FlattenOption) *FlattenMangler {
+	f := &FlattenMangler{
 		tag:              tag,
 		nameEncodeCasing: nameEnc,
 		tagEncodeCasing:  tagEnc,
 	}
+	for _, o := range opts {
+		o(f)
+	}
+	return f
+}
+
+// NewFlattenManglerWithSeparator is like NewFlattenMangler, but rather than
+// applying tagEnc to the words of the full field path (in which case the
+// separator between levels of nesting is whatever tagEnc uses between
+// words), tagEnc is applied to the tag (or decoded field name) of each level
+// of nesting individually, and the results are joined with sep. e.g. with
+// kebab-case tagEnc and a "." sep, the Host field nested within a DBConn
+// field is tagged "db-conn.host" rather than "db-conn-host".
+//
+// An empty sep is equivalent to NewFlattenMangler.
+func NewFlattenManglerWithSeparator(tag string, nameEnc, tagEnc caseconversion.EncodeCasingFunc, sep string, opts ...FlattenOption) *FlattenMangler {
+	f := NewFlattenMangler(tag, nameEnc, tagEnc, opts...)
+	f.tagSeparator = sep
+	return f
 }
 
 // Mangle goes through each StructField and flattens the structure
@@ -68,11 +129,17 @@ func (f *FlattenMangler) Mangle(sf reflect.StructField) ([]reflect.StructField,
 	if tagErr != nil {
 		return out, tagErr
 	}
+	if f.omitRootPrefix != "" && sf.Name == f.omitRootPrefix {
+		// the nested fields' tags start from scratch. (tag is
+		// still used if this field isn't flattened)
+		prefixTag = nil
+	}
 
 	switch k {
 	case reflect.Struct:
 		// only flatten the struct if it doesn't implement TextUnmarshaler
-		if t.Implements(textMReflectType) || reflect.PtrTo(t).Implements(textMReflectType) {
+		// (or BinaryUnmarshaler)
+		if implementsUnmarshaler(t) {
 			break
 		}
 		fieldPrefix := []string{}
@@ -80,6 +147,11 @@ func (f *FlattenMangler) Mangle(sf reflect.StructField) ([]reflect.StructField,
 			fieldPrefix = append(fieldPrefix, sf.Name)
 		}
 		return f.flattenStruct(fieldPrefix, prefixTag, fieldPath, sf)
+	case reflect.Array:
+		if !isFlattenableArray(t) {
+			break
+		}
+		return f.flattenArray([]string{sf.Name}, prefixTag, fieldPath, t)
 	default:
 	}
 
@@ -108,6 +180,11 @@ func (f *FlattenMangler) flattenStruct(fieldPrefix, tagPrefix, fieldPath []strin
 
 	for i := 0; i < ft.NumField(); i++ {
 		nestedsf := ft.Field(i)
+		// Skip unexported fields (which only appear in structs that
+		// weren't pointerified)
+		if !nestedsf.IsExported() {
+			continue
+		}
 
 		flattenedNames := fieldPrefix
 
@@ -133,8 +210,9 @@ func (f *FlattenMangler) flattenStruct(fieldPrefix, tagPrefix, fieldPath []strin
 		nestedK, nestedT := getUnderlyingKindType(nestedsf.Type)
 		switch nestedK {
 		case reflect.Struct:
-			// don't flatten if struct implements TextUnmarshaler
-			if nestedT.Implements(textMReflectType) || reflect.PtrTo(nestedT).Implements(textMReflectType) {
+			// don't flatten if struct implements TextUnmarshaler (or
+			// BinaryUnmarshaler)
+			if implementsUnmarshaler(nestedT) {
 				break
 			}
 			flattened, err := f.flattenStruct(flattenedNames, flattenedTags, flattenedPath, nestedsf)
@@ -143,13 +221,23 @@ func (f *FlattenMangler) flattenStruct(fieldPrefix, tagPrefix, fieldPath []strin
 			}
 			out = append(out, flattened...)
 			continue
+		case reflect.Array:
+			if !isFlattenableArray(nestedT) {
+				break
+			}
+			flattened, err := f.flattenArray(flattenedNames, flattenedTags, flattenedPath, nestedT)
+			if err != nil {
+				return out, err
+			}
+			out = append(out, flattened...)
+			continue
 		default:
 
 		}
 		name := f.nameEncodeCasing(flattenedNames)
 		newSF := reflect.StructField{
 			Name:      name,
-			Type:      nestedsf.Type,
+			Type:      nilableType(nestedsf.Type),
 			Tag:       tag,
 			Anonymous: false, // un-embed the embedded fields
 		}
@@ -159,14 +247,75 @@ func (f *FlattenMangler) flattenStruct(fieldPrefix, tagPrefix, fieldPath []strin
 	return out, nil
 }
 
+// flattenArray flattens an array of structs (arrT) into fields for each
+// index, with the index included in the name, tag and field-path (e.g. the
+// Host field of the second element of Endpoints is flattened to the
+// Endpoints1Host field, with the tag "endpoints-1-host" when encoding tags as
+// kebab-case).
+//
+// The elements of pointerified arrays of structs are pointers to
+// pointerified structs (see ptrify.Pointerify), so each element is
+// unmangled as its own pointer, which is left nil if none of its fields are
+// set. Setting some of an element's fields therefore only overrides those
+// fields when composing sources, leaving the rest of the array (and element)
+// as set by lower-precedence sources.
+func (f *FlattenMangler) flattenArray(fieldPrefix, tagPrefix, fieldPath []string, arrT reflect.Type) ([]reflect.StructField, error) {
+	out := []reflect.StructField{}
+	for i := 0; i < arrT.Len(); i++ {
+		idx := strconv.Itoa(i)
+		elemSF := reflect.StructField{
+			Name: idx,
+			Type: arrT.Elem(),
+		}
+		flattened, err := f.flattenStruct(
+			append(fieldPrefix[:len(fieldPrefix):len(fieldPrefix)], idx),
+			append(tagPrefix[:len(tagPrefix):len(tagPrefix)], idx),
+			append(fieldPath[:len(fieldPath):len(fieldPath)], idx),
+			elemSF)
+		if err != nil {
+			return out, err
+		}
+		out = append(out, flattened...)
+	}
+	return out, nil
+}
+
+// isFlattenableArray indicates whether t is an array-type with struct
+// elements (or pointers to structs) that should be flattened.
+func isFlattenableArray(t reflect.Type) bool {
+	if t.Kind() != reflect.Array {
+		return false
+	}
+	k, et := getUnderlyingKindType(t.Elem())
+	return k == reflect.Struct && !implementsUnmarshaler(et)
+}
+
+// nilableType returns t if it's nilable, and a pointer to t otherwise. The
+// fields of pointerified structs are already nilable, but those of structs
+// that weren't pointerified may not be.
+func nilableType(t reflect.Type) reflect.Type {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		return t
+	default:
+		return reflect.PtrTo(t)
+	}
+}
+
 // getTag uses the tag if one already exists or creates one based on the
 // configured EncodingCasing function and fieldName. It returns the new parsed
 // StructTag, the updated slice of tags, and any error encountered
+//
+// If a tagSeparator is configured, each element of tags is an
+// already-encoded level of nesting, otherwise it's a single word.
 func (f *FlattenMangler) getTag(sf *reflect.StructField, tags, flattenedPath []string) (reflect.StructTag, []string, error) {
 	tag, ok := sf.Tag.Lookup(f.tag)
 
 	// tag already exists so use the existing tag and append to prefix tags
 	if ok {
+		if f.tagSeparator != "" {
+			tag = f.tagEncodeCasing([]string{tag})
+		}
 		tags = append(tags[:len(tags):len(tags)], tag)
 	} else if !sf.Anonymous {
 		// tag doesn't already exist so use the field name as long as it's not
@@ -177,11 +326,19 @@ func (f *FlattenMangler) getTag(sf *reflect.StructField, tags, flattenedPath []s
 		if err != nil {
 			return sf.Tag, nil, fmt.Errorf("error decoding field name %s: %w", sf.Name, err)
 		}
-		tags = append(tags[:len(tags):len(tags)], decodedField...)
-
+		if f.tagSeparator != "" {
+			tags = append(tags[:len(tags):len(tags)], f.tagEncodeCasing(decodedField))
+		} else {
+			tags = append(tags[:len(tags):len(tags)], decodedField...)
+		}
 	}
 
-	tagVal := f.tagEncodeCasing(tags)
+	var tagVal string
+	if f.tagSeparator != "" {
+		tagVal = strings.Join(tags, f.tagSeparator)
+	} else {
+		tagVal = f.tagEncodeCasing(tags)
+	}
 
 	parsedTag, parseErr := structtag.Parse(string(sf.Tag))
 	if parseErr != nil {
@@ -242,7 +399,8 @@ func populateStruct(originalVal reflect.Value, vs []FieldValueTuple, inputIndex
 	switch kind {
 	case reflect.Struct:
 		// go through each field if the struct doesn't implement TextUnmarshaler
-		if vt.Implements(textMReflectType) || reflect.PointerTo(vt).Implements(textMReflectType) {
+		// (or BinaryUnmarshaler)
+		if implementsUnmarshaler(vt) {
 			break
 		}
 		// the originalVal is a pointer and to go through the fields, we need
@@ -253,14 +411,19 @@ func populateStruct(originalVal reflect.Value, vs []FieldValueTuple, inputIndex
 		// go through each member in the struct and populate. Recurse if one of
 		// the members is a nested struct. Otherwise populate the field
 		for i := 0; i < val.NumField(); i++ {
+			if !vt.Field(i).IsExported() {
+				// unexported fields aren't flattened
+				continue
+			}
 			nestedVal := val.Field(i)
 			// remove pointers to get the underlying kind. Ignoring the type
 			kind, t := getUnderlyingKindType(nestedVal.Type())
 
 			switch kind {
-			case reflect.Struct:
-				// don't flatten if the struct implements TextUnmarshaler
-				if t.Implements(textMReflectType) || reflect.PointerTo(t).Implements(textMReflectType) {
+			case reflect.Struct, reflect.Array:
+				// don't flatten if the struct implements TextUnmarshaler (or
+				// BinaryUnmarshaler)
+				if implementsUnmarshaler(t) || (kind == reflect.Array && !isFlattenableArray(t)) {
 					break // break out of the case, still stays within the for loop
 				}
 				var err error
@@ -277,28 +440,75 @@ func populateStruct(originalVal reflect.Value, vs []FieldValueTuple, inputIndex
 				return inputIndex, false, fmt.Errorf("nested value %s under %s cannot be set", nestedVal, originalVal)
 			}
 
-			if !vs[inputIndex].Value.Type().AssignableTo(nestedVal.Type()) {
-				return inputIndex, false, fmt.Errorf("error unmangling. Expected type %s. Actual type %s", vs[inputIndex].Value.Type(), nestedVal.Type())
-			}
-			if !isNil(vs[inputIndex].Value) {
-				nestedVal.Set(vs[inputIndex].Value)
-				anyChildSet = true
+			set, err := setLeaf(nestedVal, vs[inputIndex].Value)
+			if err != nil {
+				return inputIndex, false, err
 			}
+			anyChildSet = anyChildSet || set
 			inputIndex++
 		}
 		if anyChildSet {
-			setVal.Elem().Set(val)
-			originalVal.Set(setVal)
+			setPopulated(originalVal, setVal)
+		}
+		return inputIndex, anyChildSet, nil
+	case reflect.Array:
+		if !isFlattenableArray(vt) {
+			break
+		}
+		setVal := reflect.New(vt)
+		for i := 0; i < vt.Len(); i++ {
+			var err error
+			var elemAnySet bool
+			inputIndex, elemAnySet, err = populateStruct(setVal.Elem().Index(i), vs, inputIndex)
+			if err != nil {
+				return inputIndex, false, err
+			}
+			anyChildSet = anyChildSet || elemAnySet
+		}
+		if anyChildSet {
+			setPopulated(originalVal, setVal)
 		}
 		return inputIndex, anyChildSet, nil
 	}
-	val := vs[inputIndex].Value
-	if !isNil(val) {
-		originalVal.Set(val)
-		anyChildSet = true
+	set, err := setLeaf(originalVal, vs[inputIndex].Value)
+	if err != nil {
+		return inputIndex, false, err
 	}
 	inputIndex++
-	return inputIndex, anyChildSet, nil
+	return inputIndex, set, nil
+}
+
+// setLeaf sets target to the (flattened) value val, if it's non-nil. val may
+// be a pointer to target's type if target's type isn't nilable (see
+// nilableType).
+func setLeaf(target, val reflect.Value) (bool, error) {
+	switch {
+	case val.Type().AssignableTo(target.Type()):
+		if isNil(val) {
+			return false, nil
+		}
+		target.Set(val)
+		return true, nil
+	case val.Kind() == reflect.Ptr && val.Type().Elem().AssignableTo(target.Type()):
+		if val.IsNil() {
+			return false, nil
+		}
+		target.Set(val.Elem())
+		return true, nil
+	default:
+		return false, fmt.Errorf("error unmangling. Expected type %s. Actual type %s", val.Type(), target.Type())
+	}
+}
+
+// setPopulated sets originalVal to the newly allocated setVal (a pointer),
+// dereferencing it if originalVal isn't a pointer (as is the case for the
+// elements of arrays).
+func setPopulated(originalVal, setVal reflect.Value) {
+	if originalVal.Kind() == reflect.Ptr {
+		originalVal.Set(setVal)
+		return
+	}
+	originalVal.Set(setVal.Elem())
 }
 
 // ShouldRecurse returns false because Mangle walks through nested structs and doesn't need Transform's recursion
@@ -328,6 +538,34 @@ func stripPtrs(val reflect.Value) reflect.Value {
 	return val
 }
 
+// FieldPath should be called after calling the flatten mangler. It returns
+// the names of the fields along the path to the original field (outermost
+// first), as recorded in the dialsfieldpath tag of the mangled StructField
+// (sf). Returns nil if the tag isn't set.
+// The synthetic names of fields added by the AliasMangler are mapped back to
+// the names of the original fields.
+func FieldPath(sf reflect.StructField) []string {
+	fieldPath := sf.Tag.Get(dialsFieldPathTag)
+	if fieldPath == "" {
+		return nil
+	}
+	path := strings.Split(fieldPath, ",")
+	for i, name := range path {
+		path[i] = strings.TrimSuffix(name, aliasFieldSuffix)
+	}
+	return path
+}
+
+// fieldDisplayName returns the dotted path to the original field (see
+// FieldPath) if sf has been flattened, and its name otherwise, for use in
+// errors.
+func fieldDisplayName(sf reflect.StructField) string {
+	if path := FieldPath(sf); len(path) > 0 {
+		return strings.Join(path, ".")
+	}
+	return sf.Name
+}
+
 // GetField should be called after calling the flatten mangler. It uses
 // the dialsfieldpath tag of the mangled StructFields (sf) set by the flatten
 // mangler to get the path to the original field. It returns the concrete value
@@ -348,6 +586,15 @@ func GetField(sf reflect.StructField, v reflect.Value) reflect.Value {
 			_, t := getUnderlyingKindType(sf.Type)
 			return reflect.New(t).Elem()
 		}
+		if v.Kind() == reflect.Array {
+			idx, err := strconv.Atoi(fname)
+			if err != nil || idx >= v.Len() {
+				_, t := getUnderlyingKindType(sf.Type)
+				return reflect.New(t).Elem()
+			}
+			v = v.Index(idx)
+			continue
+		}
 		v = v.FieldByName(fname)
 	}
 
@@ -11,6 +11,7 @@ import (
 
 	"github.com/vimeo/dials/common"
 	"github.com/vimeo/dials/ptrify"
+	"github.com/vimeo/dials/tagformat/caseconversion"
 )
 
 type tu struct {
@@ -23,6 +24,16 @@ func (u tu) UnmarshalText(data []byte) error {
 	return nil
 }
 
+type bu struct {
+	Binary    string
+	Unmarshal string
+}
+
+// need a concrete type that implements BinaryUnmarshaler
+func (u *bu) UnmarshalBinary(data []byte) error {
+	return nil
+}
+
 func TestFlattenMangler(t *testing.T) {
 	type Foo struct {
 		Location    string `dials:"Location"`
@@ -659,6 +670,52 @@ func TestFlattenMangler(t *testing.T) {
 				assert.EqualValues(t, b, i)
 			},
 		},
+		{
+			name: "unmarshal binary concrete type",
+			testStruct: &struct {
+				B int
+				U bu
+			}{
+				B: 8,
+				U: bu{Binary: "Hello", Unmarshal: "World"},
+			},
+			modify: func(t testing.TB, val reflect.Value) {
+				require.Equal(t, 2, val.Type().NumField())
+
+				s := []string{
+					"ConfigFieldB",
+					"ConfigFieldU",
+				}
+
+				for i := 0; i < val.Type().NumField(); i++ {
+					assert.Equal(t, s[i], val.Type().Field(i).Name)
+				}
+
+				int1 := 1
+				testbu := bu{
+					Binary:    "Hey",
+					Unmarshal: "Jude",
+				}
+				val.Field(0).Set(reflect.ValueOf(&int1))
+				val.Field(1).Set(reflect.ValueOf(&testbu))
+			},
+			assertion: func(t testing.TB, i interface{}) {
+				int1 := 1
+				testbu := bu{
+					Binary:    "Hey",
+					Unmarshal: "Jude",
+				}
+
+				b := &struct {
+					B *int `dials:"B"`
+					U *bu  `dials:"U"`
+				}{
+					B: &int1,
+					U: &testbu,
+				}
+				assert.EqualValues(t, b, i)
+			},
+		},
 	}
 
 	for _, testcase := range testCases {
@@ -867,3 +924,223 @@ func TestGetField(t *testing.T) {
 		})
 	}
 }
+
+func TestFlattenManglerArrays(t *testing.T) {
+	t.Parallel()
+	type endpoint struct {
+		Host string
+		Port int `dials:"port"`
+		// unexported fields are ignored
+		weight int
+	}
+	type config struct {
+		Name      string
+		Endpoints [2]endpoint
+		Nested    struct {
+			Backends [2]*endpoint
+		}
+	}
+
+	tmpl := config{Endpoints: [2]endpoint{{Host: "a", Port: 80}, {Host: "b"}}}
+	ptrifiedType := ptrify.Pointerify(reflect.TypeOf(config{}), reflect.ValueOf(tmpl))
+	f := NewFlattenMangler(common.DialsTagName, caseconversion.EncodeUpperCamelCase, caseconversion.EncodeKebabCase)
+	tfmr := NewTransformer(ptrifiedType, f)
+	val, err := tfmr.Translate()
+	require.NoError(t, err)
+
+	expectedFields := []struct {
+		name, tag, path string
+		typ             reflect.Type
+	}{
+		{"Name", "name", "Name", reflect.TypeOf((*string)(nil))},
+		{"Endpoints0Host", "endpoints-0-host", "Endpoints,0,Host", reflect.TypeOf((*string)(nil))},
+		{"Endpoints0Port", "endpoints-0-port", "Endpoints,0,Port", reflect.TypeOf((*int)(nil))},
+		{"Endpoints1Host", "endpoints-1-host", "Endpoints,1,Host", reflect.TypeOf((*string)(nil))},
+		{"Endpoints1Port", "endpoints-1-port", "Endpoints,1,Port", reflect.TypeOf((*int)(nil))},
+		{"NestedBackends0Host", "nested-backends-0-host", "Nested,Backends,0,Host", reflect.TypeOf((*string)(nil))},
+		{"NestedBackends0Port", "nested-backends-0-port", "Nested,Backends,0,Port", reflect.TypeOf((*int)(nil))},
+		{"NestedBackends1Host", "nested-backends-1-host", "Nested,Backends,1,Host", reflect.TypeOf((*string)(nil))},
+		{"NestedBackends1Port", "nested-backends-1-port", "Nested,Backends,1,Port", reflect.TypeOf((*int)(nil))},
+	}
+	require.Equal(t, len(expectedFields), val.NumField())
+	for i, ef := range expectedFields {
+		sf := val.Type().Field(i)
+		assert.Equal(t, ef.name, sf.Name)
+		assert.Equal(t, ef.tag, sf.Tag.Get(common.DialsTagName))
+		assert.Equal(t, ef.path, sf.Tag.Get(dialsFieldPathTag))
+		assert.Equal(t, ef.typ, sf.Type)
+	}
+
+	// GetField indexes into arrays
+	assert.Equal(t, "b", GetField(val.Type().Field(3), reflect.ValueOf(&tmpl)).Interface())
+	assert.Equal(t, 0, GetField(val.Type().Field(6), reflect.ValueOf(&tmpl)).Interface())
+
+	host := "c"
+	port := 8080
+	val.Field(3).Set(reflect.ValueOf(&host))
+	val.Field(6).Set(reflect.ValueOf(&port))
+
+	rv, err := tfmr.ReverseTranslate(val)
+	require.NoError(t, err)
+
+	assert.Nil(t, rv.FieldByName("Name").Interface())
+	// each element is unmangled as its own pointerified struct, which is
+	// nil if none of its fields were set
+	endpoints := rv.FieldByName("Endpoints").Elem()
+	assert.True(t, endpoints.Index(0).IsNil())
+	assert.Equal(t, "c", endpoints.Index(1).Elem().FieldByName("Host").Elem().Interface())
+	assert.True(t, endpoints.Index(1).Elem().FieldByName("Port").IsNil())
+	backends := rv.FieldByName("Nested").Elem().FieldByName("Backends").Elem()
+	assert.Equal(t, 8080, backends.Index(0).Elem().FieldByName("Port").Elem().Interface())
+	assert.True(t, backends.Index(1).IsNil())
+}
+
+func TestFlattenManglerWithSeparator(t *testing.T) {
+	t.Parallel()
+	type conn struct {
+		MaxIdle int
+		Host    string `dials:"HostName"`
+	}
+	type config struct {
+		LogLevel string
+		Database struct {
+			Primary conn
+			Replica conn `dials:"read_replica"`
+		} `dials:"DB"`
+		Backends [1]conn
+	}
+
+	ptrifiedType := ptrify.Pointerify(reflect.TypeOf(config{}), reflect.ValueOf(config{}))
+	for _, tbl := range []struct {
+		sep    string
+		expect []string
+	}{
+		{
+			sep: ".",
+			expect: []string{
+				"log-level",
+				"DB.primary.max-idle", "DB.primary.HostName",
+				"DB.read_replica.max-idle", "DB.read_replica.HostName",
+				"backends.0.max-idle", "backends.0.HostName",
+			},
+		},
+		{
+			// no separator: the casing function's word separator is
+			// used between components as well (in both cases,
+			// EncodeKebabCase leaves the casing of explicit tags alone)
+			sep: "",
+			expect: []string{
+				"log-level",
+				"DB-primary-max-idle", "DB-primary-HostName",
+				"DB-read_replica-max-idle", "DB-read_replica-HostName",
+				"backends-0-max-idle", "backends-0-HostName",
+			},
+		},
+	} {
+		f := NewFlattenManglerWithSeparator(common.DialsTagName, caseconversion.EncodeUpperCamelCase,
+			caseconversion.EncodeKebabCase, tbl.sep)
+		val, err := NewTransformer(ptrifiedType, f).Translate()
+		require.NoError(t, err)
+		tags := make([]string, val.NumField())
+		for i := range tags {
+			tags[i] = val.Type().Field(i).Tag.Get(common.DialsTagName)
+		}
+		assert.Equal(t, tbl.expect, tags, "separator %q", tbl.sep)
+	}
+}
+
+func TestFlattenManglerOmitRootPrefix(t *testing.T) {
+	t.Parallel()
+	type inner struct {
+		Hello  string
+		Nested struct {
+			Count int
+			Name  string `dials:"NAME"`
+		}
+	}
+	type wrapper struct {
+		ConfigField inner
+		Other       int
+	}
+
+	ptrifiedType := ptrify.Pointerify(reflect.TypeOf(wrapper{}), reflect.ValueOf(wrapper{}))
+	for _, tbl := range []struct {
+		name   string
+		f      *FlattenMangler
+		expect []string
+	}{
+		{
+			name: "no_separator",
+			f: NewFlattenMangler(common.DialsTagName, caseconversion.EncodeUpperCamelCase,
+				caseconversion.EncodeLowerSnakeCase, OmitRootPrefix("ConfigField")),
+			expect: []string{"hello", "nested_count", "nested_name", "other"},
+		},
+		{
+			name: "separator",
+			f: NewFlattenManglerWithSeparator(common.DialsTagName, caseconversion.EncodeUpperCamelCase,
+				caseconversion.EncodeKebabCase, ".", OmitRootPrefix("ConfigField")),
+			expect: []string{"hello", "nested.count", "nested.NAME", "other"},
+		},
+	} {
+		tfmr := NewTransformer(ptrifiedType, tbl.f)
+		val, err := tfmr.Translate()
+		require.NoError(t, err, tbl.name)
+		tags := make([]string, val.NumField())
+		for i := range tags {
+			tags[i] = val.Type().Field(i).Tag.Get(common.DialsTagName)
+		}
+		assert.Equal(t, tbl.expect, tags, tbl.name)
+
+		// field names and paths still include the outermost field
+		assert.Equal(t, "ConfigFieldNestedCount", val.Type().Field(1).Name, tbl.name)
+		assert.Equal(t, []string{"ConfigField", "Nested", "Count"}, FieldPath(val.Type().Field(1)), tbl.name)
+
+		hello := "world"
+		count := 3
+		val.Field(0).Set(reflect.ValueOf(&hello))
+		val.Field(1).Set(reflect.ValueOf(&count))
+		out, err := tfmr.ReverseTranslate(val)
+		require.NoError(t, err, tbl.name)
+		cfg := out.Interface()
+		configField := reflect.ValueOf(cfg).FieldByName("ConfigField").Elem()
+		assert.Equal(t, "world", configField.FieldByName("Hello").Elem().Interface(), tbl.name)
+		assert.Equal(t, 3, configField.FieldByName("Nested").Elem().FieldByName("Count").Elem().Interface(), tbl.name)
+	}
+}
+
+func TestFlattenManglerOmitRootPrefixSiblings(t *testing.T) {
+	t.Parallel()
+	type backend struct {
+		Host string
+	}
+	type inner struct {
+		Hello string
+		DB    backend
+		Cache backend
+	}
+	type wrapper struct {
+		ConfigField inner
+	}
+
+	f := NewFlattenMangler(common.DialsTagName, caseconversion.EncodeUpperCamelCase,
+		caseconversion.EncodeLowerSnakeCase, OmitRootPrefix("ConfigField"))
+	ptrifiedType := ptrify.Pointerify(reflect.TypeOf(wrapper{}), reflect.ValueOf(wrapper{}))
+	val, err := NewTransformer(ptrifiedType, f).Translate()
+	require.NoError(t, err)
+	tags := make([]string, val.NumField())
+	for i := range tags {
+		tags[i] = val.Type().Field(i).Tag.Get(common.DialsTagName)
+	}
+	// only the root's own tag is dropped; DB and Cache keep theirs
+	assert.Equal(t, []string{"hello", "db_host", "cache_host"}, tags)
+
+	// without a wrapper, top-level structs keep their prefixes
+	unwrappedType := ptrify.Pointerify(reflect.TypeOf(inner{}), reflect.ValueOf(inner{}))
+	val, err = NewTransformer(unwrappedType, f).Translate()
+	require.NoError(t, err)
+	tags = make([]string, val.NumField())
+	for i := range tags {
+		tags[i] = val.Type().Field(i).Tag.Get(common.DialsTagName)
+	}
+	assert.Equal(t, []string{"hello", "db_host", "cache_host"}, tags)
+}
@@ -0,0 +1,144 @@
+package transform
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// FieldFunc transforms the value of a field (see FuncMangler). It's passed
+// the field's populated value (dereferenced, if the field is a pointer), and
+// should return a value of the same type (or one convertible to it), or an
+// error if the value is invalid.
+type FieldFunc func(reflect.Value) (reflect.Value, error)
+
+// funcRule pairs a FieldFunc with the fields it applies to
+type funcRule struct {
+	match func(path []string, sf reflect.StructField) bool
+	fn    FieldFunc
+}
+
+// FuncMangler applies arbitrary FieldFuncs to the values of selected fields
+// on Unmangle, as an escape hatch for per-field normalization (e.g.
+// uppercasing a region code, or expanding "~" in a path) without writing a
+// new Mangler. Fields are selected either by path (see NewFuncMangler) or by
+// a predicate (see NewPredicateFuncMangler).
+//
+// Field paths are the names of the (Go) fields from the top-level struct to
+// the field, joined with "." (e.g. "Database.Host"). If the FuncMangler comes
+// after the FlattenMangler in the chain, flattened fields are identified by
+// their original path as well.
+//
+// FieldFuncs are only called for fields that are populated: nil pointers,
+// slices and maps are skipped. Funcs are applied to outer structs before
+// the fields nested within them. Nested fields are reached through structs
+// and pointers to structs, but not through slices, maps or arrays.
+type FuncMangler struct {
+	rules []funcRule
+}
+
+// NewFuncMangler constructs a FuncMangler that applies each of funcs to the
+// field at the corresponding path (e.g. "Database.Host").
+func NewFuncMangler(funcs map[string]FieldFunc) *FuncMangler {
+	rules := make([]funcRule, 0, len(funcs))
+	for path, fn := range funcs {
+		p := path
+		rules = append(rules, funcRule{
+			match: func(fieldPath []string, _ reflect.StructField) bool {
+				return strings.Join(fieldPath, ".") == p
+			},
+			fn: fn,
+		})
+	}
+	return &FuncMangler{rules: rules}
+}
+
+// NewPredicateFuncMangler constructs a FuncMangler that applies fn to every
+// field for which pred returns true. pred is passed the field's path (as a
+// slice of field names) and its StructField (whose type may have been
+// altered by earlier manglers, e.g. pointerified).
+func NewPredicateFuncMangler(pred func(path []string, sf reflect.StructField) bool, fn FieldFunc) *FuncMangler {
+	return &FuncMangler{rules: []funcRule{{match: pred, fn: fn}}}
+}
+
+// Mangle is a no-op; all the work happens in Unmangle.
+func (f *FuncMangler) Mangle(sf reflect.StructField) ([]reflect.StructField, error) {
+	return []reflect.StructField{sf}, nil
+}
+
+// Unmangle applies the FieldFuncs for the field, and any fields nested
+// within it.
+func (f *FuncMangler) Unmangle(sf reflect.StructField, vs []FieldValueTuple) (reflect.Value, error) {
+	path := FieldPath(sf)
+	if len(path) == 0 {
+		path = []string{sf.Name}
+	}
+	return f.apply(path, sf, vs[0].Value)
+}
+
+// apply applies any matching rules to v (the value of the field sf at
+// path), and then recurses into its fields if it's a struct (or pointer to
+// one), returning the new value.
+func (f *FuncMangler) apply(path []string, sf reflect.StructField, v reflect.Value) (reflect.Value, error) {
+	if isNil(v) {
+		return v, nil
+	}
+	// work on a copy, so we don't modify anything shared with the
+	// value being unmangled.
+	ptr := v.Kind() == reflect.Ptr
+	inner := v
+	if ptr {
+		inner = v.Elem()
+	}
+	cp := reflect.New(inner.Type()).Elem()
+	cp.Set(inner)
+
+	for _, r := range f.rules {
+		if !r.match(path, sf) {
+			continue
+		}
+		out, err := r.fn(cp)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("field %q: %w", strings.Join(path, "."), err)
+		}
+		if !out.IsValid() || !out.Type().ConvertibleTo(cp.Type()) {
+			return reflect.Value{}, fmt.Errorf("field %q: func returned %s; expected %s",
+				strings.Join(path, "."), describeType(out), cp.Type())
+		}
+		cp.Set(out.Convert(cp.Type()))
+	}
+
+	if cp.Kind() == reflect.Struct && !implementsUnmarshaler(cp.Type()) {
+		for i := 0; i < cp.NumField(); i++ {
+			nestedSF := cp.Type().Field(i)
+			if !nestedSF.IsExported() {
+				continue
+			}
+			nestedPath := append(path[:len(path):len(path)], nestedSF.Name)
+			nv, err := f.apply(nestedPath, nestedSF, cp.Field(i))
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			cp.Field(i).Set(nv)
+		}
+	}
+
+	if ptr {
+		return cp.Addr(), nil
+	}
+	return cp, nil
+}
+
+// describeType returns the type of v for use in error messages
+func describeType(v reflect.Value) string {
+	if !v.IsValid() {
+		return "an invalid value"
+	}
+	return v.Type().String()
+}
+
+// ShouldRecurse returns false, as Unmangle descends into nested structs
+// itself (in order to track field paths).
+func (f *FuncMangler) ShouldRecurse(reflect.StructField) bool {
+	return false
+}
@@ -0,0 +1,151 @@
+package transform
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vimeo/dials/common"
+	"github.com/vimeo/dials/tagformat/caseconversion"
+)
+
+// funcManglerDB and funcManglerConfig are already pointerified
+type funcManglerDB struct {
+	Host   *string
+	Region *string
+}
+
+type funcManglerConfig struct {
+	Region   *string
+	Path     *string
+	Database *funcManglerDB
+	Replica  *funcManglerDB
+	Tags     []string
+}
+
+func upper(v reflect.Value) (reflect.Value, error) {
+	return reflect.ValueOf(strings.ToUpper(v.String())), nil
+}
+
+func TestFuncMangler(t *testing.T) {
+	t.Parallel()
+	sp := func(s string) *string { return &s }
+
+	isRegion := func(path []string, sf reflect.StructField) bool {
+		return path[len(path)-1] == "Region"
+	}
+
+	for name, itbl := range map[string]struct {
+		manglers []Mangler
+		// set populates the mangled value
+		set      func(val reflect.Value)
+		in       funcManglerConfig
+		expected funcManglerConfig
+		expErr   string
+	}{
+		"by_path": {
+			manglers: []Mangler{NewFuncMangler(map[string]FieldFunc{
+				"Region":          upper,
+				"Database.Region": upper,
+				"Path": func(v reflect.Value) (reflect.Value, error) {
+					return reflect.ValueOf(strings.Replace(v.String(), "~", "/home/fim", 1)), nil
+				},
+			})},
+			in: funcManglerConfig{
+				Region:   sp("us-east-1"),
+				Path:     sp("~/config"),
+				Database: &funcManglerDB{Host: sp("db"), Region: sp("eu-west-1")},
+				Replica:  &funcManglerDB{Host: sp("replica"), Region: sp("ap-south-1")},
+			},
+			expected: funcManglerConfig{
+				Region:   sp("US-EAST-1"),
+				Path:     sp("/home/fim/config"),
+				Database: &funcManglerDB{Host: sp("db"), Region: sp("EU-WEST-1")},
+				Replica:  &funcManglerDB{Host: sp("replica"), Region: sp("ap-south-1")},
+			},
+		},
+		"predicate": {
+			manglers: []Mangler{NewPredicateFuncMangler(isRegion, upper)},
+			in: funcManglerConfig{
+				Region:   sp("us-east-1"),
+				Database: &funcManglerDB{Region: sp("eu-west-1")},
+				Replica:  &funcManglerDB{Region: sp("ap-south-1")},
+			},
+			expected: funcManglerConfig{
+				Region:   sp("US-EAST-1"),
+				Database: &funcManglerDB{Region: sp("EU-WEST-1")},
+				Replica:  &funcManglerDB{Region: sp("AP-SOUTH-1")},
+			},
+		},
+		"unset_fields_skipped": {
+			manglers: []Mangler{NewFuncMangler(map[string]FieldFunc{
+				"Region": func(reflect.Value) (reflect.Value, error) {
+					return reflect.Value{}, errors.New("should not be called")
+				},
+			})},
+			in:       funcManglerConfig{Path: sp("p")},
+			expected: funcManglerConfig{Path: sp("p")},
+		},
+		"after_flatten": {
+			manglers: []Mangler{
+				NewFlattenMangler(common.DialsTagName, caseconversion.EncodeUpperCamelCase, caseconversion.EncodeKebabCase),
+				NewFuncMangler(map[string]FieldFunc{"Replica.Region": upper}),
+			},
+			set: func(val reflect.Value) {
+				val.FieldByName("ReplicaRegion").Set(reflect.ValueOf(sp("ap-south-1")))
+			},
+			expected: funcManglerConfig{Replica: &funcManglerDB{Region: sp("AP-SOUTH-1")}},
+		},
+		"error": {
+			manglers: []Mangler{NewFuncMangler(map[string]FieldFunc{
+				"Database.Host": func(reflect.Value) (reflect.Value, error) {
+					return reflect.Value{}, errors.New("bad host")
+				},
+			})},
+			in:     funcManglerConfig{Database: &funcManglerDB{Host: sp("db")}},
+			expErr: `field "Database.Host": bad host`,
+		},
+		"wrong_type": {
+			manglers: []Mangler{NewFuncMangler(map[string]FieldFunc{
+				"Tags": func(reflect.Value) (reflect.Value, error) {
+					return reflect.ValueOf(42), nil
+				},
+			})},
+			in:     funcManglerConfig{Tags: []string{"a"}},
+			expErr: `field "Tags": func returned int; expected []string`,
+		},
+	} {
+		tbl := itbl
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			tfmr := NewTransformer(reflect.TypeOf(funcManglerConfig{}), tbl.manglers...)
+			val, err := tfmr.Translate()
+			require.NoError(t, err)
+			if tbl.set != nil {
+				tbl.set(val)
+			} else {
+				// FuncMangler doesn't alter the type
+				val.Set(reflect.ValueOf(tbl.in))
+			}
+			inJSON, marshalErr := json.Marshal(tbl.in)
+			require.NoError(t, marshalErr)
+
+			out, err := tfmr.ReverseTranslate(val)
+			if tbl.expErr != "" {
+				assert.ErrorContains(t, err, tbl.expErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tbl.expected, out.Interface())
+			// the input wasn't modified
+			afterJSON, marshalErr := json.Marshal(tbl.in)
+			require.NoError(t, marshalErr)
+			assert.JSONEq(t, string(inJSON), string(afterJSON))
+		})
+	}
+}
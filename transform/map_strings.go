@@ -0,0 +1,54 @@
+package transform
+
+import (
+	"reflect"
+)
+
+// mapStrings returns v with fn applied to its string values, for manglers
+// that normalize or rewrite strings on Unmangle. v may be string-kinded, a
+// pointer to a string-kinded type, or (if slices is set) a slice of either;
+// a new value of the same type is returned, so values (and the backing arrays
+// of slices) provided by sources aren't modified. Nil pointers and slices,
+// and values of any other type, are returned unchanged.
+func mapStrings(v reflect.Value, slices bool, fn func(string) string) reflect.Value {
+	if !v.IsValid() {
+		return v
+	}
+	if v.Kind() != reflect.Slice {
+		return mapString(v, fn)
+	}
+	if !slices || v.IsNil() {
+		return v
+	}
+	switch v.Type().Elem().Kind() {
+	case reflect.String, reflect.Ptr:
+	default:
+		return v
+	}
+	out := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+	for i := 0; i < v.Len(); i++ {
+		out.Index(i).Set(mapString(v.Index(i), fn))
+	}
+	return out
+}
+
+// mapString returns a new value of the same (string-kinded or
+// pointer-to-string-kinded) type as v with fn applied, or v unchanged if
+// it's any other kind (or a nil pointer).
+func mapString(v reflect.Value, fn func(string) string) reflect.Value {
+	switch {
+	case v.Kind() == reflect.String:
+		out := reflect.New(v.Type()).Elem()
+		out.SetString(fn(v.String()))
+		return out
+	case v.Kind() == reflect.Ptr && v.Type().Elem().Kind() == reflect.String:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.New(v.Type().Elem())
+		out.Elem().SetString(fn(v.Elem().String()))
+		return out
+	default:
+		return v
+	}
+}
@@ -0,0 +1,169 @@
+package transform
+
+import (
+	"reflect"
+	"strconv"
+
+	"github.com/vimeo/dials/common"
+)
+
+// RedactedString replaces the values of secret string fields redacted by
+// RedactMangler.
+const RedactedString = "****"
+
+// RedactMangler replaces the values of fields tagged with
+// `dialssecret:"true"` on Unmangle, so a config can be logged without leaking
+// secrets. Secret string (and *string) fields are replaced by RedactedString,
+// while secret fields of any other type are zeroed. Nested structs (including
+// those behind pointers, interfaces, and within slices, arrays and maps) are
+// redacted recursively.
+//
+// Mangle leaves the fields (and types) untouched and ShouldRecurse always
+// returns false, so the translated type has the same fields as the original,
+// and nested struct types are left intact for Unmangle to traverse.
+type RedactMangler struct {
+	// Zero indicates that secret string fields should be zeroed rather
+	// than replaced by RedactedString.
+	Zero bool
+}
+
+// Mangle is a no-op; all the work happens in Unmangle.
+func (*RedactMangler) Mangle(sf reflect.StructField) ([]reflect.StructField, error) {
+	return []reflect.StructField{sf}, nil
+}
+
+// Unmangle returns a redacted copy of the field's value.
+func (r *RedactMangler) Unmangle(sf reflect.StructField, vs []FieldValueTuple) (reflect.Value, error) {
+	rd := redactor{seen: map[ptrTypeKey]reflect.Value{}, zero: r.Zero}
+	return rd.redact(vs[0].Value, isSecret(sf)), nil
+}
+
+// ShouldRecurse always returns false; nested structs are handled within
+// Unmangle.
+func (*RedactMangler) ShouldRecurse(reflect.StructField) bool {
+	return false
+}
+
+func isSecret(sf reflect.StructField) bool {
+	secret, err := strconv.ParseBool(sf.Tag.Get(common.DialsSecretTag))
+	return err == nil && secret
+}
+
+type ptrTypeKey struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// redactor tracks the pointers it's already redacted, so self-referential
+// values don't recurse infinitely.
+type redactor struct {
+	seen map[ptrTypeKey]reflect.Value
+	zero bool
+}
+
+func (r *redactor) redact(v reflect.Value, secret bool) reflect.Value {
+	if !v.IsValid() {
+		return v
+	}
+	if secret {
+		if r.zero {
+			return reflect.Zero(v.Type())
+		}
+		return redactSecret(v)
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		out := reflect.New(v.Type()).Elem()
+		// copy any unexported fields
+		out.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			sf := v.Type().Field(i)
+			if !sf.IsExported() {
+				continue
+			}
+			out.Field(i).Set(r.redact(v.Field(i), isSecret(sf)))
+		}
+		return out
+	case reflect.Ptr:
+		if v.IsNil() || !mayContainSecrets(v.Type().Elem()) {
+			return v
+		}
+		key := ptrTypeKey{ptr: v.Pointer(), typ: v.Type()}
+		if out, ok := r.seen[key]; ok {
+			return out
+		}
+		out := reflect.New(v.Type().Elem())
+		r.seen[key] = out
+		out.Elem().Set(r.redact(v.Elem(), false))
+		return out
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.New(v.Type()).Elem()
+		out.Set(r.redact(v.Elem(), false))
+		return out
+	case reflect.Slice:
+		if v.IsNil() || !mayContainSecrets(v.Type().Elem()) {
+			return v
+		}
+		out := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(r.redact(v.Index(i), false))
+		}
+		return out
+	case reflect.Array:
+		if !mayContainSecrets(v.Type().Elem()) {
+			return v
+		}
+		out := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(r.redact(v.Index(i), false))
+		}
+		return out
+	case reflect.Map:
+		if v.IsNil() || !mayContainSecrets(v.Type().Elem()) {
+			return v
+		}
+		out := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out.SetMapIndex(iter.Key(), r.redact(iter.Value(), false))
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// redactSecret returns the redacted form of a secret value.
+func redactSecret(v reflect.Value) reflect.Value {
+	switch {
+	case v.Kind() == reflect.String:
+		out := reflect.New(v.Type()).Elem()
+		out.SetString(RedactedString)
+		return out
+	case v.Kind() == reflect.Ptr && v.Type().Elem().Kind() == reflect.String:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.New(v.Type().Elem())
+		out.Elem().SetString(RedactedString)
+		return out
+	default:
+		return reflect.Zero(v.Type())
+	}
+}
+
+// mayContainSecrets indicates whether values of type t may contain
+// struct-fields (which may be tagged as secrets).
+func mayContainSecrets(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Struct, reflect.Interface:
+		return true
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return mayContainSecrets(t.Elem())
+	default:
+		return false
+	}
+}
@@ -0,0 +1,124 @@
+package transform
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactManglerUnmangle(t *testing.T) {
+	t.Parallel()
+
+	type inner struct {
+		User     string
+		Password string `dialssecret:"true"`
+	}
+	type node struct {
+		Token string `dialssecret:"1"`
+		Next  *node
+	}
+	strPtr := func(s string) *string { return &s }
+	loop := &node{Token: "t"}
+	loop.Next = loop
+
+	cases := map[string]struct {
+		tag      string
+		val      any
+		expected any
+	}{
+		"not_secret": {
+			val:      "foo",
+			expected: "foo",
+		},
+		"secret_string": {
+			tag:      `dialssecret:"true"`,
+			val:      "foo",
+			expected: RedactedString,
+		},
+		"secret_string_ptr": {
+			tag:      `dialssecret:"true"`,
+			val:      strPtr("foo"),
+			expected: strPtr(RedactedString),
+		},
+		"secret_nil_string_ptr": {
+			tag:      `dialssecret:"true"`,
+			val:      (*string)(nil),
+			expected: (*string)(nil),
+		},
+		"secret_int": {
+			tag:      `dialssecret:"true"`,
+			val:      42,
+			expected: 0,
+		},
+		"explicitly_not_secret": {
+			tag:      `dialssecret:"false"`,
+			val:      "foo",
+			expected: "foo",
+		},
+		"nested_struct": {
+			val:      inner{User: "u", Password: "p"},
+			expected: inner{User: "u", Password: RedactedString},
+		},
+		"slice_of_struct_ptrs": {
+			val:      []*inner{{User: "u", Password: "p"}},
+			expected: []*inner{{User: "u", Password: RedactedString}},
+		},
+		"map_of_structs": {
+			val:      map[string]inner{"a": {User: "u", Password: "p"}},
+			expected: map[string]inner{"a": {User: "u", Password: RedactedString}},
+		},
+		"secret_struct": {
+			tag:      `dialssecret:"true"`,
+			val:      inner{User: "u", Password: "p"},
+			expected: inner{},
+		},
+	}
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			sf := reflect.StructField{Name: "F", Type: reflect.TypeOf(c.val), Tag: reflect.StructTag(c.tag)}
+			out, err := (&RedactMangler{}).Unmangle(sf, []FieldValueTuple{{Field: sf, Value: reflect.ValueOf(c.val)}})
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, out.Interface())
+		})
+	}
+
+	t.Run("self_referential", func(t *testing.T) {
+		t.Parallel()
+		sf := reflect.StructField{Name: "F", Type: reflect.TypeOf(loop)}
+		out, err := (&RedactMangler{}).Unmangle(sf, []FieldValueTuple{{Field: sf, Value: reflect.ValueOf(loop)}})
+		require.NoError(t, err)
+		redacted := out.Interface().(*node)
+		assert.Equal(t, RedactedString, redacted.Token)
+		assert.Same(t, redacted, redacted.Next)
+		assert.Equal(t, "t", loop.Token)
+	})
+}
+
+func TestRedactManglerTransformer(t *testing.T) {
+	t.Parallel()
+
+	type inner struct {
+		Password string `dialssecret:"true"`
+	}
+	type config struct {
+		Name    string
+		Inner   inner
+		private string
+	}
+
+	tfmr := NewTransformer(reflect.TypeOf(config{}), &RedactMangler{})
+	val, err := tfmr.Translate()
+	require.NoError(t, err)
+	// the nested struct type is left intact
+	assert.Equal(t, reflect.TypeOf(inner{}), val.Field(1).Type())
+
+	val.Field(0).SetString("app")
+	val.Field(1).Set(reflect.ValueOf(inner{Password: "p"}))
+	out, err := tfmr.ReverseTranslate(val)
+	require.NoError(t, err)
+	assert.Equal(t, config{Name: "app", Inner: inner{Password: RedactedString}}, out.Interface())
+}
@@ -0,0 +1,120 @@
+package transform
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sync"
+)
+
+// DialsPatternTag is the name of the struct tag containing the regular
+// expression that string-typed fields must match when using the RegexMangler.
+const DialsPatternTag = "dialspattern"
+
+// RegexMangler validates string fields (and elements of string-slice fields)
+// against the regular expression in their `dialspattern` struct tag,
+// returning an error from Unmangle if a populated value doesn't match.
+// Patterns are compiled when the field is mangled, so invalid patterns are
+// reported by Mangle.
+//
+// Fields that have been through the FlattenMangler are identified by their
+// full path (e.g. "Inner.Host") in errors.
+//
+// The zero-value is ready to use.
+type RegexMangler struct {
+	mu       sync.Mutex
+	patterns map[string]*regexp.Regexp
+}
+
+func isStringishType(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.String
+}
+
+// compiled returns the compiled form of pattern, compiling and caching it if
+// it hasn't been seen before.
+func (r *RegexMangler) compiled(pattern string) (*regexp.Regexp, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if re, ok := r.patterns[pattern]; ok {
+		return re, nil
+	}
+	re, compileErr := regexp.Compile(pattern)
+	if compileErr != nil {
+		return nil, compileErr
+	}
+	if r.patterns == nil {
+		r.patterns = map[string]*regexp.Regexp{}
+	}
+	r.patterns[pattern] = re
+	return re, nil
+}
+
+// Mangle compiles the pattern in the `dialspattern` tag (if present) and
+// otherwise leaves the field unchanged.
+func (r *RegexMangler) Mangle(sf reflect.StructField) ([]reflect.StructField, error) {
+	pattern, ok := sf.Tag.Lookup(DialsPatternTag)
+	if !ok {
+		return []reflect.StructField{sf}, nil
+	}
+	switch {
+	case isStringishType(sf.Type):
+	case sf.Type.Kind() == reflect.Slice && isStringishType(sf.Type.Elem()):
+	default:
+		return nil, fmt.Errorf("%s tag on field %q of unsupported type %s; only strings and string-slices may be validated",
+			DialsPatternTag, fieldDisplayName(sf), sf.Type)
+	}
+	if _, compileErr := r.compiled(pattern); compileErr != nil {
+		return nil, fmt.Errorf("invalid %s tag on field %q: %w", DialsPatternTag, fieldDisplayName(sf), compileErr)
+	}
+	return []reflect.StructField{sf}, nil
+}
+
+// stringVal strips a pointer (if present) and returns the string value, and
+// whether it was set.
+func stringVal(v reflect.Value) (string, bool) {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "", false
+		}
+		v = v.Elem()
+	}
+	return v.String(), true
+}
+
+// Unmangle verifies that any populated values match the field's pattern,
+// returning the value unchanged if so.
+func (r *RegexMangler) Unmangle(sf reflect.StructField, vs []FieldValueTuple) (reflect.Value, error) {
+	pattern, ok := sf.Tag.Lookup(DialsPatternTag)
+	if !ok {
+		return vs[0].Value, nil
+	}
+	re, compileErr := r.compiled(pattern)
+	if compileErr != nil {
+		return reflect.Value{}, fmt.Errorf("invalid %s tag on field %q: %w", DialsPatternTag, fieldDisplayName(sf), compileErr)
+	}
+
+	v := vs[0].Value
+	if v.Kind() == reflect.Slice {
+		for i := 0; i < v.Len(); i++ {
+			s, set := stringVal(v.Index(i))
+			if set && !re.MatchString(s) {
+				return reflect.Value{}, fmt.Errorf("element %d (%q) of field %q does not match pattern %q",
+					i, s, fieldDisplayName(sf), pattern)
+			}
+		}
+		return v, nil
+	}
+	if s, set := stringVal(v); set && !re.MatchString(s) {
+		return reflect.Value{}, fmt.Errorf("value %q for field %q does not match pattern %q",
+			s, fieldDisplayName(sf), pattern)
+	}
+	return v, nil
+}
+
+// ShouldRecurse always returns true in order to walk nested structs.
+func (r *RegexMangler) ShouldRecurse(reflect.StructField) bool {
+	return true
+}
@@ -0,0 +1,137 @@
+package transform
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vimeo/dials/ptrify"
+)
+
+func TestRegexMangler(t *testing.T) {
+	type inner struct {
+		Host string `dialspattern:"^[a-z.]+$"`
+	}
+	type config struct {
+		Name  string   `dialspattern:"^[a-z][a-z0-9-]*$"`
+		Hosts []string `dialspattern:"^[a-z.]+$"`
+		Free  string
+		Inner inner
+	}
+
+	strPtr := func(s string) *string { return &s }
+
+	cases := map[string]struct {
+		populate    func(v reflect.Value)
+		flatten     bool
+		expectedErr string
+		expected    config
+	}{
+		"matching": {
+			populate: func(v reflect.Value) {
+				v.FieldByName("Name").Set(reflect.ValueOf(strPtr("foo-bar1")))
+				v.FieldByName("Hosts").Set(reflect.ValueOf([]string{"a.example", "b.example"}))
+				v.FieldByName("Free").Set(reflect.ValueOf(strPtr("ANY THING")))
+			},
+			expected: config{
+				Name:  "foo-bar1",
+				Hosts: []string{"a.example", "b.example"},
+				Free:  "ANY THING",
+			},
+		},
+		"unset": {
+			populate: func(v reflect.Value) {},
+			expected: config{},
+		},
+		"non_matching_scalar": {
+			populate: func(v reflect.Value) {
+				v.FieldByName("Name").Set(reflect.ValueOf(strPtr("1foo")))
+			},
+			expectedErr: `value "1foo" for field "Name" does not match pattern "^[a-z][a-z0-9-]*$"`,
+		},
+		"non_matching_slice_element": {
+			populate: func(v reflect.Value) {
+				v.FieldByName("Hosts").Set(reflect.ValueOf([]string{"a.example", "B_EXAMPLE"}))
+			},
+			expectedErr: `element 1 ("B_EXAMPLE") of field "Hosts" does not match pattern "^[a-z.]+$"`,
+		},
+		"non_matching_nested": {
+			populate: func(v reflect.Value) {
+				in := reflect.New(v.FieldByName("Inner").Type().Elem())
+				in.Elem().Field(0).Set(reflect.ValueOf(strPtr("no spaces")))
+				v.FieldByName("Inner").Set(in)
+			},
+			expectedErr: `value "no spaces" for field "Host" does not match pattern "^[a-z.]+$"`,
+		},
+		"non_matching_flattened": {
+			flatten: true,
+			populate: func(v reflect.Value) {
+				v.FieldByName("InnerHost").Set(reflect.ValueOf(strPtr("no spaces")))
+			},
+			expectedErr: `value "no spaces" for field "Inner.Host" does not match pattern "^[a-z.]+$"`,
+		},
+	}
+
+	for name, c := range cases {
+		testCase := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ptrifiedType := ptrify.Pointerify(reflect.TypeOf(config{}), reflect.ValueOf(config{}))
+
+			manglers := []Mangler{}
+			if testCase.flatten {
+				manglers = append(manglers, DefaultFlattenMangler())
+			}
+			manglers = append(manglers, &RegexMangler{})
+			tfmr := NewTransformer(ptrifiedType, manglers...)
+			val, err := tfmr.Translate()
+			require.NoError(t, err)
+
+			testCase.populate(val)
+
+			unmangled, err := tfmr.ReverseTranslate(val)
+			if testCase.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), testCase.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			out := config{}
+			if name := unmangled.FieldByName("Name"); !name.IsNil() {
+				out.Name = name.Elem().String()
+			}
+			if free := unmangled.FieldByName("Free"); !free.IsNil() {
+				out.Free = free.Elem().String()
+			}
+			out.Hosts, _ = unmangled.FieldByName("Hosts").Interface().([]string)
+			assert.Equal(t, testCase.expected, out)
+		})
+	}
+}
+
+func TestRegexManglerMangleErrors(t *testing.T) {
+	for name, sf := range map[string]reflect.StructField{
+		"invalid_pattern": {
+			Name: "Foo", Type: reflect.TypeOf(""), Tag: `dialspattern:"[a-"`,
+		},
+		"unsupported_type": {
+			Name: "Foo", Type: reflect.TypeOf(0), Tag: `dialspattern:"^[0-9]+$"`,
+		},
+	} {
+		field := sf
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			_, err := (&RegexMangler{}).Mangle(field)
+			assert.Error(t, err)
+		})
+	}
+
+	flattened := reflect.StructField{
+		Name: "InnerHost", Type: reflect.TypeOf(""),
+		Tag: `dialspattern:"[a-" dialsfieldpath:"Inner,Host"`,
+	}
+	_, err := (&RegexMangler{}).Mangle(flattened)
+	assert.ErrorContains(t, err, `invalid dialspattern tag on field "Inner.Host"`)
+}
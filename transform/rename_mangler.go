@@ -0,0 +1,174 @@
+package transform
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/fatih/structtag"
+	"github.com/vimeo/dials/common"
+)
+
+// RenameMangler overrides the dials tags of fields named in an external map,
+// so the names used by sources may be remapped without editing the struct
+// definition (e.g. for generated or vendored types).
+//
+// Fields are identified by the dotted path of Go field names from the
+// top-level config struct (e.g. "Database.Host"), looking through pointers,
+// slices and arrays of structs. When used after a FlattenMangler, the path
+// recorded by the FlattenMangler is used instead (see FieldPath), so the
+// same paths work in either position. Paths that don't name a field are
+// ignored.
+type RenameMangler struct {
+	renames map[string]string
+}
+
+var _ Mangler = (*RenameMangler)(nil)
+
+// NewRenameMangler creates a new RenameMangler, setting the dials tag of the
+// field at each path in renames (a map from field path to tag name) to the
+// associated name.
+func NewRenameMangler(renames map[string]string) *RenameMangler {
+	return &RenameMangler{renames: renames}
+}
+
+// Mangle implements the Mangler interface. The dials tag of sf is replaced
+// if its path is in the map, and the types of nested structs are rebuilt with
+// the tags of any of their renamed fields replaced.
+func (r *RenameMangler) Mangle(sf reflect.StructField) ([]reflect.StructField, error) {
+	renamed, err := r.renameField(sf, fieldDisplayName(sf))
+	if err != nil {
+		return nil, err
+	}
+	return []reflect.StructField{renamed}, nil
+}
+
+// renameField returns sf with its dials tag set to the new name for path (if
+// there is one) and its type rebuilt by renameType.
+func (r *RenameMangler) renameField(sf reflect.StructField, path string) (reflect.StructField, error) {
+	if name, ok := r.renames[path]; ok {
+		tags, parseErr := structtag.Parse(string(sf.Tag))
+		if parseErr != nil {
+			return reflect.StructField{}, fmt.Errorf("error parsing tags of field %q: %w", path, parseErr)
+		}
+		newTag := &structtag.Tag{Key: common.DialsTagName, Name: name}
+		if oldTag, getErr := tags.Get(common.DialsTagName); getErr == nil {
+			newTag.Options = oldTag.Options
+		}
+		if setErr := tags.Set(newTag); setErr != nil {
+			return reflect.StructField{}, fmt.Errorf("error setting dials tag of field %q: %w", path, setErr)
+		}
+		sf.Tag = reflect.StructTag(tags.String())
+	}
+	t, err := r.renameType(sf.Type, path)
+	if err != nil {
+		return reflect.StructField{}, err
+	}
+	sf.Type = t
+	return sf, nil
+}
+
+// hasRenamesUnder indicates whether any fields nested within the field at
+// path are to be renamed.
+func (r *RenameMangler) hasRenamesUnder(path string) bool {
+	for p := range r.renames {
+		if strings.HasPrefix(p, path+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// renameType returns t with the tags of any renamed fields of the struct
+// types it contains (looking through pointers, slices and arrays) replaced.
+// t is returned unchanged if there's nothing to rename within it.
+func (r *RenameMangler) renameType(t reflect.Type, path string) (reflect.Type, error) {
+	if !r.hasRenamesUnder(path) || implementsUnmarshaler(t) {
+		return t, nil
+	}
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		elem, err := r.renameType(t.Elem(), path)
+		if err != nil || elem == t.Elem() {
+			return t, err
+		}
+		switch t.Kind() {
+		case reflect.Ptr:
+			return reflect.PtrTo(elem), nil
+		case reflect.Slice:
+			return reflect.SliceOf(elem), nil
+		default:
+			return reflect.ArrayOf(t.Len(), elem), nil
+		}
+	case reflect.Struct:
+		fields := make([]reflect.StructField, t.NumField())
+		for i := range fields {
+			sf := t.Field(i)
+			if !sf.IsExported() {
+				return nil, fmt.Errorf("unable to rename fields within %q: type %s has unexported field %s",
+					path, t, sf.Name)
+			}
+			renamed, err := r.renameField(sf, path+"."+sf.Name)
+			if err != nil {
+				return nil, err
+			}
+			renamed.Index = nil
+			fields[i] = renamed
+		}
+		return reflect.StructOf(fields), nil
+	default:
+		return t, nil
+	}
+}
+
+// Unmangle implements the Mangler interface, converting the value back to
+// the original type of the field if its type was rebuilt by Mangle.
+func (r *RenameMangler) Unmangle(sf reflect.StructField, fvs []FieldValueTuple) (reflect.Value, error) {
+	return convertRenamed(fvs[0].Value, sf.Type), nil
+}
+
+// convertRenamed converts v, whose type was returned by renameType, back to
+// the original type t.
+func convertRenamed(v reflect.Value, t reflect.Type) reflect.Value {
+	if v.Type() == t {
+		return v
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return reflect.Zero(t)
+		}
+		out := reflect.New(t.Elem())
+		out.Elem().Set(convertRenamed(v.Elem(), t.Elem()))
+		return out
+	case reflect.Slice:
+		if v.IsNil() {
+			return reflect.Zero(t)
+		}
+		out := reflect.MakeSlice(t, v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(convertRenamed(v.Index(i), t.Elem()))
+		}
+		return out
+	case reflect.Array:
+		out := reflect.New(t).Elem()
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(convertRenamed(v.Index(i), t.Elem()))
+		}
+		return out
+	case reflect.Struct:
+		out := reflect.New(t).Elem()
+		for i := 0; i < v.NumField(); i++ {
+			out.Field(i).Set(convertRenamed(v.Field(i), t.Field(i).Type))
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// ShouldRecurse returns false, since Mangle renames nested fields itself
+// (nested fields are mangled without the path to the field containing them).
+func (r *RenameMangler) ShouldRecurse(reflect.StructField) bool {
+	return false
+}
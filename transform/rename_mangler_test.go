@@ -0,0 +1,132 @@
+package transform
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vimeo/dials/common"
+	"github.com/vimeo/dials/ptrify"
+	"github.com/vimeo/dials/tagformat/caseconversion"
+)
+
+type renameBackend struct {
+	Host string
+	Port int `dials:"port,omitempty"`
+}
+
+type renameConfig struct {
+	Name     string `dials:"name"`
+	Timeout  int
+	Primary  renameBackend
+	Replica  *renameBackend
+	Backends []renameBackend
+	Unnamed  renameBackend
+}
+
+func TestRenameMangler(t *testing.T) {
+	t.Parallel()
+	m := NewRenameMangler(map[string]string{
+		"Name":          "svc_name",
+		"Timeout":       "timeout_secs",
+		"Primary.Host":  "primary_host",
+		"Replica.Port":  "replica_port",
+		"Backends.Host": "addr",
+		"Nonexistent.X": "x",
+	})
+	tfmr := NewTransformer(reflect.TypeOf(renameConfig{}), m)
+	val, err := tfmr.Translate()
+	require.NoError(t, err)
+
+	tag := func(v reflect.Value, name string) string {
+		sf, ok := v.Type().FieldByName(name)
+		require.True(t, ok, "missing field %s", name)
+		return sf.Tag.Get(common.DialsTagName)
+	}
+	assert.Equal(t, "svc_name", tag(val, "Name"))
+	assert.Equal(t, "timeout_secs", tag(val, "Timeout"))
+	assert.Equal(t, "primary_host", tag(val.FieldByName("Primary"), "Host"))
+	assert.Equal(t, "port,omitempty", tag(val.FieldByName("Primary"), "Port"))
+	// options on the original tag are retained
+	repl := reflect.New(val.FieldByName("Replica").Type().Elem()).Elem()
+	assert.Equal(t, "replica_port,omitempty", tag(repl, "Port"))
+	backend := reflect.New(val.FieldByName("Backends").Type().Elem()).Elem()
+	assert.Equal(t, "addr", tag(backend, "Host"))
+	// nothing to rename within Unnamed, so its type is untouched
+	assert.Equal(t, reflect.TypeOf(renameBackend{}), val.FieldByName("Unnamed").Type())
+
+	val.FieldByName("Name").SetString("fim")
+	val.FieldByName("Timeout").SetInt(30)
+	val.FieldByName("Primary").FieldByName("Host").SetString("primary.local")
+	repl.FieldByName("Port").SetInt(5433)
+	val.FieldByName("Replica").Set(repl.Addr())
+	backend.FieldByName("Host").SetString("b.local")
+	backends := reflect.MakeSlice(val.FieldByName("Backends").Type(), 0, 1)
+	val.FieldByName("Backends").Set(reflect.Append(backends, backend))
+
+	out, err := tfmr.ReverseTranslate(val)
+	require.NoError(t, err)
+	assert.Equal(t, renameConfig{
+		Name:     "fim",
+		Timeout:  30,
+		Primary:  renameBackend{Host: "primary.local"},
+		Replica:  &renameBackend{Port: 5433},
+		Backends: []renameBackend{{Host: "b.local"}},
+	}, out.Interface())
+}
+
+func TestRenameManglerNilNested(t *testing.T) {
+	t.Parallel()
+	m := NewRenameMangler(map[string]string{
+		"Replica.Port":  "replica_port",
+		"Backends.Host": "addr",
+	})
+	tfmr := NewTransformer(reflect.TypeOf(renameConfig{}), m)
+	val, err := tfmr.Translate()
+	require.NoError(t, err)
+
+	out, err := tfmr.ReverseTranslate(val)
+	require.NoError(t, err)
+	assert.Equal(t, renameConfig{}, out.Interface())
+}
+
+func TestRenameManglerAfterFlatten(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		Primary renameBackend
+	}
+	f := NewFlattenMangler(common.DialsTagName, caseconversion.EncodeUpperCamelCase, caseconversion.EncodeKebabCase)
+	m := NewRenameMangler(map[string]string{"Primary.Host": "primary_host"})
+	ptrifiedType := ptrify.Pointerify(reflect.TypeOf(config{}), reflect.ValueOf(config{}))
+	tfmr := NewTransformer(ptrifiedType, f, m)
+	val, err := tfmr.Translate()
+	require.NoError(t, err)
+
+	sf, ok := val.Type().FieldByName("PrimaryHost")
+	require.True(t, ok)
+	assert.Equal(t, "primary_host", sf.Tag.Get(common.DialsTagName))
+	sf, ok = val.Type().FieldByName("PrimaryPort")
+	require.True(t, ok)
+	assert.Equal(t, "primary-port,omitempty", sf.Tag.Get(common.DialsTagName))
+
+	host := "primary.local"
+	val.FieldByName("PrimaryHost").Set(reflect.ValueOf(&host))
+	out, err := tfmr.ReverseTranslate(val)
+	require.NoError(t, err)
+	assert.Equal(t, "primary.local", out.FieldByName("Primary").Elem().FieldByName("Host").Elem().Interface())
+}
+
+func TestRenameManglerUnexportedNested(t *testing.T) {
+	t.Parallel()
+	type inner struct {
+		Host string
+		port int
+	}
+	type config struct {
+		Inner inner
+	}
+	m := NewRenameMangler(map[string]string{"Inner.Host": "host"})
+	_, err := NewTransformer(reflect.TypeOf(config{}), m).Translate()
+	assert.ErrorContains(t, err, `unable to rename fields within "Inner"`)
+}
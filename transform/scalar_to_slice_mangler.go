@@ -0,0 +1,130 @@
+package transform
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/vimeo/dials/parse"
+)
+
+var emptyInterfaceType = reflect.TypeOf((*interface{})(nil)).Elem()
+
+// ScalarToSliceMangler lets slice fields be set from either a list or a
+// single scalar value, so a YAML file with `tags: prod` populates a
+// []string field tagged "tags" with []string{"prod"}, in the same way as
+// `tags: [prod]`.
+//
+// Mangle replaces slices of strings, bools and numbers (including named
+// types such as time.Duration) with interface{} fields, so decoders such as
+// YAML and JSON accept either form. Unmangle then wraps a scalar into a
+// one-element slice, and converts each element to the slice's element type,
+// parsing from its string representation if it's not directly convertible
+// (e.g. a YAML integer destined for a []string, or "3s" for a
+// []time.Duration). Slices of other types are left untouched.
+//
+// It's intended for use with sourcewrap.NewTransformingSource, wrapping a
+// source that decodes a file.
+type ScalarToSliceMangler struct{}
+
+// isScalarSlice returns true if t is a slice with string, bool or numeric
+// elements.
+func isScalarSlice(t reflect.Type) bool {
+	if t.Kind() != reflect.Slice {
+		return false
+	}
+	switch t.Elem().Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// Mangle changes the type of scalar-slice fields to interface{}.
+func (*ScalarToSliceMangler) Mangle(sf reflect.StructField) ([]reflect.StructField, error) {
+	if isScalarSlice(sf.Type) {
+		sf.Type = emptyInterfaceType
+	}
+	return []reflect.StructField{sf}, nil
+}
+
+// Unmangle converts the value populated into the interface{} field back to
+// a slice of the original type, wrapping scalars in a one-element slice.
+func (*ScalarToSliceMangler) Unmangle(sf reflect.StructField, vs []FieldValueTuple) (reflect.Value, error) {
+	if !isScalarSlice(sf.Type) {
+		return vs[0].Value, nil
+	}
+
+	v := vs[0].Value
+	for v.IsValid() && (v.Kind() == reflect.Interface || v.Kind() == reflect.Ptr) {
+		if v.IsNil() {
+			return reflect.Zero(sf.Type), nil
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return reflect.Zero(sf.Type), nil
+	}
+
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		elem, err := convertScalar(v, sf.Type.Elem())
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("field %q: %w", fieldDisplayName(sf), err)
+		}
+		out := reflect.MakeSlice(sf.Type, 1, 1)
+		out.Index(0).Set(elem)
+		return out, nil
+	}
+
+	if v.Type() == sf.Type {
+		return v, nil
+	}
+	out := reflect.MakeSlice(sf.Type, v.Len(), v.Len())
+	for i := 0; i < v.Len(); i++ {
+		elem, err := convertScalar(v.Index(i), sf.Type.Elem())
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("field %q: element %d: %w", fieldDisplayName(sf), i, err)
+		}
+		out.Index(i).Set(elem)
+	}
+	return out, nil
+}
+
+// convertScalar converts v to type t, directly if it's already of the same
+// kind, or by parsing its string representation otherwise.
+func convertScalar(v reflect.Value, t reflect.Type) (reflect.Value, error) {
+	for v.Kind() == reflect.Interface || v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return reflect.Zero(t), nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() == t.Kind() {
+		return v.Convert(t), nil
+	}
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Struct:
+		return reflect.Value{}, fmt.Errorf("cannot convert %s to %s", v.Type(), t)
+	}
+
+	str := fmt.Sprint(v.Interface())
+	if v.Kind() == reflect.Float32 || v.Kind() == reflect.Float64 {
+		// avoid exponents, so integral floats (as decoded from JSON)
+		// parse as integers.
+		str = strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits())
+	}
+	parsed, err := parse.String(str, t)
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("failed to convert %q to %s: %w", str, t, err)
+	}
+	return parsed.Elem().Convert(t), nil
+}
+
+// ShouldRecurse always returns true in order to walk nested structs.
+func (*ScalarToSliceMangler) ShouldRecurse(reflect.StructField) bool {
+	return true
+}
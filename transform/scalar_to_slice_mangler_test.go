@@ -0,0 +1,136 @@
+package transform
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScalarToSliceMangler(t *testing.T) {
+	t.Parallel()
+	type env string
+	type config struct {
+		Tags      []string
+		Ports     []int
+		Ratios    []float32
+		Timeouts  []time.Duration
+		Envs      []env
+		Flags     []bool
+		Structs   []struct{ A int }
+		NotASlice string
+	}
+
+	cases := map[string]struct {
+		field    string
+		val      any
+		expected any
+		expErr   string
+	}{
+		"bare_string": {
+			field:    "Tags",
+			val:      "prod",
+			expected: []string{"prod"},
+		},
+		"string_list": {
+			field:    "Tags",
+			val:      []any{"prod", "canary"},
+			expected: []string{"prod", "canary"},
+		},
+		"int_for_string": {
+			field:    "Tags",
+			val:      8080,
+			expected: []string{"8080"},
+		},
+		"typed_slice": {
+			field:    "Tags",
+			val:      []string{"a", "b"},
+			expected: []string{"a", "b"},
+		},
+		"unset": {
+			field:    "Tags",
+			val:      nil,
+			expected: []string(nil),
+		},
+		"bare_int": {
+			field:    "Ports",
+			val:      80,
+			expected: []int{80},
+		},
+		"json_floats_for_ints": {
+			field:    "Ports",
+			val:      []any{float64(80), float64(1e6)},
+			expected: []int{80, 1000000},
+		},
+		"string_for_int": {
+			field:    "Ports",
+			val:      "443",
+			expected: []int{443},
+		},
+		"fractional_for_int": {
+			field:  "Ports",
+			val:    []any{80, 1.5},
+			expErr: `field "Ports": element 1: failed to convert "1.5" to int: strconv.ParseInt: parsing "1.5": invalid syntax`,
+		},
+		"float32": {
+			field:    "Ratios",
+			val:      0.5,
+			expected: []float32{0.5},
+		},
+		"duration": {
+			field:    "Timeouts",
+			val:      "3s",
+			expected: []time.Duration{3 * time.Second},
+		},
+		"named_string": {
+			field:    "Envs",
+			val:      "prod",
+			expected: []env{"prod"},
+		},
+		"bool": {
+			field:    "Flags",
+			val:      true,
+			expected: []bool{true},
+		},
+		"bad_bool": {
+			field:  "Flags",
+			val:    "fim",
+			expErr: `field "Flags": failed to convert "fim" to bool: strconv.ParseBool: parsing "fim": invalid syntax`,
+		},
+		"nested_list": {
+			field:  "Tags",
+			val:    []any{[]any{"a"}},
+			expErr: `field "Tags": element 0: cannot convert []interface {} to string`,
+		},
+	}
+
+	for n, c := range cases {
+		testCase := c
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+			tfmr := NewTransformer(reflect.TypeOf(config{}), &ScalarToSliceMangler{})
+			val, err := tfmr.Translate()
+			require.NoError(t, err)
+
+			// untouched fields retain their types
+			assert.Equal(t, reflect.TypeOf([]struct{ A int }{}), val.FieldByName("Structs").Type())
+			assert.Equal(t, reflect.TypeOf(""), val.FieldByName("NotASlice").Type())
+
+			f := val.FieldByName(testCase.field)
+			require.Equal(t, reflect.Interface, f.Kind())
+			if testCase.val != nil {
+				f.Set(reflect.ValueOf(testCase.val))
+			}
+
+			out, err := tfmr.ReverseTranslate(val)
+			if testCase.expErr != "" {
+				assert.ErrorContains(t, err, testCase.expErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, out.FieldByName(testCase.field).Interface())
+		})
+	}
+}
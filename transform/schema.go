@@ -0,0 +1,59 @@
+package transform
+
+import (
+	"reflect"
+
+	"github.com/vimeo/dials/common"
+)
+
+// FieldSchema describes a single field of a mangled type, as returned by
+// Transformer.Schema.
+type FieldSchema struct {
+	// Path is the names of the fields along the path to the original field
+	// (outermost first). For fields that haven't been through the
+	// FlattenMangler, this is just the field's name.
+	Path []string
+	// Name is the name of the field in the mangled type
+	Name string
+	// TagName is the value of the field's `dials` tag after mangling (e.g.
+	// the flattened, re-cased name set by the FlattenMangler), or an empty
+	// string if the tag isn't set.
+	TagName string
+	// Type is the type of the field in the mangled type
+	Type reflect.Type
+	// Doc is the value of the field's `dialsdesc` tag (if any)
+	Doc string
+	// Tag is the field's full struct tag after mangling, for looking up
+	// any other tags (e.g. a source-specific name).
+	Tag reflect.StructTag
+}
+
+// Schema returns a description of each field of the mangled type produced by
+// the most recent successful call to TranslateType (or Translate), in field
+// order. When used with the FlattenMangler, this is the fully-flattened
+// schema, with one entry per leaf field, which is suitable for generating
+// documentation or help text.
+//
+// Schema returns nil if the type hasn't been translated yet.
+func (t *Transformer) Schema() []FieldSchema {
+	if t.outType == nil {
+		return nil
+	}
+	out := make([]FieldSchema, t.outType.NumField())
+	for i := range out {
+		sf := t.outType.Field(i)
+		path := FieldPath(sf)
+		if path == nil {
+			path = []string{sf.Name}
+		}
+		out[i] = FieldSchema{
+			Path:    path,
+			Name:    sf.Name,
+			TagName: sf.Tag.Get(common.DialsTagName),
+			Type:    sf.Type,
+			Doc:     sf.Tag.Get(common.DialsHelpTextTag),
+			Tag:     sf.Tag,
+		}
+	}
+	return out
+}
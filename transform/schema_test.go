@@ -0,0 +1,68 @@
+package transform
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vimeo/dials/ptrify"
+)
+
+func TestSchema(t *testing.T) {
+	t.Parallel()
+	type database struct {
+		Host    string        `dialsdesc:"database hostname"`
+		Timeout time.Duration `dials:"conn_timeout"`
+	}
+	type config struct {
+		Name     string `dialsdesc:"service name"`
+		Database database
+	}
+
+	ptrifiedType := ptrify.Pointerify(reflect.TypeOf(config{}), reflect.ValueOf(config{}))
+	tfmr := NewTransformer(ptrifiedType, DefaultFlattenMangler())
+	assert.Nil(t, tfmr.Schema())
+
+	_, err := tfmr.TranslateType()
+	require.NoError(t, err)
+
+	schema := tfmr.Schema()
+	require.Len(t, schema, 3)
+	for i, expected := range []struct {
+		path    []string
+		name    string
+		tagName string
+		typ     reflect.Type
+		doc     string
+	}{
+		{
+			path:    []string{"Name"},
+			name:    "Name",
+			tagName: "name",
+			typ:     reflect.TypeOf((*string)(nil)),
+			doc:     "service name",
+		},
+		{
+			path:    []string{"Database", "Host"},
+			name:    "DatabaseHost",
+			tagName: "database_host",
+			typ:     reflect.TypeOf((*string)(nil)),
+			doc:     "database hostname",
+		},
+		{
+			path:    []string{"Database", "Timeout"},
+			name:    "DatabaseTimeout",
+			tagName: "database_conn_timeout",
+			typ:     reflect.TypeOf((*time.Duration)(nil)),
+		},
+	} {
+		fs := schema[i]
+		assert.Equal(t, expected.path, fs.Path, "field %d", i)
+		assert.Equal(t, expected.name, fs.Name, "field %d", i)
+		assert.Equal(t, expected.tagName, fs.TagName, "field %d", i)
+		assert.Equal(t, expected.typ, fs.Type, "field %d", i)
+		assert.Equal(t, expected.doc, fs.Doc, "field %d", i)
+	}
+}
@@ -10,6 +10,8 @@ import (
 // The two types must be convertible (by Go's definition), so we can directly convert one value to the other.
 // The F type-argument is the "from" type, which is being being replaced.
 // The T type-argument is the "to" type, which is taking its place during processing (and swapped back to F during Unmangle).
+// F is also substituted where it appears within pointer, array, slice, channel and map types (including as a map's key),
+// e.g. a map[int64]string field becomes a map[int8]string when substituting int8 for int64.
 type SingleTypeSubstitutionMangler[F, T any] struct {
 	from, to reflect.Type
 }
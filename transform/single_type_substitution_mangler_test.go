@@ -125,6 +125,72 @@ func TestSingleTypeSubstitutionMangler_Int64_Int8(t *testing.T) {
 	}
 }
 
+func TestSingleTypeSubstitutionMangler_Int64_Int8_maps(t *testing.T) {
+	t.Parallel()
+	type testStruct struct {
+		Vals       map[string]int64
+		Keys       map[int64]string
+		Both       map[int64]int64
+		PtrVals    map[string]*int64
+		SliceVals  map[string][]int64
+		ArrayKeys  map[[2]int64]bool
+		NilVals    map[string]int64
+		EmptyVals  map[string]int64
+		Untouched  map[string]string
+		NestedVals map[string]map[int64]int64
+	}
+
+	m, constrErr := NewSingleTypeSubstitutionMangler[int64, int8]()
+	if constrErr != nil {
+		t.Fatalf("failed to construct substitution mangler: %s", constrErr)
+	}
+
+	itype := reflect.TypeOf(testStruct{})
+
+	tfmr := NewTransformer(itype, m)
+	val, trErr := tfmr.Translate()
+	if trErr != nil {
+		t.Fatalf("failed to translate type: %s", trErr)
+	}
+	int8T := reflect.TypeOf(int8(8))
+	strT := reflect.TypeOf("")
+
+	ptrVal := int8(38)
+	checkFieldTypeAndSetVals(t, val, []checkFieldTypeAndSetValsFieldDesc{
+		{fieldName: "Vals", expType: reflect.MapOf(strT, int8T), setVal: map[string]int8{"abc": 8, "def": -9}},
+		{fieldName: "Keys", expType: reflect.MapOf(int8T, strT), setVal: map[int8]string{33: "foo", -3: "bar"}},
+		{fieldName: "Both", expType: reflect.MapOf(int8T, int8T), setVal: map[int8]int8{12: 21}},
+		{fieldName: "PtrVals", expType: reflect.MapOf(strT, reflect.PointerTo(int8T)), setVal: map[string]*int8{"set": &ptrVal, "unset": nil}},
+		{fieldName: "SliceVals", expType: reflect.MapOf(strT, reflect.SliceOf(int8T)), setVal: map[string][]int8{"abc": {1, 2, 3}}},
+		{fieldName: "ArrayKeys", expType: reflect.MapOf(reflect.ArrayOf(2, int8T), reflect.TypeOf(true)), setVal: map[[2]int8]bool{{1, 2}: true}},
+		{fieldName: "NilVals", expType: reflect.MapOf(strT, int8T), setVal: map[string]int8(nil)},
+		{fieldName: "EmptyVals", expType: reflect.MapOf(strT, int8T), setVal: map[string]int8{}},
+		{fieldName: "Untouched", expType: reflect.MapOf(strT, strT), setVal: map[string]string{"fim": "bat"}},
+		{fieldName: "NestedVals", expType: reflect.MapOf(strT, reflect.MapOf(int8T, int8T)), setVal: map[string]map[int8]int8{"abc": {4: 5}}},
+	})
+
+	revVal, revTrErr := tfmr.ReverseTranslate(val)
+	if revTrErr != nil {
+		t.Fatalf("failed to reverse translate type: %s", revTrErr)
+	}
+
+	expPtrVal := int64(38)
+	if expOut := (testStruct{
+		Vals:       map[string]int64{"abc": 8, "def": -9},
+		Keys:       map[int64]string{33: "foo", -3: "bar"},
+		Both:       map[int64]int64{12: 21},
+		PtrVals:    map[string]*int64{"set": &expPtrVal, "unset": nil},
+		SliceVals:  map[string][]int64{"abc": {1, 2, 3}},
+		ArrayKeys:  map[[2]int64]bool{{1, 2}: true},
+		NilVals:    nil,
+		EmptyVals:  map[string]int64{},
+		Untouched:  map[string]string{"fim": "bat"},
+		NestedVals: map[string]map[int64]int64{"abc": {4: 5}},
+	}); !reflect.DeepEqual(revVal.Interface(), expOut) {
+		t.Errorf("unexpected output:\n got %+v\nwant %+v", revVal.Interface(), expOut)
+	}
+}
+
 func TestSingleTypeSubstitutionMangler_timeDuration_int64(t *testing.T) {
 	t.Parallel()
 	type testStruct struct {
@@ -1,7 +1,10 @@
 package transform
 
 import (
+	"encoding"
+	"fmt"
 	"reflect"
+	"strings"
 
 	"github.com/vimeo/dials/parse"
 )
@@ -11,10 +14,38 @@ var (
 	strPtrType = reflect.TypeOf(&zeroStr)
 )
 
+// DialsStructDelimTag is the name of the struct tag that overrides the
+// separator between a struct's fields when the StringCastingMangler parses
+// a string into a slice of structs. (e.g. `dialsstructdelim:"/"`)
+const DialsStructDelimTag = "dialsstructdelim"
+
 // StringCastingMangler mangles config struct fields into string types, then
 // unmangles the filled-in fields back to the original types, in order to
 // abstract away the details of type conversion from sources.
-type StringCastingMangler struct{}
+// Fields whose types (or slice element types) implement
+// encoding.TextUnmarshaler are parsed with UnmarshalText, even if their
+// underlying kind is a string or number.
+//
+// Slices of (pointers to) structs with simple fields are parsed from
+// delimited strings: elements are separated as with other slices, and each
+// element's value for each of the struct's exported fields (in order) is
+// separated by the Delimiters' KeyValue separator (':' by default), or the
+// value of the field's `dialsstructdelim` tag if set. For example,
+// "host1:8080,host2:9090" populates a []struct{Host string; Port int} with
+// two elements. Trailing fields may be omitted (leaving them zero), and the
+// last field's value includes any remaining separators.
+type StringCastingMangler struct {
+	// Delimiters overrides the separators used when parsing slices, sets
+	// and maps. Unset delimiters retain their defaults (see
+	// parse.DefaultDelimiters).
+	Delimiters parse.Delimiters
+}
+
+// NewStringCastingMangler constructs a StringCastingMangler that parses
+// slices, sets and maps using the separators in d.
+func NewStringCastingMangler(d parse.Delimiters) *StringCastingMangler {
+	return &StringCastingMangler{Delimiters: d}
+}
 
 // Mangle changes the type of the provided StructField to string.
 func (*StringCastingMangler) Mangle(sf reflect.StructField) ([]reflect.StructField, error) {
@@ -24,7 +55,7 @@ func (*StringCastingMangler) Mangle(sf reflect.StructField) ([]reflect.StructFie
 
 // Unmangle casts the string value in the mangled config struct to the type in
 // the original struct.
-func (*StringCastingMangler) Unmangle(sf reflect.StructField, vs []FieldValueTuple) (reflect.Value, error) {
+func (s *StringCastingMangler) Unmangle(sf reflect.StructField, vs []FieldValueTuple) (reflect.Value, error) {
 	// Get the string value that was set on the mangled StructField in order to
 	// cast it to the type in the original StructField, or return with a zero
 	// value of the original StructField's type if no string value was set.
@@ -35,6 +66,32 @@ func (*StringCastingMangler) Unmangle(sf reflect.StructField, vs []FieldValueTup
 	}
 	str := *(strPtrInterface.(*string))
 
+	// Types (including named string, numeric and slice types) that
+	// implement encoding.TextUnmarshaler do their own parsing (and
+	// validation), so use that rather than converting based on the
+	// underlying kind.
+	if tuVal, ok, err := unmarshalTextValue(str, sf.Type); ok {
+		return tuVal, err
+	}
+	if sf.Type.Kind() == reflect.Slice && isDelimitedStructType(sf.Type.Elem()) {
+		return s.parseStructSlice(str, sf)
+	}
+	if sf.Type.Kind() == reflect.Slice && implementsTextUnmarshaler(sf.Type.Elem()) {
+		strs, splitErr := parse.StringSliceWithDelimiters(str, s.Delimiters)
+		if splitErr != nil {
+			return reflect.Value{}, splitErr
+		}
+		out := reflect.MakeSlice(sf.Type, 0, len(strs))
+		for idx, elemStr := range strs {
+			elemVal, _, elemErr := unmarshalTextValue(elemStr, sf.Type.Elem())
+			if elemErr != nil {
+				return reflect.Value{}, fmt.Errorf("parse error of item %d %q: %w", idx, elemStr, elemErr)
+			}
+			out = reflect.Append(out, elemVal)
+		}
+		return out, nil
+	}
+
 	// If the StructField type wasn't a TextUnmarshaler, set what type we'll be
 	// casting to. All types in these StructFields from user-defined config
 	// struct types, except for slices and maps, have been pointerified so that
@@ -49,7 +106,122 @@ func (*StringCastingMangler) Unmangle(sf reflect.StructField, vs []FieldValueTup
 		castTo = sf.Type.Elem()
 	}
 
-	return parse.String(str, castTo)
+	return parse.StringWithDelimiters(str, castTo, s.Delimiters)
+}
+
+// isDelimitedStructType indicates whether t is a struct (or pointer to one)
+// that should be parsed from a delimited string (see StringCastingMangler).
+func isDelimitedStructType(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct && !implementsTextUnmarshaler(t) && !implementsUnmarshaler(t)
+}
+
+// parseStructSlice parses str into a slice of structs (see
+// StringCastingMangler).
+func (s *StringCastingMangler) parseStructSlice(str string, sf reflect.StructField) (reflect.Value, error) {
+	d := s.Delimiters.WithDefaults()
+	fieldDelim := string(d.KeyValue)
+	if tagDelim, ok := sf.Tag.Lookup(DialsStructDelimTag); ok && tagDelim != "" {
+		fieldDelim = tagDelim
+	}
+
+	elems, splitErr := parse.StringSliceWithDelimiters(str, d)
+	if splitErr != nil {
+		return reflect.Value{}, splitErr
+	}
+
+	elemType := sf.Type.Elem()
+	structType := elemType
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	fieldIdxs := make([]int, 0, structType.NumField())
+	for i := 0; i < structType.NumField(); i++ {
+		if structType.Field(i).IsExported() {
+			fieldIdxs = append(fieldIdxs, i)
+		}
+	}
+
+	out := reflect.MakeSlice(sf.Type, 0, len(elems))
+	for idx, elemStr := range elems {
+		parts := strings.SplitN(elemStr, fieldDelim, len(fieldIdxs))
+		elem := reflect.New(structType)
+		for partIdx, part := range parts {
+			field := structType.Field(fieldIdxs[partIdx])
+			fieldVal, err := s.parseScalar(part, field.Type)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("parse error of field %s of item %d %q: %w",
+					field.Name, idx, elemStr, err)
+			}
+			elem.Elem().Field(fieldIdxs[partIdx]).Set(fieldVal)
+		}
+		if elemType.Kind() == reflect.Ptr {
+			out = reflect.Append(out, elem)
+		} else {
+			out = reflect.Append(out, elem.Elem())
+		}
+	}
+	return out, nil
+}
+
+// parseScalar parses str into a value of type t, for use within a
+// delimited struct.
+func (s *StringCastingMangler) parseScalar(str string, t reflect.Type) (reflect.Value, error) {
+	if v, ok, err := unmarshalTextValue(str, t); ok {
+		return v, err
+	}
+	base := t
+	if base.Kind() == reflect.Ptr {
+		base = base.Elem()
+	}
+	v, err := parse.StringWithDelimiters(str, base, s.Delimiters)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	// scalars are returned as pointers
+	if v.Kind() == reflect.Ptr && base.Kind() != reflect.Ptr {
+		v = v.Elem()
+	}
+	v = v.Convert(base)
+	if t.Kind() == reflect.Ptr {
+		p := reflect.New(base)
+		p.Elem().Set(v)
+		return p, nil
+	}
+	return v, nil
+}
+
+// implementsTextUnmarshaler indicates whether a pointer to t (or to the type
+// t points to) implements encoding.TextUnmarshaler.
+func implementsTextUnmarshaler(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return reflect.PtrTo(t).Implements(textUnmarshalerType)
+}
+
+// unmarshalTextValue parses str into a new value of type t using its
+// UnmarshalText method. The bool return indicates whether t (or the type
+// it points to) implements encoding.TextUnmarshaler, and is false if the
+// string must be parsed by other means.
+func unmarshalTextValue(str string, t reflect.Type) (reflect.Value, bool, error) {
+	if !implementsTextUnmarshaler(t) {
+		return reflect.Value{}, false, nil
+	}
+	base := t
+	if t.Kind() == reflect.Ptr {
+		base = t.Elem()
+	}
+	v := reflect.New(base)
+	if err := v.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(str)); err != nil {
+		return reflect.Value{}, true, fmt.Errorf("failed to unmarshal %q into type %s: %w", str, base, err)
+	}
+	if t.Kind() == reflect.Ptr {
+		return v, true, nil
+	}
+	return v.Elem(), true, nil
 }
 
 // ShouldRecurse always returns true in order to walk nested structs.
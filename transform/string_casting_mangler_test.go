@@ -1,7 +1,9 @@
 package transform
 
 import (
+	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -12,6 +14,32 @@ import (
 	"github.com/vimeo/dials/ptrify"
 )
 
+type testLogLevel string
+
+func (l *testLogLevel) UnmarshalText(b []byte) error {
+	switch lvl := strings.ToUpper(string(b)); lvl {
+	case "DEBUG", "INFO", "WARN", "ERROR":
+		*l = testLogLevel(lvl)
+		return nil
+	default:
+		return fmt.Errorf("unknown log level %q", b)
+	}
+}
+
+type testPriority int
+
+func (p *testPriority) UnmarshalText(b []byte) error {
+	switch string(b) {
+	case "low":
+		*p = 1
+	case "high":
+		*p = 10
+	default:
+		return fmt.Errorf("unknown priority %q", b)
+	}
+	return nil
+}
+
 func TestStringCastingManglerMangle(t *testing.T) {
 	m := StringCastingMangler{}
 	sf := reflect.StructField{
@@ -303,9 +331,38 @@ func TestStringCastingManglerUnmangle(t *testing.T) {
 				assert.True(t, reflect.DeepEqual(expected, actual))
 			},
 		},
-		"invalid_map": {
+		"map_string_int_slice": {
 			StructFieldType: reflect.TypeOf(map[string][]int{}),
 			StringValue:     `"asdf": 1, "asdf": 2, "zxcv": 3`,
+			AssertFunc: func(i interface{}) {
+				expected := map[string][]int{
+					"asdf": {1, 2},
+					"zxcv": {3},
+				}
+				actual := i.(map[string][]int)
+				assert.True(t, reflect.DeepEqual(expected, actual))
+			},
+		},
+		"map_int_float_slice": {
+			StructFieldType: reflect.TypeOf(map[int][]float64{}),
+			StringValue:     `1: 1.5, 2: 2.5, 1: 3`,
+			AssertFunc: func(i interface{}) {
+				expected := map[int][]float64{
+					1: {1.5, 3},
+					2: {2.5},
+				}
+				actual := i.(map[int][]float64)
+				assert.True(t, reflect.DeepEqual(expected, actual))
+			},
+		},
+		"invalid_map_slice_elem_value": {
+			StructFieldType: reflect.TypeOf(map[string][]int{}),
+			StringValue:     `"asdf": 1, "asdf": "fimbat"`,
+			ExpectedErr:     `Error casting map val "fimbat" for key "asdf"`,
+		},
+		"invalid_map": {
+			StructFieldType: reflect.TypeOf(map[string][][]int{}),
+			StringValue:     `"asdf": 1, "asdf": 2, "zxcv": 3`,
 			ExpectedErr:     "unsupported map type",
 		},
 		"string_set": {
@@ -320,6 +377,61 @@ func TestStringCastingManglerUnmangle(t *testing.T) {
 				assert.True(t, reflect.DeepEqual(expected, actual))
 			},
 		},
+		"named_string_text_unmarshaler": {
+			StructFieldType: reflect.TypeOf(testLogLevel("")),
+			StringValue:     "warn",
+			AssertFunc: func(i interface{}) {
+				assert.Equal(t, testLogLevel("WARN"), *(i.(*testLogLevel)))
+			},
+		},
+		"named_string_text_unmarshaler_invalid": {
+			StructFieldType: reflect.TypeOf(testLogLevel("")),
+			StringValue:     "loud",
+			ExpectedErr:     `failed to unmarshal "loud" into type transform.testLogLevel: unknown log level "loud"`,
+		},
+		"named_int_text_unmarshaler": {
+			StructFieldType: reflect.TypeOf(testPriority(0)),
+			StringValue:     "high",
+			AssertFunc: func(i interface{}) {
+				assert.Equal(t, testPriority(10), *(i.(*testPriority)))
+			},
+		},
+		"named_string_text_unmarshaler_slice": {
+			StructFieldType: reflect.TypeOf([]testLogLevel{}),
+			StringValue:     "info,warn",
+			AssertFunc: func(i interface{}) {
+				assert.Equal(t, []testLogLevel{"INFO", "WARN"}, i.([]testLogLevel))
+			},
+		},
+		"delimited_struct_slice": {
+			StructFieldType: reflect.TypeOf([]struct {
+				Host  string
+				Port  uint16
+				Level *testLogLevel
+			}{}),
+			StringValue: `host1:80:info,"host2:443"`,
+			AssertFunc: func(i interface{}) {
+				info := testLogLevel("INFO")
+				assert.Equal(t, []struct {
+					Host  string
+					Port  uint16
+					Level *testLogLevel
+				}{{Host: "host1", Port: 80, Level: &info}, {Host: "host2", Port: 443}}, i)
+			},
+		},
+		"delimited_struct_slice_overflow": {
+			StructFieldType: reflect.TypeOf([]struct {
+				Host string
+				Port uint8
+			}{}),
+			StringValue: "host1:8080",
+			ExpectedErr: `parse error of field Port of item 0 "host1:8080"`,
+		},
+		"named_string_text_unmarshaler_slice_invalid": {
+			StructFieldType: reflect.TypeOf([]testLogLevel{}),
+			StringValue:     "info,loud",
+			ExpectedErr:     `parse error of item 1 "loud"`,
+		},
 	}
 
 	for n, c := range cases {
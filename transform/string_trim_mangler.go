@@ -0,0 +1,54 @@
+package transform
+
+import (
+	"reflect"
+	"strings"
+)
+
+// StringTrimMangler normalizes string values on Unmangle by passing them
+// through a transform function (strings.TrimSpace by default).  It applies
+// to string and *string fields (including named string types), and
+// optionally to the elements of string-slices.
+//
+// Since it only looks at the values (not the original field types), it may
+// be placed before or after the StringCastingMangler: after, it sees every
+// field as a *string and trims values before they're parsed; before, it only
+// touches fields that are strings in the original struct.  Nil pointers and
+// nil slices are left as-is.
+type StringTrimMangler struct {
+	// Transform is applied to each string value. If nil, strings.TrimSpace
+	// is used.
+	Transform func(string) string
+	// Slices enables applying Transform to each element of string-slice
+	// fields.
+	Slices bool
+}
+
+// NewStringTrimMangler constructs a StringTrimMangler. A nil transform
+// defaults to strings.TrimSpace.
+func NewStringTrimMangler(transform func(string) string, slices bool) *StringTrimMangler {
+	return &StringTrimMangler{Transform: transform, Slices: slices}
+}
+
+// Mangle is a no-op; all the work happens in Unmangle.
+func (s *StringTrimMangler) Mangle(sf reflect.StructField) ([]reflect.StructField, error) {
+	return []reflect.StructField{sf}, nil
+}
+
+func (s *StringTrimMangler) transform(str string) string {
+	if s.Transform == nil {
+		return strings.TrimSpace(str)
+	}
+	return s.Transform(str)
+}
+
+// Unmangle applies the transform to string values, and string-slice
+// elements if enabled.
+func (s *StringTrimMangler) Unmangle(sf reflect.StructField, vs []FieldValueTuple) (reflect.Value, error) {
+	return mapStrings(vs[0].Value, s.Slices, s.transform), nil
+}
+
+// ShouldRecurse always returns true in order to walk nested structs.
+func (s *StringTrimMangler) ShouldRecurse(reflect.StructField) bool {
+	return true
+}
@@ -0,0 +1,130 @@
+package transform
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vimeo/dials/ptrify"
+)
+
+func TestStringTrimManglerUnmangle(t *testing.T) {
+	type name string
+	strPtr := func(s string) *string { return &s }
+	namePtr := func(n name) *name { return &n }
+
+	cases := map[string]struct {
+		mangler  *StringTrimMangler
+		val      any
+		expected any
+	}{
+		"string": {
+			mangler:  &StringTrimMangler{},
+			val:      " foo\n",
+			expected: "foo",
+		},
+		"string_ptr": {
+			mangler:  &StringTrimMangler{},
+			val:      strPtr("\tfoo  "),
+			expected: strPtr("foo"),
+		},
+		"nil_string_ptr": {
+			mangler:  &StringTrimMangler{},
+			val:      (*string)(nil),
+			expected: (*string)(nil),
+		},
+		"named_string_ptr": {
+			mangler:  &StringTrimMangler{},
+			val:      namePtr(" bar "),
+			expected: namePtr("bar"),
+		},
+		"custom_transform": {
+			mangler:  NewStringTrimMangler(strings.ToLower, false),
+			val:      strPtr(" FOO "),
+			expected: strPtr(" foo "),
+		},
+		"slice_disabled": {
+			mangler:  &StringTrimMangler{},
+			val:      []string{" a ", "b "},
+			expected: []string{" a ", "b "},
+		},
+		"slice_enabled": {
+			mangler:  &StringTrimMangler{Slices: true},
+			val:      []string{" a ", "b "},
+			expected: []string{"a", "b"},
+		},
+		"nil_slice": {
+			mangler:  &StringTrimMangler{Slices: true},
+			val:      []string(nil),
+			expected: []string(nil),
+		},
+		"int_ptr": {
+			mangler:  &StringTrimMangler{Slices: true},
+			val:      new(int),
+			expected: new(int),
+		},
+	}
+
+	for n, c := range cases {
+		testCase := c
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+			v := reflect.ValueOf(testCase.val)
+			sf := reflect.StructField{Name: "Field", Type: v.Type()}
+			out, err := testCase.mangler.Unmangle(sf, []FieldValueTuple{{Field: sf, Value: v}})
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, out.Interface())
+		})
+	}
+}
+
+func TestStringTrimManglerChaining(t *testing.T) {
+	type config struct {
+		Name  string
+		Count int
+	}
+	ptrifiedType := ptrify.Pointerify(reflect.TypeOf(config{}), reflect.ValueOf(config{}))
+
+	for n, itbl := range map[string]struct {
+		manglers []Mangler
+		count    string
+		expErr   bool
+	}{
+		"after_string_casting": {
+			manglers: []Mangler{&StringCastingMangler{}, &StringTrimMangler{}},
+			count:    " 42 ",
+		},
+		"before_string_casting": {
+			manglers: []Mangler{&StringTrimMangler{}, &StringCastingMangler{}},
+			count:    "42",
+		},
+		"before_string_casting_untrimmed_int": {
+			manglers: []Mangler{&StringTrimMangler{}, &StringCastingMangler{}},
+			count:    " 42 ",
+			expErr:   true,
+		},
+	} {
+		tbl := itbl
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+			tfmr := NewTransformer(ptrifiedType, tbl.manglers...)
+			val, err := tfmr.Translate()
+			require.NoError(t, err)
+
+			name := "  foo  "
+			val.FieldByName("Name").Set(reflect.ValueOf(&name))
+			val.FieldByName("Count").Set(reflect.ValueOf(&tbl.count))
+
+			out, err := tfmr.ReverseTranslate(val)
+			if tbl.expErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "foo", out.FieldByName("Name").Elem().String())
+			assert.EqualValues(t, 42, out.FieldByName("Count").Elem().Int())
+		})
+	}
+}
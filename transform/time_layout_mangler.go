@@ -0,0 +1,83 @@
+package transform
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	timePtrType = reflect.PtrTo(timeType)
+)
+
+// TimeLayoutMangler changes time.Time and *time.Time fields to strings, and
+// parses them back with the first of its Layouts (see time.Parse) that
+// succeeds, for sources providing timestamps in formats other than the
+// RFC 3339 format expected by time.Time's UnmarshalText.
+//
+// It should precede the StringCastingMangler (and TextUnmarshalerMangler) in
+// a chain of manglers, so it handles time fields before they do.
+type TimeLayoutMangler struct {
+	// Layouts are tried in order until one parses successfully.
+	Layouts []string
+	// Location, if non-nil, is used to interpret times parsed with a
+	// layout lacking a time zone (see time.ParseInLocation). Otherwise
+	// they're interpreted as UTC.
+	Location *time.Location
+}
+
+// NewTimeLayoutMangler constructs a TimeLayoutMangler that tries each of
+// layouts in order.
+func NewTimeLayoutMangler(layouts ...string) *TimeLayoutMangler {
+	return &TimeLayoutMangler{Layouts: layouts}
+}
+
+// Mangle changes the type of time.Time and *time.Time fields to string.
+// Other fields are passed through unaltered.
+func (*TimeLayoutMangler) Mangle(sf reflect.StructField) ([]reflect.StructField, error) {
+	if sf.Type == timeType || sf.Type == timePtrType {
+		sf.Type = strPtrType
+	}
+	return []reflect.StructField{sf}, nil
+}
+
+// Unmangle parses the string value of time fields using the configured
+// layouts, returning an error listing the layouts if none succeed.
+func (t *TimeLayoutMangler) Unmangle(sf reflect.StructField, vs []FieldValueTuple) (reflect.Value, error) {
+	if sf.Type != timeType && sf.Type != timePtrType {
+		return vs[0].Value, nil
+	}
+	strPtr := vs[0].Value.Interface().(*string)
+	if strPtr == nil {
+		return reflect.Zero(sf.Type), nil
+	}
+	tm, err := t.parse(*strPtr)
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("field %q: %w", fieldDisplayName(sf), err)
+	}
+	if sf.Type == timePtrType {
+		return reflect.ValueOf(&tm), nil
+	}
+	return reflect.ValueOf(tm), nil
+}
+
+func (t *TimeLayoutMangler) parse(s string) (time.Time, error) {
+	loc := t.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	for _, layout := range t.Layouts {
+		if tm, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return tm, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("failed to parse %q with any of the layouts [%s]",
+		s, strings.Join(t.Layouts, ", "))
+}
+
+// ShouldRecurse always returns true in order to walk nested structs.
+func (*TimeLayoutMangler) ShouldRecurse(reflect.StructField) bool {
+	return true
+}
@@ -0,0 +1,95 @@
+package transform
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vimeo/dials/parse"
+	"github.com/vimeo/dials/ptrify"
+)
+
+func TestTimeLayoutMangler(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		Created time.Time
+		Expires *time.Time
+		Name    string
+	}
+
+	est := time.FixedZone("EST", -5*60*60)
+
+	for name, itbl := range map[string]struct {
+		location    *time.Location
+		vals        map[string]string
+		expected    config
+		expectedErr string
+	}{
+		"first_layout": {
+			vals: map[string]string{"Created": "2021/03/04 05:06:07", "Name": "fim"},
+			expected: config{
+				Created: time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC),
+				Name:    "fim",
+			},
+		},
+		"later_layout": {
+			vals: map[string]string{"Created": "04-Mar-2021", "Expires": "2022/01/02 03:04:05"},
+			expected: config{
+				Created: time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC),
+				Expires: func() *time.Time {
+					tm := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+					return &tm
+				}(),
+			},
+		},
+		"location": {
+			location: est,
+			vals:     map[string]string{"Created": "04-Mar-2021"},
+			expected: config{Created: time.Date(2021, 3, 4, 0, 0, 0, 0, est)},
+		},
+		"unset": {
+			vals:     map[string]string{},
+			expected: config{},
+		},
+		"no_match": {
+			vals:        map[string]string{"Expires": "2021-03-04T05:06:07Z"},
+			expectedErr: `field "Expires": failed to parse "2021-03-04T05:06:07Z" with any of the layouts [2006/01/02 15:04:05, 02-Jan-2006]`,
+		},
+	} {
+		tbl := itbl
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ptrifiedType := ptrify.Pointerify(reflect.TypeOf(config{}), reflect.ValueOf(config{}))
+
+			tlm := NewTimeLayoutMangler("2006/01/02 15:04:05", "02-Jan-2006")
+			tlm.Location = tbl.location
+			tfmr := NewTransformer(ptrifiedType, tlm, NewStringCastingMangler(parse.Delimiters{}))
+			val, err := tfmr.Translate()
+			require.NoError(t, err)
+
+			for field, s := range tbl.vals {
+				str := s
+				val.FieldByName(field).Set(reflect.ValueOf(&str))
+			}
+
+			unmangled, err := tfmr.ReverseTranslate(val)
+			if tbl.expectedErr != "" {
+				assert.ErrorContains(t, err, tbl.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			out := config{Expires: unmangled.FieldByName("Expires").Interface().(*time.Time)}
+			if created := unmangled.FieldByName("Created"); !created.IsNil() {
+				out.Created = created.Elem().Interface().(time.Time)
+			}
+			if name := unmangled.FieldByName("Name"); !name.IsNil() {
+				out.Name = name.Elem().String()
+			}
+			assert.Equal(t, tbl.expected, out)
+		})
+	}
+}
@@ -31,6 +31,44 @@ type Mangler interface {
 	ShouldRecurse(reflect.StructField) bool
 }
 
+// ContextualMangler is an optional extension of the Mangler interface for
+// manglers that need to consider the other fields of the struct containing
+// each field (e.g. to require one field if another is set). The
+// Transformer calls MangleWithContext and UnmangleWithContext in place of
+// Mangle and Unmangle for Manglers implementing it.
+type ContextualMangler interface {
+	Mangler
+	// MangleWithContext is called in place of Mangle for the field
+	// fc.Fields[fc.Index]. fc.Values is nil.
+	MangleWithContext(fc FieldContext) ([]reflect.StructField, error)
+	// UnmangleWithContext is called in place of Unmangle for the field
+	// fc.Fields[fc.Index], with fc.Values populated.
+	UnmangleWithContext(fc FieldContext, vs []FieldValueTuple) (reflect.Value, error)
+}
+
+// FieldContext describes the struct containing a field passed to a
+// ContextualMangler. Its slices must not be modified.
+type FieldContext struct {
+	// Parent is the struct type being transformed: the type passed to
+	// NewTransformer, or the type of a nested struct being recursed
+	// into.
+	Parent reflect.Type
+	// Fields are the fields of the struct as passed to this mangler:
+	// the fields of Parent for the first mangler, or the fields output
+	// by the preceding mangler for subsequent ones. Unexported fields
+	// are included, although they're never mangled.
+	Fields []reflect.StructField
+	// Index is the index of the field being mangled or unmangled within
+	// Fields.
+	Index int
+	// Values is only populated when unmangling: Values[i] holds the
+	// (mangled) fields and values output by this mangler for Fields[i],
+	// with nested structs already unmangled (as passed to
+	// UnmangleWithContext for that field). It's empty for unexported
+	// fields.
+	Values [][]FieldValueTuple
+}
+
 type fieldTransformPair struct {
 	field reflect.StructField
 	// If this field is a struct-type (or pointer-to-struct, or
@@ -71,6 +109,9 @@ type Transformer struct {
 	//  - a dimension for fields in the original struct (inner)
 	mState [][]transformMappingElement
 	t      reflect.Type
+	// outType is the mangled type returned by the last successful call
+	// to TranslateType
+	outType reflect.Type
 }
 
 func unpackFields(t reflect.Type) []reflect.StructField {
@@ -107,16 +148,20 @@ func isStructishTypedField(field reflect.StructField) bool {
 	switch field.Type.Kind() {
 	case reflect.Struct:
 		return true
-	case reflect.Ptr, reflect.Array, reflect.Slice:
-		if field.Type.Elem().Kind() == reflect.Struct {
-			return true
-		}
-		return false
+	case reflect.Ptr:
+		return field.Type.Elem().Kind() == reflect.Struct
+	case reflect.Array, reflect.Slice:
+		return field.Type.Elem().Kind() == reflect.Struct || isPtrToStruct(field.Type.Elem())
 	default:
 		return false
 	}
 }
 
+// isPtrToStruct indicates whether t is a pointer to a struct type
+func isPtrToStruct(t reflect.Type) bool {
+	return t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Struct
+}
+
 func (t *Transformer) maybeRecursivelyMangle(mangler Mangler, state *transformMappingElement,
 	fields []reflect.StructField) ([]reflect.StructField, error) {
 	// copy fields into another equal-length-slice
@@ -131,8 +176,9 @@ func (t *Transformer) maybeRecursivelyMangle(mangler Mangler, state *transformMa
 
 		ft := field.Type
 
-		// also don't recurse into TextUnarshaler types
-		if ft.Implements(textMReflectType) || reflect.PointerTo(ft).Implements(textMReflectType) {
+		// also don't recurse into TextUnarshaler (or BinaryUnmarshaler)
+		// types
+		if implementsUnmarshaler(ft) {
 			continue
 		}
 
@@ -141,6 +187,15 @@ func (t *Transformer) maybeRecursivelyMangle(mangler Mangler, state *transformMa
 		case reflect.Ptr, reflect.Array, reflect.Slice:
 			ft = ft.Elem()
 		}
+		// as well as the pointer of slices and arrays of pointers to
+		// structs
+		elemPtr := isPtrToStruct(ft)
+		if elemPtr {
+			ft = ft.Elem()
+			if implementsUnmarshaler(ft) {
+				continue
+			}
+		}
 
 		fieldTransformer := Transformer{
 			manglers: []Mangler{mangler},
@@ -154,6 +209,9 @@ func (t *Transformer) maybeRecursivelyMangle(mangler Mangler, state *transformMa
 				i, field.Name, manglingErr)
 		}
 		// Reinstate pointerification, etc.
+		if elemPtr {
+			mangledType = reflect.PtrTo(mangledType)
+		}
 		switch field.Type.Kind() {
 		case reflect.Ptr:
 			mangledType = reflect.PtrTo(mangledType)
@@ -186,12 +244,24 @@ func (t *Transformer) TranslateType() (reflect.Type, error) {
 		layerState := make([]transformMappingElement, len(layerFields))
 		for i, structField := range layerFields {
 
-			// Skip unexported fields
+			// Skip unexported fields (recording the field, so
+			// ContextualManglers see it when unmangling)
 			if !ast.IsExported(structField.Name) {
+				layerState[i] = transformMappingElement{in: structField}
 				continue
 			}
 
-			fields, mangleErr := mangler.Mangle(structField)
+			var fields []reflect.StructField
+			var mangleErr error
+			if cm, ok := mangler.(ContextualMangler); ok {
+				fields, mangleErr = cm.MangleWithContext(FieldContext{
+					Parent: t.t,
+					Fields: layerFields,
+					Index:  i,
+				})
+			} else {
+				fields, mangleErr = mangler.Mangle(structField)
+			}
 			if mangleErr != nil {
 				return nil,
 					fmt.Errorf("failed to mangle field %d with mangler %d (type %T): %s",
@@ -223,7 +293,8 @@ func (t *Transformer) TranslateType() (reflect.Type, error) {
 
 		t.mState[manglerNum] = layerState
 	}
-	return reflect.StructOf(layerFields), nil
+	t.outType = reflect.StructOf(layerFields)
+	return t.outType, nil
 }
 
 // Translate calls `TranslateType` and returns an instance of the new type (or an error)
@@ -291,11 +362,24 @@ FIELDITER:
 			}
 			for l := 0; l < v.Len(); l++ {
 				av := v.Index(l)
+				elemPtr := av.Kind() == reflect.Ptr
+				if elemPtr {
+					if av.IsNil() {
+						// leave nil elements nil (the
+						// output's element is already a
+						// nil pointer)
+						continue
+					}
+					av = av.Elem()
+				}
 				unmangledVal, unmangleErr := fieldTransformer.ReverseTranslate(av)
 				if unmangleErr != nil {
 					return nil, &UnmangleError{Err: unmangleErr, ErrString: fmt.Sprintf("failed to recursively inverse transform field %s[%d]: %s",
 						field.Field.Name, l, unmangleErr)}
 				}
+				if elemPtr {
+					unmangledVal = unmangledVal.Addr()
+				}
 				if !unmangledVal.Type().AssignableTo(fieldState.out[z].field.Type.Elem()) {
 					unassignableErr := fmt.Errorf("unable to assign type %s from recursive unmangling to %s", unmangledVal.Type(), fieldState.in.Type.Elem())
 					return nil, &UnmangleError{Err: unassignableErr, ErrString: fmt.Sprintf("failed to recursively inverse transform field %s[%d]: %s",
@@ -309,18 +393,31 @@ FIELDITER:
 	return mf, nil
 }
 
-// returns the field value
+// returns the field value. fc is only non-nil for ContextualManglers, in
+// which case mf must already have been passed through
+// maybeRecursivelyUnmangle.
 func (t *Transformer) unmangleField(
-	manglerIdx int, fieldState *transformMappingElement, mangledField []FieldValueTuple) (reflect.Value, error) {
-	// Since we recurse into field types after we've mangled the
-	// field itself, we have to recurse first here.
-	mf, recursivelyUnmangleErr := t.maybeRecursivelyUnmangle(fieldState, mangledField)
-	if recursivelyUnmangleErr != nil {
-		return reflect.Value{}, recursivelyUnmangleErr
+	manglerIdx int, fieldState *transformMappingElement, mangledField []FieldValueTuple,
+	fc *FieldContext) (reflect.Value, error) {
+	mf := mangledField
+	if fc == nil {
+		// Since we recurse into field types after we've mangled the
+		// field itself, we have to recurse first here.
+		var recursivelyUnmangleErr *UnmangleError
+		mf, recursivelyUnmangleErr = t.maybeRecursivelyUnmangle(fieldState, mangledField)
+		if recursivelyUnmangleErr != nil {
+			return reflect.Value{}, recursivelyUnmangleErr
+		}
 	}
 
 	mangler := t.manglers[manglerIdx]
-	unmangledVal, unmangleErr := mangler.Unmangle(fieldState.in, mf)
+	var unmangledVal reflect.Value
+	var unmangleErr error
+	if cm, ok := mangler.(ContextualMangler); ok && fc != nil {
+		unmangledVal, unmangleErr = cm.UnmangleWithContext(*fc, mf)
+	} else {
+		unmangledVal, unmangleErr = mangler.Unmangle(fieldState.in, mf)
+	}
 	if unmangleErr != nil {
 		errString := fmt.Sprintf("unmangle from mangler %d (type %T) failed: %s",
 			manglerIdx, mangler, unmangleErr)
@@ -363,22 +460,115 @@ func (e *ReverseTranslateError) Unwrap() error {
 }
 
 // ReverseTranslate calls each Mangler's Unmangle method in reverse order.
+// Unexported fields are skipped by TranslateType, so they're left
+// zero-valued in the returned value (see ReverseTranslatePreservingUnexported).
 func (t *Transformer) ReverseTranslate(v reflect.Value) (reflect.Value, error) {
+	return t.reverseTranslate(v, reflect.Value{})
+}
+
+// ReverseTranslatePreservingUnexported is equivalent to ReverseTranslate,
+// except that the unexported fields of the returned value are copied from
+// orig, which must be a value of the Transformer's type (or a non-nil
+// pointer to one), so types carrying private state (e.g. a cache) survive
+// a round-trip through the Transformer.
+//
+// The reflect package refuses to set unexported fields individually (doing
+// so would require package unsafe), so this works by assigning all of orig
+// to the output before setting the exported fields from v. That has a few
+// consequences:
+//   - orig must not have been obtained by way of an unexported field (e.g.
+//     reflect.ValueOf(outer).Field(i) for an unexported field i), as reflect
+//     will not allow such values to be copied; an error is returned in that
+//     case.
+//   - only the unexported fields of the top-level struct are preserved;
+//     unexported fields of nested structs are rebuilt by the manglers, and
+//     are zero-valued, as with ReverseTranslate.
+//   - the copy is shallow: unexported pointer, map, slice and channel fields
+//     share memory with orig.
+func (t *Transformer) ReverseTranslatePreservingUnexported(v, orig reflect.Value) (reflect.Value, error) {
+	if orig.Kind() == reflect.Ptr {
+		if orig.IsNil() {
+			return reflect.Value{}, fmt.Errorf("nil original value of type %s", orig.Type())
+		}
+		orig = orig.Elem()
+	}
+	if !orig.IsValid() {
+		return reflect.Value{}, fmt.Errorf("invalid original value; expected %s", t.t)
+	}
+	if orig.Type() != t.t {
+		return reflect.Value{}, fmt.Errorf("original value has type %s; expected %s", orig.Type(), t.t)
+	}
+	if !orig.CanInterface() {
+		return reflect.Value{}, fmt.Errorf("original value of type %s was obtained through an unexported field, and cannot be copied", t.t)
+	}
+	return t.reverseTranslate(v, orig)
+}
+
+// unmangleContext constructs the FieldContext for unmangling the fields
+// output by the ContextualMangler at manglerNum from layerMangledVal,
+// unmangling any nested structs up-front so every field's Values are
+// complete.
+func (t *Transformer) unmangleContext(manglerNum int, layerMangledVal []FieldValueTuple) (*FieldContext, error) {
+	layerState := t.mState[manglerNum]
+	fc := FieldContext{
+		Parent: t.t,
+		Fields: make([]reflect.StructField, len(layerState)),
+		Values: make([][]FieldValueTuple, len(layerState)),
+	}
+	offset := 0
+	for i := range layerState {
+		fc.Fields[i] = layerState[i].in
+		if !ast.IsExported(layerState[i].in.Name) {
+			continue
+		}
+		fvtuples := layerMangledVal[offset : offset+len(layerState[i].out)]
+		offset += len(layerState[i].out)
+		mf, err := t.maybeRecursivelyUnmangle(&layerState[i], fvtuples)
+		if err != nil {
+			return nil, &ReverseTranslateError{Err: err, ErrString: fmt.Sprintf(
+				"failed to unmangle field %d (%q) with mangler %d (type %T): %s",
+				i, layerState[i].in.Name, manglerNum, t.manglers[manglerNum], err)}
+		}
+		fc.Values[i] = mf
+	}
+	return &fc, nil
+}
+
+// reverseTranslate implements ReverseTranslate, initializing the output
+// value with orig if it's valid.
+func (t *Transformer) reverseTranslate(v, orig reflect.Value) (reflect.Value, error) {
 	// iterate through manglers in reverse order passing the value of the struct
 	// field paired with its reflect.StructField as a FieldValueTuple
 
 	layerMangledVal := unpackValueFields(v)
 	// we're iterating backwards through manglers
 	for manglerNum := len(t.manglers) - 1; manglerNum >= 0; manglerNum-- {
+		var fc *FieldContext
+		if _, ok := t.manglers[manglerNum].(ContextualMangler); ok {
+			var fcErr error
+			if fc, fcErr = t.unmangleContext(manglerNum, layerMangledVal); fcErr != nil {
+				return reflect.Value{}, fcErr
+			}
+		}
 		mangledfieldOffset := 0
 		unmangledLayerVals := make([]FieldValueTuple, len(t.mState[manglerNum]))
 		for srcFieldIdx, srcFieldstate := range t.mState[manglerNum] {
+			if !ast.IsExported(srcFieldstate.in.Name) {
+				// TranslateType skips unexported fields
+				// (leaving them unmangled), so there's
+				// nothing to unmangle.
+				continue
+			}
 			// slice down to just the mangled fields we're
 			// interested in for this unmangled field.
 			fvtuples := layerMangledVal[mangledfieldOffset : mangledfieldOffset+len(srcFieldstate.out)]
+			if fc != nil {
+				fc.Index = srcFieldIdx
+				fvtuples = fc.Values[srcFieldIdx]
+			}
 
 			nextLayerVal, unmangleErr := t.unmangleField(
-				manglerNum, &srcFieldstate, fvtuples)
+				manglerNum, &srcFieldstate, fvtuples, fc)
 			if unmangleErr != nil {
 				errString := fmt.Sprintf("failed to unmangle field %d (%q) with mangler %d (type %T): %s",
 					srcFieldIdx, srcFieldstate.in.Name, manglerNum,
@@ -400,6 +590,11 @@ func (t *Transformer) ReverseTranslate(v reflect.Value) (reflect.Value, error) {
 
 	// Now that we've gone through all the manglers, we can reassemble the original struct value.
 	outVal := reflect.New(t.t).Elem()
+	if orig.IsValid() {
+		// every exported field is overwritten below, leaving only
+		// the unexported fields from orig.
+		outVal.Set(orig)
+	}
 	for _, field := range layerMangledVal {
 		if !ast.IsExported(field.Field.Name) {
 			// skip unexported fields
@@ -2,7 +2,9 @@ package transform
 
 import (
 	"errors"
+	"fmt"
 	"reflect"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -861,3 +863,270 @@ func TestTransformer(t *testing.T) {
 		})
 	}
 }
+
+type unexportedStateConfig struct {
+	Name  string
+	Port  int
+	cache map[string]int
+	hits  int
+}
+
+func TestReverseTranslatePreservingUnexported(t *testing.T) {
+	t.Parallel()
+	orig := unexportedStateConfig{
+		Name:  "orig",
+		Port:  80,
+		cache: map[string]int{"a": 1},
+		hits:  3,
+	}
+	for _, itbl := range []struct {
+		name      string
+		orig      func() reflect.Value
+		expectErr string
+	}{
+		{
+			name: "value",
+			orig: func() reflect.Value { return reflect.ValueOf(orig) },
+		},
+		{
+			name: "pointer",
+			orig: func() reflect.Value { return reflect.ValueOf(&orig) },
+		},
+		{
+			name:      "nil_pointer",
+			orig:      func() reflect.Value { return reflect.ValueOf((*unexportedStateConfig)(nil)) },
+			expectErr: "nil original value of type *transform.unexportedStateConfig",
+		},
+		{
+			name:      "wrong_type",
+			orig:      func() reflect.Value { return reflect.ValueOf(struct{ Name string }{}) },
+			expectErr: "original value has type struct { Name string }; expected transform.unexportedStateConfig",
+		},
+		{
+			name:      "invalid",
+			orig:      func() reflect.Value { return reflect.Value{} },
+			expectErr: "invalid original value; expected transform.unexportedStateConfig",
+		},
+		{
+			name: "via_unexported_field",
+			orig: func() reflect.Value {
+				type wrapper struct{ inner unexportedStateConfig }
+				return reflect.ValueOf(wrapper{inner: orig}).Field(0)
+			},
+			expectErr: "original value of type transform.unexportedStateConfig was obtained through an unexported field, and cannot be copied",
+		},
+	} {
+		tbl := itbl
+		t.Run(tbl.name, func(t *testing.T) {
+			t.Parallel()
+			tfmr := NewTransformer(reflect.TypeOf(unexportedStateConfig{}), &fakeMangler{
+				origFieldVals: map[string]interface{}{"Name": "new", "Port": 8080},
+			})
+			val, err := tfmr.Translate()
+			require.NoError(t, err)
+			val.FieldByName("Name").SetString("new")
+			val.FieldByName("Port").SetInt(8080)
+
+			out, revErr := tfmr.ReverseTranslatePreservingUnexported(val, tbl.orig())
+			if tbl.expectErr != "" {
+				assert.EqualError(t, revErr, tbl.expectErr)
+				return
+			}
+			require.NoError(t, revErr)
+			assert.Equal(t, unexportedStateConfig{
+				Name:  "new",
+				Port:  8080,
+				cache: map[string]int{"a": 1},
+				hits:  3,
+			}, out.Interface())
+
+			// plain ReverseTranslate drops the unexported state
+			plain, plainErr := tfmr.ReverseTranslate(val)
+			require.NoError(t, plainErr)
+			assert.Equal(t, unexportedStateConfig{Name: "new", Port: 8080}, plain.Interface())
+		})
+	}
+}
+
+// intStringMangler changes int fields to strings, and parses them back.
+type intStringMangler struct{}
+
+func (intStringMangler) Mangle(sf reflect.StructField) ([]reflect.StructField, error) {
+	if sf.Type.Kind() == reflect.Int {
+		sf.Type = reflect.TypeOf("")
+	}
+	return []reflect.StructField{sf}, nil
+}
+
+func (intStringMangler) Unmangle(sf reflect.StructField, vs []FieldValueTuple) (reflect.Value, error) {
+	if sf.Type.Kind() != reflect.Int {
+		return vs[0].Value, nil
+	}
+	i, err := strconv.Atoi(vs[0].Value.String())
+	return reflect.ValueOf(i), err
+}
+
+func (intStringMangler) ShouldRecurse(reflect.StructField) bool {
+	return true
+}
+
+func TestTransformerSliceOfStructPointers(t *testing.T) {
+	t.Parallel()
+	type endpoint struct {
+		Host string
+		Port int
+	}
+	type config struct {
+		Endpoints []*endpoint
+		Pair      [2]*endpoint
+		Nil       []*endpoint
+	}
+
+	tfmr := NewTransformer(reflect.TypeOf(config{}), intStringMangler{})
+	val, err := tfmr.Translate()
+	require.NoError(t, err)
+
+	mangledEndpointType := reflect.TypeOf(struct {
+		Host string
+		Port string
+	}{})
+	require.Equal(t, reflect.SliceOf(reflect.PtrTo(mangledEndpointType)), val.Field(0).Type())
+	require.Equal(t, reflect.ArrayOf(2, reflect.PtrTo(mangledEndpointType)), val.Field(1).Type())
+
+	newEndpoint := func(host, port string) reflect.Value {
+		ep := reflect.New(mangledEndpointType)
+		ep.Elem().Field(0).SetString(host)
+		ep.Elem().Field(1).SetString(port)
+		return ep
+	}
+	nilEndpoint := reflect.Zero(reflect.PtrTo(mangledEndpointType))
+
+	eps := reflect.MakeSlice(val.Field(0).Type(), 0, 4)
+	eps = reflect.Append(eps, nilEndpoint, newEndpoint("a", "80"), nilEndpoint, newEndpoint("b", "443"))
+	val.Field(0).Set(eps)
+	val.Field(1).Index(1).Set(newEndpoint("c", "8080"))
+
+	out, err := tfmr.ReverseTranslate(val)
+	require.NoError(t, err)
+	assert.Equal(t, config{
+		Endpoints: []*endpoint{nil, {Host: "a", Port: 80}, nil, {Host: "b", Port: 443}},
+		Pair:      [2]*endpoint{nil, {Host: "c", Port: 8080}},
+	}, out.Interface())
+
+	eps.Index(3).Elem().Field(1).SetString("https")
+	_, err = tfmr.ReverseTranslate(val)
+	assert.ErrorContains(t, err, `failed to recursively inverse transform field Endpoints[3]`)
+}
+
+// requiredIfMangler is a ContextualMangler that makes fields tagged
+// `requiredif:"Other"` required if the sibling field Other is set.
+type requiredIfMangler struct {
+	// parents records the Parent types seen by MangleWithContext
+	parents []reflect.Type
+}
+
+func (*requiredIfMangler) Mangle(sf reflect.StructField) ([]reflect.StructField, error) {
+	panic("Mangle called on ContextualMangler")
+}
+
+func (*requiredIfMangler) Unmangle(sf reflect.StructField, vs []FieldValueTuple) (reflect.Value, error) {
+	panic("Unmangle called on ContextualMangler")
+}
+
+func (*requiredIfMangler) ShouldRecurse(reflect.StructField) bool {
+	return true
+}
+
+func (r *requiredIfMangler) MangleWithContext(fc FieldContext) ([]reflect.StructField, error) {
+	r.parents = append(r.parents, fc.Parent)
+	sf := fc.Fields[fc.Index]
+	if other, ok := sf.Tag.Lookup("requiredif"); ok {
+		if _, found := fc.Parent.FieldByName(other); !found {
+			return nil, fmt.Errorf("field %s required if unknown field %s is set", sf.Name, other)
+		}
+	}
+	return []reflect.StructField{sf}, nil
+}
+
+func (*requiredIfMangler) UnmangleWithContext(fc FieldContext, vs []FieldValueTuple) (reflect.Value, error) {
+	sf := fc.Fields[fc.Index]
+	other, ok := sf.Tag.Lookup("requiredif")
+	if !ok || !vs[0].Value.IsZero() {
+		return vs[0].Value, nil
+	}
+	for i, f := range fc.Fields {
+		if f.Name == other && !fc.Values[i][0].Value.IsZero() {
+			return reflect.Value{}, fmt.Errorf("%s is required if %s is set", sf.Name, other)
+		}
+	}
+	return vs[0].Value, nil
+}
+
+func TestContextualMangler(t *testing.T) {
+	t.Parallel()
+	type TLS struct {
+		Cert *string
+		Key  *string `requiredif:"Cert"`
+	}
+	type config struct {
+		Host   *string
+		secret int
+		TLS    *TLS
+		Port   *int `requiredif:"Host"`
+	}
+	str := func(s string) *string { return &s }
+	port := 80
+
+	for name, tbl := range map[string]struct {
+		cfg    config
+		expErr string
+	}{
+		"all_set": {
+			cfg: config{Host: str("fim"), Port: &port, TLS: &TLS{Cert: str("c"), Key: str("k")}},
+		},
+		"none_set": {},
+		"missing_port": {
+			cfg:    config{Host: str("fim")},
+			expErr: "Port is required if Host is set",
+		},
+		"missing_nested_key": {
+			cfg:    config{TLS: &TLS{Cert: str("c")}},
+			expErr: "Key is required if Cert is set",
+		},
+	} {
+		tbl := tbl
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// chain with another mangler, so the ContextualMangler
+			// sees fields output by a previous mangler
+			r := &requiredIfMangler{}
+			tfmr := NewTransformer(reflect.TypeOf(config{}), &SetSliceMangler{}, r)
+			val, err := tfmr.Translate()
+			require.NoError(t, err)
+			// the nested struct's type is the one output by the
+			// SetSliceMangler
+			assert.Contains(t, r.parents, reflect.TypeOf(config{}))
+
+			// the SetSliceMangler leaves these fields unchanged
+			// (other than dropping the unexported field)
+			v := reflect.ValueOf(tbl.cfg)
+			for _, f := range []string{"Host", "TLS", "Port"} {
+				fv := val.FieldByName(f)
+				fv.Set(v.FieldByName(f).Convert(fv.Type()))
+			}
+			out, err := tfmr.ReverseTranslate(val)
+			if tbl.expErr != "" {
+				assert.ErrorContains(t, err, tbl.expErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tbl.cfg, out.Interface())
+		})
+	}
+
+	type badConfig struct {
+		Port *int `requiredif:"Host"`
+	}
+	_, err := NewTransformer(reflect.TypeOf(badConfig{}), &requiredIfMangler{}).Translate()
+	assert.ErrorContains(t, err, "field Port required if unknown field Host is set")
+}
@@ -0,0 +1,104 @@
+package dials
+
+import (
+	"context"
+	"reflect"
+)
+
+// VerifyAll verifies each of the structs embedded in cfg (which should be a
+// pointer to a struct) that implements [VerifiedConfigWithWarnings],
+// [VerifiedConfigCtx] or [VerifiedConfig], returning all of their errors
+// joined into one (or nil, if they all passed), rather than just the first.
+// Embedded structs that implement none of those interfaces are searched for
+// embedded structs that do. Nil embedded pointers are skipped, and warnings
+// are discarded (see [VerifyAllWithWarnings]).
+//
+// Go's method promotion only promotes the Verify method of one embedded
+// struct: the least-deeply embedded one, or none at all if there are
+// several at the same depth, in which case the config isn't a
+// [VerifiedConfig] and nothing is verified. VerifyAll is an explicit opt-in
+// for configs composed of several separately verified structs; dials
+// doesn't call it itself, so call it from the config's own Verify method:
+//
+//	type Config struct {
+//		ServerConfig
+//		DBConfig
+//	}
+//
+//	func (c *Config) VerifyContext(ctx context.Context) error {
+//		return dials.VerifyAll(ctx, c)
+//	}
+//
+// Only embedded structs are verified, so calling VerifyAll from a Verify
+// method doesn't recurse into that method. As with the rest of dials, only
+// exported fields are considered: structs of unexported types (and any
+// structs embedded within them) can't be reached through reflection, so
+// they're skipped, and must be verified by the config's own Verify method.
+func VerifyAll(ctx context.Context, cfg any) error {
+	_, err := VerifyAllWithWarnings(ctx, cfg)
+	return err
+}
+
+// VerifyAllWithWarnings is like [VerifyAll], but also returns the warnings
+// from any embedded structs implementing [VerifiedConfigWithWarnings] (if
+// none returned an error), for use in a VerifyWithWarnings method.
+func VerifyAllWithWarnings(ctx context.Context, cfg any) ([]error, error) {
+	v := reflect.ValueOf(cfg)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, nil
+	}
+	if !v.CanAddr() {
+		// copy the struct so methods with pointer receivers may be
+		// called
+		addressable := reflect.New(v.Type()).Elem()
+		addressable.Set(v)
+		v = addressable
+	}
+	warnings, errs := verifyEmbedded(ctx, v, nil, nil)
+	if len(errs) > 0 {
+		return nil, joinErrors(errs)
+	}
+	return warnings, nil
+}
+
+// verifyEmbedded verifies each of the embedded structs in the addressable
+// struct v, appending the warnings and errors to those passed in.
+func verifyEmbedded(ctx context.Context, v reflect.Value, warnings, errs []error) ([]error, []error) {
+	for i := 0; i < v.NumField(); i++ {
+		sf := v.Type().Field(i)
+		if !sf.Anonymous || !sf.IsExported() {
+			continue
+		}
+		// get a pointer to the embedded struct
+		fv := v.Field(i)
+		var ptr reflect.Value
+		switch fv.Kind() {
+		case reflect.Ptr:
+			if fv.IsNil() || fv.Type().Elem().Kind() != reflect.Struct {
+				continue
+			}
+			ptr = fv
+		case reflect.Struct:
+			ptr = fv.Addr()
+		default:
+			continue
+		}
+		switch ptr.Interface().(type) {
+		case VerifiedConfigWithWarnings, VerifiedConfigCtx, VerifiedConfig:
+			w, err := verify(ctx, ptr.Interface())
+			if err != nil {
+				errs = append(errs, err)
+			}
+			warnings = append(warnings, w...)
+		default:
+			warnings, errs = verifyEmbedded(ctx, ptr.Elem(), warnings, errs)
+		}
+	}
+	return warnings, errs
+}
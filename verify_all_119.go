@@ -0,0 +1,30 @@
+//go:build !go1.20
+
+package dials
+
+import (
+	"strings"
+)
+
+// joinedErrors mirrors the error returned by errors.Join (which isn't
+// available before go 1.20).
+type joinedErrors struct {
+	errs []error
+}
+
+func (j *joinedErrors) Error() string {
+	msgs := make([]string, len(j.errs))
+	for i, err := range j.errs {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+func (j *joinedErrors) Unwrap() []error {
+	return j.errs
+}
+
+// joinErrors joins the non-empty slice of non-nil errors errs into one.
+func joinErrors(errs []error) error {
+	return &joinedErrors{errs: errs}
+}
@@ -0,0 +1,12 @@
+//go:build go1.20
+
+package dials
+
+import (
+	"errors"
+)
+
+// joinErrors joins the non-empty slice of non-nil errors errs into one.
+func joinErrors(errs []error) error {
+	return errors.Join(errs...)
+}
@@ -0,0 +1,155 @@
+package dials
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	errBadServer = errors.New("bad server")
+	errBadDB     = errors.New("bad db")
+)
+
+type ServerPart struct {
+	Port int
+}
+
+func (s *ServerPart) Verify() error {
+	if s.Port <= 0 {
+		return errBadServer
+	}
+	return nil
+}
+
+type DBPart struct {
+	Host string
+}
+
+func (d DBPart) VerifyWithWarnings() ([]error, error) {
+	switch d.Host {
+	case "":
+		return nil, errBadDB
+	case "localhost":
+		return []error{errors.New("using localhost")}, nil
+	}
+	return nil, nil
+}
+
+type CtxPart struct {
+	Name string
+}
+
+func (c CtxPart) VerifyContext(ctx context.Context) error {
+	return ctx.Err()
+}
+
+// hiddenPart's type is unexported, so VerifyAll can't reach it
+type hiddenPart struct{}
+
+func (hiddenPart) Verify() error {
+	return errors.New("hidden")
+}
+
+// GroupPart doesn't implement any verification itself, so VerifyAll looks
+// through it to DBPart
+type GroupPart struct {
+	DBPart
+}
+
+type verifyAllConfig struct {
+	*ServerPart
+	GroupPart
+	CtxPart
+	Name string
+}
+
+func (v *verifyAllConfig) VerifyContext(ctx context.Context) error {
+	return VerifyAll(ctx, v)
+}
+
+func TestVerifyAll(t *testing.T) {
+	t.Parallel()
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for name, tbl := range map[string]struct {
+		ctx         context.Context
+		cfg         any
+		expectedErr []error
+		warnings    int
+	}{
+		"all_good": {
+			ctx: context.Background(),
+			cfg: &verifyAllConfig{ServerPart: &ServerPart{Port: 80}, GroupPart: GroupPart{DBPart{Host: "db"}}},
+		},
+		"all_bad": {
+			ctx:         canceled,
+			cfg:         &verifyAllConfig{ServerPart: &ServerPart{}},
+			expectedErr: []error{errBadServer, errBadDB, context.Canceled},
+		},
+		"nil_embedded_pointer_skipped": {
+			ctx:         context.Background(),
+			cfg:         &verifyAllConfig{},
+			expectedErr: []error{errBadDB},
+		},
+		"warnings": {
+			ctx:      context.Background(),
+			cfg:      &verifyAllConfig{ServerPart: &ServerPart{Port: 80}, GroupPart: GroupPart{DBPart{Host: "localhost"}}},
+			warnings: 1,
+		},
+		"non_pointer": {
+			ctx:         context.Background(),
+			cfg:         verifyAllConfig{ServerPart: &ServerPart{}, GroupPart: GroupPart{DBPart{Host: "db"}}},
+			expectedErr: []error{errBadServer},
+		},
+		"unexported_embedded_skipped": {
+			ctx: context.Background(),
+			cfg: &struct {
+				hiddenPart
+				*ServerPart
+			}{ServerPart: &ServerPart{Port: 80}},
+		},
+		"non_struct": {
+			ctx: context.Background(),
+			cfg: 3,
+		},
+	} {
+		tbl := tbl
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			warnings, err := VerifyAllWithWarnings(tbl.ctx, tbl.cfg)
+			if len(tbl.expectedErr) == 0 {
+				require.NoError(t, err)
+				assert.Len(t, warnings, tbl.warnings)
+				return
+			}
+			require.Error(t, err)
+			assert.Nil(t, warnings)
+			for _, expected := range tbl.expectedErr {
+				assert.ErrorIs(t, err, expected)
+			}
+		})
+	}
+}
+
+func TestVerifyAllConfig(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	_, err := Config(ctx, &verifyAllConfig{})
+	require.Error(t, err)
+	assert.ErrorContains(t, err, errBadDB.Error())
+
+	d, err := Config(ctx, &verifyAllConfig{
+		ServerPart: &ServerPart{Port: 80},
+		GroupPart:  GroupPart{DBPart{Host: "db"}},
+		Name:       "fim",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "fim", d.View().Name)
+}